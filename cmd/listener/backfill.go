@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain"
+	"github.com/EmekaIwuagwu/articium-hub/internal/config"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	algorandlistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/algorand"
+	aptoslistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/aptos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/listener/backfill"
+	bitcoinlistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/bitcoin"
+	cosmoslistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/cosmos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/listener/evm"
+	nearlistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/near"
+	solanalistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/solana"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
+	"github.com/rs/zerolog"
+)
+
+// runBackfill runs `listener backfill`, which reprocesses a range of one
+// chain and records the lock events missing from the database. Recorded
+// messages go through the outbox, so the running listener service's
+// publisher delivers them.
+func runBackfill(args []string) {
+	flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+	path := flags.String("config", "config/config.testnet.yaml", "Path to configuration file")
+	chain := flags.String("chain", "", "Name of the chain to backfill")
+	from := flags.Uint64("from", 0, "First block (slot, round or ledger version) of the range")
+	to := flags.Uint64("to", 0, "Last block (slot, round or ledger version) of the range")
+	batch := flags.Uint64("batch", 10, "Blocks processed per batch")
+	rate := flags.Float64("rate", 2, "Batches processed per second; 0 is unlimited")
+	dryRun := flags.Bool("dry-run", false, "Report missing lock events without recording them")
+	flags.Parse(args)
+
+	logger := setupLogger()
+
+	if *chain == "" {
+		logger.Fatal().Msg("--chain is required")
+	}
+
+	cfg, err := config.LoadConfig(*path)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	var chainCfg *types.ChainConfig
+	for i := range cfg.Chains {
+		if cfg.Chains[i].Name == *chain {
+			chainCfg = &cfg.Chains[i]
+			break
+		}
+	}
+	if chainCfg == nil {
+		logger.Fatal().Str("chain", *chain).Msg("Chain not found in configuration")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	db, err := database.NewDB(&cfg.Database, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer db.Close()
+
+	client, err := blockchain.NewClientFactory(logger).CreateClient(ctx, chainCfg)
+	if err != nil {
+		logger.Fatal().Err(err).Str("chain", *chain).Msg("Failed to create blockchain client")
+	}
+	defer client.Close()
+
+	source, err := newBackfillSource(client, chainCfg, cfg.Chains, db, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Str("chain", *chain).Msg("Failed to create listener")
+	}
+
+	store := &backfillStore{
+		DB:       db,
+		notifier: webhooks.NewQueueNotifier(db, logger),
+	}
+
+	logger.Info().
+		Str("chain", *chain).
+		Uint64("from", *from).
+		Uint64("to", *to).
+		Bool("dry_run", *dryRun).
+		Msg("Starting backfill")
+
+	report, err := backfill.Run(ctx, source, store, backfill.Config{
+		Chain:     *chain,
+		From:      *from,
+		To:        *to,
+		BatchSize: *batch,
+		Rate:      *rate,
+		DryRun:    *dryRun,
+	}, logger)
+	if err != nil {
+		logger.Error().Err(err).Str("chain", *chain).Msg("Backfill failed")
+		db.Close()
+		os.Exit(1)
+	}
+
+	for _, msg := range report.Missing {
+		fmt.Printf("%s\t%d\t%s\t%d\n", msg.ID, msg.SourceBlock, msg.SourceTxHash, msg.SourceLogIndex)
+	}
+}
+
+// newBackfillSource creates a listener for the chain without starting it
+func newBackfillSource(client types.UniversalClient, chainCfg *types.ChainConfig, chains []types.ChainConfig, db *database.DB, logger zerolog.Logger) (backfill.Source, error) {
+	switch c := client.(type) {
+	case *blockchain.EVMClientAdapter:
+		return evm.NewListener(c.GetUnderlyingClient(), chainCfg, logger)
+	case *blockchain.SolanaClientAdapter:
+		return solanalistener.NewListener(c.GetUnderlyingClient(), chainCfg, chains, logger)
+	case *blockchain.NEARClientAdapter:
+		return nearlistener.NewListener(c.GetUnderlyingClient(), chainCfg, chains, logger)
+	case *blockchain.AlgorandClientAdapter:
+		return algorandlistener.NewListener(c.GetUnderlyingClient(), chainCfg, chains, logger)
+	case *blockchain.AptosClientAdapter:
+		return aptoslistener.NewListener(c.GetUnderlyingClient(), chainCfg, chains, logger)
+	case *blockchain.CosmosClientAdapter:
+		return cosmoslistener.NewListener(c.GetUnderlyingClient(), chainCfg, chains, logger)
+	case *blockchain.BitcoinClientAdapter:
+		return bitcoinlistener.NewListener(c.GetUnderlyingClient(), chainCfg, chains, db, logger)
+	default:
+		return nil, fmt.Errorf("unsupported chain type: %s", chainCfg.ChainType)
+	}
+}
+
+// backfillStore records messages like the event processor does, notifying
+// webhooks of each one
+type backfillStore struct {
+	*database.DB
+	notifier *webhooks.Notifier
+}
+
+func (s *backfillStore) InsertMessageWithOutbox(ctx context.Context, msg *types.CrossChainMessage) (bool, error) {
+	inserted, err := s.DB.InsertMessageWithOutbox(ctx, msg)
+	if err != nil || !inserted {
+		return inserted, err
+	}
+	s.notifier.NotifyMessageCreated(ctx, msg)
+	return true, nil
+}
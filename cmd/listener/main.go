@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"os"
 	"os/signal"
@@ -11,11 +12,17 @@ import (
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain"
 	"github.com/EmekaIwuagwu/articium-hub/internal/config"
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	algorandlistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/algorand"
+	aptoslistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/aptos"
+	bitcoinlistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/bitcoin"
+	cosmoslistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/cosmos"
 	"github.com/EmekaIwuagwu/articium-hub/internal/listener/evm"
 	nearlistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/near"
 	solanalistener "github.com/EmekaIwuagwu/articium-hub/internal/listener/solana"
+	"github.com/EmekaIwuagwu/articium-hub/internal/outbox"
 	"github.com/EmekaIwuagwu/articium-hub/internal/queue"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
 	"github.com/rs/zerolog"
 )
 
@@ -29,6 +36,11 @@ type EventListener interface {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		runBackfill(os.Args[2:])
+		return
+	}
+
 	flag.Parse()
 
 	// Setup logger
@@ -84,8 +96,20 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Start outbox publisher
+	publisher := outbox.NewPublisher(nil, db, q, logger)
+	if err := publisher.Start(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start outbox publisher")
+	}
+	defer publisher.Stop()
+
+	// Webhook events are queued in the database and delivered by the API
+	// servers
+	notifier := webhooks.NewQueueNotifier(db, logger)
+
 	// Start listeners based on chain type
-	for _, chainCfg := range cfg.Chains {
+	for i := range cfg.Chains {
+		chainCfg := &cfg.Chains[i]
 		switch chainCfg.ChainType {
 		case types.ChainTypeEVM:
 			evmClient, ok := clients[chainCfg.Name].(*blockchain.EVMClientAdapter)
@@ -95,7 +119,7 @@ func main() {
 					Msg("Failed to cast client to EVM client")
 			}
 
-			listener, err := evm.NewListener(evmClient.GetUnderlyingClient(), &chainCfg, logger)
+			listener, err := evm.NewListener(evmClient.GetUnderlyingClient(), chainCfg, logger)
 			if err != nil {
 				logger.Fatal().
 					Err(err).
@@ -112,7 +136,7 @@ func main() {
 			}
 
 			// Start event processor
-			go processEvents(ctx, listener, q, db, logger, chainCfg.Name)
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
 
 			logger.Info().
 				Str("chain", chainCfg.Name).
@@ -126,7 +150,7 @@ func main() {
 					Msg("Failed to cast client to Solana client")
 			}
 
-			listener, err := solanalistener.NewListener(solanaClient.GetUnderlyingClient(), &chainCfg, logger)
+			listener, err := solanalistener.NewListener(solanaClient.GetUnderlyingClient(), chainCfg, cfg.Chains, logger)
 			if err != nil {
 				logger.Fatal().
 					Err(err).
@@ -143,7 +167,7 @@ func main() {
 			}
 
 			// Start event processor
-			go processEvents(ctx, listener, q, db, logger, chainCfg.Name)
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
 
 			logger.Info().
 				Str("chain", chainCfg.Name).
@@ -157,7 +181,7 @@ func main() {
 					Msg("Failed to cast client to NEAR client")
 			}
 
-			listener, err := nearlistener.NewListener(nearClient.GetUnderlyingClient(), &chainCfg, logger)
+			listener, err := nearlistener.NewListener(nearClient.GetUnderlyingClient(), chainCfg, cfg.Chains, logger)
 			if err != nil {
 				logger.Fatal().
 					Err(err).
@@ -174,12 +198,137 @@ func main() {
 			}
 
 			// Start event processor
-			go processEvents(ctx, listener, q, db, logger, chainCfg.Name)
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
 
 			logger.Info().
 				Str("chain", chainCfg.Name).
 				Msg("NEAR listener started")
 
+		case types.ChainTypeAlgorand:
+			algorandClient, ok := clients[chainCfg.Name].(*blockchain.AlgorandClientAdapter)
+			if !ok {
+				logger.Fatal().
+					Str("chain", chainCfg.Name).
+					Msg("Failed to cast client to Algorand client")
+			}
+
+			listener, err := algorandlistener.NewListener(algorandClient.GetUnderlyingClient(), chainCfg, cfg.Chains, logger)
+			if err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to create Algorand listener")
+			}
+
+			// Start listener
+			if err := listener.Start(ctx); err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to start listener")
+			}
+
+			// Start event processor
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
+
+			logger.Info().
+				Str("chain", chainCfg.Name).
+				Msg("Algorand listener started")
+
+		case types.ChainTypeAptos:
+			aptosClient, ok := clients[chainCfg.Name].(*blockchain.AptosClientAdapter)
+			if !ok {
+				logger.Fatal().
+					Str("chain", chainCfg.Name).
+					Msg("Failed to cast client to Aptos client")
+			}
+
+			listener, err := aptoslistener.NewListener(aptosClient.GetUnderlyingClient(), chainCfg, cfg.Chains, logger)
+			if err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to create Aptos listener")
+			}
+
+			// Start listener
+			if err := listener.Start(ctx); err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to start listener")
+			}
+
+			// Start event processor
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
+
+			logger.Info().
+				Str("chain", chainCfg.Name).
+				Msg("Aptos listener started")
+
+		case types.ChainTypeCosmos:
+			cosmosClient, ok := clients[chainCfg.Name].(*blockchain.CosmosClientAdapter)
+			if !ok {
+				logger.Fatal().
+					Str("chain", chainCfg.Name).
+					Msg("Failed to cast client to Cosmos client")
+			}
+
+			listener, err := cosmoslistener.NewListener(cosmosClient.GetUnderlyingClient(), chainCfg, cfg.Chains, logger)
+			if err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to create Cosmos listener")
+			}
+
+			// Start listener
+			if err := listener.Start(ctx); err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to start listener")
+			}
+
+			// Start event processor
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
+
+			logger.Info().
+				Str("chain", chainCfg.Name).
+				Msg("Cosmos listener started")
+
+		case types.ChainTypeBitcoin:
+			bitcoinClient, ok := clients[chainCfg.Name].(*blockchain.BitcoinClientAdapter)
+			if !ok {
+				logger.Fatal().
+					Str("chain", chainCfg.Name).
+					Msg("Failed to cast client to Bitcoin client")
+			}
+
+			// The listener indexes the custody's UTXOs for releases
+			listener, err := bitcoinlistener.NewListener(bitcoinClient.GetUnderlyingClient(), chainCfg, cfg.Chains, db, logger)
+			if err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to create Bitcoin listener")
+			}
+
+			// Start listener
+			if err := listener.Start(ctx); err != nil {
+				logger.Fatal().
+					Err(err).
+					Str("chain", chainCfg.Name).
+					Msg("Failed to start listener")
+			}
+
+			// Start event processor
+			go processEvents(ctx, listener, publisher, notifier, db, logger, chainCfg.Name)
+
+			logger.Info().
+				Str("chain", chainCfg.Name).
+				Msg("Bitcoin listener started")
+
 		default:
 			logger.Warn().
 				Str("chain", chainCfg.Name).
@@ -222,8 +371,9 @@ func setupLogger() zerolog.Logger {
 		Logger()
 }
 
-// processEvents processes events from a listener and publishes them to the queue
-func processEvents(ctx context.Context, listener EventListener, q queue.Queue, db *database.DB, logger zerolog.Logger, chainName string) {
+// processEvents saves events from a listener to the database and outbox; the
+// outbox publisher delivers them to the queue
+func processEvents(ctx context.Context, listener EventListener, publisher *outbox.Publisher, notifier *webhooks.Notifier, db *database.DB, logger zerolog.Logger, chainName string) {
 	eventLogger := logger.With().Str("chain", chainName).Str("component", "event-processor").Logger()
 	eventLogger.Info().Msg("Event processor started")
 
@@ -239,28 +389,39 @@ func processEvents(ctx context.Context, listener EventListener, q queue.Queue, d
 				return
 			}
 
-			// Save message to database
-			if err := db.SaveMessage(ctx, msg); err != nil {
+			// Save message and outbox entry in one transaction. Events are
+			// seen again after a restart, gap fill or re-page; a message
+			// already recorded is left as it is and not published again.
+			inserted, err := db.InsertMessageWithOutbox(ctx, msg)
+			if err != nil {
+				if errors.Is(err, database.ErrDuplicateSourceEvent) {
+					eventLogger.Debug().
+						Err(err).
+						Str("message_id", msg.ID).
+						Msg("Skipping already recorded source event")
+					continue
+				}
 				eventLogger.Error().
 					Err(err).
 					Str("message_id", msg.ID).
 					Msg("Failed to save message to database")
 				continue
 			}
-
-			// Publish to queue
-			if err := q.Publish(ctx, msg); err != nil {
-				eventLogger.Error().
-					Err(err).
+			if !inserted {
+				eventLogger.Debug().
 					Str("message_id", msg.ID).
-					Msg("Failed to publish message to queue")
+					Msg("Skipping already recorded message")
 				continue
 			}
 
+			publisher.Notify()
+
 			eventLogger.Info().
 				Str("message_id", msg.ID).
 				Str("type", string(msg.Type)).
-				Msg("Message published to queue")
+				Msg("Message saved to outbox")
+
+			notifier.NotifyMessageCreated(ctx, msg)
 		}
 	}
 }
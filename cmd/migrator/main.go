@@ -48,15 +48,7 @@ func main() {
 	logger.Info().Msg("Database connection established")
 
 	// Execute schema files in order
-	schemaFiles := []string{
-		"schema.sql",   // Main tables (chains, messages, validators, etc.)
-		"auth.sql",     // Authentication tables (users, api_keys)
-		"batches.sql",  // Batch processing tables
-		"routes.sql",   // Multi-hop routing tables
-		"webhooks.sql", // Webhook integration tables
-	}
-
-	for _, filename := range schemaFiles {
+	for _, filename := range database.SchemaFiles {
 		schemaPath := fmt.Sprintf("%s/%s", *schemaDir, filename)
 
 		logger.Info().
@@ -150,7 +150,7 @@ func createSigners(cfg *config.Config, logger zerolog.Logger) (map[string]crypto
 		var err error
 
 		switch chain.ChainType {
-		case types.ChainTypeEVM:
+		case types.ChainTypeEVM, types.ChainTypeCosmos, types.ChainTypeBitcoin:
 			// In production, load private key from secure storage (HSM, KMS, etc.)
 			// For now, we'll create a placeholder signer
 			// You would use: evmCrypto.NewECDSASigner(privateKeyHex)
@@ -165,7 +165,7 @@ func createSigners(cfg *config.Config, logger zerolog.Logger) (map[string]crypto
 				return nil, fmt.Errorf("failed to create EVM signer for %s: %w", chain.Name, err)
 			}
 
-		case types.ChainTypeSolana, types.ChainTypeNEAR:
+		case types.ChainTypeSolana, types.ChainTypeNEAR, types.ChainTypeAlgorand, types.ChainTypeAptos:
 			// In production, load Ed25519 private key from secure storage
 			logger.Warn().
 				Str("chain", chain.Name).
@@ -333,7 +333,7 @@ build_services() {
 
     # Build Listener
     log_info "Building Listener service..."
-    go build $BUILD_FLAGS -o bin/listener cmd/listener/main.go
+    go build $BUILD_FLAGS -o bin/listener ./cmd/listener
     log_success "Listener built"
 
     # Build Relayer
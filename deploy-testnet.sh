@@ -122,7 +122,7 @@ build_services() {
 
     # Build Listener
     log_info "Building Listener service..."
-    go build -o bin/listener cmd/listener/main.go
+    go build -o bin/listener ./cmd/listener
     log_success "Listener built"
 
     # Build Relayer
@@ -0,0 +1,376 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/config"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultDedupeWindow       = 15 * time.Minute
+	defaultMaxAlertsPerMinute = 30
+	defaultGasSpikeMultiplier = 3.0
+	defaultSendTimeout        = 10 * time.Second
+	defaultSource             = "articium-hub"
+)
+
+// Dispatcher fans alerts out to the configured sinks with deduplication,
+// per-sink rate limiting and resolve notifications. A nil Dispatcher is
+// valid and silently drops every alert.
+type Dispatcher struct {
+	config       *config.AlertingConfig
+	logger       zerolog.Logger
+	sinks        []Sink
+	limiters     map[string]*tokenBucket
+	dedupeWindow time.Duration
+	maxPerMinute int
+	now          func() time.Time
+
+	mu     sync.Mutex
+	active map[string]*activeAlert
+
+	// Trigger state
+	chains   map[string]*chainState
+	failures map[string]int
+	gas      map[string]*gasState
+}
+
+// activeAlert tracks an alert that has fired and not yet been resolved
+type activeAlert struct {
+	alert      Alert
+	firstFired time.Time
+	lastSent   time.Time
+	lastSeen   time.Time
+	suppressed int
+}
+
+// NewDispatcher creates a dispatcher with sinks built from the alerting configuration
+func NewDispatcher(cfg *config.AlertingConfig, logger zerolog.Logger) *Dispatcher {
+	d := &Dispatcher{
+		config:       cfg,
+		logger:       logger.With().Str("component", "alerting").Logger(),
+		limiters:     make(map[string]*tokenBucket),
+		dedupeWindow: defaultDedupeWindow,
+		maxPerMinute: defaultMaxAlertsPerMinute,
+		now:          time.Now,
+		active:       make(map[string]*activeAlert),
+		chains:       make(map[string]*chainState),
+		failures:     make(map[string]int),
+		gas:          make(map[string]*gasState),
+	}
+
+	if cfg.DedupeWindow != "" {
+		if window, err := time.ParseDuration(cfg.DedupeWindow); err == nil {
+			d.dedupeWindow = window
+		} else {
+			d.logger.Warn().
+				Err(err).
+				Str("dedupe_window", cfg.DedupeWindow).
+				Msg("Invalid dedupe window, using default")
+		}
+	}
+	if cfg.MaxAlertsPerMinute > 0 {
+		d.maxPerMinute = cfg.MaxAlertsPerMinute
+	}
+
+	if !cfg.Enabled {
+		d.logger.Info().Msg("Alerting disabled in configuration")
+		return d
+	}
+
+	client := &http.Client{Timeout: defaultSendTimeout}
+
+	if cfg.SlackWebhook != "" {
+		d.AddSink(NewSlackSink(cfg.SlackWebhook, client))
+	}
+	if cfg.PagerDutyKey != "" {
+		d.AddSink(NewPagerDutySink(cfg.PagerDutyKey, cfg.PagerDutyEndpoint, client))
+	}
+	if cfg.WebhookURL != "" {
+		d.AddSink(NewWebhookSink(cfg.WebhookURL, client))
+	}
+
+	d.logger.Info().
+		Int("sinks", len(d.sinks)).
+		Dur("dedupe_window", d.dedupeWindow).
+		Int("max_per_minute", d.maxPerMinute).
+		Msg("Alert dispatcher initialized")
+
+	return d
+}
+
+// AddSink registers an additional sink
+func (d *Dispatcher) AddSink(sink Sink) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sinks = append(d.sinks, sink)
+	d.limiters[sink.Name()] = newTokenBucket(d.maxPerMinute, time.Minute, d.now())
+}
+
+// Enabled reports whether alerts will be delivered anywhere
+func (d *Dispatcher) Enabled() bool {
+	if d == nil || !d.config.Enabled {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.sinks) > 0
+}
+
+// Fire sends an alert unless an alert with the same key was already sent
+// within the dedupe window at the same or higher severity
+func (d *Dispatcher) Fire(ctx context.Context, alert Alert) error {
+	if !d.Enabled() {
+		return nil
+	}
+
+	now := d.now()
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = now
+	}
+	if alert.Source == "" {
+		alert.Source = defaultSource
+	}
+	if alert.Severity == "" {
+		alert.Severity = SeverityWarning
+	}
+	alert.Resolved = false
+
+	d.mu.Lock()
+	d.pruneLocked(now)
+	existing, ok := d.active[alert.Key]
+	var previous activeAlert
+	if ok {
+		previous = *existing
+		existing.lastSeen = now
+		escalated := alert.Severity.rank() > existing.alert.Severity.rank()
+		if !escalated && now.Sub(existing.lastSent) < d.dedupeWindow {
+			existing.suppressed++
+			d.mu.Unlock()
+			AlertsSuppressed.WithLabelValues(alert.Category).Inc()
+			d.logger.Debug().
+				Str("key", alert.Key).
+				Int("suppressed", existing.suppressed).
+				Msg("Duplicate alert suppressed")
+			return nil
+		}
+		if existing.suppressed > 0 {
+			if alert.Fields == nil {
+				alert.Fields = make(map[string]string)
+			}
+			alert.Fields["suppressed_duplicates"] = fmt.Sprintf("%d", existing.suppressed)
+		}
+		existing.alert = alert
+		existing.lastSent = now
+		existing.suppressed = 0
+	} else {
+		d.active[alert.Key] = &activeAlert{
+			alert:      alert,
+			firstFired: now,
+			lastSent:   now,
+			lastSeen:   now,
+		}
+	}
+	AlertsActive.Set(float64(len(d.active)))
+	d.mu.Unlock()
+
+	d.logger.Warn().
+		Str("key", alert.Key).
+		Str("severity", string(alert.Severity)).
+		Str("title", alert.Title).
+		Msg("Alert fired")
+
+	delivered, err := d.fanOut(ctx, &alert)
+	if delivered == 0 {
+		// Nothing went out, so roll back the send record; otherwise a
+		// rate-limited or failed alert would be deduped for the whole window
+		d.mu.Lock()
+		if current, ok := d.active[alert.Key]; ok && current.lastSent.Equal(now) {
+			if previous.firstFired.IsZero() {
+				delete(d.active, alert.Key)
+				AlertsActive.Set(float64(len(d.active)))
+			} else {
+				current.alert = previous.alert
+				current.lastSent = previous.lastSent
+				current.suppressed = previous.suppressed
+			}
+		}
+		d.mu.Unlock()
+	}
+
+	return err
+}
+
+// Resolve sends a resolve notification for a firing alert. It is a no-op
+// if no alert with the key is active.
+func (d *Dispatcher) Resolve(ctx context.Context, key, message string) error {
+	if !d.Enabled() {
+		return nil
+	}
+
+	d.mu.Lock()
+	existing, ok := d.active[key]
+	if ok {
+		delete(d.active, key)
+		AlertsActive.Set(float64(len(d.active)))
+	}
+	d.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	alert := existing.alert
+	alert.Resolved = true
+	alert.Timestamp = d.now()
+	if message != "" {
+		alert.Message = message
+	}
+
+	d.logger.Info().
+		Str("key", key).
+		Dur("duration", alert.Timestamp.Sub(existing.firstFired)).
+		Msg("Alert resolved")
+
+	_, err := d.fanOut(ctx, &alert)
+	return err
+}
+
+// IsActive reports whether an alert with the given key is currently firing
+func (d *Dispatcher) IsActive(key string) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.active[key]
+	return ok
+}
+
+// fanOut delivers an alert to every sink concurrently and returns the number
+// of sinks that accepted it. Resolve notifications bypass the rate limit so
+// incidents are never left open downstream.
+func (d *Dispatcher) fanOut(ctx context.Context, alert *Alert) (int, error) {
+	d.mu.Lock()
+	sinks := make([]Sink, len(d.sinks))
+	copy(sinks, d.sinks)
+	d.mu.Unlock()
+
+	var (
+		wg        sync.WaitGroup
+		errMu     sync.Mutex
+		errs      []error
+		delivered int
+	)
+
+	for _, sink := range sinks {
+		if !alert.Resolved && !d.allow(sink.Name()) {
+			AlertsRateLimited.WithLabelValues(sink.Name()).Inc()
+			d.logger.Warn().
+				Str("sink", sink.Name()).
+				Str("key", alert.Key).
+				Msg("Alert rate limit exceeded, dropping alert")
+			continue
+		}
+
+		wg.Add(1)
+		go func(sink Sink) {
+			defer wg.Done()
+
+			sendCtx, cancel := context.WithTimeout(ctx, defaultSendTimeout)
+			defer cancel()
+
+			if err := sink.Send(sendCtx, alert); err != nil {
+				AlertsFailed.WithLabelValues(sink.Name(), alert.Category).Inc()
+				d.logger.Error().
+					Err(err).
+					Str("sink", sink.Name()).
+					Str("key", alert.Key).
+					Msg("Failed to deliver alert")
+
+				errMu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+				errMu.Unlock()
+				return
+			}
+
+			AlertsSent.WithLabelValues(sink.Name(), alert.Category, string(alert.Severity)).Inc()
+
+			errMu.Lock()
+			delivered++
+			errMu.Unlock()
+		}(sink)
+	}
+
+	wg.Wait()
+	return delivered, errors.Join(errs...)
+}
+
+// allow consumes a token from the sink's rate limiter
+func (d *Dispatcher) allow(sinkName string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	limiter, ok := d.limiters[sinkName]
+	if !ok {
+		return true
+	}
+	return limiter.allow(d.now())
+}
+
+// pruneLocked forgets alerts that have not been seen for two dedupe windows.
+// One-shot alerts are never resolved explicitly and would otherwise accumulate.
+func (d *Dispatcher) pruneLocked(now time.Time) {
+	for key, a := range d.active {
+		if now.Sub(a.lastSeen) > 2*d.dedupeWindow {
+			delete(d.active, key)
+		}
+	}
+}
+
+// tokenBucket is a simple token bucket refilled continuously over a period
+type tokenBucket struct {
+	capacity   float64
+	tokens     float64
+	refillRate float64 // tokens per second
+	lastRefill time.Time
+}
+
+func newTokenBucket(capacity int, period time.Duration, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity:   float64(capacity),
+		tokens:     float64(capacity),
+		refillRate: float64(capacity) / period.Seconds(),
+		lastRefill: now,
+	}
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.refillRate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.lastRefill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,399 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/config"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// recorder is a local HTTP stand-in that records every JSON body it receives
+type recorder struct {
+	mu      sync.Mutex
+	bodies  []map[string]interface{}
+	headers []http.Header
+	status  int
+	server  *httptest.Server
+}
+
+func newRecorder(t *testing.T) *recorder {
+	r := &recorder{status: http.StatusOK}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode body: %v", err)
+		}
+
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.headers = append(r.headers, req.Header.Clone())
+		status := r.status
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func (r *recorder) last() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.bodies) == 0 {
+		return nil
+	}
+	return r.bodies[len(r.bodies)-1]
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestDispatcher(t *testing.T, cfg *config.AlertingConfig) (*Dispatcher, *recorder, *recorder, *recorder, *testClock) {
+	slack := newRecorder(t)
+	pagerduty := newRecorder(t)
+	webhook := newRecorder(t)
+
+	cfg.Enabled = true
+	cfg.SlackWebhook = slack.server.URL
+	cfg.PagerDutyKey = "test-routing-key"
+	cfg.PagerDutyEndpoint = pagerduty.server.URL
+	cfg.WebhookURL = webhook.server.URL
+
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	d := NewDispatcher(cfg, zerolog.Nop())
+	d.now = clock.Now
+	// Re-create limiters against the test clock
+	for name := range d.limiters {
+		d.limiters[name] = newTokenBucket(d.maxPerMinute, time.Minute, clock.Now())
+	}
+
+	return d, slack, pagerduty, webhook, clock
+}
+
+func testMessage() *types.CrossChainMessage {
+	return &types.CrossChainMessage{
+		ID:               "msg-1",
+		SourceChain:      types.ChainInfo{Name: "polygon-amoy"},
+		DestinationChain: types.ChainInfo{Name: "solana-devnet"},
+		Sender:           types.Address{Raw: "0xsender"},
+		Recipient:        types.Address{Raw: "recipient"},
+		SourceTxHash:     "0xabc",
+	}
+}
+
+func TestDispatcher_FanOutToAllSinks(t *testing.T) {
+	d, slack, pagerduty, webhook, _ := newTestDispatcher(t, &config.AlertingConfig{AlertOnLargeTransaction: true})
+
+	if err := d.LargeTransaction(context.Background(), testMessage(), big.NewInt(500000)); err != nil {
+		t.Fatalf("LargeTransaction failed: %v", err)
+	}
+
+	if slack.count() != 1 || pagerduty.count() != 1 || webhook.count() != 1 {
+		t.Fatalf("Expected one delivery per sink, got slack=%d pagerduty=%d webhook=%d",
+			slack.count(), pagerduty.count(), webhook.count())
+	}
+
+	pd := pagerduty.last()
+	if pd["event_action"] != "trigger" {
+		t.Errorf("Expected PagerDuty trigger, got %v", pd["event_action"])
+	}
+	if pd["routing_key"] != "test-routing-key" {
+		t.Errorf("Expected routing key to be sent, got %v", pd["routing_key"])
+	}
+	if pd["dedup_key"] != "large_transaction:msg-1" {
+		t.Errorf("Unexpected dedup key: %v", pd["dedup_key"])
+	}
+
+	wh := webhook.last()
+	if wh["severity"] != string(SeverityWarning) {
+		t.Errorf("Expected warning severity, got %v", wh["severity"])
+	}
+	if webhook.headers[0].Get("X-Alert-Key") != "large_transaction:msg-1" {
+		t.Errorf("Expected X-Alert-Key header, got %q", webhook.headers[0].Get("X-Alert-Key"))
+	}
+}
+
+func TestDispatcher_Deduplication(t *testing.T) {
+	d, slack, _, _, clock := newTestDispatcher(t, &config.AlertingConfig{DedupeWindow: "10m"})
+	ctx := context.Background()
+	msg := testMessage()
+
+	for i := 0; i < 3; i++ {
+		if err := d.FraudFlag(ctx, msg, "rapid_transactions"); err != nil {
+			t.Fatalf("FraudFlag failed: %v", err)
+		}
+	}
+	if slack.count() != 1 {
+		t.Fatalf("Expected duplicates to be suppressed, got %d deliveries", slack.count())
+	}
+
+	// After the window the alert is re-sent with the suppressed count
+	clock.Advance(11 * time.Minute)
+	if err := d.FraudFlag(ctx, msg, "rapid_transactions"); err != nil {
+		t.Fatalf("FraudFlag failed: %v", err)
+	}
+	if slack.count() != 2 {
+		t.Fatalf("Expected re-notification after dedupe window, got %d deliveries", slack.count())
+	}
+}
+
+func TestDispatcher_EscalationBypassesDedupe(t *testing.T) {
+	d, slack, _, _, _ := newTestDispatcher(t, &config.AlertingConfig{})
+	ctx := context.Background()
+
+	alert := Alert{Key: "custom:1", Category: "custom", Severity: SeverityWarning, Title: "test"}
+	_ = d.Fire(ctx, alert)
+	_ = d.Fire(ctx, alert)
+
+	alert.Severity = SeverityCritical
+	_ = d.Fire(ctx, alert)
+
+	if slack.count() != 2 {
+		t.Errorf("Expected warning and escalated critical alert, got %d deliveries", slack.count())
+	}
+}
+
+func TestDispatcher_Resolve(t *testing.T) {
+	d, slack, pagerduty, _, _ := newTestDispatcher(t, &config.AlertingConfig{})
+	ctx := context.Background()
+
+	// Resolving an unknown alert is a no-op
+	if err := d.Resolve(ctx, "unknown", ""); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if pagerduty.count() != 0 {
+		t.Fatalf("Expected no deliveries, got %d", pagerduty.count())
+	}
+
+	if err := d.ChainHealth(ctx, "polygon-amoy", false); err != nil {
+		t.Fatalf("ChainHealth failed: %v", err)
+	}
+	if !d.IsActive("chain_health:polygon-amoy") {
+		t.Fatal("Expected chain health alert to be active")
+	}
+
+	if err := d.ChainHealth(ctx, "polygon-amoy", true); err != nil {
+		t.Fatalf("ChainHealth failed: %v", err)
+	}
+	if d.IsActive("chain_health:polygon-amoy") {
+		t.Error("Expected chain health alert to be resolved")
+	}
+
+	pd := pagerduty.last()
+	if pd["event_action"] != "resolve" {
+		t.Errorf("Expected PagerDuty resolve, got %v", pd["event_action"])
+	}
+	if pd["dedup_key"] != "chain_health:polygon-amoy" {
+		t.Errorf("Unexpected dedup key: %v", pd["dedup_key"])
+	}
+
+	text, _ := slack.last()["text"].(string)
+	if text != "[RESOLVED] Chain polygon-amoy is unhealthy" {
+		t.Errorf("Unexpected Slack text: %q", text)
+	}
+}
+
+func TestDispatcher_RateLimit(t *testing.T) {
+	d, slack, _, _, clock := newTestDispatcher(t, &config.AlertingConfig{MaxAlertsPerMinute: 2})
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		_ = d.Fire(ctx, Alert{Key: key, Category: "custom", Title: key})
+	}
+	if slack.count() != 2 {
+		t.Fatalf("Expected 2 deliveries within the rate limit, got %d", slack.count())
+	}
+
+	// Resolves are never rate limited
+	_ = d.Resolve(ctx, "a", "")
+	if slack.count() != 3 {
+		t.Fatalf("Expected resolve to bypass the rate limit, got %d deliveries", slack.count())
+	}
+
+	clock.Advance(time.Minute)
+	_ = d.Fire(ctx, Alert{Key: "d", Category: "custom", Title: "d"})
+	if slack.count() != 4 {
+		t.Errorf("Expected tokens to refill after a minute, got %d deliveries", slack.count())
+	}
+}
+
+func TestDispatcher_ChainFlapping(t *testing.T) {
+	d, _, _, _, clock := newTestDispatcher(t, &config.AlertingConfig{})
+	ctx := context.Background()
+
+	healthy := false
+	for i := 0; i < flapThreshold; i++ {
+		_ = d.ChainHealth(ctx, "near-testnet", healthy)
+		healthy = !healthy
+		clock.Advance(time.Minute)
+	}
+
+	if !d.IsActive("chain_flapping:near-testnet") {
+		t.Fatal("Expected flapping alert to be active")
+	}
+
+	clock.Advance(flapWindow)
+	_ = d.ChainHealth(ctx, "near-testnet", true)
+	if d.IsActive("chain_flapping:near-testnet") {
+		t.Error("Expected flapping alert to resolve once health is stable")
+	}
+}
+
+func TestDispatcher_FailureThreshold(t *testing.T) {
+	d, _, pagerduty, _, _ := newTestDispatcher(t, &config.AlertingConfig{AlertOnFailureThreshold: 3})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_ = d.MessageOutcome(ctx, "solana-devnet", false, nil)
+	}
+	if pagerduty.count() != 0 {
+		t.Fatalf("Expected no alert below threshold, got %d", pagerduty.count())
+	}
+
+	_ = d.MessageOutcome(ctx, "solana-devnet", false, nil)
+	if pagerduty.count() != 1 {
+		t.Fatalf("Expected alert at threshold, got %d", pagerduty.count())
+	}
+
+	_ = d.MessageOutcome(ctx, "solana-devnet", true, nil)
+	if pagerduty.last()["event_action"] != "resolve" {
+		t.Errorf("Expected success to resolve the alert")
+	}
+}
+
+func TestDispatcher_GasSpike(t *testing.T) {
+	d, _, _, _, _ := newTestDispatcher(t, &config.AlertingConfig{AlertOnHighGas: true, GasSpikeMultiplier: 2})
+	ctx := context.Background()
+	key := "gas_spike:polygon-amoy"
+
+	for i := 0; i < gasWarmupSamples; i++ {
+		_ = d.GasPrice(ctx, "polygon-amoy", big.NewInt(30_000_000_000), "")
+	}
+	if d.IsActive(key) {
+		t.Fatal("Expected no alert for steady gas prices")
+	}
+
+	_ = d.GasPrice(ctx, "polygon-amoy", big.NewInt(90_000_000_000), "")
+	if !d.IsActive(key) {
+		t.Fatal("Expected alert on gas spike")
+	}
+
+	_ = d.GasPrice(ctx, "polygon-amoy", big.NewInt(31_000_000_000), "")
+	if d.IsActive(key) {
+		t.Error("Expected alert to resolve when gas returns to normal")
+	}
+
+	// Reaching the configured maximum alerts even without a spike
+	_ = d.GasPrice(ctx, "polygon-amoy", big.NewInt(31_000_000_000), "30")
+	if !d.IsActive(key) {
+		t.Error("Expected alert when gas price reaches the configured maximum")
+	}
+}
+
+func TestDispatcher_GasPriceMaxIsGwei(t *testing.T) {
+	d, _, _, _, _ := newTestDispatcher(t, &config.AlertingConfig{AlertOnHighGas: true})
+	ctx := context.Background()
+	key := "gas_spike:polygon-amoy"
+
+	// 30 gwei is well below a 500 gwei maximum
+	_ = d.GasPrice(ctx, "polygon-amoy", big.NewInt(30_000_000_000), "500")
+	if d.IsActive(key) {
+		t.Fatal("Expected no alert below the configured maximum")
+	}
+
+	// Fractional maximums are supported
+	_ = d.GasPrice(ctx, "polygon-amoy", big.NewInt(100_000_000), "0.1")
+	if !d.IsActive(key) {
+		t.Error("Expected alert when gas price reaches a fractional gwei maximum")
+	}
+}
+
+func TestGweiToWei(t *testing.T) {
+	tests := map[string]string{
+		"500": "500000000000",
+		"0.1": "100000000",
+		"20":  "20000000000",
+	}
+	for gwei, want := range tests {
+		got, ok := gweiToWei(gwei)
+		if !ok || got.String() != want {
+			t.Errorf("gweiToWei(%q) = %v, %v; want %s", gwei, got, ok, want)
+		}
+	}
+
+	if _, ok := gweiToWei("not-a-number"); ok {
+		t.Error("Expected invalid gwei amount to be rejected")
+	}
+}
+
+func TestDispatcher_RateLimitedAlertNotDeduped(t *testing.T) {
+	d, slack, _, _, clock := newTestDispatcher(t, &config.AlertingConfig{MaxAlertsPerMinute: 1})
+	ctx := context.Background()
+
+	// Keep only the Slack sink so a rate-limited send delivers nowhere
+	d.sinks = d.sinks[:1]
+
+	_ = d.Fire(ctx, Alert{Key: "a", Category: "custom", Title: "a"})
+	_ = d.Fire(ctx, Alert{Key: "b", Category: "custom", Title: "b"})
+	if slack.count() != 1 {
+		t.Fatalf("Expected second alert to be rate limited, got %d deliveries", slack.count())
+	}
+	if d.IsActive("b") {
+		t.Fatal("Expected a dropped alert not to be recorded as active")
+	}
+
+	// Once tokens refill the same alert goes out instead of being deduped
+	clock.Advance(time.Minute)
+	_ = d.Fire(ctx, Alert{Key: "b", Category: "custom", Title: "b"})
+	if slack.count() != 2 {
+		t.Errorf("Expected dropped alert to be sent after refill, got %d deliveries", slack.count())
+	}
+}
+
+func TestDispatcher_SinkFailure(t *testing.T) {
+	d, slack, pagerduty, _, _ := newTestDispatcher(t, &config.AlertingConfig{})
+	slack.status = http.StatusInternalServerError
+
+	err := d.Fire(context.Background(), Alert{Key: "k", Category: "custom", Title: "t"})
+	if err == nil {
+		t.Error("Expected error when a sink fails")
+	}
+	if pagerduty.count() != 1 {
+		t.Errorf("Expected other sinks to still receive the alert, got %d", pagerduty.count())
+	}
+}
+
+func TestDispatcher_Disabled(t *testing.T) {
+	var nilDispatcher *Dispatcher
+	if err := nilDispatcher.FraudFlag(context.Background(), testMessage(), "volume_spike"); err != nil {
+		t.Errorf("Expected nil dispatcher to be a no-op, got %v", err)
+	}
+
+	d := NewDispatcher(&config.AlertingConfig{Enabled: false, SlackWebhook: "http://127.0.0.1:1"}, zerolog.Nop())
+	if d.Enabled() {
+		t.Error("Expected disabled dispatcher")
+	}
+	if err := d.Fire(context.Background(), Alert{Key: "k"}); err != nil {
+		t.Errorf("Expected disabled dispatcher to be a no-op, got %v", err)
+	}
+}
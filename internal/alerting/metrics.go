@@ -0,0 +1,45 @@
+package alerting
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	AlertsSent = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_alerts_sent_total",
+			Help: "Total number of alerts delivered to a sink",
+		},
+		[]string{"sink", "category", "severity"},
+	)
+
+	AlertsFailed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_alerts_failed_total",
+			Help: "Total number of alert deliveries that failed",
+		},
+		[]string{"sink", "category"},
+	)
+
+	AlertsSuppressed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_alerts_suppressed_total",
+			Help: "Total number of alerts suppressed by deduplication",
+		},
+		[]string{"category"},
+	)
+
+	AlertsRateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_alerts_rate_limited_total",
+			Help: "Total number of alerts dropped by the per-sink rate limit",
+		},
+		[]string{"sink"},
+	)
+
+	AlertsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bridge_alerts_active",
+		Help: "Number of alerts currently firing",
+	})
+)
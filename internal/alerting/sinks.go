@@ -0,0 +1,188 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// DefaultPagerDutyEndpoint is the PagerDuty Events API v2 enqueue endpoint
+const DefaultPagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+
+// SlackSink posts alerts to a Slack incoming webhook
+type SlackSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackSink creates a new Slack sink
+func NewSlackSink(url string, client *http.Client) *SlackSink {
+	return &SlackSink{url: url, client: client}
+}
+
+// Name returns the sink name
+func (s *SlackSink) Name() string {
+	return "slack"
+}
+
+// Send posts the alert as a Slack attachment
+func (s *SlackSink) Send(ctx context.Context, alert *Alert) error {
+	color := "#439FE0"
+	switch {
+	case alert.Resolved:
+		color = "good"
+	case alert.Severity == SeverityCritical:
+		color = "danger"
+	case alert.Severity == SeverityWarning:
+		color = "warning"
+	}
+
+	title := alert.Title
+	if alert.Resolved {
+		title = "[RESOLVED] " + title
+	} else {
+		title = fmt.Sprintf("[%s] %s", alert.Severity, title)
+	}
+
+	fields := make([]map[string]interface{}, 0, len(alert.Fields))
+	for _, k := range sortedKeys(alert.Fields) {
+		fields = append(fields, map[string]interface{}{
+			"title": k,
+			"value": alert.Fields[k],
+			"short": true,
+		})
+	}
+
+	body := map[string]interface{}{
+		"text": title,
+		"attachments": []map[string]interface{}{
+			{
+				"color":  color,
+				"title":  title,
+				"text":   alert.Message,
+				"fields": fields,
+				"footer": alert.Source,
+				"ts":     alert.Timestamp.Unix(),
+			},
+		},
+	}
+
+	return postJSON(ctx, s.client, s.url, body, nil)
+}
+
+// PagerDutySink sends alerts to the PagerDuty Events API v2
+type PagerDutySink struct {
+	routingKey string
+	endpoint   string
+	client     *http.Client
+}
+
+// NewPagerDutySink creates a new PagerDuty sink
+func NewPagerDutySink(routingKey, endpoint string, client *http.Client) *PagerDutySink {
+	if endpoint == "" {
+		endpoint = DefaultPagerDutyEndpoint
+	}
+	return &PagerDutySink{routingKey: routingKey, endpoint: endpoint, client: client}
+}
+
+// Name returns the sink name
+func (s *PagerDutySink) Name() string {
+	return "pagerduty"
+}
+
+// Send triggers or resolves a PagerDuty incident keyed on the alert key
+func (s *PagerDutySink) Send(ctx context.Context, alert *Alert) error {
+	body := map[string]interface{}{
+		"routing_key": s.routingKey,
+		"dedup_key":   alert.Key,
+	}
+
+	if alert.Resolved {
+		body["event_action"] = "resolve"
+	} else {
+		severity := string(alert.Severity)
+		if severity == "" {
+			severity = string(SeverityInfo)
+		}
+		body["event_action"] = "trigger"
+		body["payload"] = map[string]interface{}{
+			"summary":        alert.Title,
+			"source":         alert.Source,
+			"severity":       severity,
+			"timestamp":      alert.Timestamp.Format(time.RFC3339),
+			"component":      alert.Category,
+			"custom_details": alert.Fields,
+		}
+	}
+
+	return postJSON(ctx, s.client, s.endpoint, body, nil)
+}
+
+// WebhookSink posts the raw alert JSON to a generic HTTP endpoint
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a new generic webhook sink
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	return &WebhookSink{url: url, client: client}
+}
+
+// Name returns the sink name
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Send posts the alert to the webhook
+func (s *WebhookSink) Send(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, s.client, s.url, alert, map[string]string{
+		"X-Alert-Key": alert.Key,
+	})
+}
+
+// postJSON posts a JSON body and treats any non-2xx response as an error
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}, headers map[string]string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Articium-Alerting/1.0")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// sortedKeys returns the map keys in a stable order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
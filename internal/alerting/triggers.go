@@ -0,0 +1,273 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+)
+
+const (
+	// flapWindow is the period over which chain health transitions are counted
+	flapWindow = 10 * time.Minute
+	// flapThreshold is the number of transitions within flapWindow that counts as flapping
+	flapThreshold = 4
+	// gasWarmupSamples is the number of gas samples required before spike detection starts
+	gasWarmupSamples = 5
+	// gasSmoothing is the EWMA weight given to each new gas price sample
+	gasSmoothing = 0.2
+)
+
+// chainState tracks health transitions for flap detection
+type chainState struct {
+	healthy     bool
+	transitions []time.Time
+}
+
+// gasState tracks a moving average of observed gas prices
+type gasState struct {
+	average float64
+	samples int
+}
+
+// LargeTransaction alerts on a transfer at or above the large transaction threshold
+func (d *Dispatcher) LargeTransaction(ctx context.Context, msg *types.CrossChainMessage, amount *big.Int) error {
+	if d == nil || !d.config.AlertOnLargeTransaction {
+		return nil
+	}
+
+	return d.Fire(ctx, Alert{
+		Key:      fmt.Sprintf("%s:%s", CategoryLargeTransaction, msg.ID),
+		Category: CategoryLargeTransaction,
+		Severity: SeverityWarning,
+		Title:    fmt.Sprintf("Large transaction %s -> %s", msg.SourceChain.Name, msg.DestinationChain.Name),
+		Message:  fmt.Sprintf("Message %s transfers %s", msg.ID, amount.String()),
+		Fields: map[string]string{
+			"message_id":   msg.ID,
+			"amount":       amount.String(),
+			"source_chain": msg.SourceChain.Name,
+			"dest_chain":   msg.DestinationChain.Name,
+			"sender":       msg.Sender.Raw,
+			"recipient":    msg.Recipient.Raw,
+			"source_tx":    msg.SourceTxHash,
+		},
+	})
+}
+
+// FraudFlag alerts on a message rejected by fraud detection
+func (d *Dispatcher) FraudFlag(ctx context.Context, msg *types.CrossChainMessage, reason string) error {
+	if d == nil {
+		return nil
+	}
+
+	return d.Fire(ctx, Alert{
+		Key:      fmt.Sprintf("%s:%s:%s", CategoryFraud, reason, msg.Sender.Raw),
+		Category: CategoryFraud,
+		Severity: SeverityCritical,
+		Title:    fmt.Sprintf("Suspicious transaction flagged: %s", reason),
+		Message:  fmt.Sprintf("Message %s from %s was rejected by fraud detection", msg.ID, msg.Sender.Raw),
+		Fields: map[string]string{
+			"message_id":   msg.ID,
+			"reason":       reason,
+			"source_chain": msg.SourceChain.Name,
+			"dest_chain":   msg.DestinationChain.Name,
+			"sender":       msg.Sender.Raw,
+			"source_tx":    msg.SourceTxHash,
+		},
+	})
+}
+
+// ChainHealth records a health check result. An unhealthy chain fires a
+// critical alert that is resolved once the chain recovers; repeated
+// transitions within the flap window fire a separate flapping alert.
+func (d *Dispatcher) ChainHealth(ctx context.Context, chain string, healthy bool) error {
+	if d == nil {
+		return nil
+	}
+
+	now := d.now()
+
+	d.mu.Lock()
+	state, ok := d.chains[chain]
+	if !ok {
+		// Assume chains start healthy so the first failure is a transition
+		state = &chainState{healthy: true}
+		d.chains[chain] = state
+	}
+
+	changed := state.healthy != healthy
+	state.healthy = healthy
+	if changed {
+		state.transitions = append(state.transitions, now)
+	}
+
+	recent := state.transitions[:0]
+	for _, t := range state.transitions {
+		if now.Sub(t) <= flapWindow {
+			recent = append(recent, t)
+		}
+	}
+	state.transitions = recent
+	flapping := len(recent) >= flapThreshold
+	transitions := len(recent)
+	d.mu.Unlock()
+
+	healthKey := fmt.Sprintf("%s:%s", CategoryChainHealth, chain)
+	flapKey := fmt.Sprintf("%s:%s", CategoryChainFlapping, chain)
+
+	var err error
+	if flapping {
+		err = d.Fire(ctx, Alert{
+			Key:      flapKey,
+			Category: CategoryChainFlapping,
+			Severity: SeverityWarning,
+			Title:    fmt.Sprintf("Chain %s health is flapping", chain),
+			Message:  fmt.Sprintf("%d health transitions in the last %s", transitions, flapWindow),
+			Fields: map[string]string{
+				"chain":       chain,
+				"transitions": fmt.Sprintf("%d", transitions),
+			},
+		})
+	} else if transitions == 0 {
+		err = d.Resolve(ctx, flapKey, fmt.Sprintf("Chain %s health stable", chain))
+	}
+
+	if !healthy {
+		return errors.Join(err, d.Fire(ctx, Alert{
+			Key:      healthKey,
+			Category: CategoryChainHealth,
+			Severity: SeverityCritical,
+			Title:    fmt.Sprintf("Chain %s is unhealthy", chain),
+			Message:  fmt.Sprintf("Health check for %s failed", chain),
+			Fields: map[string]string{
+				"chain": chain,
+			},
+		}))
+	}
+
+	return errors.Join(err, d.Resolve(ctx, healthKey, fmt.Sprintf("Chain %s recovered", chain)))
+}
+
+// MessageOutcome records a processing result for a destination chain and
+// alerts once consecutive failures reach AlertOnFailureThreshold. The alert
+// is resolved by the next success.
+func (d *Dispatcher) MessageOutcome(ctx context.Context, chain string, success bool, lastErr error) error {
+	if d == nil || d.config.AlertOnFailureThreshold <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s:%s", CategoryFailureRate, chain)
+
+	d.mu.Lock()
+	if success {
+		d.failures[chain] = 0
+		d.mu.Unlock()
+		return d.Resolve(ctx, key, fmt.Sprintf("Messages to %s are succeeding again", chain))
+	}
+	d.failures[chain]++
+	count := d.failures[chain]
+	d.mu.Unlock()
+
+	if count < d.config.AlertOnFailureThreshold {
+		return nil
+	}
+
+	fields := map[string]string{
+		"chain":                chain,
+		"consecutive_failures": fmt.Sprintf("%d", count),
+		"threshold":            fmt.Sprintf("%d", d.config.AlertOnFailureThreshold),
+	}
+	if lastErr != nil {
+		fields["last_error"] = lastErr.Error()
+	}
+
+	return d.Fire(ctx, Alert{
+		Key:      key,
+		Category: CategoryFailureRate,
+		Severity: SeverityCritical,
+		Title:    fmt.Sprintf("Message failures on %s", chain),
+		Message:  fmt.Sprintf("%d consecutive messages to %s failed", count, chain),
+		Fields:   fields,
+	})
+}
+
+// GasPrice records an observed gas price (in wei) and alerts when it reaches
+// the configured maximum (in gwei, as max_gas_price is written in chain
+// configs) or spikes above the moving average
+func (d *Dispatcher) GasPrice(ctx context.Context, chain string, price *big.Int, maxGasPrice string) error {
+	if d == nil || !d.config.AlertOnHighGas || price == nil {
+		return nil
+	}
+
+	multiplier := d.config.GasSpikeMultiplier
+	if multiplier <= 1 {
+		multiplier = defaultGasSpikeMultiplier
+	}
+
+	value, _ := new(big.Float).SetInt(price).Float64()
+
+	d.mu.Lock()
+	state, ok := d.gas[chain]
+	if !ok {
+		state = &gasState{}
+		d.gas[chain] = state
+	}
+	average := state.average
+	spike := state.samples >= gasWarmupSamples && value > average*multiplier
+	if !spike {
+		// Only fold normal samples into the baseline so a sustained spike keeps firing
+		if state.samples == 0 {
+			state.average = value
+		} else {
+			state.average = gasSmoothing*value + (1-gasSmoothing)*state.average
+		}
+		state.samples++
+	}
+	d.mu.Unlock()
+
+	atMax := false
+	if maxGasPrice != "" {
+		if max, ok := gweiToWei(maxGasPrice); ok && price.Cmp(max) >= 0 {
+			atMax = true
+		}
+	}
+
+	key := fmt.Sprintf("%s:%s", CategoryGasSpike, chain)
+	if !spike && !atMax {
+		return d.Resolve(ctx, key, fmt.Sprintf("Gas price on %s back to normal", chain))
+	}
+
+	reason := "spike"
+	if atMax {
+		reason = "at_max"
+	}
+
+	return d.Fire(ctx, Alert{
+		Key:      key,
+		Category: CategoryGasSpike,
+		Severity: SeverityWarning,
+		Title:    fmt.Sprintf("High gas price on %s", chain),
+		Message:  fmt.Sprintf("Gas price %s wei (average %.0f wei)", price.String(), average),
+		Fields: map[string]string{
+			"chain":     chain,
+			"gas_price": price.String(),
+			"average":   fmt.Sprintf("%.0f", average),
+			"max":       maxGasPrice,
+			"reason":    reason,
+		},
+	})
+}
+
+// gweiToWei converts a decimal gwei amount such as "500" or "0.1" to wei
+func gweiToWei(gwei string) (*big.Int, bool) {
+	value, ok := new(big.Rat).SetString(gwei)
+	if !ok || value.Sign() < 0 {
+		return nil, false
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(1_000_000_000))
+	return new(big.Int).Quo(value.Num(), value.Denom()), true
+}
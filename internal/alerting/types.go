@@ -0,0 +1,56 @@
+package alerting
+
+import (
+	"context"
+	"time"
+)
+
+// Severity represents the urgency of an alert
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// rank returns an ordering used to detect escalations
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Alert categories used to build deduplication keys
+const (
+	CategoryLargeTransaction = "large_transaction"
+	CategoryFraud            = "fraud"
+	CategoryChainHealth      = "chain_health"
+	CategoryChainFlapping    = "chain_flapping"
+	CategoryFailureRate      = "failure_rate"
+	CategoryGasSpike         = "gas_spike"
+)
+
+// Alert represents a single alert notification
+type Alert struct {
+	Key       string            `json:"key"`
+	Category  string            `json:"category"`
+	Severity  Severity          `json:"severity"`
+	Title     string            `json:"title"`
+	Message   string            `json:"message"`
+	Source    string            `json:"source"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Resolved  bool              `json:"resolved"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Sink delivers alerts to an external notification channel
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, alert *Alert) error
+}
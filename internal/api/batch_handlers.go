@@ -34,10 +34,11 @@ func (s *Server) handleListBatches(w http.ResponseWriter, r *http.Request) {
 	var batches []database.Batch
 	var err error
 
+	scope := tenantScope(r)
 	if status != "" {
-		batches, err = s.db.GetBatchesByStatus(r.Context(), status, limit, offset)
+		batches, err = s.db.GetBatchesByStatus(r.Context(), status, scope, limit, offset)
 	} else {
-		batches, err = s.db.GetAllBatches(r.Context(), limit, offset)
+		batches, err = s.db.GetAllBatches(r.Context(), scope, limit, offset)
 	}
 
 	if err != nil {
@@ -46,7 +47,7 @@ func (s *Server) handleListBatches(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	total, _ := s.db.GetBatchesCount(r.Context())
+	total, _ := s.db.GetBatchesCount(r.Context(), scope)
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"batches": batches,
@@ -64,7 +65,8 @@ func (s *Server) handleGetBatch(w http.ResponseWriter, r *http.Request) {
 	batchID := vars["id"]
 
 	// Query batch from database
-	batch, err := s.db.GetBatch(r.Context(), batchID)
+	scope := tenantScope(r)
+	batch, err := s.db.GetBatch(r.Context(), batchID, scope)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, http.StatusNotFound, "batch not found", err)
@@ -76,7 +78,7 @@ func (s *Server) handleGetBatch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get messages in this batch
-	messages, err := s.db.GetBatchMessages(r.Context(), batchID)
+	messages, err := s.db.GetBatchMessages(r.Context(), batchID, scope)
 	if err != nil {
 		s.logger.Warn().Err(err).Str("batch_id", batchID).Msg("Failed to get batch messages")
 		// Continue without messages
@@ -90,7 +92,7 @@ func (s *Server) handleGetBatch(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleBatchStats(w http.ResponseWriter, r *http.Request) {
 	// Get batch statistics from database
-	totalBatches, err := s.db.GetBatchesCount(r.Context())
+	totalBatches, err := s.db.GetBatchesCount(r.Context(), "")
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to get batches count")
 		totalBatches = 0
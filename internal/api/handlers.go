@@ -1,11 +1,14 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/nft"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/gorilla/mux"
 )
@@ -109,6 +112,12 @@ func (s *Server) handleBridgeToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Replay or reject retries carrying an Idempotency-Key
+	idem, done := s.checkIdempotency(w, r, req)
+	if done {
+		return
+	}
+
 	// Check if chains exist
 	sourceClient, sourceExists := s.clients[req.SourceChain]
 	if !sourceExists {
@@ -141,9 +150,8 @@ func (s *Server) handleBridgeToken(w http.ResponseWriter, r *http.Request) {
 	// Create payload
 	payload := types.TokenTransferPayload{
 		TokenAddress: types.Address{
-			Raw:      req.TokenAddress,
-			Type:     string(sourceChainInfo.Type),
-			Standard: tokenStandard,
+			Raw:       req.TokenAddress,
+			ChainType: sourceChainInfo.Type,
 		},
 		Amount:        req.Amount,
 		TokenStandard: tokenStandard,
@@ -162,14 +170,12 @@ func (s *Server) handleBridgeToken(w http.ResponseWriter, r *http.Request) {
 		sourceChainInfo,
 		destChainInfo,
 		types.Address{
-			Raw:      senderAddress,
-			Type:     string(sourceChainInfo.Type),
-			Standard: tokenStandard,
+			Raw:       senderAddress,
+			ChainType: sourceChainInfo.Type,
 		},
 		types.Address{
-			Raw:      req.Recipient,
-			Type:     string(destChainInfo.Type),
-			Standard: tokenStandard,
+			Raw:       req.Recipient,
+			ChainType: destChainInfo.Type,
 		},
 		payload,
 	)
@@ -182,39 +188,49 @@ func (s *Server) handleBridgeToken(w http.ResponseWriter, r *http.Request) {
 	// Set required signatures based on config
 	msg.RequiredSignatures = s.config.Security.RequiredSignatures
 
-	// Save message to database
-	if err := s.db.SaveMessage(r.Context(), msg); err != nil {
+	s.submitAndRespond(w, r, msg, idem, http.StatusAccepted, map[string]interface{}{
+		"status":     "pending",
+		"message":    "Bridge request received and queued for processing",
+		"message_id": msg.ID,
+		"request":    req,
+	})
+}
+
+// submitMessage saves a new message together with its outbox entry. The
+// outbox publisher delivers it to the queue, so a saved message is never
+// left unqueued. If idem is set and its key was already claimed, nothing is
+// saved and the existing record is returned.
+func (s *Server) submitMessage(ctx context.Context, msg *types.CrossChainMessage, idem *database.IdempotencyRecord) (*database.IdempotencyRecord, error) {
+	if idem != nil {
+		existing, err := s.db.SaveMessageWithIdempotency(ctx, msg, idem)
+		if err != nil {
+			s.logger.Error().Err(err).Str("message_id", msg.ID).Msg("Failed to save message to database")
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	} else if err := s.db.SaveMessageWithOutbox(ctx, msg); err != nil {
 		s.logger.Error().Err(err).Str("message_id", msg.ID).Msg("Failed to save message to database")
-		respondError(w, http.StatusInternalServerError, "failed to save message", err)
-		return
+		return nil, err
 	}
 
 	s.logger.Info().
 		Str("message_id", msg.ID).
-		Str("source", req.SourceChain).
-		Str("destination", req.DestinationChain).
-		Msg("Message saved to database")
-
-	// Publish message to queue for processing
-	if s.queue != nil {
-		if err := s.queue.Publish(r.Context(), msg); err != nil {
-			s.logger.Error().Err(err).Str("message_id", msg.ID).Msg("Failed to publish message to queue")
-			// Don't return error - message is saved in DB and can be processed later
-		} else {
-			s.logger.Info().
-				Str("message_id", msg.ID).
-				Msg("Message published to queue")
-		}
+		Str("source", msg.SourceChain.Name).
+		Str("destination", msg.DestinationChain.Name).
+		Str("type", string(msg.Type)).
+		Msg("Message saved to outbox")
+
+	s.notifier.NotifyMessageCreated(ctx, msg)
+
+	if s.outboxPublisher != nil {
+		s.outboxPublisher.Notify()
 	} else {
-		s.logger.Warn().Msg("Queue not available, message saved but not queued")
+		s.logger.Warn().Msg("Queue not available, message will be published by another service")
 	}
 
-	respondJSON(w, http.StatusAccepted, map[string]interface{}{
-		"status":     "pending",
-		"message":    "Bridge request received and queued for processing",
-		"message_id": msg.ID,
-		"request":    req,
-	})
+	return nil, nil
 }
 
 type BridgeNFTRequest struct {
@@ -224,6 +240,8 @@ type BridgeNFTRequest struct {
 	TokenID          string `json:"token_id"`
 	Recipient        string `json:"recipient"`
 	Sender           string `json:"sender,omitempty"`
+	Standard         string `json:"standard,omitempty"` // ERC721, ERC1155, Metaplex, NEP171
+	Amount           uint64 `json:"amount,omitempty"`   // ERC1155 only
 }
 
 func (s *Server) handleBridgeNFT(w http.ResponseWriter, r *http.Request) {
@@ -244,10 +262,122 @@ func (s *Server) handleBridgeNFT(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusAccepted, map[string]interface{}{
-		"status":  "pending",
-		"message": "NFT bridge request received and will be processed",
-		"request": req,
+	// Replay or reject retries carrying an Idempotency-Key
+	idem, done := s.checkIdempotency(w, r, req)
+	if done {
+		return
+	}
+
+	// Check if chains exist
+	sourceClient, sourceExists := s.clients[req.SourceChain]
+	if !sourceExists {
+		respondError(w, http.StatusBadRequest, "invalid source chain", nil)
+		return
+	}
+	destClient, destExists := s.clients[req.DestinationChain]
+	if !destExists {
+		respondError(w, http.StatusBadRequest, "invalid destination chain", nil)
+		return
+	}
+
+	// Get chain info
+	sourceChainInfo := sourceClient.GetChainInfo()
+	destChainInfo := destClient.GetChainInfo()
+
+	// Determine NFT standard, defaulting to the source chain's native standard
+	standard := req.Standard
+	if standard == "" {
+		var err error
+		standard, err = nft.DefaultStandard(sourceChainInfo.Type)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "unsupported source chain for NFTs", err)
+			return
+		}
+	}
+	if err := nft.ValidateStandard(standard, sourceChainInfo.Type); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid NFT standard", err)
+		return
+	}
+	if _, err := nft.DefaultStandard(destChainInfo.Type); err != nil {
+		respondError(w, http.StatusBadRequest, "unsupported destination chain for NFTs", err)
+		return
+	}
+
+	// Only ERC1155 tokens are fungible within an ID
+	amount := req.Amount
+	if standard == nft.StandardERC1155 {
+		if amount == 0 {
+			amount = 1
+		}
+	} else if amount > 1 {
+		respondError(w, http.StatusBadRequest, "amount is only supported for ERC1155 tokens", nil)
+		return
+	}
+
+	// Resolve token URI and metadata from the source contract
+	metadata, err := s.nftResolver.Resolve(r.Context(), sourceClient, standard, req.NFTContract, req.TokenID)
+	if err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("chain", req.SourceChain).
+			Str("contract", req.NFTContract).
+			Str("token_id", req.TokenID).
+			Msg("Failed to resolve NFT metadata")
+		respondError(w, http.StatusUnprocessableEntity, "failed to resolve NFT metadata", err)
+		return
+	}
+
+	// Create payload
+	payload := types.NFTTransferPayload{
+		ContractAddress: types.Address{
+			Raw:       req.NFTContract,
+			ChainType: sourceChainInfo.Type,
+		},
+		TokenID:  req.TokenID,
+		TokenURI: metadata.TokenURI,
+		Standard: standard,
+		Metadata: string(metadata.Raw),
+		Amount:   amount,
+	}
+
+	// Create sender address (use provided or default)
+	senderAddress := req.Sender
+	if senderAddress == "" {
+		senderAddress = "0x0000000000000000000000000000000000000000" // Placeholder
+	}
+
+	// Create cross-chain message
+	msg, err := types.NewCrossChainMessage(
+		types.MessageTypeNFTTransfer,
+		sourceChainInfo,
+		destChainInfo,
+		types.Address{
+			Raw:       senderAddress,
+			ChainType: sourceChainInfo.Type,
+		},
+		types.Address{
+			Raw:       req.Recipient,
+			ChainType: destChainInfo.Type,
+		},
+		payload,
+	)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to create cross-chain message")
+		respondError(w, http.StatusInternalServerError, "failed to create message", err)
+		return
+	}
+
+	// Set required signatures based on config
+	msg.RequiredSignatures = s.config.Security.RequiredSignatures
+
+	s.submitAndRespond(w, r, msg, idem, http.StatusAccepted, map[string]interface{}{
+		"status":     "pending",
+		"message":    "NFT bridge request received and queued for processing",
+		"message_id": msg.ID,
+		"standard":   standard,
+		"token_uri":  metadata.TokenURI,
+		"name":       metadata.Name,
+		"request":    req,
 	})
 }
 
@@ -275,12 +405,13 @@ func (s *Server) handleListMessages(w http.ResponseWriter, r *http.Request) {
 	var messages []types.CrossChainMessage
 	var err error
 
+	scope := tenantScope(r)
 	if status != "" {
-		messages, err = s.db.GetMessagesByStatus(r.Context(), types.MessageStatus(status), limit, offset)
+		messages, err = s.db.GetMessagesByStatus(r.Context(), scope, types.MessageStatus(status), limit, offset)
 	} else {
 		// Get all recent messages (using completed status with high limit as fallback)
 		// TODO: Add GetAllMessages method to database package for better performance
-		messages, err = s.db.GetMessagesByStatus(r.Context(), types.MessageStatusCompleted, limit, offset)
+		messages, err = s.db.GetMessagesByStatus(r.Context(), scope, types.MessageStatusCompleted, limit, offset)
 	}
 
 	if err != nil {
@@ -290,9 +421,9 @@ func (s *Server) handleListMessages(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get total count
-	totalPending, _ := s.db.GetPendingMessagesCount(r.Context())
-	totalCompleted, _ := s.db.GetProcessedMessagesCount(r.Context())
-	totalFailed, _ := s.db.GetFailedMessagesCount(r.Context())
+	totalPending, _ := s.db.CountMessagesByStatus(r.Context(), scope, types.MessageStatusPending)
+	totalCompleted, _ := s.db.CountMessagesByStatus(r.Context(), scope, types.MessageStatusCompleted)
+	totalFailed, _ := s.db.CountMessagesByStatus(r.Context(), scope, types.MessageStatusFailed)
 	total := totalPending + totalCompleted + totalFailed
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -308,7 +439,7 @@ func (s *Server) handleGetMessage(w http.ResponseWriter, r *http.Request) {
 	messageID := vars["id"]
 
 	// Query message from database
-	message, err := s.db.GetMessage(r.Context(), messageID)
+	message, err := s.db.GetMessage(r.Context(), messageID, tenantScope(r))
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, http.StatusNotFound, "message not found", err)
@@ -337,7 +468,7 @@ func (s *Server) handleMessageStatus(w http.ResponseWriter, r *http.Request) {
 	messageID := vars["id"]
 
 	// Query message status from database
-	status, err := s.db.GetMessageStatus(r.Context(), messageID)
+	message, err := s.db.GetMessage(r.Context(), messageID, tenantScope(r))
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, http.StatusNotFound, "message not found", err)
@@ -350,7 +481,7 @@ func (s *Server) handleMessageStatus(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"message_id": messageID,
-		"status":     status,
+		"status":     message.Status,
 	})
 }
 
@@ -358,19 +489,20 @@ func (s *Server) handleMessageStatus(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	// Get bridge statistics from database
-	pendingCount, err := s.db.GetPendingMessagesCount(r.Context())
+	scope := tenantScope(r)
+	pendingCount, err := s.db.CountMessagesByStatus(r.Context(), scope, types.MessageStatusPending)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to get pending messages count")
 		pendingCount = 0
 	}
 
-	completedCount, err := s.db.GetProcessedMessagesCount(r.Context())
+	completedCount, err := s.db.CountMessagesByStatus(r.Context(), scope, types.MessageStatusCompleted)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to get completed messages count")
 		completedCount = 0
 	}
 
-	failedCount, err := s.db.GetFailedMessagesCount(r.Context())
+	failedCount, err := s.db.CountMessagesByStatus(r.Context(), scope, types.MessageStatusFailed)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to get failed messages count")
 		failedCount = 0
@@ -400,13 +532,14 @@ func (s *Server) handleChainStats(w http.ResponseWriter, r *http.Request) {
 	// Get chain-specific statistics
 	// Query messages where this chain is either source or destination
 	limit := 1000 // High limit to get accurate count
-	messagesFrom, err := s.db.GetMessagesByChains(r.Context(), chainName, "", limit)
+	scope := tenantScope(r)
+	messagesFrom, err := s.db.GetMessagesByChains(r.Context(), scope, chainName, "", limit)
 	if err != nil {
 		s.logger.Error().Err(err).Str("chain", chainName).Msg("Failed to get messages from chain")
 		messagesFrom = []types.CrossChainMessage{}
 	}
 
-	messagesTo, err := s.db.GetMessagesByChains(r.Context(), "", chainName, limit)
+	messagesTo, err := s.db.GetMessagesByChains(r.Context(), scope, "", chainName, limit)
 	if err != nil {
 		s.logger.Error().Err(err).Str("chain", chainName).Msg("Failed to get messages to chain")
 		messagesTo = []types.CrossChainMessage{}
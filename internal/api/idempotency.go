@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+)
+
+const (
+	// idempotencyKeyHeader carries the client-supplied idempotency key
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader is set on responses replayed from a stored key
+	idempotentReplayHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength is the longest accepted idempotency key
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyTTL is how long a key is remembered
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyPurgeInterval is how often expired keys are deleted
+	idempotencyPurgeInterval = time.Hour
+)
+
+// checkIdempotency inspects the request's Idempotency-Key header. It returns
+// a pending record to store with the new message, or nil if the request has
+// no key. If done is true a response (replay, conflict or error) has already
+// been written and the handler must return.
+func (s *Server) checkIdempotency(w http.ResponseWriter, r *http.Request, req interface{}) (*database.IdempotencyRecord, bool) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" {
+		return nil, false
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength), nil)
+		return nil, true
+	}
+
+	hash, err := fingerprintRequest(r, req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to fingerprint request", err)
+		return nil, true
+	}
+
+	rec := &database.IdempotencyRecord{
+		Scope:       idempotencyScope(r),
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   time.Now().Add(idempotencyKeyTTL),
+	}
+
+	existing, err := s.db.GetIdempotencyRecord(r.Context(), rec.Scope, rec.Key)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to check idempotency key", err)
+		return nil, true
+	}
+	if existing != nil {
+		s.replayIdempotent(w, rec, existing)
+		return nil, true
+	}
+
+	return rec, false
+}
+
+// submitAndRespond saves the message and writes the response. With an
+// idempotency record the response is stored alongside the message; if a
+// concurrent request claimed the same key first, its response is replayed
+// instead.
+func (s *Server) submitAndRespond(w http.ResponseWriter, r *http.Request, msg *types.CrossChainMessage, idem *database.IdempotencyRecord, status int, response interface{}) {
+	// Messages belong to the submitting organization and count against its
+	// daily quota
+	msg.OrgID = tenantOrg(r)
+	if err := s.db.CheckQuota(r.Context(), msg.OrgID, database.QuotaDailyMessages); err != nil {
+		respondQuotaError(w, http.StatusTooManyRequests, err)
+		return
+	}
+
+	if idem != nil {
+		body, err := json.Marshal(response)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to encode response", err)
+			return
+		}
+		idem.StatusCode = status
+		idem.Response = body
+	}
+
+	existing, err := s.submitMessage(r.Context(), msg, idem)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to save message", err)
+		return
+	}
+	if existing != nil {
+		s.replayIdempotent(w, idem, existing)
+		return
+	}
+
+	respondJSON(w, status, response)
+}
+
+// replayIdempotent writes the stored response for a key, or a conflict if
+// the key was first used with a different request
+func (s *Server) replayIdempotent(w http.ResponseWriter, rec, existing *database.IdempotencyRecord) {
+	if existing.RequestHash != rec.RequestHash {
+		respondError(w, http.StatusConflict, fmt.Sprintf("%s was already used with a different request", idempotencyKeyHeader), nil)
+		return
+	}
+
+	s.logger.Info().
+		Str("idempotency_key", existing.Key).
+		Str("message_id", existing.MessageID).
+		Msg("Replaying idempotent response")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(idempotentReplayHeader, "true")
+	w.WriteHeader(existing.StatusCode)
+	w.Write(existing.Response)
+}
+
+// idempotencyScope namespaces idempotency keys per authenticated caller
+func idempotencyScope(r *http.Request) string {
+	authCtx := auth.GetAuthContext(r)
+	if authCtx == nil {
+		return "anonymous"
+	}
+	if authCtx.APIKeyID != "" {
+		return "key:" + authCtx.APIKeyID
+	}
+	return "user:" + authCtx.UserID
+}
+
+// fingerprintRequest hashes the route and decoded request body, so
+// formatting differences in a retried body do not count as a different
+// request
+func fingerprintRequest(r *http.Request, req interface{}) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// purgeIdempotencyKeys deletes expired idempotency keys every
+// idempotencyPurgeInterval until ctx is cancelled
+func (s *Server) purgeIdempotencyKeys(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.db.PurgeExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				s.logger.Warn().Err(err).Msg("Failed to purge expired idempotency keys")
+				continue
+			}
+			if deleted > 0 {
+				s.logger.Info().Int64("deleted", deleted).Msg("Purged expired idempotency keys")
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+)
+
+var idempotencyColumns = []string{
+	"scope", "idempotency_key", "request_hash", "message_id", "status_code",
+	"response", "created_at", "expires_at",
+}
+
+// newMockDBServer returns a server whose database is backed by sqlmock
+func newMockDBServer(t *testing.T) (*Server, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+
+	return &Server{
+		db:     &database.DB{DB: conn},
+		router: mux.NewRouter(),
+		logger: zerolog.Nop(),
+	}, mock
+}
+
+func bridgeRequest(key string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/bridge/token", nil)
+	req.Header.Set(idempotencyKeyHeader, key)
+	return req
+}
+
+func TestCheckIdempotency_ReplaysMatchingRequest(t *testing.T) {
+	s, mock := newMockDBServer(t)
+	body := BridgeTokenRequest{SourceChain: "ethereum", DestinationChain: "polygon", Amount: "1"}
+	req := bridgeRequest("key-1")
+
+	hash, err := fingerprintRequest(req, body)
+	if err != nil {
+		t.Fatalf("fingerprintRequest failed: %v", err)
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("FROM idempotency_keys").
+		WithArgs("anonymous", "key-1").
+		WillReturnRows(sqlmock.NewRows(idempotencyColumns).
+			AddRow("anonymous", "key-1", hash, "msg-1", http.StatusAccepted, []byte(`{"message_id":"msg-1"}`), now, now.Add(time.Hour)))
+
+	rec := httptest.NewRecorder()
+	pending, done := s.checkIdempotency(rec, req, body)
+	if !done || pending != nil {
+		t.Fatalf("Expected stored response to be replayed, got pending=%v done=%v", pending, done)
+	}
+	if rec.Code != http.StatusAccepted || rec.Body.String() != `{"message_id":"msg-1"}` {
+		t.Errorf("Unexpected replay: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get(idempotentReplayHeader) != "true" {
+		t.Error("Expected replay header")
+	}
+}
+
+func TestCheckIdempotency_RejectsMismatchedBody(t *testing.T) {
+	s, mock := newMockDBServer(t)
+	original := BridgeTokenRequest{SourceChain: "ethereum", DestinationChain: "polygon", Amount: "1"}
+	retry := BridgeTokenRequest{SourceChain: "ethereum", DestinationChain: "polygon", Amount: "1000"}
+	req := bridgeRequest("key-1")
+
+	hash, _ := fingerprintRequest(req, original)
+	now := time.Now()
+	mock.ExpectQuery("FROM idempotency_keys").
+		WillReturnRows(sqlmock.NewRows(idempotencyColumns).
+			AddRow("anonymous", "key-1", hash, "msg-1", http.StatusAccepted, []byte(`{}`), now, now.Add(time.Hour)))
+
+	rec := httptest.NewRecorder()
+	if _, done := s.checkIdempotency(rec, req, retry); !done {
+		t.Fatal("Expected request to be answered")
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a reused key with a different body, got %d", rec.Code)
+	}
+	if rec.Header().Get(idempotentReplayHeader) != "" {
+		t.Error("Expected no replay for a mismatched body")
+	}
+}
+
+func TestCheckIdempotency_NewKey(t *testing.T) {
+	s, mock := newMockDBServer(t)
+	body := BridgeTokenRequest{SourceChain: "ethereum"}
+
+	mock.ExpectQuery("FROM idempotency_keys").WillReturnRows(sqlmock.NewRows(idempotencyColumns))
+
+	rec := httptest.NewRecorder()
+	pending, done := s.checkIdempotency(rec, bridgeRequest("key-2"), body)
+	if done || pending == nil {
+		t.Fatalf("Expected a pending record for a new key, got pending=%v done=%v", pending, done)
+	}
+	if pending.Key != "key-2" || pending.RequestHash == "" {
+		t.Errorf("Unexpected pending record: %+v", pending)
+	}
+}
+
+func TestCheckIdempotency_KeyTooLong(t *testing.T) {
+	s, _ := newMockDBServer(t)
+
+	rec := httptest.NewRecorder()
+	if _, done := s.checkIdempotency(rec, bridgeRequest(strings.Repeat("k", maxIdempotencyKeyLength+1)), nil); !done {
+		t.Fatal("Expected oversized key to be rejected")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
+
+func TestFingerprintRequest_IgnoresFormatting(t *testing.T) {
+	req := bridgeRequest("key")
+	a, _ := fingerprintRequest(req, map[string]string{"amount": "1", "to": "x"})
+	b, _ := fingerprintRequest(req, map[string]string{"to": "x", "amount": "1"})
+	c, _ := fingerprintRequest(req, map[string]string{"to": "x", "amount": "2"})
+
+	if a != b {
+		t.Error("Expected key order not to change the fingerprint")
+	}
+	if a == c {
+		t.Error("Expected a different body to change the fingerprint")
+	}
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
+)
+
+const (
+	// accessPublic marks routes that need no authentication
+	accessPublic auth.Permission = "public"
+	// accessAuthenticated marks routes open to any authenticated caller
+	accessAuthenticated auth.Permission = "authenticated"
+)
+
+// route is an API route and the permission required to call it
+type route struct {
+	method     string
+	path       string
+	handler    http.HandlerFunc
+	permission auth.Permission
+}
+
+// routes returns every API route. Static paths are listed before
+// parameterized paths with the same prefix so they are matched first.
+func (s *Server) routes() []route {
+	return []route{
+		// Health check
+		{"GET", "/health", s.handleHealth, accessPublic},
+		{"GET", "/ready", s.handleReady, accessPublic},
+
+		// Public keys for verifying access tokens
+		{"GET", "/.well-known/jwks.json", s.authHandler.HandleJWKS, accessPublic},
+
+		// Chain endpoints
+		{"GET", "/v1/chains", s.handleListChains, accessAuthenticated},
+		{"GET", "/v1/chains/status", s.handleAllChainsStatus, accessAuthenticated},
+		{"GET", "/v1/chains/{chain}/status", s.handleChainStatus, accessAuthenticated},
+
+		// Bridge endpoints
+		{"POST", "/v1/bridge/token", s.handleBridgeToken, auth.PermissionWriteMessages},
+		{"POST", "/v1/bridge/nft", s.handleBridgeNFT, auth.PermissionWriteMessages},
+
+		// Message endpoints
+		{"GET", "/v1/messages", s.handleListMessages, auth.PermissionReadMessages},
+		{"GET", "/v1/messages/{id}", s.handleGetMessage, auth.PermissionReadMessages},
+		{"GET", "/v1/messages/{id}/status", s.handleMessageStatus, auth.PermissionReadMessages},
+
+		// Batch endpoints
+		{"GET", "/v1/batches", s.handleListBatches, auth.PermissionReadBatches},
+		{"GET", "/v1/batches/stats", s.handleBatchStats, auth.PermissionReadBatches},
+		{"POST", "/v1/batches/submit", s.handleSubmitToBatch, auth.PermissionWriteBatches},
+		{"GET", "/v1/batches/{id}", s.handleGetBatch, auth.PermissionReadBatches},
+		{"GET", "/v1/batches/{id}/efficiency", s.handleBatchEfficiency, auth.PermissionReadBatches},
+
+		// Statistics endpoints
+		{"GET", "/v1/stats", s.handleStats, auth.PermissionReadStats},
+		{"GET", "/v1/stats/{chain}", s.handleChainStats, auth.PermissionReadStats},
+
+		// Transaction endpoints
+		{"GET", "/v1/transactions/{hash}", s.handleGetTransaction, auth.PermissionReadMessages},
+
+		// Webhook endpoints (handlers scope webhooks to the caller's organization)
+		{"POST", "/v1/webhooks", s.handleRegisterWebhook, auth.PermissionWriteWebhooks},
+		{"GET", "/v1/webhooks", s.handleListWebhooks, auth.PermissionReadWebhooks},
+		{"GET", "/v1/webhooks/{id}", s.handleGetWebhook, auth.PermissionReadWebhooks},
+		{"PUT", "/v1/webhooks/{id}", s.handleUpdateWebhook, auth.PermissionWriteWebhooks},
+		{"DELETE", "/v1/webhooks/{id}", s.handleDeleteWebhook, auth.PermissionWriteWebhooks},
+		{"POST", "/v1/webhooks/{id}/pause", s.handlePauseWebhook, auth.PermissionWriteWebhooks},
+		{"POST", "/v1/webhooks/{id}/resume", s.handleResumeWebhook, auth.PermissionWriteWebhooks},
+		{"POST", "/v1/webhooks/{id}/test", s.handleTestWebhook, auth.PermissionWriteWebhooks},
+		{"POST", "/v1/webhooks/{id}/rotate-secret", s.handleRotateWebhookSecret, auth.PermissionWriteWebhooks},
+		{"POST", "/v1/webhooks/{id}/replay", s.handleReplayWebhookEvents, auth.PermissionWriteWebhooks},
+		{"POST", "/v1/webhooks/{id}/events/{event_id}/redeliver", s.handleRedeliverWebhookEvent, auth.PermissionWriteWebhooks},
+		{"GET", "/v1/webhooks/{id}/attempts", s.handleWebhookDeliveryAttempts, auth.PermissionReadWebhooks},
+
+		// Tracking endpoints
+		{"GET", "/v1/track/query", s.handleQueryMessages, auth.PermissionReadMessages},
+		{"GET", "/v1/track/recent", s.handleRecentMessages, auth.PermissionReadMessages},
+		{"GET", "/v1/track/stats", s.handleTrackingStats, auth.PermissionReadStats},
+		{"GET", "/v1/track/search", s.handleSearchMessages, auth.PermissionReadMessages},
+		{"GET", "/v1/track/stream", s.handleTrackingStreamSSE, auth.PermissionReadMessages},
+		{"GET", "/v1/track/stream/ws", s.handleTrackingStreamWS, auth.PermissionReadMessages},
+		{"GET", "/v1/track/tx/{hash}", s.handleTrackByTxHash, auth.PermissionReadMessages},
+		{"GET", "/v1/track/status/{status}", s.handleMessagesByStatus, auth.PermissionReadMessages},
+		{"GET", "/v1/track/{id}", s.handleTrackMessage, auth.PermissionReadMessages},
+		{"GET", "/v1/track/{id}/timeline", s.handleMessageTimeline, auth.PermissionReadMessages},
+		{"POST", "/v1/track/{id}/events", s.handleRecordTimelineEvent, auth.PermissionAdmin},
+
+		// Routing endpoints
+		{"POST", "/v1/routes/find", s.handleFindRoutes, auth.PermissionReadRoutes},
+		{"GET", "/v1/routes/topology", s.handleGetChainTopology, auth.PermissionReadRoutes},
+		{"GET", "/v1/routes/liquidity", s.handleGetLiquidity, auth.PermissionReadRoutes},
+		{"GET", "/v1/routes/cache/stats", s.handleGetRouteCacheStats, auth.PermissionReadRoutes},
+		{"POST", "/v1/routes/cache/invalidate", s.handleInvalidateCache, auth.PermissionAdmin},
+		{"GET", "/v1/routes/estimate", s.handleGetRouteEstimate, auth.PermissionReadRoutes},
+		{"GET", "/v1/routes/{id}", s.handleGetRoute, auth.PermissionReadRoutes},
+		{"POST", "/v1/routes/{id}/execute", s.handleExecuteRoute, auth.PermissionWriteRoutes},
+
+		// Authentication endpoints (handlers scope API keys to their owner)
+		{"POST", "/auth/login", s.authHandler.HandleLogin, accessPublic},
+		{"POST", "/auth/refresh", s.authHandler.HandleRefreshToken, accessPublic},
+		{"POST", "/auth/logout", s.authHandler.HandleLogout, accessAuthenticated},
+		{"GET", "/auth/me", s.authHandler.HandleGetMe, accessAuthenticated},
+		{"POST", "/auth/api-keys", s.authHandler.HandleCreateAPIKey, accessAuthenticated},
+		{"GET", "/auth/api-keys", s.authHandler.HandleListAPIKeys, accessAuthenticated},
+		{"DELETE", "/auth/api-keys/{id}", s.authHandler.HandleRevokeAPIKey, accessAuthenticated},
+		{"GET", "/auth/api-keys/{id}/usage", s.authHandler.HandleGetAPIKeyUsage, accessAuthenticated},
+		{"GET", "/auth/usage", s.authHandler.HandleGetUsage, accessAuthenticated},
+		{"POST", "/auth/password/reset", s.authHandler.HandleResetPassword, accessPublic},
+
+		// Organization and user administration
+		{"GET", "/v1/admin/organizations", s.authHandler.HandleListOrganizations, auth.PermissionAdmin},
+		{"POST", "/v1/admin/organizations", s.authHandler.HandleCreateOrganization, auth.PermissionAdmin},
+		{"GET", "/v1/admin/organizations/{id}", s.authHandler.HandleGetOrganization, auth.PermissionAdmin},
+		{"PATCH", "/v1/admin/organizations/{id}", s.authHandler.HandleUpdateOrganization, auth.PermissionAdmin},
+		{"GET", "/v1/admin/users", s.authHandler.HandleListUsers, auth.PermissionAdmin},
+		{"POST", "/v1/admin/users", s.authHandler.HandleCreateUser, auth.PermissionAdmin},
+		{"GET", "/v1/admin/users/{id}", s.authHandler.HandleGetUser, auth.PermissionAdmin},
+		{"PATCH", "/v1/admin/users/{id}", s.authHandler.HandleUpdateUser, auth.PermissionAdmin},
+		{"DELETE", "/v1/admin/users/{id}", s.authHandler.HandleDeactivateUser, auth.PermissionAdmin},
+		{"POST", "/v1/admin/users/{id}/password-reset", s.authHandler.HandleIssuePasswordReset, auth.PermissionAdmin},
+
+		// Audit log
+		{"GET", "/v1/admin/audit", s.authHandler.HandleQueryAuditLog, auth.PermissionAdmin},
+		{"GET", "/v1/admin/audit/verify", s.authHandler.HandleVerifyAuditLog, auth.PermissionAdmin},
+
+		// Rate limit plans
+		{"GET", "/v1/admin/plans", s.authHandler.HandleListPlans, auth.PermissionAdmin},
+		{"POST", "/v1/admin/plans", s.authHandler.HandleCreatePlan, auth.PermissionAdmin},
+		{"PATCH", "/v1/admin/plans/{id}", s.authHandler.HandleUpdatePlan, auth.PermissionAdmin},
+		{"PUT", "/v1/admin/api-keys/{id}/plan", s.authHandler.HandleSetAPIKeyPlan, auth.PermissionAdmin},
+	}
+}
+
+// authorize wraps a route's handler with the authentication, rate limit and
+// permission checks its access level requires. Each IP address is limited
+// before authentication, so failed attempts count; the caller's own limits
+// are applied after it so the caller's plan is known.
+func (s *Server) authorize(rt route) http.Handler {
+	m := s.authMiddleware
+
+	var h http.Handler
+	switch rt.permission {
+	case accessPublic:
+		return m.RateLimit(rt.handler)
+	case accessAuthenticated:
+		h = m.IPRateLimit(m.AuthRequired(m.RateLimit(rt.handler)))
+	default:
+		h = m.IPRateLimit(m.AuthRequired(m.RateLimit(m.RequirePermission(rt.permission)(rt.handler))))
+	}
+
+	if streamPaths[rt.path] {
+		h = queryTokenAuth(h)
+	}
+	return h
+}
+
+// streamPaths are the routes browsers open with EventSource or WebSocket,
+// which cannot set an Authorization header
+var streamPaths = map[string]bool{
+	"/v1/track/stream":    true,
+	"/v1/track/stream/ws": true,
+}
+
+// queryTokenAuth accepts an access token in the access_token query
+// parameter when the request has no Authorization header. Only streams
+// accept it; tokens in URLs can end up in proxy logs, so clients should use
+// short-lived access tokens.
+func queryTokenAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+)
+
+// Minimum access for each route: "public", or the lowest role allowed.
+// Roles are ordered readonly < user < developer < admin.
+var expectedRouteAccess = map[string]string{
+	"GET /health":                   "public",
+	"GET /ready":                    "public",
+	"GET /.well-known/jwks.json":    "public",
+	"GET /v1/chains":                "readonly",
+	"GET /v1/chains/status":         "readonly",
+	"GET /v1/chains/{chain}/status": "readonly",
+
+	"POST /v1/bridge/token": "user",
+	"POST /v1/bridge/nft":   "user",
+
+	"GET /v1/messages":             "readonly",
+	"GET /v1/messages/{id}":        "readonly",
+	"GET /v1/messages/{id}/status": "readonly",
+
+	"GET /v1/batches":                 "readonly",
+	"GET /v1/batches/stats":           "readonly",
+	"POST /v1/batches/submit":         "user",
+	"GET /v1/batches/{id}":            "readonly",
+	"GET /v1/batches/{id}/efficiency": "readonly",
+
+	"GET /v1/stats":         "readonly",
+	"GET /v1/stats/{chain}": "readonly",
+
+	"GET /v1/transactions/{hash}": "readonly",
+
+	"POST /v1/webhooks":                                  "developer",
+	"GET /v1/webhooks":                                   "developer",
+	"GET /v1/webhooks/{id}":                              "developer",
+	"PUT /v1/webhooks/{id}":                              "developer",
+	"DELETE /v1/webhooks/{id}":                           "developer",
+	"POST /v1/webhooks/{id}/pause":                       "developer",
+	"POST /v1/webhooks/{id}/resume":                      "developer",
+	"POST /v1/webhooks/{id}/test":                        "developer",
+	"POST /v1/webhooks/{id}/rotate-secret":               "developer",
+	"POST /v1/webhooks/{id}/replay":                      "developer",
+	"POST /v1/webhooks/{id}/events/{event_id}/redeliver": "developer",
+	"GET /v1/webhooks/{id}/attempts":                     "developer",
+
+	"GET /v1/track/query":           "readonly",
+	"GET /v1/track/recent":          "readonly",
+	"GET /v1/track/stats":           "readonly",
+	"GET /v1/track/stream":          "readonly",
+	"GET /v1/track/stream/ws":       "readonly",
+	"GET /v1/track/search":          "readonly",
+	"GET /v1/track/tx/{hash}":       "readonly",
+	"GET /v1/track/status/{status}": "readonly",
+	"GET /v1/track/{id}":            "readonly",
+	"GET /v1/track/{id}/timeline":   "readonly",
+	"POST /v1/track/{id}/events":    "admin",
+
+	"POST /v1/routes/find":             "readonly",
+	"GET /v1/routes/topology":          "readonly",
+	"GET /v1/routes/liquidity":         "readonly",
+	"GET /v1/routes/cache/stats":       "readonly",
+	"POST /v1/routes/cache/invalidate": "admin",
+	"GET /v1/routes/estimate":          "readonly",
+	"GET /v1/routes/{id}":              "readonly",
+	"POST /v1/routes/{id}/execute":     "developer",
+
+	"POST /auth/login":              "public",
+	"POST /auth/refresh":            "public",
+	"POST /auth/logout":             "readonly",
+	"GET /auth/me":                  "readonly",
+	"POST /auth/api-keys":           "readonly",
+	"GET /auth/api-keys":            "readonly",
+	"DELETE /auth/api-keys/{id}":    "readonly",
+	"GET /auth/api-keys/{id}/usage": "readonly",
+	"GET /auth/usage":               "readonly",
+	"POST /auth/password/reset":     "public",
+
+	"GET /v1/admin/organizations":              "admin",
+	"POST /v1/admin/organizations":             "admin",
+	"GET /v1/admin/organizations/{id}":         "admin",
+	"PATCH /v1/admin/organizations/{id}":       "admin",
+	"GET /v1/admin/users":                      "admin",
+	"POST /v1/admin/users":                     "admin",
+	"GET /v1/admin/users/{id}":                 "admin",
+	"PATCH /v1/admin/users/{id}":               "admin",
+	"DELETE /v1/admin/users/{id}":              "admin",
+	"POST /v1/admin/users/{id}/password-reset": "admin",
+
+	"GET /v1/admin/audit":        "admin",
+	"GET /v1/admin/audit/verify": "admin",
+
+	"GET /v1/admin/plans":              "admin",
+	"POST /v1/admin/plans":             "admin",
+	"PATCH /v1/admin/plans/{id}":       "admin",
+	"PUT /v1/admin/api-keys/{id}/plan": "admin",
+}
+
+var roleRank = map[string]int{
+	"public":                   0,
+	string(auth.RoleReadOnly):  1,
+	string(auth.RoleUser):      2,
+	string(auth.RoleDeveloper): 3,
+	string(auth.RoleAdmin):     4,
+}
+
+func testAuthConfig() *auth.AuthConfig {
+	config := auth.DefaultAuthConfig()
+	config.JWTSecret = "test-secret-key"
+	config.RateLimitPerMinute = 1000000
+	config.IPRateLimitPerMinute = 1000000
+	return config
+}
+
+func newTestServer(config *auth.AuthConfig) *Server {
+	return &Server{
+		router:         mux.NewRouter(),
+		logger:         zerolog.Nop(),
+		authMiddleware: auth.NewMiddleware(config, nil, zerolog.Nop()),
+	}
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+func TestRoutes_EveryRouteHasDeclaredAccess(t *testing.T) {
+	s := newTestServer(testAuthConfig())
+	s.setupRoutes()
+
+	registered := make(map[string]bool)
+	err := s.router.Walk(func(rt *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := rt.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := rt.GetMethods()
+		if err != nil {
+			return fmt.Errorf("route %s has no methods", path)
+		}
+		for _, method := range methods {
+			registered[routeKey(method, path)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk routes: %v", err)
+	}
+
+	for key := range registered {
+		if _, ok := expectedRouteAccess[key]; !ok {
+			t.Errorf("Route %s has no expected access level", key)
+		}
+	}
+	for key := range expectedRouteAccess {
+		if !registered[key] {
+			t.Errorf("Route %s is expected but not registered", key)
+		}
+	}
+}
+
+func TestRoutes_RoleMatrix(t *testing.T) {
+	config := testAuthConfig()
+	s := newTestServer(config)
+
+	// Register every route with a stub handler that reports which route matched
+	for _, rt := range s.routes() {
+		key := routeKey(rt.method, rt.path)
+		rt.handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Route", key)
+			w.WriteHeader(http.StatusOK)
+		}
+		s.router.Handle(rt.path, s.authorize(rt)).Methods(rt.method)
+	}
+
+	jwtService, err := auth.NewJWTServiceFromConfig(config, nil)
+	if err != nil {
+		t.Fatalf("Failed to create JWT service: %v", err)
+	}
+
+	callers := []string{"", string(auth.RoleReadOnly), string(auth.RoleUser), string(auth.RoleDeveloper), string(auth.RoleAdmin)}
+	tokens := make(map[string]string)
+	for _, role := range callers[1:] {
+		token, _, err := jwtService.GenerateToken(&auth.User{ID: "user-" + role, Email: role + "@example.com", Role: role})
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+		tokens[role] = token
+	}
+
+	for _, rt := range s.routes() {
+		key := routeKey(rt.method, rt.path)
+		minimum, ok := expectedRouteAccess[key]
+		if !ok {
+			t.Errorf("Route %s has no expected access level", key)
+			continue
+		}
+
+		// Fill path variables
+		path := rt.path
+		for strings.Contains(path, "{") {
+			start := strings.Index(path, "{")
+			end := strings.Index(path, "}")
+			path = path[:start] + "x1" + path[end+1:]
+		}
+
+		for _, role := range callers {
+			name := role
+			if name == "" {
+				name = "anonymous"
+			}
+
+			t.Run(key+"/"+name, func(t *testing.T) {
+				req := httptest.NewRequest(rt.method, path, nil)
+				if role != "" {
+					req.Header.Set("Authorization", "Bearer "+tokens[role])
+				}
+				rec := httptest.NewRecorder()
+				s.router.ServeHTTP(rec, req)
+
+				want := http.StatusOK
+				switch {
+				case minimum == "public":
+				case role == "":
+					want = http.StatusUnauthorized
+				case roleRank[role] < roleRank[minimum]:
+					want = http.StatusForbidden
+				}
+
+				if rec.Code != want {
+					t.Fatalf("%s as %s: status = %d, want %d", key, name, rec.Code, want)
+				}
+				if want == http.StatusOK && rec.Header().Get("X-Route") != key {
+					t.Errorf("%s matched route %q", path, rec.Header().Get("X-Route"))
+				}
+			})
+		}
+	}
+}
+
+func TestRoutes_FailedAuthenticationIsRateLimited(t *testing.T) {
+	config := testAuthConfig()
+	config.IPRateLimitPerMinute = 2
+	s := newTestServer(config)
+	s.setupRoutes()
+
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/messages", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+
+		if rec.Code != want {
+			t.Fatalf("request %d: status = %d, want %d", i+1, rec.Code, want)
+		}
+	}
+
+	// Other addresses keep their own limit
+	req := httptest.NewRequest(http.MethodGet, "/v1/messages", nil)
+	req.RemoteAddr = "198.51.100.1:4000"
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("other address: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
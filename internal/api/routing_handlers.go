@@ -56,6 +56,7 @@ func (s *Server) handleFindRoutes(w http.ResponseWriter, r *http.Request) {
 		TokenAddress: req.TokenAddress,
 		MaxHops:      req.MaxHops,
 		OptimizeFor:  req.OptimizeFor,
+		OrgID:        tenantOrg(r),
 	}
 
 	// Parse optional fields
@@ -85,6 +86,13 @@ func (s *Server) handleExecuteRoute(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	routeID := vars["id"]
 
+	// Only the owning organization may execute a route
+	route, err := s.routingService.GetRouteStatus(r.Context(), routeID)
+	if err != nil || !inTenantScope(tenantScope(r), route.OrgID) {
+		respondError(w, http.StatusNotFound, "route not found", err)
+		return
+	}
+
 	execution, err := s.routingService.ExecuteRoute(r.Context(), routeID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to execute route", err)
@@ -103,7 +111,7 @@ func (s *Server) handleGetRoute(w http.ResponseWriter, r *http.Request) {
 	routeID := vars["id"]
 
 	route, err := s.routingService.GetRouteStatus(r.Context(), routeID)
-	if err != nil {
+	if err != nil || !inTenantScope(tenantScope(r), route.OrgID) {
 		respondError(w, http.StatusNotFound, "route not found", err)
 		return
 	}
@@ -202,6 +210,7 @@ func (s *Server) handleGetRouteEstimate(w http.ResponseWriter, r *http.Request)
 		DestChain:   destChain,
 		Amount:      amountBig,
 		MaxHops:     2, // Quick estimate with max 2 hops
+		OrgID:       tenantOrg(r),
 	}
 
 	result, err := s.routingService.FindRoutes(r.Context(), query)
@@ -14,7 +14,10 @@ import (
 	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
 	"github.com/EmekaIwuagwu/articium-hub/internal/config"
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/nft"
+	"github.com/EmekaIwuagwu/articium-hub/internal/outbox"
 	"github.com/EmekaIwuagwu/articium-hub/internal/queue"
+	"github.com/EmekaIwuagwu/articium-hub/internal/ratelimit"
 	"github.com/EmekaIwuagwu/articium-hub/internal/routing"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
@@ -31,12 +34,17 @@ type Server struct {
 	logger          zerolog.Logger
 	clients         map[string]types.UniversalClient
 	queue           queue.Queue
+	outboxPublisher *outbox.Publisher
 	webhookRegistry *webhooks.Registry
 	webhookDelivery *webhooks.DeliveryService
+	notifier        *webhooks.Notifier
 	trackingService *webhooks.TrackingService
+	streamHub       *webhooks.StreamHub
 	routingService  *routing.Service
+	nftResolver     *nft.Resolver
 	authMiddleware  *auth.Middleware
 	authHandler     *auth.Handler
+	stopBackground  context.CancelFunc
 }
 
 // NewServer creates a new API server
@@ -53,12 +61,14 @@ func NewServer(
 	webhookRegistry := webhooks.NewRegistry(db, logger)
 	trackingService := webhooks.NewTrackingService(db, logger)
 	webhookDelivery := webhooks.NewDeliveryService(nil, webhookRegistry, db, logger)
+	streamHub := webhooks.NewStreamHub(nil, trackingService, logger)
 
 	// Initialize routing service
 	routingService := routing.NewService(db, nil, logger)
 
 	// Initialize authentication
 	authConfig := getAuthConfig()
+	authConfig.RateLimitStore = newRateLimitStore(cfg, db, logger)
 	authMiddleware := auth.NewMiddleware(authConfig, db, logger)
 	authHandler := auth.NewHandler(db, authConfig, logger)
 
@@ -71,18 +81,36 @@ func NewServer(
 		queue:           messageQueue,
 		webhookRegistry: webhookRegistry,
 		webhookDelivery: webhookDelivery,
+		notifier:        webhooks.NewNotifier(webhookDelivery, trackingService, logger),
 		trackingService: trackingService,
+		streamHub:       streamHub,
 		routingService:  routingService,
+		nftResolver:     nft.NewResolver(logger),
 		authMiddleware:  authMiddleware,
 		authHandler:     authHandler,
 	}
 
 	// Start webhook delivery service
-	go webhookDelivery.Start(context.Background())
+	webhookDelivery.Start(context.Background())
+
+	// Start live tracking streams
+	streamHub.Start(context.Background())
 
 	// Start routing service
 	go routingService.Start(context.Background())
 
+	// Start background maintenance
+	var background context.Context
+	background, s.stopBackground = context.WithCancel(context.Background())
+	go s.purgeIdempotencyKeys(background)
+
+	// Start outbox publisher. Without a queue, messages stay in the outbox
+	// until another service with a queue connection publishes them.
+	if messageQueue != nil {
+		s.outboxPublisher = outbox.NewPublisher(nil, db, messageQueue, logger)
+		s.outboxPublisher.Start(context.Background())
+	}
+
 	// Setup routes
 	s.setupRoutes()
 
@@ -99,92 +127,17 @@ func NewServer(
 	return s
 }
 
-// setupRoutes configures all API routes
+// setupRoutes configures all API routes. Access to each route is declared
+// in routes.
 func (s *Server) setupRoutes() {
-	// Health check
-	s.router.HandleFunc("/health", s.handleHealth).Methods("GET")
-	s.router.HandleFunc("/ready", s.handleReady).Methods("GET")
-
-	// API v1
-	v1 := s.router.PathPrefix("/v1").Subrouter()
-
-	// Chain endpoints
-	v1.HandleFunc("/chains", s.handleListChains).Methods("GET")
-	v1.HandleFunc("/chains/{chain}/status", s.handleChainStatus).Methods("GET")
-	v1.HandleFunc("/chains/status", s.handleAllChainsStatus).Methods("GET")
-
-	// Bridge endpoints
-	v1.HandleFunc("/bridge/token", s.handleBridgeToken).Methods("POST")
-	v1.HandleFunc("/bridge/nft", s.handleBridgeNFT).Methods("POST")
-
-	// Message endpoints
-	v1.HandleFunc("/messages", s.handleListMessages).Methods("GET")
-	v1.HandleFunc("/messages/{id}", s.handleGetMessage).Methods("GET")
-	v1.HandleFunc("/messages/{id}/status", s.handleMessageStatus).Methods("GET")
-
-	// Batch endpoints
-	v1.HandleFunc("/batches", s.handleListBatches).Methods("GET")
-	v1.HandleFunc("/batches/stats", s.handleBatchStats).Methods("GET")
-	v1.HandleFunc("/batches/{id}", s.handleGetBatch).Methods("GET")
-	v1.HandleFunc("/batches/{id}/efficiency", s.handleBatchEfficiency).Methods("GET")
-	v1.HandleFunc("/batches/submit", s.handleSubmitToBatch).Methods("POST")
-
-	// Statistics endpoints
-	v1.HandleFunc("/stats", s.handleStats).Methods("GET")
-	v1.HandleFunc("/stats/{chain}", s.handleChainStats).Methods("GET")
-
-	// Transaction endpoints
-	v1.HandleFunc("/transactions/{hash}", s.handleGetTransaction).Methods("GET")
-
-	// Webhook endpoints
-	v1.HandleFunc("/webhooks", s.handleRegisterWebhook).Methods("POST")
-	v1.HandleFunc("/webhooks", s.handleListWebhooks).Methods("GET")
-	v1.HandleFunc("/webhooks/{id}", s.handleGetWebhook).Methods("GET")
-	v1.HandleFunc("/webhooks/{id}", s.handleUpdateWebhook).Methods("PUT")
-	v1.HandleFunc("/webhooks/{id}", s.handleDeleteWebhook).Methods("DELETE")
-	v1.HandleFunc("/webhooks/{id}/pause", s.handlePauseWebhook).Methods("POST")
-	v1.HandleFunc("/webhooks/{id}/resume", s.handleResumeWebhook).Methods("POST")
-	v1.HandleFunc("/webhooks/{id}/test", s.handleTestWebhook).Methods("POST")
-	v1.HandleFunc("/webhooks/{id}/attempts", s.handleWebhookDeliveryAttempts).Methods("GET")
-
-	// Tracking endpoints
-	v1.HandleFunc("/track/{id}", s.handleTrackMessage).Methods("GET")
-	v1.HandleFunc("/track/tx/{hash}", s.handleTrackByTxHash).Methods("GET")
-	v1.HandleFunc("/track/query", s.handleQueryMessages).Methods("GET")
-	v1.HandleFunc("/track/recent", s.handleRecentMessages).Methods("GET")
-	v1.HandleFunc("/track/status/{status}", s.handleMessagesByStatus).Methods("GET")
-	v1.HandleFunc("/track/{id}/timeline", s.handleMessageTimeline).Methods("GET")
-	v1.HandleFunc("/track/{id}/events", s.handleRecordTimelineEvent).Methods("POST")
-	v1.HandleFunc("/track/stats", s.handleTrackingStats).Methods("GET")
-	v1.HandleFunc("/track/search", s.handleSearchMessages).Methods("GET")
-
-	// Routing endpoints
-	v1.HandleFunc("/routes/find", s.handleFindRoutes).Methods("POST")
-	v1.HandleFunc("/routes/{id}", s.handleGetRoute).Methods("GET")
-	v1.HandleFunc("/routes/{id}/execute", s.handleExecuteRoute).Methods("POST")
-	v1.HandleFunc("/routes/topology", s.handleGetChainTopology).Methods("GET")
-	v1.HandleFunc("/routes/liquidity", s.handleGetLiquidity).Methods("GET")
-	v1.HandleFunc("/routes/cache/stats", s.handleGetRouteCacheStats).Methods("GET")
-	v1.HandleFunc("/routes/cache/invalidate", s.handleInvalidateCache).Methods("POST")
-	v1.HandleFunc("/routes/estimate", s.handleGetRouteEstimate).Methods("GET")
-
-	// Authentication endpoints (public)
-	authRouter := s.router.PathPrefix("/auth").Subrouter()
-	authRouter.HandleFunc("/login", s.authHandler.HandleLogin).Methods("POST")
-	authRouter.HandleFunc("/refresh", s.authHandler.HandleRefreshToken).Methods("POST")
-	authRouter.HandleFunc("/me", s.authHandler.HandleGetMe).Methods("GET")
-	authRouter.HandleFunc("/api-keys", s.authHandler.HandleCreateAPIKey).Methods("POST")
-	authRouter.HandleFunc("/api-keys", s.authHandler.HandleListAPIKeys).Methods("GET")
-	authRouter.HandleFunc("/api-keys/{id}", s.authHandler.HandleRevokeAPIKey).Methods("DELETE")
+	for _, rt := range s.routes() {
+		s.router.Handle(rt.path, s.authorize(rt)).Methods(rt.method)
+	}
 
 	// Apply global middleware (order matters!)
 	s.router.Use(s.recoverMiddleware)
 	s.router.Use(s.loggingMiddleware)
 	s.router.Use(s.corsMiddleware)
-	s.router.Use(s.authMiddleware.RateLimit)
-
-	// Apply auth middleware to v1 routes only
-	v1.Use(s.authMiddleware.AuthRequired)
 }
 
 // Start starts the API server
@@ -206,7 +159,15 @@ func (s *Server) Start() error {
 // Stop gracefully stops the API server
 func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info().Msg("Stopping API server")
-	return s.server.Shutdown(ctx)
+	s.stopBackground()
+	if s.outboxPublisher != nil {
+		s.outboxPublisher.Stop()
+	}
+	// End open tracking streams so shutdown does not wait for them
+	s.streamHub.Stop()
+	err := s.server.Shutdown(ctx)
+	s.webhookDelivery.Stop()
+	return err
 }
 
 // Health check handlers
@@ -268,29 +229,14 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 
 func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get allowed origins from environment, default to * for development
-		allowedOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
-		if allowedOrigins == "" {
-			allowedOrigins = "*"
-		}
-
 		origin := r.Header.Get("Origin")
-
-		// Check if origin is allowed
-		if allowedOrigins == "*" {
+		if corsAllowedOrigins() == "*" {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-		} else {
-			// Check if origin is in allowed list
-			allowedList := strings.Split(allowedOrigins, ",")
-			for _, allowed := range allowedList {
-				if strings.TrimSpace(allowed) == origin {
-					w.Header().Set("Access-Control-Allow-Origin", origin)
-					break
-				}
-			}
+		} else if originAllowed(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
 		}
 
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
@@ -304,6 +250,29 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// corsAllowedOrigins returns the comma-separated origins allowed to call
+// the API, defaulting to * for development
+func corsAllowedOrigins() string {
+	if allowedOrigins := os.Getenv("CORS_ALLOWED_ORIGINS"); allowedOrigins != "" {
+		return allowedOrigins
+	}
+	return "*"
+}
+
+// originAllowed reports whether a browser origin may call the API
+func originAllowed(origin string) bool {
+	allowedOrigins := corsAllowedOrigins()
+	if allowedOrigins == "*" {
+		return true
+	}
+	for _, allowed := range strings.Split(allowedOrigins, ",") {
+		if strings.TrimSpace(allowed) == origin {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) recoverMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -346,6 +315,27 @@ func respondError(w http.ResponseWriter, status int, message string, err error)
 	respondJSON(w, status, response)
 }
 
+// newRateLimitStore returns the shared rate limit store: Redis if a Redis
+// cache is configured, falling back to Postgres while Redis is unavailable.
+// Without a database, limits are kept in process.
+func newRateLimitStore(cfg *config.Config, db *database.DB, logger zerolog.Logger) ratelimit.Store {
+	if db == nil {
+		return nil
+	}
+
+	postgres := ratelimit.NewPostgresStore(db, logger)
+	if cfg.Cache.Type != "redis" || len(cfg.Cache.Addresses) == 0 {
+		return postgres
+	}
+
+	redis := ratelimit.NewRedisStore(ratelimit.RedisConfig{
+		Addr:     cfg.Cache.Addresses[0],
+		Password: cfg.Cache.Password,
+		DB:       cfg.Cache.DB,
+	})
+	return ratelimit.NewFallbackStore(redis, postgres, logger)
+}
+
 // getAuthConfig returns authentication configuration from environment variables
 func getAuthConfig() *auth.AuthConfig {
 	config := auth.DefaultAuthConfig()
@@ -362,12 +352,53 @@ func getAuthConfig() *auth.AuthConfig {
 		}
 	}
 
+	// JWT issuer and audience
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		config.JWTIssuer = issuer
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		config.JWTAudience = audience
+	}
+
+	// Asymmetric signing key (RS256 or EdDSA, PEM). Retired keys listed in
+	// JWT_VERIFY_KEY_FILES keep validating tokens during rotation.
+	if keyFile := os.Getenv("JWT_PRIVATE_KEY_FILE"); keyFile != "" {
+		key, err := auth.LoadJWTKeyFile(os.Getenv("JWT_KEY_ID"), keyFile)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing key: %v", err)
+		}
+		config.JWTKeys = append(config.JWTKeys, key)
+
+		for _, verifyFile := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+			if verifyFile = strings.TrimSpace(verifyFile); verifyFile == "" {
+				continue
+			}
+			key, err := auth.LoadJWTKeyFile("", verifyFile)
+			if err != nil {
+				log.Fatalf("Failed to load JWT verification key: %v", err)
+			}
+			config.JWTKeys = append(config.JWTKeys, key)
+		}
+	}
+
+	// Refresh token expiration (in hours)
+	if expiry := os.Getenv("JWT_REFRESH_EXPIRATION_HOURS"); expiry != "" {
+		if hours, err := strconv.Atoi(expiry); err == nil && hours > 0 {
+			config.RefreshExpirationHours = hours
+		}
+	}
+
 	// Rate Limit
 	if rateLimit := os.Getenv("RATE_LIMIT_PER_MINUTE"); rateLimit != "" {
 		if limit, err := strconv.Atoi(rateLimit); err == nil && limit > 0 {
 			config.RateLimitPerMinute = limit
 		}
 	}
+	if rateLimit := os.Getenv("IP_RATE_LIMIT_PER_MINUTE"); rateLimit != "" {
+		if limit, err := strconv.Atoi(rateLimit); err == nil && limit >= 0 {
+			config.IPRateLimitPerMinute = limit
+		}
+	}
 
 	// Require Authentication (default: true)
 	if requireAuth := os.Getenv("REQUIRE_AUTH"); requireAuth != "" {
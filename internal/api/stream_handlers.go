@@ -0,0 +1,268 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamKeepAlive is how often an idle stream is pinged so proxies keep
+	// the connection open
+	streamKeepAlive = 15 * time.Second
+	// streamWriteTimeout bounds a single write to a stream
+	streamWriteTimeout = 10 * time.Second
+	// streamRetryMillis is how long SSE clients wait before reconnecting
+	streamRetryMillis = 3000
+	// streamBacklogPage is how many missed events are read at once when a
+	// client resumes
+	streamBacklogPage = 500
+)
+
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || originAllowed(origin)
+	},
+}
+
+// handleTrackingStreamSSE streams message events as Server-Sent Events.
+// Clients resume after a disconnect with the Last-Event-ID header, which
+// EventSource sends automatically, or ?last_event_id.
+func (s *Server) handleTrackingStreamSSE(w http.ResponseWriter, r *http.Request) {
+	filter, lastID, err := parseStreamRequest(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "streaming not supported", nil)
+		return
+	}
+
+	sub, backlog, err := s.openStream(r, filter, lastID)
+	if err != nil {
+		respondStreamError(w, err)
+		return
+	}
+	defer s.streamHub.Unsubscribe(sub)
+
+	webhooks.RecordTrackingStreamOpened("sse")
+	defer webhooks.RecordTrackingStreamClosed("sse")
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		s.logger.Debug().Err(err).Msg("Failed to clear stream write deadline")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+
+	send := func(event *webhooks.StreamEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.EventType, data)
+		return err
+	}
+
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return
+		}
+		lastID = event.ID
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+			lastID = event.ID
+			flusher.Flush()
+		}
+	}
+}
+
+// handleTrackingStreamWS streams message events over a WebSocket, one JSON
+// event per message. Clients resume after a disconnect with
+// ?last_event_id.
+func (s *Server) handleTrackingStreamWS(w http.ResponseWriter, r *http.Request) {
+	filter, lastID, err := parseStreamRequest(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	sub, backlog, err := s.openStream(r, filter, lastID)
+	if err != nil {
+		respondStreamError(w, err)
+		return
+	}
+	defer s.streamHub.Unsubscribe(sub)
+
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written the error response
+		return
+	}
+	defer conn.Close()
+
+	webhooks.RecordTrackingStreamOpened("websocket")
+	defer webhooks.RecordTrackingStreamClosed("websocket")
+
+	// Clients only send control frames. Reading handles them and notices
+	// when the client goes away.
+	closed := make(chan struct{})
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(2 * streamKeepAlive))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * streamKeepAlive))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(event *webhooks.StreamEvent) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return conn.WriteJSON(event)
+	}
+
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return
+		}
+		lastID = event.ID
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "stream closed, resume from the last event"),
+					time.Now().Add(streamWriteTimeout))
+				return
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+			lastID = event.ID
+		}
+	}
+}
+
+// openStream subscribes to live events before reading the events after
+// lastID, so no event published in between is missed. Live events already
+// in the backlog are skipped by ID.
+func (s *Server) openStream(r *http.Request, filter webhooks.StreamFilter, lastID int64) (*webhooks.StreamSubscription, []*webhooks.StreamEvent, error) {
+	sub, err := s.streamHub.Subscribe(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lastID <= 0 {
+		return sub, nil, nil
+	}
+
+	var backlog []*webhooks.StreamEvent
+	for {
+		events, err := s.trackingService.StreamEvents(r.Context(), lastID, &filter, streamBacklogPage)
+		if err != nil {
+			s.streamHub.Unsubscribe(sub)
+			return nil, nil, err
+		}
+		backlog = append(backlog, events...)
+		if len(events) < streamBacklogPage {
+			return sub, backlog, nil
+		}
+		lastID = events[len(events)-1].ID
+	}
+}
+
+// parseStreamRequest returns the stream filter and the ID of the last event
+// the client received
+func parseStreamRequest(r *http.Request) (webhooks.StreamFilter, int64, error) {
+	query := r.URL.Query()
+	filter := webhooks.StreamFilter{
+		OrgID:     tenantScope(r),
+		MessageID: query.Get("message_id"),
+		Sender:    query.Get("sender"),
+		Chain:     query.Get("chain"),
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+
+	var lastID int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			return filter, 0, fmt.Errorf("invalid last event ID: %s", lastEventID)
+		}
+		lastID = id
+	}
+
+	return filter, lastID, nil
+}
+
+func respondStreamError(w http.ResponseWriter, err error) {
+	if errors.Is(err, webhooks.ErrTooManyStreams) {
+		respondError(w, http.StatusServiceUnavailable, err.Error(), nil)
+		return
+	}
+	respondError(w, http.StatusInternalServerError, "failed to open tracking stream", err)
+}
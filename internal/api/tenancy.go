@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+)
+
+// tenantScope returns the organization whose data the caller may read.
+// Platform admins see every organization and may narrow the view with
+// ?org_id; everyone else is limited to their own organization. An empty
+// scope means all organizations.
+func tenantScope(r *http.Request) string {
+	authCtx := auth.GetAuthContext(r)
+	if authCtx == nil {
+		// Authentication disabled (development mode)
+		return r.URL.Query().Get("org_id")
+	}
+	if authCtx.HasPermission(auth.PermissionAdmin) {
+		return r.URL.Query().Get("org_id")
+	}
+	return tenantOrg(r)
+}
+
+// tenantOrg returns the organization that owns resources the caller creates
+func tenantOrg(r *http.Request) string {
+	if authCtx := auth.GetAuthContext(r); authCtx != nil && authCtx.OrgID != "" {
+		return authCtx.OrgID
+	}
+	return database.DefaultOrganizationID
+}
+
+// inTenantScope reports whether a resource owned by orgID is visible in scope
+func inTenantScope(scope, orgID string) bool {
+	return scope == "" || scope == orgID
+}
+
+// respondQuotaError writes the error for a failed quota check. status is
+// the code used when the quota is exhausted.
+func respondQuotaError(w http.ResponseWriter, status int, err error) {
+	if errors.Is(err, database.ErrQuotaExceeded) {
+		respondError(w, status, err.Error(), nil)
+		return
+	}
+	respondError(w, http.StatusInternalServerError, "failed to check organization quota", err)
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
+	"github.com/EmekaIwuagwu/articium-hub/internal/routing"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+)
+
+var (
+	batchColumns = []string{
+		"id", "status", "source_chain", "destination_chain", "message_count",
+		"total_gas_saved", "tx_hash", "created_at", "confirmed_at",
+	}
+	routeColumns = []string{
+		"id", "source_chain", "dest_chain", "total_hops", "total_cost",
+		"total_time_seconds", "total_fee", "score", "status", "created_at",
+		"updated_at", "org_id",
+	}
+)
+
+// asOrg attaches an auth context for a member of orgID to the request
+func asOrg(r *http.Request, orgID string, role auth.Role) *http.Request {
+	authCtx := &auth.AuthContext{
+		UserID:   "user-" + orgID,
+		OrgID:    orgID,
+		Role:     string(role),
+		AuthType: auth.AuthTypeJWT,
+	}
+	return r.WithContext(context.WithValue(r.Context(), auth.AuthContextKey, authCtx))
+}
+
+// serve runs handler for a request matched against pattern
+func serve(pattern string, handler http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc(pattern, handler)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestGetBatch_OtherOrganizationNotFound(t *testing.T) {
+	s, mock := newMockDBServer(t)
+
+	mock.ExpectQuery("FROM batches b").
+		WithArgs("batch-1", "org-b").
+		WillReturnError(sql.ErrNoRows)
+
+	req := asOrg(httptest.NewRequest(http.MethodGet, "/v1/batches/batch-1", nil), "org-b", auth.RoleUser)
+	rec := serve("/v1/batches/{id}", s.handleGetBatch, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for another organization's batch, got %d", rec.Code)
+	}
+}
+
+func TestGetBatch_ListsOnlyOwnMessages(t *testing.T) {
+	s, mock := newMockDBServer(t)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM batches b").
+		WithArgs("batch-1", "org-a").
+		WillReturnRows(sqlmock.NewRows(batchColumns).
+			AddRow("batch-1", "CONFIRMED", "ethereum", "polygon", 2, "0", "0xabc", now, nil))
+	mock.ExpectQuery("FROM batch_messages bm").
+		WithArgs("batch-1", "org-a").
+		WillReturnRows(sqlmock.NewRows([]string{"batch_id", "message_id", "added_at"}).
+			AddRow("batch-1", "msg-a", now))
+
+	req := asOrg(httptest.NewRequest(http.MethodGet, "/v1/batches/batch-1", nil), "org-a", auth.RoleUser)
+	rec := serve("/v1/batches/{id}", s.handleGetBatch, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListBatches_ScopedToOrganization(t *testing.T) {
+	s, mock := newMockDBServer(t)
+
+	mock.ExpectQuery("FROM batches b").
+		WithArgs(50, 0, "org-a").
+		WillReturnRows(sqlmock.NewRows(batchColumns))
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs("org-a").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	req := asOrg(httptest.NewRequest(http.MethodGet, "/v1/batches", nil), "org-a", auth.RoleUser)
+	rec := serve("/v1/batches", s.handleListBatches, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListBatches_AdminSeesAllOrganizations(t *testing.T) {
+	s, mock := newMockDBServer(t)
+
+	mock.ExpectQuery("FROM batches b").
+		WithArgs("PENDING", 50, 0, "").
+		WillReturnRows(sqlmock.NewRows(batchColumns))
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs("").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	req := asOrg(httptest.NewRequest(http.MethodGet, "/v1/batches?status=PENDING", nil), "org-a", auth.RoleAdmin)
+	rec := serve("/v1/batches", s.handleListBatches, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// expectRoute returns a route owned by orgID from the routes table
+func expectRoute(mock sqlmock.Sqlmock, routeID, orgID string) {
+	now := time.Now()
+	mock.ExpectQuery("FROM routes").
+		WithArgs(routeID).
+		WillReturnRows(sqlmock.NewRows(routeColumns).
+			AddRow(routeID, "ethereum", "polygon", 1, "100", 60, "10", 0.9, "PENDING", now, now, orgID))
+}
+
+func TestGetRoute_CrossOrganization(t *testing.T) {
+	tests := []struct {
+		name   string
+		orgID  string
+		role   auth.Role
+		status int
+	}{
+		{"owner", "org-a", auth.RoleUser, http.StatusOK},
+		{"other organization", "org-b", auth.RoleUser, http.StatusNotFound},
+		{"admin", "org-b", auth.RoleAdmin, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mock := newMockDBServer(t)
+			s.routingService = routing.NewService(s.db, nil, zerolog.Nop())
+			expectRoute(mock, "route-1", "org-a")
+
+			req := asOrg(httptest.NewRequest(http.MethodGet, "/v1/routes/route-1", nil), tt.orgID, tt.role)
+			rec := serve("/v1/routes/{id}", s.handleGetRoute, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestExecuteRoute_OtherOrganizationRejected(t *testing.T) {
+	s, mock := newMockDBServer(t)
+	s.routingService = routing.NewService(s.db, nil, zerolog.Nop())
+	expectRoute(mock, "route-1", "org-a")
+
+	// No further queries are expected: the route must not be executed
+	req := asOrg(httptest.NewRequest(http.MethodPost, "/v1/routes/route-1/execute", nil), "org-b", auth.RoleUser)
+	rec := serve("/v1/routes/{id}/execute", s.handleExecuteRoute, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for another organization's route, got %d", rec.Code)
+	}
+}
@@ -20,7 +20,7 @@ func (s *Server) handleTrackMessage(w http.ResponseWriter, r *http.Request) {
 		webhooks.RecordTrackingQueryLatency(time.Since(start).Seconds())
 	}()
 
-	timeline, err := s.trackingService.TrackMessage(r.Context(), messageID)
+	timeline, err := s.trackingService.TrackMessage(r.Context(), messageID, tenantScope(r))
 	if err != nil {
 		respondError(w, http.StatusNotFound, "message not found", err)
 		return
@@ -43,14 +43,14 @@ func (s *Server) handleTrackByTxHash(w http.ResponseWriter, r *http.Request) {
 		webhooks.RecordTrackingQueryLatency(time.Since(start).Seconds())
 	}()
 
-	message, err := s.trackingService.GetMessageByTxHash(r.Context(), txHash)
+	message, err := s.trackingService.GetMessageByTxHash(r.Context(), txHash, tenantScope(r))
 	if err != nil {
 		respondError(w, http.StatusNotFound, "message not found", err)
 		return
 	}
 
 	// Get full timeline
-	timeline, err := s.trackingService.TrackMessage(r.Context(), message.ID)
+	timeline, err := s.trackingService.TrackMessage(r.Context(), message.ID, message.OrgID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to get timeline", err)
 		return
@@ -72,6 +72,7 @@ func (s *Server) handleQueryMessages(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	query := &webhooks.TrackingQuery{
+		OrgID:       tenantScope(r),
 		MessageID:   r.URL.Query().Get("message_id"),
 		TxHash:      r.URL.Query().Get("tx_hash"),
 		Sender:      r.URL.Query().Get("sender"),
@@ -134,7 +135,7 @@ func (s *Server) handleRecentMessages(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	messages, err := s.trackingService.GetRecentMessages(r.Context(), limit)
+	messages, err := s.trackingService.GetRecentMessages(r.Context(), tenantScope(r), limit)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to get recent messages", err)
 		return
@@ -165,7 +166,7 @@ func (s *Server) handleMessagesByStatus(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	messages, err := s.trackingService.GetMessagesByStatus(r.Context(), status, limit)
+	messages, err := s.trackingService.GetMessagesByStatus(r.Context(), tenantScope(r), status, limit)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to get messages by status", err)
 		return
@@ -190,7 +191,7 @@ func (s *Server) handleMessageTimeline(w http.ResponseWriter, r *http.Request) {
 		webhooks.RecordTrackingQueryLatency(time.Since(start).Seconds())
 	}()
 
-	timeline, err := s.trackingService.TrackMessage(r.Context(), messageID)
+	timeline, err := s.trackingService.TrackMessage(r.Context(), messageID, tenantScope(r))
 	if err != nil {
 		respondError(w, http.StatusNotFound, "message not found", err)
 		return
@@ -263,10 +264,11 @@ func (s *Server) handleTrackingStats(w http.ResponseWriter, r *http.Request) {
 			AVG(EXTRACT(EPOCH FROM (COALESCE(confirmed_at, NOW()) - created_at))) as avg_time_seconds
 		FROM messages
 		WHERE created_at > NOW() - INTERVAL '24 hours'
+		AND ($1 = '' OR org_id = $1)
 		GROUP BY status
 	`
 
-	rows, err := s.db.QueryContext(r.Context(), query)
+	rows, err := s.db.QueryContext(r.Context(), query, tenantScope(r))
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to get stats", err)
 		return
@@ -331,18 +333,20 @@ func (s *Server) handleSearchMessages(w http.ResponseWriter, r *http.Request) {
 			source_tx_hash, dest_tx_hash, validator_signatures,
 			created_at, updated_at, submitted_at, confirmed_at
 		FROM messages
-		WHERE
+		WHERE (
 			id ILIKE $1 OR
 			sender ILIKE $1 OR
 			recipient ILIKE $1 OR
 			source_tx_hash ILIKE $1 OR
 			dest_tx_hash ILIKE $1
+		)
+		AND ($3 = '' OR org_id = $3)
 		ORDER BY created_at DESC
 		LIMIT $2
 	`
 
 	searchPattern := "%" + searchTerm + "%"
-	rows, err := s.db.QueryContext(r.Context(), query, searchPattern, limit)
+	rows, err := s.db.QueryContext(r.Context(), query, searchPattern, limit, tenantScope(r))
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to search messages", err)
 		return
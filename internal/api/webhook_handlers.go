@@ -2,14 +2,28 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
 	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
+const (
+	// defaultSecretGracePeriod is how long a rotated webhook secret stays
+	// valid by default
+	defaultSecretGracePeriod = 24 * time.Hour
+	// maxSecretGracePeriodHours is the longest grace period allowed
+	maxSecretGracePeriodHours = 7 * 24
+	// maxReplayWindow is the longest time range one replay may cover
+	maxReplayWindow = 30 * 24 * time.Hour
+)
+
 // handleRegisterWebhook registers a new webhook
 func (s *Server) handleRegisterWebhook(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -20,6 +34,9 @@ func (s *Server) handleRegisterWebhook(w http.ResponseWriter, r *http.Request) {
 		DestChains   []string             `json:"dest_chains,omitempty"`
 		MinAmount    string               `json:"min_amount,omitempty"`
 		MaxAmount    string               `json:"max_amount,omitempty"`
+		// LegacySignature opts in to the deprecated X-Webhook-Signature
+		// header, sent until webhooks.LegacySignatureSunset
+		LegacySignature bool `json:"legacy_signature,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -38,22 +55,24 @@ func (s *Server) handleRegisterWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// For now, use a placeholder for created_by
-	// In production, this should come from authentication
-	createdBy := r.Header.Get("X-User-ID")
-	if createdBy == "" {
-		createdBy = "anonymous"
+	orgID := tenantOrg(r)
+	if err := s.db.CheckQuota(r.Context(), orgID, database.QuotaWebhooks); err != nil {
+		respondQuotaError(w, http.StatusForbidden, err)
+		return
 	}
 
 	webhook := &webhooks.Webhook{
+		OrgID:        orgID,
 		URL:          req.URL,
 		Events:       req.Events,
 		Description:  req.Description,
-		CreatedBy:    createdBy,
+		CreatedBy:    webhookOwner(r),
 		SourceChains: req.SourceChains,
 		DestChains:   req.DestChains,
 		MinAmount:    req.MinAmount,
 		MaxAmount:    req.MaxAmount,
+
+		LegacySignature: req.LegacySignature,
 	}
 
 	if err := s.webhookRegistry.Register(r.Context(), webhook); err != nil {
@@ -70,14 +89,9 @@ func (s *Server) handleRegisterWebhook(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleListWebhooks lists all webhooks for the current user
+// handleListWebhooks lists the webhooks of the caller's organization
 func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
-	createdBy := r.Header.Get("X-User-ID")
-	if createdBy == "" {
-		createdBy = "anonymous"
-	}
-
-	hooks, err := s.webhookRegistry.List(r.Context(), createdBy)
+	hooks, err := s.webhookRegistry.List(r.Context(), tenantScope(r))
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to list webhooks", err)
 		return
@@ -91,12 +105,8 @@ func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
 
 // handleGetWebhook retrieves a specific webhook
 func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
-
-	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "webhook not found", err)
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
 		return
 	}
 
@@ -107,9 +117,6 @@ func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
 
 // handleUpdateWebhook updates an existing webhook
 func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
-
 	var req struct {
 		URL          string                 `json:"url"`
 		Events       []webhooks.EventType   `json:"events"`
@@ -119,6 +126,9 @@ func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
 		DestChains   []string               `json:"dest_chains,omitempty"`
 		MinAmount    string                 `json:"min_amount,omitempty"`
 		MaxAmount    string                 `json:"max_amount,omitempty"`
+		// LegacySignature turns the deprecated X-Webhook-Signature header
+		// on or off
+		LegacySignature *bool `json:"legacy_signature,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -127,9 +137,8 @@ func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get existing webhook
-	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "webhook not found", err)
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
 		return
 	}
 
@@ -160,6 +169,9 @@ func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
 	if req.MaxAmount != "" {
 		webhook.MaxAmount = req.MaxAmount
 	}
+	if req.LegacySignature != nil {
+		webhook.LegacySignature = *req.LegacySignature
+	}
 
 	if err := s.webhookRegistry.Update(r.Context(), webhook); err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to update webhook", err)
@@ -174,17 +186,13 @@ func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
 
 // handleDeleteWebhook deletes a webhook
 func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
-
 	// Get webhook to get its status before deletion
-	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "webhook not found", err)
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
 		return
 	}
 
-	if err := s.webhookRegistry.Delete(r.Context(), webhookID); err != nil {
+	if err := s.webhookRegistry.Delete(r.Context(), webhook.ID); err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to delete webhook", err)
 		return
 	}
@@ -199,18 +207,14 @@ func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
 
 // handlePauseWebhook pauses a webhook
 func (s *Server) handlePauseWebhook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
-
 	// Get current status
-	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "webhook not found", err)
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
 		return
 	}
 
 	oldStatus := webhook.Status
-	if err := s.webhookRegistry.UpdateStatus(r.Context(), webhookID, webhooks.WebhookStatusPaused); err != nil {
+	if err := s.webhookRegistry.UpdateStatus(r.Context(), webhook.ID, webhooks.WebhookStatusPaused); err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to pause webhook", err)
 		return
 	}
@@ -224,18 +228,14 @@ func (s *Server) handlePauseWebhook(w http.ResponseWriter, r *http.Request) {
 
 // handleResumeWebhook resumes a paused webhook
 func (s *Server) handleResumeWebhook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
-
 	// Get current status
-	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "webhook not found", err)
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
 		return
 	}
 
 	oldStatus := webhook.Status
-	if err := s.webhookRegistry.UpdateStatus(r.Context(), webhookID, webhooks.WebhookStatusActive); err != nil {
+	if err := s.webhookRegistry.UpdateStatus(r.Context(), webhook.ID, webhooks.WebhookStatusActive); err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to resume webhook", err)
 		return
 	}
@@ -249,12 +249,8 @@ func (s *Server) handleResumeWebhook(w http.ResponseWriter, r *http.Request) {
 
 // handleTestWebhook sends a test event to a webhook
 func (s *Server) handleTestWebhook(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
-
-	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
-	if err != nil {
-		respondError(w, http.StatusNotFound, "webhook not found", err)
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
 		return
 	}
 
@@ -262,7 +258,7 @@ func (s *Server) handleTestWebhook(w http.ResponseWriter, r *http.Request) {
 	testPayload := map[string]interface{}{
 		"test":       true,
 		"message":    "This is a test webhook delivery",
-		"webhook_id": webhookID,
+		"webhook_id": webhook.ID,
 		"timestamp":  time.Now().UTC(),
 	}
 
@@ -276,7 +272,7 @@ func (s *Server) handleTestWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Dispatch test event
-	if err := s.webhookDelivery.Dispatch(event); err != nil {
+	if err := s.webhookDelivery.Dispatch(r.Context(), event); err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to dispatch test webhook", err)
 		return
 	}
@@ -287,10 +283,137 @@ func (s *Server) handleTestWebhook(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleRotateWebhookSecret replaces a webhook's signing secret. The
+// previous secret stays valid for the requested grace period.
+func (s *Server) handleRotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GracePeriodHours *int `json:"grace_period_hours,omitempty"`
+	}
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body", err)
+			return
+		}
+	}
+
+	grace := defaultSecretGracePeriod
+	if req.GracePeriodHours != nil {
+		hours := *req.GracePeriodHours
+		if hours < 0 || hours > maxSecretGracePeriodHours {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("grace_period_hours must be between 0 and %d", maxSecretGracePeriodHours), nil)
+			return
+		}
+		grace = time.Duration(hours) * time.Hour
+	}
+
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := s.webhookRegistry.RotateSecret(r.Context(), webhook.ID, grace)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to rotate webhook secret", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"webhook": webhook,
+		"message": "Webhook secret rotated successfully",
+	})
+}
+
+// handleReplayWebhookEvents queues a webhook's past events for delivery
+// again. With dry_run, it only reports how many events would be replayed.
+func (s *Server) handleReplayWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		webhooks.ReplayFilter
+		DryRun bool `json:"dry_run,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if req.From.IsZero() || req.To.IsZero() {
+		respondError(w, http.StatusBadRequest, "from and to are required", nil)
+		return
+	}
+	if !req.From.Before(req.To) {
+		respondError(w, http.StatusBadRequest, "from must be before to", nil)
+		return
+	}
+	if req.To.Sub(req.From) > maxReplayWindow {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("replay window must not exceed %d days", int(maxReplayWindow.Hours()/24)), nil)
+		return
+	}
+	if len(req.EventTypes) > 0 {
+		if err := webhooks.ValidateEventTypes(req.EventTypes); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+	}
+
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := s.webhookDelivery.Replay(r.Context(), webhook, &req.ReplayFilter, req.DryRun)
+	switch {
+	case errors.Is(err, webhooks.ErrReplayTooLarge):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":  err.Error(),
+			"replay": result,
+		})
+		return
+	case errors.Is(err, webhooks.ErrReplayInProgress):
+		respondError(w, http.StatusConflict, err.Error(), nil)
+		return
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "failed to replay webhook events", err)
+		return
+	}
+
+	status := http.StatusAccepted
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	respondJSON(w, status, result)
+}
+
+// handleRedeliverWebhookEvent queues a single past event for delivery again
+func (s *Server) handleRedeliverWebhookEvent(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := s.webhookDelivery.Redeliver(r.Context(), webhook, mux.Vars(r)["event_id"])
+	switch {
+	case errors.Is(err, webhooks.ErrEventNotFound):
+		respondError(w, http.StatusNotFound, err.Error(), nil)
+		return
+	case errors.Is(err, webhooks.ErrEventPending), errors.Is(err, webhooks.ErrReplayInProgress):
+		respondError(w, http.StatusConflict, err.Error(), nil)
+		return
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "failed to redeliver webhook event", err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, result)
+}
+
 // handleWebhookDeliveryAttempts retrieves delivery attempts for a webhook
 func (s *Server) handleWebhookDeliveryAttempts(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	webhookID := vars["id"]
+	webhook, ok := s.getOwnedWebhook(w, r)
+	if !ok {
+		return
+	}
+	webhookID := webhook.ID
 
 	// Query delivery attempts from database
 	query := `
@@ -357,3 +480,38 @@ func (s *Server) handleWebhookDeliveryAttempts(w http.ResponseWriter, r *http.Re
 		"count":      len(attempts),
 	})
 }
+
+// webhookOwner returns the caller recorded as creating webhooks registered
+// by the request
+func webhookOwner(r *http.Request) string {
+	if authCtx := auth.GetAuthContext(r); authCtx != nil {
+		return authCtx.UserID
+	}
+
+	// Authentication disabled (development mode)
+	if createdBy := r.Header.Get("X-User-ID"); createdBy != "" {
+		return createdBy
+	}
+	return "anonymous"
+}
+
+// getOwnedWebhook loads the webhook named in the request path if it belongs
+// to the caller's organization or the caller is an admin. Webhooks of other
+// organizations are reported as not found so their existence is not
+// revealed.
+func (s *Server) getOwnedWebhook(w http.ResponseWriter, r *http.Request) (*webhooks.Webhook, bool) {
+	webhookID := mux.Vars(r)["id"]
+
+	webhook, err := s.webhookRegistry.Get(r.Context(), webhookID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "webhook not found", err)
+		return nil, false
+	}
+
+	if !inTenantScope(tenantScope(r), webhook.OrgID) {
+		respondError(w, http.StatusNotFound, "webhook not found", nil)
+		return nil, false
+	}
+
+	return webhook, true
+}
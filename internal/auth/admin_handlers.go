@@ -0,0 +1,788 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// minPasswordLength is the shortest accepted password
+	minPasswordLength = 8
+	// inviteTokenTTL is how long an invited user has to set a password
+	inviteTokenTTL = 7 * 24 * time.Hour
+	// resetTokenTTL is how long a password reset token is valid
+	resetTokenTTL = time.Hour
+)
+
+// Password token purposes
+const (
+	PasswordTokenInvite = "invite"
+	PasswordTokenReset  = "reset"
+)
+
+// slugPattern matches valid organization slugs
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// Organization handlers
+
+// HandleListOrganizations lists all organizations
+func (h *Handler) HandleListOrganizations(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.db.ListOrganizations(r.Context())
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to list organizations", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"organizations": orgs,
+		"count":         len(orgs),
+	})
+}
+
+// HandleCreateOrganization creates a new organization
+func (h *Handler) HandleCreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var req OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		h.respondError(w, http.StatusBadRequest, "name is required", nil)
+		return
+	}
+	if req.Slug == nil || !slugPattern.MatchString(*req.Slug) {
+		h.respondError(w, http.StatusBadRequest, "slug must be 2-63 lowercase letters, digits or hyphens", nil)
+		return
+	}
+
+	org := &database.Organization{
+		ID:     "org-" + uuid.New().String(),
+		Slug:   *req.Slug,
+		Active: true,
+	}
+	if err := applyOrganizationRequest(org, &req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if !h.checkPlanExists(w, r, org.PlanID) {
+		return
+	}
+
+	if err := h.db.CreateOrganization(r.Context(), org); err != nil {
+		if isUniqueViolation(err) {
+			h.respondError(w, http.StatusConflict, "organization slug already exists", nil)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "failed to create organization", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("org_id", org.ID).
+		Str("slug", org.Slug).
+		Str("admin_id", adminID(r)).
+		Msg("Organization created")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditOrgCreated,
+		Success:    true,
+		TargetType: AuditTargetOrganization,
+		TargetID:   org.ID,
+		Details:    map[string]string{"slug": org.Slug},
+	})
+
+	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"organization": org,
+	})
+}
+
+// HandleGetOrganization returns an organization and its quota usage
+func (h *Handler) HandleGetOrganization(w http.ResponseWriter, r *http.Request) {
+	org, ok := h.getOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.db.GetOrganizationUsage(r.Context(), org.ID)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get organization usage", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"organization": org,
+		"usage":        usage,
+	})
+}
+
+// HandleUpdateOrganization updates an organization's name, status or quotas.
+// Deactivating an organization blocks logins, token refresh and API keys
+// for all of its users.
+func (h *Handler) HandleUpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	var req OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	org, ok := h.getOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	if req.Slug != nil && *req.Slug != org.Slug {
+		h.respondError(w, http.StatusBadRequest, "slug cannot be changed", nil)
+		return
+	}
+	if req.Active != nil && !*req.Active && org.ID == database.DefaultOrganizationID {
+		h.respondError(w, http.StatusBadRequest, "the default organization cannot be deactivated", nil)
+		return
+	}
+	if err := applyOrganizationRequest(org, &req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if !h.checkPlanExists(w, r, org.PlanID) {
+		return
+	}
+
+	if err := h.db.UpdateOrganization(r.Context(), org); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to update organization", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("org_id", org.ID).
+		Bool("active", org.Active).
+		Str("admin_id", adminID(r)).
+		Msg("Organization updated")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditOrgUpdated,
+		Success:    true,
+		TargetType: AuditTargetOrganization,
+		TargetID:   org.ID,
+		Details:    organizationAuditDetails(org),
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"organization": org,
+	})
+}
+
+// User lifecycle handlers
+
+// HandleListUsers lists users, optionally filtered by org_id and active
+func (h *Handler) HandleListUsers(w http.ResponseWriter, r *http.Request) {
+	orgID := r.URL.Query().Get("org_id")
+
+	var active *bool
+	if activeStr := r.URL.Query().Get("active"); activeStr != "" {
+		value := activeStr == "true"
+		active = &value
+	}
+
+	query := `
+		SELECT id, org_id, email, name, role, active, created_at, updated_at
+		FROM users
+		WHERE ($1 = '' OR org_id = $1)
+		AND ($2::BOOLEAN IS NULL OR active = $2)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := h.db.QueryContext(r.Context(), query, orgID, active)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to list users", err)
+		return
+	}
+	defer rows.Close()
+
+	users := []*User{}
+	for rows.Next() {
+		var user User
+		err := rows.Scan(
+			&user.ID,
+			&user.OrgID,
+			&user.Email,
+			&user.Name,
+			&user.Role,
+			&user.Active,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			h.respondError(w, http.StatusInternalServerError, "failed to scan user", err)
+			return
+		}
+		users = append(users, &user)
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"users": users,
+		"count": len(users),
+	})
+}
+
+// HandleCreateUser creates a user. Without a password the user is invited:
+// the response carries a single-use token for setting one.
+func (h *Handler) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	email, err := mail.ParseAddress(req.Email)
+	if err != nil || email.Address != req.Email {
+		h.respondError(w, http.StatusBadRequest, "a valid email is required", nil)
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		h.respondError(w, http.StatusBadRequest, "name is required", nil)
+		return
+	}
+	if !IsValidRole(req.Role) {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown role %q", req.Role), nil)
+		return
+	}
+	if req.Password != "" && len(req.Password) < minPasswordLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("password must be at least %d characters", minPasswordLength), nil)
+		return
+	}
+
+	orgID := req.OrgID
+	if orgID == "" {
+		orgID = database.DefaultOrganizationID
+	}
+	org, err := h.db.GetOrganization(r.Context(), orgID)
+	if errors.Is(err, database.ErrOrganizationNotFound) {
+		h.respondError(w, http.StatusBadRequest, "organization not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get organization", err)
+		return
+	}
+	if !org.Active {
+		h.respondError(w, http.StatusBadRequest, "organization is disabled", nil)
+		return
+	}
+	if err := h.db.CheckQuota(r.Context(), org.ID, database.QuotaUsers); err != nil {
+		h.respondQuotaError(w, err)
+		return
+	}
+
+	// Invited users get an empty hash, which no password matches
+	passwordHash := ""
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			h.respondError(w, http.StatusInternalServerError, "failed to hash password", err)
+			return
+		}
+		passwordHash = string(hash)
+	}
+
+	user := &User{
+		ID:     uuid.New().String(),
+		OrgID:  org.ID,
+		Email:  req.Email,
+		Name:   req.Name,
+		Role:   req.Role,
+		Active: true,
+	}
+
+	query := `
+		INSERT INTO users (id, org_id, email, name, password_hash, role, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	err = h.db.QueryRowContext(r.Context(), query,
+		user.ID,
+		user.OrgID,
+		user.Email,
+		user.Name,
+		passwordHash,
+		user.Role,
+		user.Active,
+	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			h.respondError(w, http.StatusConflict, "a user with this email already exists", nil)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "failed to create user", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("user_id", user.ID).
+		Str("org_id", user.OrgID).
+		Str("role", user.Role).
+		Bool("invited", req.Password == "").
+		Str("admin_id", adminID(r)).
+		Msg("User created")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditUserCreated,
+		Success:    true,
+		TargetType: AuditTargetUser,
+		TargetID:   user.ID,
+		Details: map[string]string{
+			"email":   user.Email,
+			"org_id":  user.OrgID,
+			"role":    user.Role,
+			"invited": strconv.FormatBool(req.Password == ""),
+		},
+	})
+
+	if req.Password != "" {
+		h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+			"user": user,
+		})
+		return
+	}
+
+	token, expiresAt, err := h.issuePasswordToken(r.Context(), user.ID, PasswordTokenInvite, adminID(r))
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to create invite token", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, PasswordTokenResponse{
+		User:      user,
+		Token:     token,
+		Purpose:   PasswordTokenInvite,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// HandleGetUser returns a user
+func (h *Handler) HandleGetUser(w http.ResponseWriter, r *http.Request) {
+	rec, err := h.getUserByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.respondError(w, http.StatusNotFound, "user not found", nil)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"user": rec.User,
+	})
+}
+
+// HandleUpdateUser changes a user's name, role or active status. Role
+// changes reach access tokens on their next refresh; API keys pick them up
+// immediately.
+func (h *Handler) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	rec, err := h.getUserByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.respondError(w, http.StatusNotFound, "user not found", nil)
+		return
+	}
+	user := rec.User
+	previousRole := user.Role
+
+	if req.Role != nil && !IsValidRole(*req.Role) {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown role %q", *req.Role), nil)
+		return
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		h.respondError(w, http.StatusBadRequest, "name cannot be empty", nil)
+		return
+	}
+	if user.ID == adminID(r) && ((req.Role != nil && *req.Role != user.Role) || (req.Active != nil && !*req.Active)) {
+		h.respondError(w, http.StatusBadRequest, "admins cannot change their own role or deactivate themselves", nil)
+		return
+	}
+
+	if req.Active != nil && !*req.Active && user.Active {
+		if err := h.deactivateUser(r.Context(), user.ID); err != nil {
+			h.respondError(w, http.StatusInternalServerError, "failed to deactivate user", err)
+			return
+		}
+		user.Active = false
+	}
+	if req.Active != nil && *req.Active && !user.Active {
+		if err := h.db.CheckQuota(r.Context(), user.OrgID, database.QuotaUsers); err != nil {
+			h.respondQuotaError(w, err)
+			return
+		}
+		user.Active = true
+	}
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Role != nil {
+		user.Role = *req.Role
+	}
+
+	query := `
+		UPDATE users
+		SET name = $2, role = $3, active = $4
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	if err := h.db.QueryRowContext(r.Context(), query, user.ID, user.Name, user.Role, user.Active).Scan(&user.UpdatedAt); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to update user", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("user_id", user.ID).
+		Str("role", user.Role).
+		Bool("active", user.Active).
+		Str("admin_id", adminID(r)).
+		Msg("User updated")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditUserUpdated,
+		Success:    true,
+		TargetType: AuditTargetUser,
+		TargetID:   user.ID,
+		Details: map[string]string{
+			"previous_role": previousRole,
+			"role":          user.Role,
+			"active":        strconv.FormatBool(user.Active),
+		},
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+// HandleDeactivateUser deactivates a user, revoking their API keys and
+// refresh tokens. Access tokens already issued remain valid until they
+// expire.
+func (h *Handler) HandleDeactivateUser(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+	if userID == adminID(r) {
+		h.respondError(w, http.StatusBadRequest, "admins cannot deactivate themselves", nil)
+		return
+	}
+
+	if _, err := h.getUserByID(r.Context(), userID); err != nil {
+		h.respondError(w, http.StatusNotFound, "user not found", nil)
+		return
+	}
+
+	if err := h.deactivateUser(r.Context(), userID); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to deactivate user", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("user_id", userID).
+		Str("admin_id", adminID(r)).
+		Msg("User deactivated")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditUserDeactivated,
+		Success:    true,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "User deactivated successfully",
+	})
+}
+
+// HandleIssuePasswordReset issues a password reset token for a user. The
+// token is returned once and must be passed to the user out of band.
+func (h *Handler) HandleIssuePasswordReset(w http.ResponseWriter, r *http.Request) {
+	rec, err := h.getUserByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.respondError(w, http.StatusNotFound, "user not found", nil)
+		return
+	}
+	if !rec.Active {
+		h.respondError(w, http.StatusBadRequest, "user account is disabled", nil)
+		return
+	}
+
+	token, expiresAt, err := h.issuePasswordToken(r.Context(), rec.ID, PasswordTokenReset, adminID(r))
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to create reset token", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("user_id", rec.ID).
+		Str("admin_id", adminID(r)).
+		Msg("Password reset token issued")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditPasswordResetIssued,
+		Success:    true,
+		TargetType: AuditTargetUser,
+		TargetID:   rec.ID,
+		Details:    map[string]string{"expires_at": expiresAt.Format(time.RFC3339)},
+	})
+
+	h.respondJSON(w, http.StatusCreated, PasswordTokenResponse{
+		User:      rec.User,
+		Token:     token,
+		Purpose:   PasswordTokenReset,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// HandleResetPassword sets a user's password from an invite or reset token
+// and signs out their existing sessions
+func (h *Handler) HandleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		h.respondError(w, http.StatusBadRequest, "token is required", nil)
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("password must be at least %d characters", minPasswordLength), nil)
+		return
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to hash password", err)
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to reset password", err)
+		return
+	}
+	defer tx.Rollback()
+
+	var userID string
+	err = tx.QueryRowContext(r.Context(), `
+		UPDATE password_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`, hashRefreshToken(req.Token)).Scan(&userID)
+	if err == sql.ErrNoRows {
+		h.respondError(w, http.StatusBadRequest, "invalid or expired token", nil)
+		return
+	}
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to reset password", err)
+		return
+	}
+
+	result, err := tx.ExecContext(r.Context(), `
+		UPDATE users SET password_hash = $2 WHERE id = $1 AND active = true
+	`, userID, string(passwordHash))
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to reset password", err)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		h.respondError(w, http.StatusBadRequest, "user account is disabled", nil)
+		return
+	}
+
+	if _, err := tx.ExecContext(r.Context(), `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to reset password", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to reset password", err)
+		return
+	}
+
+	h.logger.Info().Str("user_id", userID).Msg("Password reset")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditPasswordReset,
+		Success:    true,
+		ActorID:    userID,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Password updated successfully",
+	})
+}
+
+// Private methods
+
+// getOrganization loads the organization named in the request path
+func (h *Handler) getOrganization(w http.ResponseWriter, r *http.Request) (*database.Organization, bool) {
+	org, err := h.db.GetOrganization(r.Context(), mux.Vars(r)["id"])
+	if errors.Is(err, database.ErrOrganizationNotFound) {
+		h.respondError(w, http.StatusNotFound, "organization not found", nil)
+		return nil, false
+	}
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get organization", err)
+		return nil, false
+	}
+	return org, true
+}
+
+// deactivateUser disables a user and revokes their API keys and refresh tokens
+func (h *Handler) deactivateUser(ctx context.Context, userID string) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`UPDATE users SET active = false WHERE id = $1`,
+		`UPDATE api_keys SET active = false WHERE user_id = $1 AND active = true`,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		`UPDATE password_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt, userID); err != nil {
+			return fmt.Errorf("failed to deactivate user: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// issuePasswordToken creates a single-use invite or reset token. Earlier
+// unused tokens for the user are invalidated.
+func (h *Handler) issuePasswordToken(ctx context.Context, userID, purpose, createdBy string) (string, time.Time, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate password token: %w", err)
+	}
+	token := "pwt_" + hex.EncodeToString(bytes)
+
+	ttl := resetTokenTTL
+	if purpose == PasswordTokenInvite {
+		ttl = inviteTokenTTL
+	}
+	expiresAt := time.Now().UTC().Add(ttl)
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE password_tokens SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`, userID); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to invalidate password tokens: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO password_tokens (id, user_id, token_hash, purpose, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, uuid.New().String(), userID, hashRefreshToken(token), purpose, createdBy, expiresAt); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to save password token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// applyOrganizationRequest copies the set fields of req onto org
+func applyOrganizationRequest(org *database.Organization, req *OrganizationRequest) error {
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return fmt.Errorf("name cannot be empty")
+		}
+		org.Name = *req.Name
+	}
+	if req.Active != nil {
+		org.Active = *req.Active
+	}
+	if req.PlanID != nil {
+		org.PlanID = *req.PlanID
+	}
+
+	quotas := []struct {
+		value *int
+		field *int
+		name  string
+	}{
+		{req.MaxUsers, &org.MaxUsers, "max_users"},
+		{req.MaxAPIKeys, &org.MaxAPIKeys, "max_api_keys"},
+		{req.MaxWebhooks, &org.MaxWebhooks, "max_webhooks"},
+		{req.MaxDailyMessages, &org.MaxDailyMessages, "max_daily_messages"},
+	}
+	for _, q := range quotas {
+		if q.value == nil {
+			continue
+		}
+		if *q.value < 0 {
+			return fmt.Errorf("%s cannot be negative", q.name)
+		}
+		*q.field = *q.value
+	}
+
+	return nil
+}
+
+// organizationAuditDetails summarizes an organization's status and quotas
+// for the audit log
+func organizationAuditDetails(org *database.Organization) map[string]string {
+	return map[string]string{
+		"name":               org.Name,
+		"active":             strconv.FormatBool(org.Active),
+		"max_users":          strconv.Itoa(org.MaxUsers),
+		"max_api_keys":       strconv.Itoa(org.MaxAPIKeys),
+		"max_webhooks":       strconv.Itoa(org.MaxWebhooks),
+		"max_daily_messages": strconv.Itoa(org.MaxDailyMessages),
+		"plan_id":            org.PlanID,
+	}
+}
+
+// adminID returns the ID of the user making an admin request
+func adminID(r *http.Request) string {
+	if authCtx := GetAuthContext(r); authCtx != nil {
+		return authCtx.UserID
+	}
+	return ""
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint error
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/rs/zerolog"
+)
+
+// Audit event types
+const (
+	AuditLogin               = "login"
+	AuditLogout              = "logout"
+	AuditTokenRefresh        = "token_refresh"
+	AuditAPIKeyCreated       = "api_key_created"
+	AuditAPIKeyRevoked       = "api_key_revoked"
+	AuditPermissionDenied    = "permission_denied"
+	AuditOrgCreated          = "organization_created"
+	AuditOrgUpdated          = "organization_updated"
+	AuditUserCreated         = "user_created"
+	AuditUserUpdated         = "user_updated"
+	AuditUserDeactivated     = "user_deactivated"
+	AuditPasswordResetIssued = "password_reset_issued"
+	AuditPasswordReset       = "password_reset"
+	AuditPlanCreated         = "rate_limit_plan_created"
+	AuditPlanUpdated         = "rate_limit_plan_updated"
+	AuditAPIKeyPlanChanged   = "api_key_plan_changed"
+)
+
+// Audit target types
+const (
+	AuditTargetUser         = "user"
+	AuditTargetAPIKey       = "api_key"
+	AuditTargetOrganization = "organization"
+	AuditTargetRoute        = "route"
+	AuditTargetPlan         = "rate_limit_plan"
+)
+
+// maxAuditEmailLength matches the auth_audit_log email column
+const maxAuditEmailLength = 255
+
+// auditTimeout bounds how long a request waits for its audit entry
+const auditTimeout = 5 * time.Second
+
+// AuditEvent describes something to record in the audit log. The IP
+// address and user agent are taken from the request, and the actor from its
+// auth context unless set on the event.
+type AuditEvent struct {
+	Type       string
+	Success    bool
+	ActorID    string
+	Email      string
+	OrgID      string
+	TargetType string
+	TargetID   string
+	Error      string
+	Details    map[string]string
+}
+
+// AuditLogger records audit events in the database audit log
+type AuditLogger struct {
+	db     *database.DB
+	logger zerolog.Logger
+}
+
+// NewAuditLogger creates an audit logger. With no database, events are only
+// written to the application log.
+func NewAuditLogger(db *database.DB, logger zerolog.Logger) *AuditLogger {
+	return &AuditLogger{
+		db:     db,
+		logger: logger.With().Str("component", "audit").Logger(),
+	}
+}
+
+// Record appends an event to the audit log. Failures are logged rather than
+// returned so an audit outage does not block the request being audited.
+func (a *AuditLogger) Record(r *http.Request, event AuditEvent) {
+	entry := &database.AuditEntry{
+		EventType:  event.Type,
+		Success:    event.Success,
+		ActorID:    event.ActorID,
+		ActorEmail: truncate(event.Email, maxAuditEmailLength),
+		OrgID:      event.OrgID,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IPAddress:  clientIP(r),
+		UserAgent:  r.UserAgent(),
+		Error:      event.Error,
+		Details:    event.Details,
+	}
+
+	if authCtx := GetAuthContext(r); authCtx != nil && entry.ActorID == "" {
+		entry.ActorID = authCtx.UserID
+		entry.ActorEmail = authCtx.Email
+		entry.APIKeyID = authCtx.APIKeyID
+		entry.OrgID = authCtx.OrgID
+	}
+
+	a.RecordEntry(r.Context(), entry)
+}
+
+// RecordEntry appends a prepared entry to the audit log
+func (a *AuditLogger) RecordEntry(ctx context.Context, entry *database.AuditEntry) {
+	a.logger.Info().
+		Str("event_type", entry.EventType).
+		Bool("success", entry.Success).
+		Str("actor_id", entry.ActorID).
+		Str("target_id", entry.TargetID).
+		Msg("Audit event")
+
+	if a.db == nil {
+		return
+	}
+
+	// Record even if the client has gone away
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditTimeout)
+	defer cancel()
+
+	if err := a.db.AppendAuditEntry(ctx, entry); err != nil {
+		a.logger.Error().Err(err).Str("event_type", entry.EventType).Msg("Failed to write audit entry")
+	}
+}
+
+// clientIP returns the IP address of the connection the request came from
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
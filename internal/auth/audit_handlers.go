@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+)
+
+const (
+	// defaultAuditLimit is the page size when no limit is given
+	defaultAuditLimit = 100
+	// maxAuditLimit caps JSON pages
+	maxAuditLimit = 1000
+	// maxAuditExportLimit caps a single CSV export
+	maxAuditExportLimit = 10000
+)
+
+// auditCSVHeader lists the columns of a CSV audit export
+var auditCSVHeader = []string{
+	"id", "created_at", "event_type", "success", "actor_id", "actor_email",
+	"api_key_id", "org_id", "target_type", "target_id", "ip_address",
+	"user_agent", "error", "details", "prev_hash", "hash",
+}
+
+// HandleQueryAuditLog lists audit entries, newest first. Entries can be
+// filtered by event_type, actor_id, org_id, target_type, target_id, success
+// and an RFC 3339 from/to range. format=csv downloads them as CSV.
+func (h *Handler) HandleQueryAuditLog(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.respondError(w, http.StatusBadRequest, "format must be json or csv", nil)
+		return
+	}
+
+	maxLimit := maxAuditLimit
+	if format == "csv" {
+		maxLimit = maxAuditExportLimit
+	}
+
+	filter, err := parseAuditFilter(r, maxLimit)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	entries, err := h.db.QueryAuditLog(r.Context(), filter)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to query audit log", err)
+		return
+	}
+
+	if format == "csv" {
+		h.writeAuditCSV(w, entries)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+		"count":   len(entries),
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	})
+}
+
+// HandleVerifyAuditLog checks the audit log hash chain for tampering
+func (h *Handler) HandleVerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	result, err := h.db.VerifyAuditChain(r.Context())
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to verify audit log", err)
+		return
+	}
+
+	if !result.Valid {
+		h.logger.Error().
+			Int64("broken_at", result.BrokenAt).
+			Str("reason", result.Reason).
+			Msg("Audit log hash chain is broken")
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// parseAuditFilter reads audit filters from the query string
+func parseAuditFilter(r *http.Request, maxLimit int) (*database.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := &database.AuditFilter{
+		EventType:  q.Get("event_type"),
+		ActorID:    q.Get("actor_id"),
+		OrgID:      q.Get("org_id"),
+		TargetType: q.Get("target_type"),
+		TargetID:   q.Get("target_id"),
+		Limit:      defaultAuditLimit,
+	}
+
+	if v := q.Get("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("success must be true or false")
+		}
+		filter.Success = &success
+	}
+
+	for name, dest := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+			*dest = &t
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		filter.Limit = limit
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
+// writeAuditCSV writes audit entries as a CSV attachment
+func (h *Handler) writeAuditCSV(w http.ResponseWriter, entries []*database.AuditEntry) {
+	filename := fmt.Sprintf("audit-%s.csv", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write(auditCSVHeader)
+	for _, e := range entries {
+		cw.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339Nano),
+			e.EventType,
+			strconv.FormatBool(e.Success),
+			e.ActorID,
+			e.ActorEmail,
+			e.APIKeyID,
+			e.OrgID,
+			e.TargetType,
+			e.TargetID,
+			e.IPAddress,
+			e.UserAgent,
+			e.Error,
+			formatAuditDetails(e.Details),
+			e.PrevHash,
+			e.Hash,
+		})
+	}
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to write audit export")
+	}
+}
+
+// formatAuditDetails renders details as sorted key=value pairs
+func formatAuditDetails(details map[string]string) string {
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + details[k]
+	}
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+)
+
+func TestAuditEntry_HashChain(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC)
+	first := &database.AuditEntry{
+		EventType: AuditLogin,
+		Success:   true,
+		ActorID:   "user-1",
+		IPAddress: "10.0.0.1",
+		Details:   map[string]string{"b": "2", "a": "1"},
+		CreatedAt: created,
+	}
+	firstHash, err := first.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash() error = %v", err)
+	}
+	first.Hash = firstHash
+
+	// Detail order and time zone must not affect the hash
+	same := *first
+	same.Details = map[string]string{"a": "1", "b": "2"}
+	same.CreatedAt = created.In(time.FixedZone("UTC+2", 2*60*60))
+	if h, _ := same.ComputeHash(); h != firstHash {
+		t.Errorf("Equivalent entry hash = %s, want %s", h, firstHash)
+	}
+
+	tampered := *first
+	tampered.Success = false
+	if h, _ := tampered.ComputeHash(); h == firstHash {
+		t.Error("Changing an entry should change its hash")
+	}
+
+	second := &database.AuditEntry{
+		EventType: AuditLogout,
+		Success:   true,
+		ActorID:   "user-1",
+		CreatedAt: created.Add(time.Minute),
+		PrevHash:  firstHash,
+	}
+	secondHash, _ := second.ComputeHash()
+
+	relinked := *second
+	relinked.PrevHash = ""
+	if h, _ := relinked.ComputeHash(); h == secondHash {
+		t.Error("Changing the previous hash should change an entry's hash")
+	}
+}
+
+func TestParseAuditFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+		check   func(t *testing.T, f *database.AuditFilter)
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			check: func(t *testing.T, f *database.AuditFilter) {
+				if f.Limit != defaultAuditLimit || f.Offset != 0 || f.Success != nil {
+					t.Errorf("Unexpected defaults: %+v", f)
+				}
+			},
+		},
+		{
+			name:  "all filters",
+			query: "event_type=login&actor_id=u1&org_id=o1&target_type=user&target_id=u2&success=false&from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z&limit=10&offset=20",
+			check: func(t *testing.T, f *database.AuditFilter) {
+				if f.EventType != "login" || f.ActorID != "u1" || f.OrgID != "o1" || f.TargetType != "user" || f.TargetID != "u2" {
+					t.Errorf("Unexpected string filters: %+v", f)
+				}
+				if f.Success == nil || *f.Success {
+					t.Error("Expected success=false filter")
+				}
+				if f.From == nil || f.To == nil || !f.From.Before(*f.To) {
+					t.Error("Expected from/to range")
+				}
+				if f.Limit != 10 || f.Offset != 20 {
+					t.Errorf("Limit/offset = %d/%d, want 10/20", f.Limit, f.Offset)
+				}
+			},
+		},
+		{name: "bad success", query: "success=maybe", wantErr: true},
+		{name: "bad from", query: "from=yesterday", wantErr: true},
+		{name: "limit too large", query: "limit=5000", wantErr: true},
+		{name: "negative offset", query: "offset=-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/admin/audit?"+tt.query, nil)
+			f, err := parseAuditFilter(r, maxAuditLimit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAuditFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/ratelimit"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -21,6 +25,9 @@ type Handler struct {
 	db         *database.DB
 	jwtService *JWTService
 	config     *AuthConfig
+	audit      *AuditLogger
+	limiter    ratelimit.Store
+	rateLimits *rateLimitPolicy
 	logger     zerolog.Logger
 }
 
@@ -32,8 +39,11 @@ func NewHandler(db *database.DB, config *AuthConfig, logger zerolog.Logger) *Han
 
 	return &Handler{
 		db:         db,
-		jwtService: NewJWTService(config.JWTSecret, config.JWTExpirationHours),
+		jwtService: newJWTService(config, db, logger),
 		config:     config,
+		audit:      NewAuditLogger(db, logger),
+		limiter:    newRateLimitStore(config),
+		rateLimits: newRateLimitPolicy(config, db, logger),
 		logger:     logger.With().Str("component", "auth-handler").Logger(),
 	}
 }
@@ -47,68 +57,128 @@ func (h *Handler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user from database
-	user, passwordHash, err := h.getUserByEmail(r.Context(), req.Email)
+	rec, err := h.getUserByEmail(r.Context(), req.Email)
 	if err != nil {
+		h.auditLoginFailure(r, req.Email, nil, "unknown user")
 		h.respondError(w, http.StatusUnauthorized, "invalid credentials", nil)
 		return
 	}
+	user := rec.User
 
-	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)); err != nil {
+	// Verify password. Invited users have no password until they accept.
+	if err := bcrypt.CompareHashAndPassword([]byte(rec.passwordHash), []byte(req.Password)); err != nil {
+		h.auditLoginFailure(r, req.Email, user, "invalid password")
 		h.respondError(w, http.StatusUnauthorized, "invalid credentials", nil)
 		return
 	}
 
-	// Check if user is active
+	// Check if user and organization are active
 	if !user.Active {
+		h.auditLoginFailure(r, req.Email, user, "user account is disabled")
 		h.respondError(w, http.StatusUnauthorized, "user account is disabled", nil)
 		return
 	}
+	if !rec.orgActive {
+		h.auditLoginFailure(r, req.Email, user, "organization is disabled")
+		h.respondError(w, http.StatusUnauthorized, "organization is disabled", nil)
+		return
+	}
 
-	// Generate JWT token
-	token, expiresAt, err := h.jwtService.GenerateToken(user)
+	// Generate access and refresh tokens
+	tokens, err := h.jwtService.IssueTokenPair(r.Context(), user)
 	if err != nil {
 		h.respondError(w, http.StatusInternalServerError, "failed to generate token", err)
 		return
 	}
 
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditLogin,
+		Success:    true,
+		ActorID:    user.ID,
+		Email:      user.Email,
+		OrgID:      user.OrgID,
+		TargetType: AuditTargetUser,
+		TargetID:   user.ID,
+	})
+
 	response := LoginResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User:      user,
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.ExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+		User:             user,
 	}
 
 	h.respondJSON(w, http.StatusOK, response)
 }
 
-// HandleRefreshToken refreshes a JWT token
+// HandleRefreshToken exchanges a refresh token for a new token pair
 func (h *Handler) HandleRefreshToken(w http.ResponseWriter, r *http.Request) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		h.respondError(w, http.StatusUnauthorized, "missing authorization header", nil)
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		h.respondError(w, http.StatusBadRequest, "refresh_token is required", nil)
 		return
 	}
 
-	token := ""
-	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		token = authHeader[7:]
-	} else {
-		h.respondError(w, http.StatusUnauthorized, "invalid authorization format", nil)
+	tokens, err := h.jwtService.RefreshToken(r.Context(), req.RefreshToken, h.getActiveUser)
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			h.logger.Warn().Msg("Refresh token reuse detected, token family revoked")
+			h.audit.Record(r, AuditEvent{
+				Type:  AuditTokenRefresh,
+				Error: err.Error(),
+			})
+		}
+		h.respondError(w, http.StatusUnauthorized, "invalid or expired refresh token", err)
 		return
 	}
 
-	newToken, expiresAt, err := h.jwtService.RefreshToken(token)
-	if err != nil {
-		h.respondError(w, http.StatusUnauthorized, "invalid or expired token", err)
+	h.respondJSON(w, http.StatusOK, tokens)
+}
+
+// HandleLogout revokes the current access token and, if given, the refresh
+// token family
+func (h *Handler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	authCtx := GetAuthContext(r)
+	if authCtx == nil || authCtx.TokenClaims == nil {
+		h.respondError(w, http.StatusUnauthorized, "authentication required", nil)
+		return
+	}
+
+	if err := h.jwtService.RevokeToken(r.Context(), authCtx.TokenClaims); err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to revoke token", err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"token":      newToken,
-		"expires_at": expiresAt,
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err == nil && req.RefreshToken != "" {
+		if err := h.jwtService.RevokeRefreshToken(r.Context(), req.RefreshToken); err != nil && !errors.Is(err, ErrRefreshTokenNotFound) {
+			h.respondError(w, http.StatusInternalServerError, "failed to revoke refresh token", err)
+			return
+		}
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	h.logger.Info().
+		Str("user_id", authCtx.UserID).
+		Str("jti", authCtx.TokenClaims.ID).
+		Msg("User logged out")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditLogout,
+		Success:    true,
+		TargetType: AuditTargetUser,
+		TargetID:   authCtx.UserID,
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Logged out successfully",
+	})
+}
+
+// HandleJWKS serves the public keys used to verify access tokens
+func (h *Handler) HandleJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	h.respondJSON(w, http.StatusOK, h.jwtService.JWKS())
 }
 
 // HandleCreateAPIKey creates a new API key
@@ -137,32 +207,48 @@ func (h *Handler) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		expiresAt = &expires
 	}
 
-	// Default permissions from user role if not specified
+	// Default permissions from the caller's permissions if not specified.
+	// A key can never hold a permission its creator lacks.
 	permissions := req.Permissions
 	if len(permissions) == 0 {
-		rolePerms := RolePermissions[Role(authCtx.Role)]
-		permissions = make([]string, len(rolePerms))
-		for i, p := range rolePerms {
+		permissions = make([]string, len(authCtx.Permissions))
+		for i, p := range authCtx.Permissions {
 			permissions[i] = string(p)
 		}
 	}
+	for _, p := range permissions {
+		if !IsKnownPermission(Permission(p)) {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown permission %q", p), nil)
+			return
+		}
+		if !authCtx.HasPermission(Permission(p)) {
+			h.respondError(w, http.StatusForbidden, fmt.Sprintf("cannot grant permission %q", p), nil)
+			return
+		}
+	}
+
+	if err := h.db.CheckQuota(r.Context(), authCtx.OrgID, database.QuotaAPIKeys); err != nil {
+		h.respondQuotaError(w, err)
+		return
+	}
 
 	// Insert into database
 	apiKeyID := uuid.New().String()
 	query := `
 		INSERT INTO api_keys (
-			id, user_id, name, key_hash, permissions,
+			id, user_id, org_id, name, key_hash, permissions,
 			active, expires_at, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	now := time.Now().UTC()
 	_, err := h.db.ExecContext(r.Context(), query,
 		apiKeyID,
 		authCtx.UserID,
+		authCtx.OrgID,
 		req.Name,
 		keyHash,
-		fmt.Sprintf("{%s}", joinStrings(permissions, ",")),
+		pq.Array(permissions),
 		true,
 		expiresAt,
 		now,
@@ -178,6 +264,7 @@ func (h *Handler) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		APIKey: &APIKey{
 			ID:          apiKeyID,
 			UserID:      authCtx.UserID,
+			OrgID:       authCtx.OrgID,
 			Name:        req.Name,
 			Permissions: permissions,
 			Active:      true,
@@ -193,6 +280,17 @@ func (h *Handler) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		Str("user_id", authCtx.UserID).
 		Msg("API key created")
 
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditAPIKeyCreated,
+		Success:    true,
+		TargetType: AuditTargetAPIKey,
+		TargetID:   apiKeyID,
+		Details: map[string]string{
+			"name":        req.Name,
+			"permissions": strings.Join(permissions, ","),
+		},
+	})
+
 	h.respondJSON(w, http.StatusCreated, response)
 }
 
@@ -206,7 +304,7 @@ func (h *Handler) HandleListAPIKeys(w http.ResponseWriter, r *http.Request) {
 
 	query := `
 		SELECT
-			id, user_id, name, permissions, active,
+			id, user_id, org_id, name, permissions, active,
 			expires_at, last_used_at, created_at, updated_at
 		FROM api_keys
 		WHERE user_id = $1
@@ -223,13 +321,13 @@ func (h *Handler) HandleListAPIKeys(w http.ResponseWriter, r *http.Request) {
 	apiKeys := []*APIKey{}
 	for rows.Next() {
 		var apiKey APIKey
-		var permissionsStr string
 
 		err := rows.Scan(
 			&apiKey.ID,
 			&apiKey.UserID,
+			&apiKey.OrgID,
 			&apiKey.Name,
-			&permissionsStr,
+			pq.Array(&apiKey.Permissions),
 			&apiKey.Active,
 			&apiKey.ExpiresAt,
 			&apiKey.LastUsedAt,
@@ -240,11 +338,6 @@ func (h *Handler) HandleListAPIKeys(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Parse permissions (simple split for now)
-		if permissionsStr != "" {
-			apiKey.Permissions = []string{permissionsStr}
-		}
-
 		apiKeys = append(apiKeys, &apiKey)
 	}
 
@@ -289,6 +382,13 @@ func (h *Handler) HandleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 		Str("user_id", authCtx.UserID).
 		Msg("API key revoked")
 
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditAPIKeyRevoked,
+		Success:    true,
+		TargetType: AuditTargetAPIKey,
+		TargetID:   apiKeyID,
+	})
+
 	h.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"message": "API key revoked successfully",
 	})
@@ -302,73 +402,94 @@ func (h *Handler) HandleGetMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, _, err := h.getUserByID(r.Context(), authCtx.UserID)
+	rec, err := h.getUserByID(r.Context(), authCtx.UserID)
 	if err != nil {
 		h.respondError(w, http.StatusNotFound, "user not found", err)
 		return
 	}
 
 	h.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"user": user,
+		"user": rec.User,
 	})
 }
 
 // Private methods
 
-func (h *Handler) getUserByEmail(ctx context.Context, email string) (*User, string, error) {
-	query := `
-		SELECT id, email, name, role, password_hash, active, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
+// auditLoginFailure records a failed login. user is nil if the email is
+// unknown.
+func (h *Handler) auditLoginFailure(r *http.Request, email string, user *User, reason string) {
+	event := AuditEvent{
+		Type:  AuditLogin,
+		Email: email,
+		Error: reason,
+	}
+	if user != nil {
+		event.ActorID = user.ID
+		event.OrgID = user.OrgID
+		event.TargetType = AuditTargetUser
+		event.TargetID = user.ID
+	}
+	h.audit.Record(r, event)
+}
 
-	var user User
-	var passwordHash string
-
-	err := h.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Role,
-		&passwordHash,
-		&user.Active,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+// userRecord is a user with its password hash and organization status
+type userRecord struct {
+	*User
+	passwordHash string
+	orgActive    bool
+}
 
-	if err != nil {
-		return nil, "", err
-	}
+func (h *Handler) getUserByEmail(ctx context.Context, email string) (*userRecord, error) {
+	return h.getUser(ctx, "u.email = $1", email)
+}
 
-	return &user, passwordHash, nil
+func (h *Handler) getUserByID(ctx context.Context, userID string) (*userRecord, error) {
+	return h.getUser(ctx, "u.id = $1", userID)
 }
 
-func (h *Handler) getUserByID(ctx context.Context, userID string) (*User, string, error) {
+func (h *Handler) getUser(ctx context.Context, where string, arg interface{}) (*userRecord, error) {
 	query := `
-		SELECT id, email, name, role, password_hash, active, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-
-	var user User
-	var passwordHash string
-
-	err := h.db.QueryRowContext(ctx, query, userID).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Role,
-		&passwordHash,
-		&user.Active,
-		&user.CreatedAt,
-		&user.UpdatedAt,
+		SELECT u.id, u.org_id, u.email, u.name, u.role, u.password_hash, u.active,
+			u.created_at, u.updated_at, o.active
+		FROM users u
+		JOIN organizations o ON u.org_id = o.id
+		WHERE ` + where
+
+	rec := &userRecord{User: &User{}}
+	err := h.db.QueryRowContext(ctx, query, arg).Scan(
+		&rec.ID,
+		&rec.OrgID,
+		&rec.Email,
+		&rec.Name,
+		&rec.Role,
+		&rec.passwordHash,
+		&rec.Active,
+		&rec.CreatedAt,
+		&rec.UpdatedAt,
+		&rec.orgActive,
 	)
 
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	return &user, passwordHash, nil
+	return rec, nil
+}
+
+// getActiveUser loads a user for token refresh, rejecting disabled accounts
+// and organizations
+func (h *Handler) getActiveUser(ctx context.Context, userID string) (*User, error) {
+	rec, err := h.getUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !rec.Active {
+		return nil, fmt.Errorf("user account is disabled")
+	}
+	if !rec.orgActive {
+		return nil, fmt.Errorf("organization is disabled")
+	}
+	return rec.User, nil
 }
 
 func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -377,6 +498,15 @@ func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{
 	json.NewEncoder(w).Encode(data)
 }
 
+// respondQuotaError responds to a failed quota check
+func (h *Handler) respondQuotaError(w http.ResponseWriter, err error) {
+	if errors.Is(err, database.ErrQuotaExceeded) {
+		h.respondError(w, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+	h.respondError(w, http.StatusInternalServerError, "failed to check organization quota", err)
+}
+
 func (h *Handler) respondError(w http.ResponseWriter, status int, message string, err error) {
 	if err != nil {
 		h.logger.Error().Err(err).Msg(message)
@@ -388,14 +518,3 @@ func (h *Handler) respondError(w http.ResponseWriter, status int, message string
 		"error": message,
 	})
 }
-
-func joinStrings(strs []string, sep string) string {
-	result := ""
-	for i, s := range strs {
-		if i > 0 {
-			result += sep
-		}
-		result += s
-	}
-	return result
-}
@@ -1,7 +1,7 @@
 package auth_test
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -227,23 +227,26 @@ func TestTokenRefresh(t *testing.T) {
 		Role:  string(auth.RoleDeveloper),
 	}
 
-	oldToken, oldExpiry, err := jwtService.GenerateToken(user)
+	oldPair, err := jwtService.IssueTokenPair(context.Background(), user)
 	if err != nil {
-		t.Fatalf("Failed to generate token: %v", err)
+		t.Fatalf("Failed to issue token pair: %v", err)
 	}
 
-	t.Logf("Original token expires at: %v", oldExpiry)
+	t.Logf("Original token expires at: %v", oldPair.ExpiresAt)
 
 	// Refresh the token
-	newToken, newExpiry, err := jwtService.RefreshToken(oldToken)
+	lookup := func(ctx context.Context, userID string) (*auth.User, error) {
+		return user, nil
+	}
+	newPair, err := jwtService.RefreshToken(context.Background(), oldPair.RefreshToken, lookup)
 	if err != nil {
 		t.Fatalf("Failed to refresh token: %v", err)
 	}
 
-	t.Logf("New token expires at: %v", newExpiry)
+	t.Logf("New token expires at: %v", newPair.ExpiresAt)
 
 	// Verify new token is valid
-	claims, err := jwtService.ValidateToken(newToken)
+	claims, err := jwtService.ValidateToken(newPair.AccessToken)
 	if err != nil {
 		t.Fatalf("Failed to validate refreshed token: %v", err)
 	}
@@ -257,9 +260,13 @@ func TestTokenRefresh(t *testing.T) {
 		t.Errorf("Email mismatch: got %s, want %s", claims.Email, user.Email)
 	}
 
-	// New expiry should be later
-	if !newExpiry.After(oldExpiry) {
-		t.Error("Refreshed token should have later expiry")
+	// The refresh token rotates and the old one cannot be used again
+	if newPair.RefreshToken == oldPair.RefreshToken {
+		t.Error("Refresh token should rotate")
+	}
+
+	if _, err := jwtService.RefreshToken(context.Background(), oldPair.RefreshToken, lookup); err == nil {
+		t.Error("Rotated refresh token should be rejected")
 	}
 
 	t.Log("✓ Token refresh completed successfully")
@@ -332,8 +339,8 @@ func TestRolePermissionMatrix(t *testing.T) {
 	t.Log("✓ Role permission matrix validated")
 }
 
-// ExampleAuthenticationFlow demonstrates basic authentication usage
-func ExampleAuthenticationFlow() {
+// Example_authenticationFlow demonstrates basic authentication usage
+func Example_authenticationFlow() {
 	// Create JWT service
 	jwtService := auth.NewJWTService("your-secret-key", 24)
 
@@ -1,30 +1,172 @@
 package auth
 
 import (
+	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-// JWTService handles JWT token operations
+// defaultRefreshExpiry is the refresh token lifetime when none is configured
+const defaultRefreshExpiry = 30 * 24 * time.Hour
+
+// ErrRefreshTokenReused is returned when an already rotated refresh token is
+// presented again. The whole token family is revoked when this happens.
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+// UserLookup loads the current state of a user when refreshing tokens
+type UserLookup func(ctx context.Context, userID string) (*User, error)
+
+// TokenPair is an access token together with its refresh token
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// JWTService handles JWT token operations. Tokens are signed with the
+// current signing key; any key in the key set can verify, which allows keys
+// to be rotated without invalidating outstanding tokens.
 type JWTService struct {
-	secret []byte
-	expiry time.Duration
+	mu           sync.RWMutex
+	keys         map[string]*JWTKey
+	signingKeyID string
+
+	issuer        string
+	audience      string
+	expiry        time.Duration
+	refreshExpiry time.Duration
+	store         TokenStore
 }
 
-// NewJWTService creates a new JWT service
+// NewJWTService creates a new HS256 JWT service with an in-memory token store
 func NewJWTService(secret string, expiryHours int) *JWTService {
+	key := NewHMACKey("", []byte(secret))
+
 	return &JWTService{
-		secret: []byte(secret),
-		expiry: time.Duration(expiryHours) * time.Hour,
+		keys:          map[string]*JWTKey{key.ID: key},
+		signingKeyID:  key.ID,
+		expiry:        time.Duration(expiryHours) * time.Hour,
+		refreshExpiry: defaultRefreshExpiry,
+		store:         NewMemoryTokenStore(),
+	}
+}
+
+// NewJWTServiceFromConfig creates a JWT service from auth configuration. The
+// first configured key signs tokens; the rest only verify. Without
+// configured keys an HS256 key is derived from JWTSecret.
+func NewJWTServiceFromConfig(config *AuthConfig, store TokenStore) (*JWTService, error) {
+	keys := config.JWTKeys
+	if len(keys) == 0 {
+		keys = []*JWTKey{NewHMACKey("", []byte(config.JWTSecret))}
+	}
+
+	if !keys[0].CanSign() {
+		return nil, fmt.Errorf("signing key %s has no private key", keys[0].ID)
+	}
+
+	refreshExpiry := time.Duration(config.RefreshExpirationHours) * time.Hour
+	if refreshExpiry <= 0 {
+		refreshExpiry = defaultRefreshExpiry
+	}
+
+	if store == nil {
+		store = NewMemoryTokenStore()
+	}
+
+	j := &JWTService{
+		keys:          make(map[string]*JWTKey, len(keys)),
+		signingKeyID:  keys[0].ID,
+		issuer:        config.JWTIssuer,
+		audience:      config.JWTAudience,
+		expiry:        time.Duration(config.JWTExpirationHours) * time.Hour,
+		refreshExpiry: refreshExpiry,
+		store:         store,
+	}
+
+	for _, key := range keys {
+		if err := j.AddKey(key); err != nil {
+			return nil, err
+		}
 	}
+
+	return j, nil
 }
 
-// GenerateToken generates a JWT token for a user
+// AddKey adds a key to the verification key set
+func (j *JWTService) AddKey(key *JWTKey) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, exists := j.keys[key.ID]; exists {
+		return fmt.Errorf("duplicate key id %s", key.ID)
+	}
+	j.keys[key.ID] = key
+	return nil
+}
+
+// SetSigningKey makes a key from the key set the signing key
+func (j *JWTService) SetSigningKey(keyID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key, ok := j.keys[keyID]
+	if !ok {
+		return fmt.Errorf("unknown key id %s", keyID)
+	}
+	if !key.CanSign() {
+		return fmt.Errorf("key %s has no private key", keyID)
+	}
+
+	j.signingKeyID = keyID
+	return nil
+}
+
+// RemoveKey removes a retired key. Tokens signed with it stop validating.
+func (j *JWTService) RemoveKey(keyID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if keyID == j.signingKeyID {
+		return fmt.Errorf("cannot remove the signing key")
+	}
+	delete(j.keys, keyID)
+	return nil
+}
+
+// JWKS returns the public keys in the key set. HMAC keys are omitted.
+func (j *JWTService) JWKS() *JWKSet {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	set := &JWKSet{Keys: []JWK{}}
+	for _, key := range j.keys {
+		if jwk, ok := key.JWK(); ok {
+			set.Keys = append(set.Keys, *jwk)
+		}
+	}
+	return set
+}
+
+// GenerateToken generates a JWT access token for a user
 func (j *JWTService) GenerateToken(user *User) (string, time.Time, error) {
 	now := time.Now().UTC()
 	expiresAt := now.Add(j.expiry)
@@ -37,68 +179,108 @@ func (j *JWTService) GenerateToken(user *User) (string, time.Time, error) {
 	}
 
 	claims := JWTClaims{
+		ID:          uuid.New().String(),
+		Issuer:      j.issuer,
+		Subject:     user.ID,
+		Audience:    j.audience,
 		UserID:      user.ID,
+		OrgID:       user.OrgID,
 		Email:       user.Email,
 		Role:        user.Role,
 		Permissions: permStrings,
 		IssuedAt:    now.Unix(),
+		NotBefore:   now.Unix(),
 		ExpiresAt:   expiresAt.Unix(),
 	}
 
-	// Create header
-	header := map[string]string{
-		"alg": "HS256",
-		"typ": "JWT",
+	token, err := j.sign(&claims)
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
-	headerJSON, err := json.Marshal(header)
+	return token, expiresAt, nil
+}
+
+// IssueTokenPair generates an access token and starts a new refresh token family
+func (j *JWTService) IssueTokenPair(ctx context.Context, user *User) (*TokenPair, error) {
+	return j.issueTokenPair(ctx, user, uuid.New().String())
+}
+
+// issueTokenPair generates an access token and a refresh token in the given family
+func (j *JWTService) issueTokenPair(ctx context.Context, user *User, familyID string) (*TokenPair, error) {
+	accessToken, expiresAt, err := j.GenerateToken(user)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to marshal header: %w", err)
+		return nil, err
 	}
 
-	claimsJSON, err := json.Marshal(claims)
+	refreshToken, err := generateRefreshToken()
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to marshal claims: %w", err)
+		return nil, err
 	}
 
-	// Base64 encode
-	headerEncoded := base64.RawURLEncoding.EncodeToString(headerJSON)
-	claimsEncoded := base64.RawURLEncoding.EncodeToString(claimsJSON)
-
-	// Create signature
-	message := headerEncoded + "." + claimsEncoded
-	signature := j.sign(message)
-	signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+	now := time.Now().UTC()
+	rec := &RefreshTokenRecord{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(j.refreshExpiry),
+		CreatedAt: now,
+	}
 
-	// Combine
-	token := message + "." + signatureEncoded
+	if err := j.store.SaveRefreshToken(ctx, rec); err != nil {
+		return nil, err
+	}
 
-	return token, expiresAt, nil
+	return &TokenPair{
+		AccessToken:      accessToken,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: rec.ExpiresAt,
+	}, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// ValidateToken validates a JWT access token and returns the claims
 func (j *JWTService) ValidateToken(token string) (*JWTClaims, error) {
+	return j.ValidateTokenContext(context.Background(), token)
+}
+
+// ValidateTokenContext validates a JWT access token, including its
+// revocation status, and returns the claims
+func (j *JWTService) ValidateTokenContext(ctx context.Context, token string) (*JWTClaims, error) {
 	// Split token
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
 		return nil, fmt.Errorf("invalid token format")
 	}
 
-	headerEncoded := parts[0]
-	claimsEncoded := parts[1]
-	signatureEncoded := parts[2]
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode header: %w", err)
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal header: %w", err)
+	}
 
-	// Verify signature
-	message := headerEncoded + "." + claimsEncoded
-	expectedSignature := j.sign(message)
-	expectedSignatureEncoded := base64.RawURLEncoding.EncodeToString(expectedSignature)
+	key, err := j.verificationKey(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
 
-	if signatureEncoded != expectedSignatureEncoded {
+	// The algorithm is bound to the key, never taken from the token alone
+	if header.Algorithm != key.Algorithm {
+		return nil, fmt.Errorf("algorithm mismatch")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
 		return nil, fmt.Errorf("invalid signature")
 	}
 
 	// Decode claims
-	claimsJSON, err := base64.RawURLEncoding.DecodeString(claimsEncoded)
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode claims: %w", err)
 	}
@@ -108,66 +290,162 @@ func (j *JWTService) ValidateToken(token string) (*JWTClaims, error) {
 		return nil, fmt.Errorf("failed to unmarshal claims: %w", err)
 	}
 
-	// Check expiration
-	if time.Now().UTC().Unix() > claims.ExpiresAt {
+	now := time.Now().UTC().Unix()
+
+	// Check expiration; a token is expired at its exp time (RFC 7519)
+	if now >= claims.ExpiresAt {
 		return nil, fmt.Errorf("token expired")
 	}
 
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, fmt.Errorf("token not yet valid")
+	}
+
+	if j.issuer != "" && claims.Issuer != j.issuer {
+		return nil, fmt.Errorf("invalid issuer")
+	}
+
+	if j.audience != "" && claims.Audience != j.audience {
+		return nil, fmt.Errorf("invalid audience")
+	}
+
+	if claims.ID != "" {
+		revoked, err := j.store.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, fmt.Errorf("token revoked")
+		}
+	}
+
 	return &claims, nil
 }
 
-// sign creates an HMAC signature
-func (j *JWTService) sign(message string) []byte {
-	h := hmac.New(sha256.New, j.secret)
-	h.Write([]byte(message))
-	return h.Sum(nil)
+// RefreshToken exchanges a refresh token for a new token pair. The refresh
+// token is single use: it is rotated on every call, and presenting a
+// rotated token again revokes its whole family.
+func (j *JWTService) RefreshToken(ctx context.Context, refreshToken string, lookup UserLookup) (*TokenPair, error) {
+	rec, err := j.store.ConsumeRefreshToken(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if rec.RevokedAt != nil {
+		return nil, fmt.Errorf("refresh token revoked")
+	}
+
+	if rec.UsedAt != nil {
+		// A rotated token was replayed, so assume the family is compromised
+		if err := j.store.RevokeRefreshFamily(ctx, rec.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().UTC().After(rec.ExpiresAt) {
+		return nil, fmt.Errorf("refresh token expired")
+	}
+
+	// Reload the user so role changes and deactivation take effect
+	user, err := lookup(ctx, rec.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.issueTokenPair(ctx, user, rec.FamilyID)
 }
 
-// RefreshToken generates a new token with extended expiry
-func (j *JWTService) RefreshToken(token string) (string, time.Time, error) {
-	// Validate existing token
-	claims, err := j.ValidateToken(token)
+// RevokeToken revokes an access token until it expires
+func (j *JWTService) RevokeToken(ctx context.Context, claims *JWTClaims) error {
+	if claims.ID == "" {
+		return fmt.Errorf("token has no jti")
+	}
+
+	return j.store.RevokeToken(ctx, claims.ID, claims.UserID, time.Unix(claims.ExpiresAt, 0).UTC())
+}
+
+// RevokeRefreshToken revokes a refresh token and every token rotated from it
+func (j *JWTService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	rec, err := j.store.ConsumeRefreshToken(ctx, hashRefreshToken(refreshToken))
 	if err != nil {
-		// Allow refresh even if expired (within grace period)
-		if !strings.Contains(err.Error(), "token expired") {
-			return "", time.Time{}, err
-		}
+		return err
 	}
 
-	// Create new token with same claims but new expiry
-	now := time.Now().UTC()
-	expiresAt := now.Add(j.expiry)
+	return j.store.RevokeRefreshFamily(ctx, rec.FamilyID)
+}
 
-	claims.IssuedAt = now.Unix()
-	claims.ExpiresAt = expiresAt.Unix()
+// sign encodes and signs claims with the current signing key
+func (j *JWTService) sign(claims *JWTClaims) (string, error) {
+	j.mu.RLock()
+	key := j.keys[j.signingKeyID]
+	j.mu.RUnlock()
 
-	// Create header
-	header := map[string]string{
-		"alg": "HS256",
-		"typ": "JWT",
+	header := jwtHeader{
+		Algorithm: key.Algorithm,
+		Type:      "JWT",
+		KeyID:     key.ID,
 	}
 
 	headerJSON, err := json.Marshal(header)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to marshal header: %w", err)
+		return "", fmt.Errorf("failed to marshal header: %w", err)
 	}
 
 	claimsJSON, err := json.Marshal(claims)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to marshal claims: %w", err)
+		return "", fmt.Errorf("failed to marshal claims: %w", err)
 	}
 
 	// Base64 encode
-	headerEncoded := base64.RawURLEncoding.EncodeToString(headerJSON)
-	claimsEncoded := base64.RawURLEncoding.EncodeToString(claimsJSON)
+	message := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
 
 	// Create signature
-	message := headerEncoded + "." + claimsEncoded
-	signature := j.sign(message)
-	signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+	signature, err := key.sign([]byte(message))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
 
-	// Combine
-	newToken := message + "." + signatureEncoded
+	return message + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// verificationKey returns the key for a kid. Tokens issued before kids were
+// introduced carry none and are checked against the signing key. An unknown
+// kid is reported as an invalid signature.
+func (j *JWTService) verificationKey(keyID string) (*JWTKey, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if keyID == "" {
+		keyID = j.signingKeyID
+	}
+
+	key, ok := j.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return key, nil
+}
+
+// hmacSHA256 creates an HMAC signature
+func hmacSHA256(secret, message []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// generateRefreshToken generates an opaque refresh token
+func generateRefreshToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return "rt_" + hex.EncodeToString(bytes), nil
+}
 
-	return newToken, expiresAt, nil
+// hashRefreshToken returns the stored form of a refresh token
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
 }
@@ -1,6 +1,14 @@
 package auth
 
 import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -165,38 +173,37 @@ func TestJWTService_RefreshToken(t *testing.T) {
 	service := NewJWTService(secret, 1) // 1 hour expiry
 
 	user := &User{
-		ID:    "user-123",
-		Email: "test@example.com",
-		Role:  string(RoleDeveloper),
+		ID:     "user-123",
+		Email:  "test@example.com",
+		Role:   string(RoleDeveloper),
+		Active: true,
+	}
+	lookup := func(ctx context.Context, userID string) (*User, error) {
+		return user, nil
 	}
 
-	// Generate initial token
-	oldToken, oldExpiresAt, err := service.GenerateToken(user)
+	// Issue initial token pair
+	oldPair, err := service.IssueTokenPair(context.Background(), user)
 	if err != nil {
-		t.Fatalf("Failed to generate token: %v", err)
+		t.Fatalf("Failed to issue token pair: %v", err)
 	}
 
-	// Wait a moment
-	time.Sleep(100 * time.Millisecond)
-
-	// Refresh the token
-	newToken, newExpiresAt, err := service.RefreshToken(oldToken)
+	// Refresh with the refresh token
+	newPair, err := service.RefreshToken(context.Background(), oldPair.RefreshToken, lookup)
 	if err != nil {
 		t.Fatalf("Failed to refresh token: %v", err)
 	}
 
-	// Tokens should be different
-	if oldToken == newToken {
-		t.Error("Refreshed token should be different from old token")
+	// Both tokens should rotate
+	if oldPair.AccessToken == newPair.AccessToken {
+		t.Error("Refreshed access token should be different from old token")
 	}
-
-	// New expiry should be later than old expiry
-	if !newExpiresAt.After(oldExpiresAt) {
-		t.Error("Refreshed token expiry should be later than original")
+	if oldPair.RefreshToken == newPair.RefreshToken {
+		t.Error("Refresh token should rotate")
 	}
 
 	// Validate new token
-	claims, err := service.ValidateToken(newToken)
+	claims, err := service.ValidateToken(newPair.AccessToken)
 	if err != nil {
 		t.Fatalf("Failed to validate refreshed token: %v", err)
 	}
@@ -205,6 +212,259 @@ func TestJWTService_RefreshToken(t *testing.T) {
 	if claims.UserID != user.ID {
 		t.Errorf("UserID mismatch after refresh: got %s, want %s", claims.UserID, user.ID)
 	}
+
+	// An access token is not a refresh token
+	if _, err := service.RefreshToken(context.Background(), oldPair.AccessToken, lookup); err == nil {
+		t.Error("Expected refresh with an access token to fail")
+	}
+}
+
+func TestJWTService_RefreshTokenReuse(t *testing.T) {
+	service := NewJWTService("test-secret-key", 1)
+
+	user := &User{ID: "user-123", Email: "test@example.com", Role: string(RoleUser), Active: true}
+	lookup := func(ctx context.Context, userID string) (*User, error) {
+		return user, nil
+	}
+
+	first, err := service.IssueTokenPair(context.Background(), user)
+	if err != nil {
+		t.Fatalf("Failed to issue token pair: %v", err)
+	}
+
+	second, err := service.RefreshToken(context.Background(), first.RefreshToken, lookup)
+	if err != nil {
+		t.Fatalf("Failed to refresh token: %v", err)
+	}
+
+	// Replaying the rotated token is detected
+	if _, err := service.RefreshToken(context.Background(), first.RefreshToken, lookup); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Expected ErrRefreshTokenReused, got: %v", err)
+	}
+
+	// And revokes the rest of the family
+	if _, err := service.RefreshToken(context.Background(), second.RefreshToken, lookup); err == nil {
+		t.Error("Expected refresh token family to be revoked after reuse")
+	}
+}
+
+func TestJWTService_RevokeToken(t *testing.T) {
+	service := NewJWTService("test-secret", 24)
+
+	user := &User{ID: "user-123", Email: "test@example.com", Role: string(RoleUser)}
+
+	token, _, err := service.GenerateToken(user)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	claims, err := service.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate token: %v", err)
+	}
+
+	if claims.ID == "" {
+		t.Fatal("Token should carry a jti")
+	}
+
+	if err := service.RevokeToken(context.Background(), claims); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+
+	_, err = service.ValidateToken(token)
+	if err == nil || err.Error() != "token revoked" {
+		t.Errorf("Expected 'token revoked' error, got: %v", err)
+	}
+}
+
+func TestJWTService_AsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+
+	testCases := []struct {
+		name      string
+		signer    crypto.Signer
+		algorithm string
+		keyType   string
+	}{
+		{"RS256", rsaKey, AlgorithmRS256, "RSA"},
+		{"EdDSA", edKey, AlgorithmEdDSA, "OKP"},
+	}
+
+	user := &User{ID: "user-123", Email: "test@example.com", Role: string(RoleUser)}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := NewSigningKey("", tc.signer)
+			if err != nil {
+				t.Fatalf("Failed to create signing key: %v", err)
+			}
+
+			if key.Algorithm != tc.algorithm {
+				t.Errorf("Algorithm = %s, want %s", key.Algorithm, tc.algorithm)
+			}
+
+			service, err := NewJWTServiceFromConfig(&AuthConfig{
+				JWTKeys:            []*JWTKey{key},
+				JWTExpirationHours: 1,
+			}, nil)
+			if err != nil {
+				t.Fatalf("Failed to create service: %v", err)
+			}
+
+			token, _, err := service.GenerateToken(user)
+			if err != nil {
+				t.Fatalf("Failed to generate token: %v", err)
+			}
+
+			if _, err := service.ValidateToken(token); err != nil {
+				t.Fatalf("Failed to validate token: %v", err)
+			}
+
+			// A tampered payload fails verification
+			parts := strings.Split(token, ".")
+			tampered := parts[0] + "." + parts[1] + "x." + parts[2]
+			if _, err := service.ValidateToken(tampered); err == nil {
+				t.Error("Expected tampered token to fail validation")
+			}
+
+			jwks := service.JWKS()
+			if len(jwks.Keys) != 1 {
+				t.Fatalf("Expected 1 JWK, got %d", len(jwks.Keys))
+			}
+			if jwks.Keys[0].KeyID != key.ID || jwks.Keys[0].KeyType != tc.keyType {
+				t.Errorf("Unexpected JWK: %+v", jwks.Keys[0])
+			}
+		})
+	}
+}
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	oldSigning, err := NewSigningKey("key-1", oldKey)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	newSigning, err := NewSigningKey("key-2", newKey)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	service, err := NewJWTServiceFromConfig(&AuthConfig{
+		JWTKeys:            []*JWTKey{oldSigning},
+		JWTExpirationHours: 1,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	user := &User{ID: "user-123", Email: "test@example.com", Role: string(RoleUser)}
+	oldToken, _, _ := service.GenerateToken(user)
+
+	// Rotate to the new key
+	if err := service.AddKey(newSigning); err != nil {
+		t.Fatalf("Failed to add key: %v", err)
+	}
+	if err := service.SetSigningKey("key-2"); err != nil {
+		t.Fatalf("Failed to set signing key: %v", err)
+	}
+
+	newToken, _, _ := service.GenerateToken(user)
+
+	// Tokens from both keys validate while both are in the key set
+	if _, err := service.ValidateToken(oldToken); err != nil {
+		t.Errorf("Old token should still validate: %v", err)
+	}
+	if _, err := service.ValidateToken(newToken); err != nil {
+		t.Errorf("New token should validate: %v", err)
+	}
+
+	// Retiring the old key invalidates its tokens
+	if err := service.RemoveKey("key-1"); err != nil {
+		t.Fatalf("Failed to remove key: %v", err)
+	}
+	if _, err := service.ValidateToken(oldToken); err == nil {
+		t.Error("Old token should fail after its key is removed")
+	}
+	if err := service.RemoveKey("key-2"); err == nil {
+		t.Error("Removing the signing key should fail")
+	}
+}
+
+func TestJWTService_IssuerAndAudience(t *testing.T) {
+	newService := func(issuer, audience string) *JWTService {
+		service, err := NewJWTServiceFromConfig(&AuthConfig{
+			JWTSecret:          "test-secret",
+			JWTExpirationHours: 1,
+			JWTIssuer:          issuer,
+			JWTAudience:        audience,
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+		return service
+	}
+
+	user := &User{ID: "user-123", Email: "test@example.com", Role: string(RoleUser)}
+	token, _, err := newService("hub", "api").GenerateToken(user)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		issuer   string
+		audience string
+		wantErr  string
+	}{
+		{"matching", "hub", "api", ""},
+		{"wrong issuer", "other", "api", "invalid issuer"},
+		{"wrong audience", "hub", "other", "invalid audience"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newService(tc.issuer, tc.audience).ValidateToken(token)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("Expected %q error, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestJWTService_AlgorithmMismatch(t *testing.T) {
+	service := NewJWTService("test-secret", 1)
+
+	user := &User{ID: "user-123", Email: "test@example.com", Role: string(RoleUser)}
+	token, _, err := service.GenerateToken(user)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	// Swap the header algorithm, keeping the kid
+	parts := strings.Split(token, ".")
+	header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	forged := strings.Replace(string(header), AlgorithmHS256, "none", 1)
+	parts[0] = base64.RawURLEncoding.EncodeToString([]byte(forged))
+
+	_, err = service.ValidateToken(strings.Join(parts, "."))
+	if err == nil || err.Error() != "algorithm mismatch" {
+		t.Errorf("Expected 'algorithm mismatch' error, got: %v", err)
+	}
 }
 
 func TestJWTService_RolePermissions(t *testing.T) {
@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for RS256
+const minRSAKeyBits = 2048
+
+// JWTKey is a key used to sign or verify JWTs, identified by its kid
+type JWTKey struct {
+	ID        string
+	Algorithm string
+
+	secret     []byte
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+// NewHMACKey creates an HS256 key. If id is empty it is derived from the secret.
+func NewHMACKey(id string, secret []byte) *JWTKey {
+	if id == "" {
+		sum := sha256.Sum256(secret)
+		id = "hs256-" + hex.EncodeToString(sum[:8])
+	}
+
+	return &JWTKey{
+		ID:        id,
+		Algorithm: AlgorithmHS256,
+		secret:    secret,
+	}
+}
+
+// NewSigningKey creates an RS256 or EdDSA key from a private key. If id is
+// empty the RFC 7638 thumbprint of the public key is used.
+func NewSigningKey(id string, privateKey crypto.Signer) (*JWTKey, error) {
+	key := &JWTKey{
+		ID:         id,
+		privateKey: privateKey,
+		publicKey:  privateKey.Public(),
+	}
+
+	if err := key.init(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// NewVerificationKey creates an RS256 or EdDSA key that can only verify
+// tokens, e.g. a retired signing key kept during rotation
+func NewVerificationKey(id string, publicKey crypto.PublicKey) (*JWTKey, error) {
+	key := &JWTKey{
+		ID:        id,
+		publicKey: publicKey,
+	}
+
+	if err := key.init(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ParseJWTKeyPEM parses a PEM encoded RSA or Ed25519 key. Private keys can
+// sign and verify; public keys can only verify.
+func ParseJWTKeyPEM(id string, data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		return NewSigningKey(id, privateKey)
+
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
+		}
+		return NewSigningKey(id, signer)
+
+	case "RSA PUBLIC KEY":
+		publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA public key: %w", err)
+		}
+		return NewVerificationKey(id, publicKey)
+
+	case "PUBLIC KEY":
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return NewVerificationKey(id, publicKey)
+
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// LoadJWTKeyFile reads a PEM encoded key from disk
+func LoadJWTKeyFile(id, path string) (*JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+
+	key, err := ParseJWTKeyPEM(id, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key file %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// init sets the algorithm from the public key type and derives the kid
+func (k *JWTKey) init() error {
+	switch pub := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return fmt.Errorf("RSA key must be at least %d bits, got %d", minRSAKeyBits, pub.N.BitLen())
+		}
+		k.Algorithm = AlgorithmRS256
+	case ed25519.PublicKey:
+		k.Algorithm = AlgorithmEdDSA
+	default:
+		return fmt.Errorf("unsupported public key type %T", k.publicKey)
+	}
+
+	if k.ID == "" {
+		thumbprint, err := k.thumbprint()
+		if err != nil {
+			return err
+		}
+		k.ID = thumbprint
+	}
+
+	return nil
+}
+
+// CanSign reports whether the key holds private key material
+func (k *JWTKey) CanSign() bool {
+	return k.secret != nil || k.privateKey != nil
+}
+
+// sign signs a JWT signing input
+func (k *JWTKey) sign(message []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case AlgorithmHS256:
+		return hmacSHA256(k.secret, message), nil
+	case AlgorithmRS256:
+		if k.privateKey == nil {
+			return nil, fmt.Errorf("key %s cannot sign", k.ID)
+		}
+		digest := sha256.Sum256(message)
+		return k.privateKey.Sign(nil, digest[:], crypto.SHA256)
+	case AlgorithmEdDSA:
+		if k.privateKey == nil {
+			return nil, fmt.Errorf("key %s cannot sign", k.ID)
+		}
+		return k.privateKey.Sign(nil, message, crypto.Hash(0))
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", k.Algorithm)
+	}
+}
+
+// verify checks a JWT signature
+func (k *JWTKey) verify(message, signature []byte) bool {
+	switch k.Algorithm {
+	case AlgorithmHS256:
+		return hmac.Equal(hmacSHA256(k.secret, message), signature)
+	case AlgorithmRS256:
+		digest := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(k.publicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	case AlgorithmEdDSA:
+		return ed25519.Verify(k.publicKey.(ed25519.PublicKey), message, signature)
+	default:
+		return false
+	}
+}
+
+// JWK is a JSON Web Key (RFC 7517) for a public verification key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set served to token verifiers
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK returns the public JWK for the key. HMAC keys are never published.
+func (k *JWTKey) JWK() (*JWK, bool) {
+	jwk := &JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Algorithm,
+	}
+
+	switch pub := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return nil, false
+	}
+
+	return jwk, true
+}
+
+// thumbprint returns the RFC 7638 JWK thumbprint of the public key
+func (k *JWTKey) thumbprint() (string, error) {
+	jwk, ok := k.JWK()
+	if !ok {
+		return "", fmt.Errorf("key has no public JWK")
+	}
+
+	// Required members only, in lexicographic order
+	var members interface{}
+	if jwk.KeyType == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
 	"fmt"
 	"net/http"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/ratelimit"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -24,11 +27,13 @@ const (
 
 // Middleware provides authentication middleware
 type Middleware struct {
-	config      *AuthConfig
-	jwtService  *JWTService
-	db          *database.DB
-	logger      zerolog.Logger
-	rateLimiter *RateLimiter
+	config     *AuthConfig
+	jwtService *JWTService
+	db         *database.DB
+	audit      *AuditLogger
+	logger     zerolog.Logger
+	limiter    ratelimit.Store
+	rateLimits *rateLimitPolicy
 }
 
 // NewMiddleware creates a new authentication middleware
@@ -37,15 +42,35 @@ func NewMiddleware(config *AuthConfig, db *database.DB, logger zerolog.Logger) *
 		config = DefaultAuthConfig()
 	}
 
+	logger = logger.With().Str("component", "auth-middleware").Logger()
+
 	return &Middleware{
-		config:      config,
-		jwtService:  NewJWTService(config.JWTSecret, config.JWTExpirationHours),
-		db:          db,
-		logger:      logger.With().Str("component", "auth-middleware").Logger(),
-		rateLimiter: NewRateLimiter(config.RateLimitPerMinute),
+		config:     config,
+		jwtService: newJWTService(config, db, logger),
+		db:         db,
+		audit:      NewAuditLogger(db, logger),
+		logger:     logger,
+		limiter:    newRateLimitStore(config),
+		rateLimits: newRateLimitPolicy(config, db, logger),
 	}
 }
 
+// newJWTService creates the JWT service shared by the middleware and handlers.
+// Revocations and refresh tokens are stored in the database when one is given.
+func newJWTService(config *AuthConfig, db *database.DB, logger zerolog.Logger) *JWTService {
+	var store TokenStore
+	if db != nil {
+		store = NewDBTokenStore(db)
+	}
+
+	jwtService, err := NewJWTServiceFromConfig(config, store)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid JWT configuration")
+	}
+
+	return jwtService
+}
+
 // AuthRequired is middleware that requires authentication
 func (m *Middleware) AuthRequired(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -66,7 +91,7 @@ func (m *Middleware) AuthRequired(next http.Handler) http.Handler {
 		if authHeader != "" {
 			if strings.HasPrefix(authHeader, "Bearer ") {
 				token := strings.TrimPrefix(authHeader, "Bearer ")
-				authCtx, err := m.authenticateJWT(token)
+				authCtx, err := m.authenticateJWT(r.Context(), token)
 				if err != nil {
 					m.logger.Warn().Err(err).Msg("JWT authentication failed")
 					m.respondUnauthorized(w, "Invalid or expired token")
@@ -101,37 +126,16 @@ func (m *Middleware) AuthRequired(next http.Handler) http.Handler {
 	})
 }
 
-// RateLimit is middleware that enforces rate limiting
-func (m *Middleware) RateLimit(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get identifier (user ID, API key, or IP)
-		identifier := m.getIdentifier(r)
-
-		// Check rate limit
-		if !m.rateLimiter.Allow(identifier) {
-			info := m.rateLimiter.GetInfo(identifier)
-			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", info.Limit))
-			w.Header().Set("X-RateLimit-Remaining", "0")
-			w.Header().Set("X-RateLimit-Reset", info.Reset.Format(time.RFC3339))
-
-			m.respondRateLimited(w)
-			return
-		}
-
-		// Add rate limit headers
-		info := m.rateLimiter.GetInfo(identifier)
-		w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", info.Limit))
-		w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", info.Remaining))
-		w.Header().Set("X-RateLimit-Reset", info.Reset.Format(time.RFC3339))
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 // RequirePermission creates middleware that requires specific permissions
 func (m *Middleware) RequirePermission(perm Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Skip checks if auth is not required (development mode)
+			if !m.config.RequireAuth {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			authCtx := GetAuthContext(r)
 			if authCtx == nil {
 				m.respondUnauthorized(w, "Authentication required")
@@ -139,6 +143,19 @@ func (m *Middleware) RequirePermission(perm Permission) func(http.Handler) http.
 			}
 
 			if !authCtx.HasPermission(perm) {
+				m.logger.Warn().
+					Str("user_id", authCtx.UserID).
+					Str("role", authCtx.Role).
+					Str("permission", string(perm)).
+					Str("method", r.Method).
+					Str("path", r.URL.Path).
+					Msg("Permission denied")
+				m.audit.Record(r, AuditEvent{
+					Type:       AuditPermissionDenied,
+					TargetType: AuditTargetRoute,
+					TargetID:   r.Method + " " + r.URL.Path,
+					Error:      "missing permission " + string(perm),
+				})
 				m.respondForbidden(w, "Insufficient permissions")
 				return
 			}
@@ -170,26 +187,67 @@ func (m *Middleware) RequireRole(role Role) func(http.Handler) http.Handler {
 
 // Private methods
 
-func (m *Middleware) authenticateJWT(token string) (*AuthContext, error) {
-	claims, err := m.jwtService.ValidateToken(token)
+func (m *Middleware) authenticateJWT(ctx context.Context, token string) (*AuthContext, error) {
+	claims, err := m.jwtService.ValidateTokenContext(ctx, token)
 	if err != nil {
 		return nil, err
 	}
 
+	// Access tokens outlive deactivation, so the account is checked on
+	// every request like it is for API keys
+	if m.db != nil {
+		if err := m.checkUserActive(ctx, claims.UserID); err != nil {
+			return nil, err
+		}
+	}
+
 	permissions := make([]Permission, len(claims.Permissions))
 	for i, p := range claims.Permissions {
 		permissions[i] = Permission(p)
 	}
 
+	// Tokens issued before organizations existed belong to the default one
+	orgID := claims.OrgID
+	if orgID == "" {
+		orgID = database.DefaultOrganizationID
+	}
+
 	return &AuthContext{
 		UserID:      claims.UserID,
+		OrgID:       orgID,
 		Email:       claims.Email,
 		Role:        claims.Role,
 		Permissions: permissions,
 		AuthType:    AuthTypeJWT,
+		TokenClaims: claims,
 	}, nil
 }
 
+// checkUserActive returns an error unless the user and their organization
+// are active
+func (m *Middleware) checkUserActive(ctx context.Context, userID string) error {
+	query := `
+		SELECT u.active AND o.active
+		FROM users u
+		JOIN organizations o ON u.org_id = o.id
+		WHERE u.id = $1
+	`
+
+	var active bool
+	err := m.db.QueryRowContext(ctx, query, userID).Scan(&active)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check user status: %w", err)
+	}
+	if !active {
+		return fmt.Errorf("user is deactivated")
+	}
+
+	return nil
+}
+
 func (m *Middleware) authenticateAPIKey(ctx context.Context, apiKey string) (*AuthContext, error) {
 	// Hash the API key
 	hash := sha256.Sum256([]byte(apiKey))
@@ -199,27 +257,30 @@ func (m *Middleware) authenticateAPIKey(ctx context.Context, apiKey string) (*Au
 	query := `
 		SELECT
 			ak.id, ak.user_id, ak.permissions, ak.active, ak.expires_at,
-			u.email, u.role
+			u.email, u.role, u.org_id
 		FROM api_keys ak
 		JOIN users u ON ak.user_id = u.id
+		JOIN organizations o ON u.org_id = o.id
 		WHERE ak.key_hash = $1
 		AND ak.active = true
 		AND u.active = true
+		AND o.active = true
 	`
 
-	var apiKeyID, userID, email, role string
+	var apiKeyID, userID, email, role, orgID string
 	var active bool
 	var expiresAt *time.Time
-	var permissionsJSON []byte
+	var keyPermissions []string
 
 	err := m.db.QueryRowContext(ctx, query, keyHash).Scan(
 		&apiKeyID,
 		&userID,
-		&permissionsJSON,
+		pq.Array(&keyPermissions),
 		&active,
 		&expiresAt,
 		&email,
 		&role,
+		&orgID,
 	)
 
 	if err != nil {
@@ -231,28 +292,14 @@ func (m *Middleware) authenticateAPIKey(ctx context.Context, apiKey string) (*Au
 		return nil, fmt.Errorf("API key expired")
 	}
 
-	// Parse permissions
-	var permStrings []string
-	if len(permissionsJSON) > 0 {
-		// Assuming PostgreSQL array format
-		// For simplicity, get permissions from role
-		perms := RolePermissions[Role(role)]
-		permStrings = make([]string, len(perms))
-		for i, p := range perms {
-			permStrings[i] = string(p)
-		}
-	}
-
-	permissions := make([]Permission, len(permStrings))
-	for i, p := range permStrings {
-		permissions[i] = Permission(p)
-	}
+	permissions := apiKeyPermissions(keyPermissions, Role(role))
 
 	// Update last used timestamp
 	go m.updateAPIKeyLastUsed(apiKeyID)
 
 	return &AuthContext{
 		UserID:      userID,
+		OrgID:       orgID,
 		Email:       email,
 		Role:        role,
 		Permissions: permissions,
@@ -261,6 +308,27 @@ func (m *Middleware) authenticateAPIKey(ctx context.Context, apiKey string) (*Au
 	}, nil
 }
 
+// apiKeyPermissions returns the permissions granted to an API key: those it
+// was created with that its owner's role still holds. Keys created without
+// explicit permissions get the role's permissions.
+func apiKeyPermissions(keyPermissions []string, role Role) []Permission {
+	rolePerms := RolePermissions[role]
+	if len(keyPermissions) == 0 {
+		return append([]Permission(nil), rolePerms...)
+	}
+
+	permissions := make([]Permission, 0, len(keyPermissions))
+	for _, p := range keyPermissions {
+		for _, rp := range rolePerms {
+			if Permission(p) == rp {
+				permissions = append(permissions, rp)
+				break
+			}
+		}
+	}
+	return permissions
+}
+
 func (m *Middleware) updateAPIKeyLastUsed(apiKeyID string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -281,7 +349,14 @@ func (m *Middleware) isPublicEndpoint(path string) bool {
 	return false
 }
 
+// getIdentifier identifies the caller for rate limiting
 func (m *Middleware) getIdentifier(r *http.Request) string {
+	return rateLimitSubject(r)
+}
+
+// rateLimitSubject identifies the caller for rate limiting: its API key,
+// user, or IP address if unauthenticated
+func rateLimitSubject(r *http.Request) string {
 	authCtx := GetAuthContext(r)
 	if authCtx != nil {
 		if authCtx.APIKeyID != "" {
@@ -293,7 +368,7 @@ func (m *Middleware) getIdentifier(r *http.Request) string {
 	}
 
 	// Fallback to IP address
-	return "ip:" + r.RemoteAddr
+	return "ip:" + clientIP(r)
 }
 
 func (m *Middleware) respondUnauthorized(w http.ResponseWriter, message string) {
@@ -332,7 +407,10 @@ func SetAuthContext(ctx context.Context, authCtx *AuthContext) context.Context {
 	return context.WithValue(ctx, AuthContextKey, authCtx)
 }
 
-// RateLimiter implements token bucket rate limiting
+// RateLimiter implements in-process fixed window rate limiting.
+//
+// Deprecated: limits are not shared between replicas. The middleware uses
+// a ratelimit.Store instead.
 type RateLimiter struct {
 	limit   int
 	buckets map[string]*bucket
@@ -6,6 +6,10 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/rs/zerolog"
 )
 
 // Mock database for testing
@@ -268,7 +272,7 @@ func TestMiddleware_GetIdentifier(t *testing.T) {
 			setupReq: func(r *http.Request) {
 				r.RemoteAddr = "192.168.1.1:12345"
 			},
-			expected: "ip:192.168.1.1:12345",
+			expected: "ip:192.168.1.1",
 		},
 	}
 
@@ -353,6 +357,47 @@ func TestGetAuthContext(t *testing.T) {
 	}
 }
 
+func TestMiddleware_AuthenticateJWT_RejectsDeactivatedUser(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer conn.Close()
+
+	config := DefaultAuthConfig()
+	config.JWTSecret = "test-secret-key"
+	middleware := NewMiddleware(config, &database.DB{DB: conn}, zerolog.Nop())
+
+	token, _, err := middleware.jwtService.GenerateToken(&User{
+		ID:    "user-123",
+		Email: "test@example.com",
+		Role:  string(RoleDeveloper),
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	for _, active := range []bool{true, false} {
+		mock.ExpectQuery("SELECT EXISTS").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectQuery("FROM users u").
+			WithArgs("user-123").
+			WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(active))
+
+		authCtx, err := middleware.authenticateJWT(context.Background(), token)
+		if active && (err != nil || authCtx.UserID != "user-123") {
+			t.Errorf("Expected active user to authenticate, got %v", err)
+		}
+		if !active && err == nil {
+			t.Error("Expected token of deactivated user to be rejected")
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet expectations: %v", err)
+	}
+}
+
 func TestRolePermissions(t *testing.T) {
 	// Test that all roles have defined permissions
 	for role, perms := range RolePermissions {
@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/ratelimit"
+	"github.com/rs/zerolog"
+)
+
+// planCacheTTL is how long plan assignments are cached, and so how long a
+// plan change takes to reach every replica
+const planCacheTTL = time.Minute
+
+// rateLimitCheck is one limit a request is counted against
+type rateLimitCheck struct {
+	subject string
+	limit   ratelimit.Limit
+}
+
+// rateLimitPolicy decides which limits apply to a request. The caller (API
+// key, user or IP address) is limited by its API key's plan, or the default
+// limit. If the caller's organization has a plan, the organization's
+// combined traffic is limited by it too.
+type rateLimitPolicy struct {
+	db           *database.DB
+	defaultLimit int
+	logger       zerolog.Logger
+
+	plans map[string]cachedPlan
+	mu    sync.Mutex
+}
+
+type cachedPlan struct {
+	plan    *database.RateLimitPlan
+	expires time.Time
+}
+
+func newRateLimitPolicy(config *AuthConfig, db *database.DB, logger zerolog.Logger) *rateLimitPolicy {
+	return &rateLimitPolicy{
+		db:           db,
+		defaultLimit: config.RateLimitPerMinute,
+		logger:       logger,
+		plans:        make(map[string]cachedPlan),
+	}
+}
+
+// newRateLimitStore returns the configured store, or an in-process store
+// if none is configured
+func newRateLimitStore(config *AuthConfig) ratelimit.Store {
+	if config.RateLimitStore != nil {
+		return config.RateLimitStore
+	}
+	return ratelimit.NewMemoryStore()
+}
+
+// checks returns the limits a request is counted against
+func (p *rateLimitPolicy) checks(ctx context.Context, r *http.Request, class ratelimit.Class) []rateLimitCheck {
+	authCtx := GetAuthContext(r)
+	caller := rateLimitCheck{
+		subject: rateLimitSubject(r),
+		limit:   ratelimit.Limit{PerMinute: p.defaultLimit},
+	}
+	if authCtx == nil {
+		return []rateLimitCheck{caller}
+	}
+
+	if authCtx.APIKeyID != "" {
+		if plan := p.plan(ctx, "key:"+authCtx.APIKeyID); plan != nil {
+			caller.limit = planLimit(plan, class)
+		}
+	}
+	checks := []rateLimitCheck{caller}
+
+	if authCtx.OrgID != "" {
+		if plan := p.plan(ctx, "org:"+authCtx.OrgID); plan != nil {
+			checks = append(checks, rateLimitCheck{
+				subject: "org:" + authCtx.OrgID,
+				limit:   planLimit(plan, class),
+			})
+		}
+	}
+
+	return checks
+}
+
+// plan returns the plan assigned to "key:<id>" or "org:<id>", caching the
+// result. Lookup failures are logged and treated as no plan.
+func (p *rateLimitPolicy) plan(ctx context.Context, owner string) *database.RateLimitPlan {
+	if p.db == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	cached, ok := p.plans[owner]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.plan
+	}
+
+	var plan *database.RateLimitPlan
+	var err error
+	if id, isKey := strings.CutPrefix(owner, "key:"); isKey {
+		plan, err = p.db.GetAPIKeyRateLimitPlan(ctx, id)
+	} else if id, isOrg := strings.CutPrefix(owner, "org:"); isOrg {
+		plan, err = p.db.GetOrganizationRateLimitPlan(ctx, id)
+	}
+	if err != nil {
+		p.logger.Warn().Err(err).Str("owner", owner).Msg("Failed to load rate limit plan")
+		return cached.plan
+	}
+
+	p.mu.Lock()
+	p.plans[owner] = cachedPlan{plan: plan, expires: time.Now().Add(planCacheTTL)}
+	p.mu.Unlock()
+
+	return plan
+}
+
+// RateLimit is middleware that enforces the caller's and organization's
+// rate limits. It must run after authentication so plans can be applied.
+// If the rate limit store is unreachable, requests are let through.
+func (m *Middleware) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		class := ratelimit.ClassForMethod(r.Method)
+		if m.enforceRateLimits(w, r, class, m.rateLimits.checks(r.Context(), r, class)) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// IPRateLimit is middleware that limits each IP address. It runs before
+// authentication, so requests with bad or missing credentials are counted
+// as well as authenticated ones.
+func (m *Middleware) IPRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.config.IPRateLimitPerMinute <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		class := ratelimit.ClassForMethod(r.Method)
+		check := rateLimitCheck{
+			subject: "preauth:ip:" + clientIP(r),
+			limit:   ratelimit.Limit{PerMinute: m.config.IPRateLimitPerMinute},
+		}
+		if m.enforceRateLimits(w, r, class, []rateLimitCheck{check}) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// enforceRateLimits counts a request against each check, sets the rate
+// limit headers from the tightest one and reports whether the request may
+// proceed. A rejected request has already been answered.
+func (m *Middleware) enforceRateLimits(w http.ResponseWriter, r *http.Request, class ratelimit.Class, checks []rateLimitCheck) bool {
+	var tightest *ratelimit.Result
+	for _, check := range checks {
+		res, err := m.limiter.Allow(r.Context(), check.subject, class, check.limit)
+		if err != nil {
+			m.logger.Error().Err(err).Str("subject", check.subject).Msg("Rate limit check failed")
+			ratelimit.RecordError()
+			continue
+		}
+
+		if tightest == nil || !res.Allowed || res.Remaining < tightest.Remaining {
+			tightest = res
+		}
+		if !res.Allowed {
+			break
+		}
+	}
+
+	if tightest == nil {
+		return true
+	}
+
+	ratelimit.RecordDecision(class, tightest.Allowed)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(tightest.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+	w.Header().Set("X-RateLimit-Reset", time.Now().Add(tightest.ResetAfter).UTC().Format(time.RFC3339))
+
+	if !tightest.Allowed {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int(tightest.RetryAfter.Seconds())+1))
+		m.respondRateLimited(w)
+		return false
+	}
+	return true
+}
+
+// planLimit returns a plan's limit for a route class
+func planLimit(plan *database.RateLimitPlan, class ratelimit.Class) ratelimit.Limit {
+	perMinute := plan.ReadPerMinute
+	if class == ratelimit.ClassWrite {
+		perMinute = plan.WritePerMinute
+	}
+	return ratelimit.Limit{PerMinute: perMinute, Burst: plan.Burst}
+}
@@ -0,0 +1,364 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultUsageHours is the usage history returned by default
+	defaultUsageHours = 24
+	// maxUsageHours is the longest usage history kept
+	maxUsageHours = 7 * 24
+)
+
+// Usage handlers
+
+// HandleGetUsage returns the caller's rate limits and recent usage, and its
+// organization's if the organization has a plan
+func (h *Handler) HandleGetUsage(w http.ResponseWriter, r *http.Request) {
+	authCtx := GetAuthContext(r)
+	if authCtx == nil {
+		h.respondError(w, http.StatusUnauthorized, "authentication required", nil)
+		return
+	}
+
+	since, ok := h.usageSince(w, r)
+	if !ok {
+		return
+	}
+
+	var keyPlan *database.RateLimitPlan
+	if authCtx.APIKeyID != "" {
+		keyPlan = h.rateLimits.plan(r.Context(), "key:"+authCtx.APIKeyID)
+	}
+
+	caller, err := h.rateLimitUsage(r.Context(), rateLimitSubject(r), keyPlan, since)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get usage", err)
+		return
+	}
+	response := map[string]interface{}{
+		"caller": caller,
+	}
+
+	if authCtx.OrgID != "" {
+		if orgPlan := h.rateLimits.plan(r.Context(), "org:"+authCtx.OrgID); orgPlan != nil {
+			org, err := h.rateLimitUsage(r.Context(), "org:"+authCtx.OrgID, orgPlan, since)
+			if err != nil {
+				h.respondError(w, http.StatusInternalServerError, "failed to get usage", err)
+				return
+			}
+			response["organization"] = org
+		}
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// HandleGetAPIKeyUsage returns the limits and recent usage of one of the
+// caller's API keys
+func (h *Handler) HandleGetAPIKeyUsage(w http.ResponseWriter, r *http.Request) {
+	authCtx := GetAuthContext(r)
+	if authCtx == nil {
+		h.respondError(w, http.StatusUnauthorized, "authentication required", nil)
+		return
+	}
+
+	apiKeyID := mux.Vars(r)["id"]
+
+	var exists int
+	err := h.db.QueryRowContext(r.Context(),
+		`SELECT 1 FROM api_keys WHERE id = $1 AND user_id = $2`,
+		apiKeyID, authCtx.UserID,
+	).Scan(&exists)
+	if err == sql.ErrNoRows {
+		h.respondError(w, http.StatusNotFound, "API key not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get API key", err)
+		return
+	}
+
+	since, ok := h.usageSince(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.db.GetAPIKeyRateLimitPlan(r.Context(), apiKeyID)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get rate limit plan", err)
+		return
+	}
+
+	usage, err := h.rateLimitUsage(r.Context(), "key:"+apiKeyID, plan, since)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get usage", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, usage)
+}
+
+// usageSince parses the hours query parameter into the start of the usage
+// history to return
+func (h *Handler) usageSince(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	hours := defaultUsageHours
+	if hoursStr := r.URL.Query().Get("hours"); hoursStr != "" {
+		parsed, err := strconv.Atoi(hoursStr)
+		if err != nil || parsed < 1 || parsed > maxUsageHours {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("hours must be between 1 and %d", maxUsageHours), nil)
+			return time.Time{}, false
+		}
+		hours = parsed
+	}
+	return time.Now().Add(-time.Duration(hours) * time.Hour), true
+}
+
+// rateLimitUsage describes a subject's limits under a plan, or the default
+// limit if plan is nil, along with its usage since the given time
+func (h *Handler) rateLimitUsage(ctx context.Context, subject string, plan *database.RateLimitPlan, since time.Time) (*RateLimitUsage, error) {
+	usage, err := h.limiter.Usage(ctx, subject, since)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &RateLimitUsage{
+		Subject:        subject,
+		Plan:           plan,
+		ReadPerMinute:  h.rateLimits.defaultLimit,
+		WritePerMinute: h.rateLimits.defaultLimit,
+		Usage:          usage,
+	}
+	if plan != nil {
+		resp.ReadPerMinute = plan.ReadPerMinute
+		resp.WritePerMinute = plan.WritePerMinute
+		resp.Burst = plan.Burst
+	}
+
+	return resp, nil
+}
+
+// Plan handlers
+
+// HandleListPlans lists all rate limit plans
+func (h *Handler) HandleListPlans(w http.ResponseWriter, r *http.Request) {
+	plans, err := h.db.ListRateLimitPlans(r.Context())
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to list rate limit plans", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"plans": plans,
+		"count": len(plans),
+	})
+}
+
+// HandleCreatePlan creates a rate limit plan
+func (h *Handler) HandleCreatePlan(w http.ResponseWriter, r *http.Request) {
+	var req RateLimitPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if req.Name == nil || req.ReadPerMinute == nil || req.WritePerMinute == nil {
+		h.respondError(w, http.StatusBadRequest, "name, read_per_minute and write_per_minute are required", nil)
+		return
+	}
+
+	plan := &database.RateLimitPlan{ID: "plan-" + uuid.New().String()}
+	if err := applyPlanRequest(plan, &req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := h.db.CreateRateLimitPlan(r.Context(), plan); err != nil {
+		if isUniqueViolation(err) {
+			h.respondError(w, http.StatusConflict, "a plan with this name already exists", nil)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "failed to create rate limit plan", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("plan_id", plan.ID).
+		Str("admin_id", adminID(r)).
+		Msg("Rate limit plan created")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditPlanCreated,
+		Success:    true,
+		TargetType: AuditTargetPlan,
+		TargetID:   plan.ID,
+		Details:    planAuditDetails(plan),
+	})
+
+	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"plan": plan,
+	})
+}
+
+// HandleUpdatePlan updates a rate limit plan. Changes reach every replica
+// within a minute.
+func (h *Handler) HandleUpdatePlan(w http.ResponseWriter, r *http.Request) {
+	var req RateLimitPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	plan, err := h.db.GetRateLimitPlan(r.Context(), mux.Vars(r)["id"])
+	if errors.Is(err, database.ErrPlanNotFound) {
+		h.respondError(w, http.StatusNotFound, "rate limit plan not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get rate limit plan", err)
+		return
+	}
+
+	if err := applyPlanRequest(plan, &req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := h.db.UpdateRateLimitPlan(r.Context(), plan); err != nil {
+		if isUniqueViolation(err) {
+			h.respondError(w, http.StatusConflict, "a plan with this name already exists", nil)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "failed to update rate limit plan", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("plan_id", plan.ID).
+		Str("admin_id", adminID(r)).
+		Msg("Rate limit plan updated")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditPlanUpdated,
+		Success:    true,
+		TargetType: AuditTargetPlan,
+		TargetID:   plan.ID,
+		Details:    planAuditDetails(plan),
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"plan": plan,
+	})
+}
+
+// HandleSetAPIKeyPlan assigns a rate limit plan to an API key
+func (h *Handler) HandleSetAPIKeyPlan(w http.ResponseWriter, r *http.Request) {
+	var req AssignPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if !h.checkPlanExists(w, r, req.PlanID) {
+		return
+	}
+
+	apiKeyID := mux.Vars(r)["id"]
+	if err := h.db.SetAPIKeyRateLimitPlan(r.Context(), apiKeyID, req.PlanID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.respondError(w, http.StatusNotFound, "API key not found", nil)
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "failed to set API key plan", err)
+		return
+	}
+
+	h.logger.Info().
+		Str("api_key_id", apiKeyID).
+		Str("plan_id", req.PlanID).
+		Str("admin_id", adminID(r)).
+		Msg("API key plan changed")
+
+	h.audit.Record(r, AuditEvent{
+		Type:       AuditAPIKeyPlanChanged,
+		Success:    true,
+		TargetType: AuditTargetAPIKey,
+		TargetID:   apiKeyID,
+		Details:    map[string]string{"plan_id": req.PlanID},
+	})
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"api_key_id": apiKeyID,
+		"plan_id":    req.PlanID,
+	})
+}
+
+// checkPlanExists responds with an error and returns false if planID is set
+// but names no plan
+func (h *Handler) checkPlanExists(w http.ResponseWriter, r *http.Request, planID string) bool {
+	if planID == "" {
+		return true
+	}
+
+	_, err := h.db.GetRateLimitPlan(r.Context(), planID)
+	if errors.Is(err, database.ErrPlanNotFound) {
+		h.respondError(w, http.StatusBadRequest, "rate limit plan not found", nil)
+		return false
+	}
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "failed to get rate limit plan", err)
+		return false
+	}
+	return true
+}
+
+// applyPlanRequest copies the set fields of a plan request onto a plan
+func applyPlanRequest(plan *database.RateLimitPlan, req *RateLimitPlanRequest) error {
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return errors.New("name cannot be empty")
+		}
+		plan.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.ReadPerMinute != nil {
+		if *req.ReadPerMinute < 1 {
+			return errors.New("read_per_minute must be positive")
+		}
+		plan.ReadPerMinute = *req.ReadPerMinute
+	}
+	if req.WritePerMinute != nil {
+		if *req.WritePerMinute < 1 {
+			return errors.New("write_per_minute must be positive")
+		}
+		plan.WritePerMinute = *req.WritePerMinute
+	}
+	if req.Burst != nil {
+		if *req.Burst < 0 {
+			return errors.New("burst cannot be negative")
+		}
+		plan.Burst = *req.Burst
+	}
+	return nil
+}
+
+func planAuditDetails(plan *database.RateLimitPlan) map[string]string {
+	return map[string]string{
+		"name":             plan.Name,
+		"read_per_minute":  strconv.Itoa(plan.ReadPerMinute),
+		"write_per_minute": strconv.Itoa(plan.WritePerMinute),
+		"burst":            strconv.Itoa(plan.Burst),
+	}
+}
@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+)
+
+// ErrRefreshTokenNotFound is returned for unknown refresh tokens
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// RefreshTokenRecord is a stored refresh token. Only the token hash is kept.
+// Tokens issued by rotating one another share a family ID.
+type RefreshTokenRecord struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// TokenStore persists access token revocations and refresh tokens
+type TokenStore interface {
+	// RevokeToken revokes an access token by jti until it expires
+	RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	// IsTokenRevoked reports whether an access token has been revoked
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// SaveRefreshToken stores a newly issued refresh token
+	SaveRefreshToken(ctx context.Context, rec *RefreshTokenRecord) error
+	// ConsumeRefreshToken atomically marks a refresh token as used and
+	// returns it as it was before the call, so a UsedAt value means the
+	// token had already been used
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (*RefreshTokenRecord, error)
+	// RevokeRefreshFamily revokes every refresh token in a family
+	RevokeRefreshFamily(ctx context.Context, familyID string) error
+}
+
+// MemoryTokenStore is an in-process TokenStore for tests and single-node development
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+	refresh map[string]*RefreshTokenRecord
+}
+
+// NewMemoryTokenStore creates a new in-memory token store
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		revoked: make(map[string]time.Time),
+		refresh: make(map[string]*RefreshTokenRecord),
+	}
+}
+
+// RevokeToken revokes an access token by jti
+func (s *MemoryTokenStore) RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revoked[jti] = expiresAt
+	return nil
+}
+
+// IsTokenRevoked reports whether an access token has been revoked
+func (s *MemoryTokenStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.revoked[jti]
+	if ok && time.Now().After(expiresAt) {
+		delete(s.revoked, jti)
+	}
+	return ok, nil
+}
+
+// SaveRefreshToken stores a newly issued refresh token
+func (s *MemoryTokenStore) SaveRefreshToken(ctx context.Context, rec *RefreshTokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *rec
+	s.refresh[rec.TokenHash] = &stored
+	return nil
+}
+
+// ConsumeRefreshToken marks a refresh token as used
+func (s *MemoryTokenStore) ConsumeRefreshToken(ctx context.Context, tokenHash string) (*RefreshTokenRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.refresh[tokenHash]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	before := *rec
+	if rec.UsedAt == nil {
+		now := time.Now().UTC()
+		rec.UsedAt = &now
+	}
+	return &before, nil
+}
+
+// RevokeRefreshFamily revokes every refresh token in a family
+func (s *MemoryTokenStore) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, rec := range s.refresh {
+		if rec.FamilyID == familyID && rec.RevokedAt == nil {
+			rec.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// DBTokenStore is a TokenStore backed by the revoked_tokens and
+// refresh_tokens tables
+type DBTokenStore struct {
+	db *database.DB
+}
+
+// NewDBTokenStore creates a new database token store
+func NewDBTokenStore(db *database.DB) *DBTokenStore {
+	return &DBTokenStore{db: db}
+}
+
+// RevokeToken revokes an access token by jti
+func (s *DBTokenStore) RevokeToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, jti, userID, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	return nil
+}
+
+// IsTokenRevoked reports whether an access token has been revoked
+func (s *DBTokenStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`
+
+	var revoked bool
+	if err := s.db.QueryRowContext(ctx, query, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// SaveRefreshToken stores a newly issued refresh token
+func (s *DBTokenStore) SaveRefreshToken(ctx context.Context, rec *RefreshTokenRecord) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := s.db.ExecContext(ctx, query,
+		rec.ID,
+		rec.UserID,
+		rec.FamilyID,
+		rec.TokenHash,
+		rec.ExpiresAt,
+		rec.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeRefreshToken marks a refresh token as used
+func (s *DBTokenStore) ConsumeRefreshToken(ctx context.Context, tokenHash string) (*RefreshTokenRecord, error) {
+	// The CTE locks the row and captures used_at before the update, so
+	// concurrent refreshes with the same token see it as already used
+	query := `
+		WITH prev AS (
+			SELECT id, used_at FROM refresh_tokens
+			WHERE token_hash = $1
+			FOR UPDATE
+		)
+		UPDATE refresh_tokens rt
+		SET used_at = COALESCE(rt.used_at, NOW())
+		FROM prev
+		WHERE rt.id = prev.id
+		RETURNING rt.id, rt.user_id, rt.family_id, rt.token_hash, rt.expires_at,
+			rt.created_at, prev.used_at, rt.revoked_at
+	`
+
+	var rec RefreshTokenRecord
+	err := s.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&rec.ID,
+		&rec.UserID,
+		&rec.FamilyID,
+		&rec.TokenHash,
+		&rec.ExpiresAt,
+		&rec.CreatedAt,
+		&rec.UsedAt,
+		&rec.RevokedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// RevokeRefreshFamily revokes every refresh token in a family
+func (s *DBTokenStore) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/ratelimit"
 )
 
 // AuthType represents the type of authentication
@@ -18,6 +21,7 @@ const (
 // User represents an authenticated user
 type User struct {
 	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	Role      string    `json:"role"`
@@ -30,6 +34,7 @@ type User struct {
 type APIKey struct {
 	ID          string     `json:"id"`
 	UserID      string     `json:"user_id"`
+	OrgID       string     `json:"org_id"`
 	Name        string     `json:"name"`
 	Key         string     `json:"key"`
 	KeyHash     string     `json:"-"` // Never expose hash
@@ -43,11 +48,17 @@ type APIKey struct {
 
 // JWTClaims represents JWT token claims
 type JWTClaims struct {
+	ID          string   `json:"jti,omitempty"`
+	Issuer      string   `json:"iss,omitempty"`
+	Subject     string   `json:"sub,omitempty"`
+	Audience    string   `json:"aud,omitempty"`
 	UserID      string   `json:"user_id"`
+	OrgID       string   `json:"org_id,omitempty"`
 	Email       string   `json:"email"`
 	Role        string   `json:"role"`
 	Permissions []string `json:"permissions"`
 	IssuedAt    int64    `json:"iat"`
+	NotBefore   int64    `json:"nbf,omitempty"`
 	ExpiresAt   int64    `json:"exp"`
 }
 
@@ -55,26 +66,43 @@ type JWTClaims struct {
 type AuthConfig struct {
 	JWTSecret          string
 	JWTExpirationHours int
-	APIKeyEnabled      bool
-	RequireAuth        bool
-	PublicEndpoints    []string
+	JWTIssuer          string
+	JWTAudience        string
+	// JWTKeys overrides JWTSecret. The first key signs; the rest only verify.
+	JWTKeys                []*JWTKey
+	RefreshExpirationHours int
+	APIKeyEnabled          bool
+	RequireAuth            bool
+	PublicEndpoints        []string
+	// RateLimitPerMinute is the default per-caller limit for callers
+	// without a plan
 	RateLimitPerMinute int
+	// IPRateLimitPerMinute limits each IP address before authentication,
+	// so callers failing authentication are limited too. It is shared by
+	// every caller behind an address, so it should sit well above
+	// RateLimitPerMinute. 0 disables it.
+	IPRateLimitPerMinute int
+	// RateLimitStore holds rate limit state shared between replicas. If
+	// nil, limits are kept in process.
+	RateLimitStore ratelimit.Store
 }
 
 // DefaultAuthConfig returns default authentication configuration
 func DefaultAuthConfig() *AuthConfig {
 	return &AuthConfig{
-		JWTSecret:          generateSecret(32),
-		JWTExpirationHours: 24,
-		APIKeyEnabled:      true,
-		RequireAuth:        true,
+		JWTSecret:              generateSecret(32),
+		JWTExpirationHours:     24,
+		JWTIssuer:              "articium-hub",
+		JWTAudience:            "articium-api",
+		RefreshExpirationHours: 720,
+		APIKeyEnabled:          true,
+		RequireAuth:            true,
 		PublicEndpoints: []string{
 			"/health",
 			"/ready",
-			"/v1/chains",
-			"/v1/routes/estimate",
 		},
-		RateLimitPerMinute: 100,
+		RateLimitPerMinute:   100,
+		IPRateLimitPerMinute: 600,
 	}
 }
 
@@ -122,6 +150,7 @@ var RolePermissions = map[Role][]Permission{
 		PermissionReadMessages,
 		PermissionWriteMessages,
 		PermissionReadBatches,
+		PermissionWriteBatches,
 		PermissionReadWebhooks,
 		PermissionWriteWebhooks,
 		PermissionReadRoutes,
@@ -132,6 +161,7 @@ var RolePermissions = map[Role][]Permission{
 		PermissionReadMessages,
 		PermissionWriteMessages,
 		PermissionReadBatches,
+		PermissionWriteBatches,
 		PermissionReadRoutes,
 		PermissionReadStats,
 	},
@@ -151,9 +181,16 @@ type LoginRequest struct {
 
 // LoginResponse represents a login response
 type LoginResponse struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
-	User      *User     `json:"user"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	User             *User     `json:"user"`
+}
+
+// RefreshTokenRequest represents a token refresh or logout request
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // CreateAPIKeyRequest represents a request to create an API key
@@ -169,20 +206,96 @@ type CreateAPIKeyResponse struct {
 	Key    string  `json:"key"` // Only returned on creation
 }
 
+// CreateUserRequest represents a request to create a user. Without a
+// password the user is invited and must set one with the returned token.
+type CreateUserRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	OrgID    string `json:"org_id,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// UpdateUserRequest represents a request to update a user. Omitted fields
+// are left unchanged.
+type UpdateUserRequest struct {
+	Name   *string `json:"name,omitempty"`
+	Role   *string `json:"role,omitempty"`
+	Active *bool   `json:"active,omitempty"`
+}
+
+// PasswordTokenResponse returns a single-use invite or password reset token
+type PasswordTokenResponse struct {
+	User      *User     `json:"user"`
+	Token     string    `json:"token"`
+	Purpose   string    `json:"purpose"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResetPasswordRequest sets a password using an invite or reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// OrganizationRequest represents a request to create or update an
+// organization. Omitted fields are left unchanged on update.
+type OrganizationRequest struct {
+	Name             *string `json:"name,omitempty"`
+	Slug             *string `json:"slug,omitempty"`
+	Active           *bool   `json:"active,omitempty"`
+	MaxUsers         *int    `json:"max_users,omitempty"`
+	MaxAPIKeys       *int    `json:"max_api_keys,omitempty"`
+	MaxWebhooks      *int    `json:"max_webhooks,omitempty"`
+	MaxDailyMessages *int    `json:"max_daily_messages,omitempty"`
+	PlanID           *string `json:"plan_id,omitempty"` // "" removes the plan
+}
+
+// RateLimitPlanRequest represents a request to create or update a rate
+// limit plan. Omitted fields are left unchanged on update.
+type RateLimitPlanRequest struct {
+	Name           *string `json:"name,omitempty"`
+	ReadPerMinute  *int    `json:"read_per_minute,omitempty"`
+	WritePerMinute *int    `json:"write_per_minute,omitempty"`
+	Burst          *int    `json:"burst,omitempty"`
+}
+
+// AssignPlanRequest assigns a rate limit plan. An empty PlanID removes it.
+type AssignPlanRequest struct {
+	PlanID string `json:"plan_id"`
+}
+
+// RateLimitUsage is a rate limited subject's limits and recent usage
+type RateLimitUsage struct {
+	Subject        string                  `json:"subject"`
+	Plan           *database.RateLimitPlan `json:"plan,omitempty"`
+	ReadPerMinute  int                     `json:"read_per_minute"`
+	WritePerMinute int                     `json:"write_per_minute"`
+	Burst          int                     `json:"burst"`
+	Usage          []ratelimit.Usage       `json:"usage"`
+}
+
 // AuthContext represents authentication context in requests
 type AuthContext struct {
 	UserID      string
+	OrgID       string
 	Email       string
 	Role        string
 	Permissions []Permission
 	AuthType    AuthType
 	APIKeyID    string
+	TokenClaims *JWTClaims // set for JWT auth
+}
+
+// IsAdmin checks if context belongs to an admin
+func (ac *AuthContext) IsAdmin() bool {
+	return ac.Role == string(RoleAdmin)
 }
 
 // HasPermission checks if context has a specific permission
 func (ac *AuthContext) HasPermission(perm Permission) bool {
-	// Admin has all permissions
-	if ac.Role == string(RoleAdmin) {
+	// Admin has all permissions, except through an API key scoped to fewer
+	if ac.Role == string(RoleAdmin) && ac.AuthType != AuthTypeAPIKey {
 		return true
 	}
 
@@ -204,6 +317,22 @@ func (ac *AuthContext) HasAnyPermission(perms ...Permission) bool {
 	return false
 }
 
+// IsValidRole reports whether role is a defined role
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[Role(role)]
+	return ok
+}
+
+// IsKnownPermission reports whether perm is a defined permission
+func IsKnownPermission(perm Permission) bool {
+	for _, p := range RolePermissions[RoleAdmin] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
 // RateLimitInfo represents rate limit information
 type RateLimitInfo struct {
 	Limit     int       `json:"limit"`
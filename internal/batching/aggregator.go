@@ -9,6 +9,7 @@ import (
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
 	"github.com/rs/zerolog"
 )
 
@@ -20,6 +21,7 @@ type Aggregator struct {
 	mu             sync.RWMutex
 	pendingBatches map[string]*Batch // key: "sourceChain-destChain"
 	optimizer      *Optimizer
+	notifier       *webhooks.Notifier
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
 }
@@ -40,6 +42,7 @@ func NewAggregator(
 		logger:         logger.With().Str("component", "batch-aggregator").Logger(),
 		pendingBatches: make(map[string]*Batch),
 		optimizer:      NewOptimizer(config, logger),
+		notifier:       webhooks.NewQueueNotifier(db, logger),
 		stopChan:       make(chan struct{}),
 	}
 }
@@ -196,9 +199,15 @@ func (a *Aggregator) submitBatch(ctx context.Context, batch *Batch) error {
 
 	// Store batch in database
 	if err := a.storeBatch(ctx, batch, merkleData); err != nil {
+		a.notifier.NotifyBatchFailed(ctx, batch.ID, len(batch.Messages), err.Error())
 		return fmt.Errorf("failed to store batch: %w", err)
 	}
 
+	a.notifier.NotifyBatchCreated(ctx, batch.ID, len(batch.Messages))
+	for _, msg := range batch.Messages {
+		a.notifier.RecordMessageBatched(ctx, msg, batch.ID)
+	}
+
 	// TODO: Send batch to relayer for on-chain submission
 	// This will be handled by the batcher service
 
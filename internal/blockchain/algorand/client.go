@@ -1,51 +1,447 @@
 package algorand
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/rpcpool"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/rs/zerolog"
 )
 
-// Client represents an Algorand blockchain client
+// indexerPageSize is the number of transactions requested per indexer page
+const indexerPageSize = 1000
+
+// Client represents an Algorand blockchain client. Node state and
+// transaction submission go through algod; application history is read
+// from the indexer.
 type Client struct {
-	config *types.ChainConfig
-	logger zerolog.Logger
-	// TODO: Add actual Algorand SDK client when implementing
+	config     *types.ChainConfig
+	httpClient *http.Client
+	pool       *rpcpool.Pool
+	indexer    *rpcpool.Pool
+	logger     zerolog.Logger
 }
 
 // NewClient creates a new Algorand client
 func NewClient(config *types.ChainConfig, logger zerolog.Logger) (*Client, error) {
+	if config.ChainType != types.ChainTypeAlgorand {
+		return nil, fmt.Errorf("invalid chain type: expected ALGORAND, got %s", config.ChainType)
+	}
+	if len(config.RPCEndpoints) == 0 {
+		return nil, fmt.Errorf("at least one algod endpoint is required")
+	}
+
 	client := &Client{
 		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
 		logger: logger.With().Str("chain", config.Name).Str("type", "algorand").Logger(),
 	}
 
-	client.logger.Info().Msg("Algorand client initialized (placeholder)")
+	pool, err := rpcpool.New(config.Name, config.RPCEndpoints, rpcpool.Config{
+		Quorum:     config.RPCQuorum,
+		MaxHeadLag: config.RPCMaxHeadLag,
+		Head: func(ctx context.Context, e *rpcpool.Endpoint) (uint64, error) {
+			var status NodeStatus
+			if err := client.request(ctx, e, http.MethodGet, "/v2/status", nil, nil, &status); err != nil {
+				return 0, err
+			}
+			return status.LastRound, nil
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	client.pool = pool
+
+	if config.IndexerEndpoint != "" {
+		indexer, err := rpcpool.New(config.Name, []string{config.IndexerEndpoint}, rpcpool.Config{}, logger)
+		if err != nil {
+			return nil, err
+		}
+		client.indexer = indexer
+	}
+
+	client.logger.Info().
+		Int("endpoints", len(config.RPCEndpoints)).
+		Str("indexer", config.IndexerEndpoint).
+		Str("network", config.NetworkID).
+		Msg("Algorand client initialized")
+
 	return client, nil
 }
 
+// APIError is an error response from algod or the indexer
+type APIError struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err means the requested resource does not
+// exist, e.g. a transaction unknown to the node
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// NodeStatus is the response of algod's /v2/status
+type NodeStatus struct {
+	LastRound          uint64 `json:"last-round"`
+	CatchupTime        uint64 `json:"catchup-time"`
+	TimeSinceLastRound uint64 `json:"time-since-last-round"`
+}
+
+// Block is the header of a block returned by algod's /v2/blocks
+type Block struct {
+	Round        uint64            `json:"rnd"`
+	Timestamp    int64             `json:"ts"`
+	Transactions []json.RawMessage `json:"txns"`
+}
+
+// TransactionParams are the parameters needed to build a transaction
+type TransactionParams struct {
+	Fee         uint64 `json:"fee"`
+	MinFee      uint64 `json:"min-fee"`
+	GenesisID   string `json:"genesis-id"`
+	GenesisHash []byte `json:"genesis-hash"`
+	LastRound   uint64 `json:"last-round"`
+}
+
+// PendingTransaction is algod's view of a recently submitted transaction
+type PendingTransaction struct {
+	ConfirmedRound uint64   `json:"confirmed-round"`
+	PoolError      string   `json:"pool-error"`
+	Logs           [][]byte `json:"logs"`
+}
+
+// Account is the balance information of an account
+type Account struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+// AssetHolding is an account's balance of an asset
+type AssetHolding struct {
+	AssetID uint64 `json:"asset-id"`
+	Amount  uint64 `json:"amount"`
+}
+
+// ApplicationTransaction holds the application call fields of a transaction
+type ApplicationTransaction struct {
+	ApplicationID uint64 `json:"application-id"`
+}
+
+// Transaction is a confirmed transaction returned by the indexer
+type Transaction struct {
+	ID                     string                  `json:"id"`
+	Sender                 string                  `json:"sender"`
+	TxType                 string                  `json:"tx-type"`
+	ConfirmedRound         uint64                  `json:"confirmed-round"`
+	RoundTime              int64                   `json:"round-time"`
+	IntraRoundOffset       uint64                  `json:"intra-round-offset"`
+	ApplicationTransaction *ApplicationTransaction `json:"application-transaction,omitempty"`
+	Logs                   [][]byte                `json:"logs"`
+	InnerTxns              []Transaction           `json:"inner-txns"`
+}
+
+// request performs a request against an endpoint. Client errors other
+// than rate limiting are the endpoint's answer and are not retried on other
+// endpoints, since they would answer the same.
+func (c *Client) request(ctx context.Context, e *rpcpool.Endpoint, method, path string, query url.Values, body []byte, out interface{}) error {
+	endpoint := e.URL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return rpcpool.Permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-binary")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return rpcpool.Permanent(apiErr)
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return rpcpool.Permanent(fmt.Errorf("failed to unmarshal response: %w", err))
+	}
+	return nil
+}
+
+// send performs a request against the best endpoint of a pool that answers
+func (c *Client) send(ctx context.Context, pool *rpcpool.Pool, method, path string, query url.Values, body []byte, out interface{}) error {
+	return pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		return c.request(ctx, e, method, path, query, body, out)
+	})
+}
+
+// algod performs a GET request against algod
+func (c *Client) algod(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.send(ctx, c.pool, http.MethodGet, path, query, nil, out)
+}
+
+// indexerGet performs a GET request against the indexer
+func (c *Client) indexerGet(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if c.indexer == nil {
+		return fmt.Errorf("indexer endpoint not configured")
+	}
+	return c.send(ctx, c.indexer, http.MethodGet, path, query, nil, out)
+}
+
+// GetStatus gets the node status
+func (c *Client) GetStatus(ctx context.Context) (*NodeStatus, error) {
+	var status NodeStatus
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		if err := c.request(ctx, e, http.MethodGet, "/v2/status", nil, nil, &status); err != nil {
+			return err
+		}
+		c.pool.ObserveHead(e, status.LastRound)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	return &status, nil
+}
+
 // GetLatestRound gets the latest round number
 func (c *Client) GetLatestRound(ctx context.Context) (uint64, error) {
-	// TODO: Implement actual Algorand API call
-	return 0, fmt.Errorf("not implemented yet")
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return status.LastRound, nil
+}
+
+// GetBlock gets the block of a round
+func (c *Client) GetBlock(ctx context.Context, round uint64) (*Block, error) {
+	var resp struct {
+		Block Block `json:"block"`
+	}
+	query := url.Values{"format": {"json"}}
+	if err := c.algod(ctx, fmt.Sprintf("/v2/blocks/%d", round), query, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", round, err)
+	}
+	return &resp.Block, nil
 }
 
 // GetBlockByRound gets block by round number
 func (c *Client) GetBlockByRound(ctx context.Context, round uint64) (*types.BlockInfo, error) {
-	// TODO: Implement actual Algorand API call
-	return nil, fmt.Errorf("not implemented yet")
+	block, err := c.GetBlock(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	var hash struct {
+		BlockHash string `json:"blockHash"`
+	}
+	if err := c.algod(ctx, fmt.Sprintf("/v2/blocks/%d/hash", round), nil, &hash); err != nil {
+		return nil, fmt.Errorf("failed to get block hash %d: %w", round, err)
+	}
+
+	return &types.BlockInfo{
+		Number:    block.Round,
+		Hash:      hash.BlockHash,
+		Timestamp: time.Unix(block.Timestamp, 0),
+		TxCount:   len(block.Transactions),
+	}, nil
 }
 
-// IsHealthy checks if the client is healthy
+// GetTransactionParams gets the suggested parameters for a new transaction
+func (c *Client) GetTransactionParams(ctx context.Context) (*TransactionParams, error) {
+	var params TransactionParams
+	if err := c.algod(ctx, "/v2/transactions/params", nil, &params); err != nil {
+		return nil, fmt.Errorf("failed to get transaction params: %w", err)
+	}
+	return &params, nil
+}
+
+// SendRawTransaction submits an encoded signed transaction and returns its
+// ID
+func (c *Client) SendRawTransaction(ctx context.Context, signedTxn []byte) (string, error) {
+	var resp struct {
+		TxID string `json:"txId"`
+	}
+	if err := c.send(ctx, c.pool, http.MethodPost, "/v2/transactions", nil, signedTxn, &resp); err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return resp.TxID, nil
+}
+
+// GetPendingTransaction gets a transaction from the node's pool, or one the
+// node confirmed recently
+func (c *Client) GetPendingTransaction(ctx context.Context, txID string) (*PendingTransaction, error) {
+	var pending PendingTransaction
+	query := url.Values{"format": {"json"}}
+	if err := c.algod(ctx, "/v2/transactions/pending/"+url.PathEscape(txID), query, &pending); err != nil {
+		return nil, fmt.Errorf("failed to get pending transaction: %w", err)
+	}
+	return &pending, nil
+}
+
+// WaitForConfirmation waits until a submitted transaction is confirmed.
+// Algorand has instant finality, so a confirmed transaction is final.
+func (c *Client) WaitForConfirmation(ctx context.Context, txID string, timeout time.Duration) (*PendingTransaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(c.config.GetBlockTimeDuration())
+	defer ticker.Stop()
+
+	for {
+		pending, err := c.GetPendingTransaction(ctx, txID)
+		if err != nil {
+			return nil, err
+		}
+		if pending.PoolError != "" {
+			return nil, fmt.Errorf("transaction rejected: %s", pending.PoolError)
+		}
+		if pending.ConfirmedRound > 0 {
+			return pending, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout waiting for confirmation: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetAccount gets an account's balance information
+func (c *Client) GetAccount(ctx context.Context, address string) (*Account, error) {
+	var account Account
+	query := url.Values{"exclude": {"all"}}
+	if err := c.algod(ctx, "/v2/accounts/"+url.PathEscape(address), query, &account); err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return &account, nil
+}
+
+// GetAssetHolding gets an account's balance of an asset
+func (c *Client) GetAssetHolding(ctx context.Context, address string, assetID uint64) (*AssetHolding, error) {
+	var resp struct {
+		AssetHolding AssetHolding `json:"asset-holding"`
+	}
+	path := fmt.Sprintf("/v2/accounts/%s/assets/%d", url.PathEscape(address), assetID)
+	if err := c.algod(ctx, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get asset holding: %w", err)
+	}
+	return &resp.AssetHolding, nil
+}
+
+// GetIndexerRound gets the latest round the indexer has imported
+func (c *Client) GetIndexerRound(ctx context.Context) (uint64, error) {
+	var health struct {
+		Round uint64 `json:"round"`
+	}
+	if err := c.indexerGet(ctx, "/health", nil, &health); err != nil {
+		return 0, fmt.Errorf("failed to get indexer health: %w", err)
+	}
+	return health.Round, nil
+}
+
+// GetTransaction gets a confirmed transaction from the indexer
+func (c *Client) GetTransaction(ctx context.Context, txID string) (*Transaction, error) {
+	var resp struct {
+		Transaction Transaction `json:"transaction"`
+	}
+	if err := c.indexerGet(ctx, "/v2/transactions/"+url.PathEscape(txID), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &resp.Transaction, nil
+}
+
+// SearchApplicationTransactions returns the confirmed transactions that
+// called an application between two rounds, inclusive, in the order they
+// were confirmed. Transactions that called the application through an inner
+// transaction are returned as their top-level transaction.
+func (c *Client) SearchApplicationTransactions(ctx context.Context, appID, minRound, maxRound uint64) ([]Transaction, error) {
+	query := url.Values{
+		"application-id": {strconv.FormatUint(appID, 10)},
+		"min-round":      {strconv.FormatUint(minRound, 10)},
+		"max-round":      {strconv.FormatUint(maxRound, 10)},
+		"limit":          {strconv.Itoa(indexerPageSize)},
+	}
+
+	var txns []Transaction
+	for {
+		var resp struct {
+			Transactions []Transaction `json:"transactions"`
+			NextToken    string        `json:"next-token"`
+		}
+		if err := c.indexerGet(ctx, "/v2/transactions", query, &resp); err != nil {
+			return nil, fmt.Errorf("failed to search transactions: %w", err)
+		}
+
+		txns = append(txns, resp.Transactions...)
+		if resp.NextToken == "" || len(resp.Transactions) < indexerPageSize {
+			return txns, nil
+		}
+		query.Set("next", resp.NextToken)
+	}
+}
+
+// IsHealthy checks if the client is healthy. A node that is catching up
+// serves stale state and is reported unhealthy.
 func (c *Client) IsHealthy(ctx context.Context) bool {
-	// TODO: Implement actual health check
-	return true
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		c.logger.Warn().Err(err).Msg("Health check failed")
+		return false
+	}
+	return status.CatchupTime == 0
 }
 
 // Close closes the client
 func (c *Client) Close() error {
 	c.logger.Info().Msg("Closing Algorand client")
+	c.pool.Close()
+	if c.indexer != nil {
+		c.indexer.Close()
+	}
+	c.httpClient.CloseIdleConnections()
 	return nil
 }
@@ -0,0 +1,252 @@
+package algorand
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+)
+
+// transactionPrefix is prepended to an encoded transaction before it is
+// hashed or signed
+var transactionPrefix = []byte("TX")
+
+var addressEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EncodeAddress encodes a public key as an Algorand address
+func EncodeAddress(publicKey [32]byte) string {
+	checksum := sha512.Sum512_256(publicKey[:])
+	addr := make([]byte, 0, 36)
+	addr = append(addr, publicKey[:]...)
+	addr = append(addr, checksum[28:]...)
+	return addressEncoding.EncodeToString(addr)
+}
+
+// DecodeAddress decodes an Algorand address to its public key
+func DecodeAddress(addr string) ([32]byte, error) {
+	var publicKey [32]byte
+
+	decoded, err := addressEncoding.DecodeString(addr)
+	if err != nil {
+		return publicKey, fmt.Errorf("invalid address encoding: %w", err)
+	}
+	if len(decoded) != 36 {
+		return publicKey, fmt.Errorf("invalid address length: %d", len(decoded))
+	}
+
+	copy(publicKey[:], decoded[:32])
+	checksum := sha512.Sum512_256(publicKey[:])
+	if !bytes.Equal(checksum[28:], decoded[32:]) {
+		return publicKey, fmt.Errorf("invalid address checksum")
+	}
+
+	return publicKey, nil
+}
+
+// MethodSelector returns the 4-byte selector of an ARC-4 method or ARC-28
+// event signature, e.g. "unlock(byte[32],address,uint64,uint64,byte[])void"
+func MethodSelector(signature string) [4]byte {
+	var selector [4]byte
+	hash := sha512.Sum512_256([]byte(signature))
+	copy(selector[:], hash[:4])
+	return selector
+}
+
+// Uint64Arg encodes an ARC-4 uint64 argument
+func Uint64Arg(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+// BytesArg encodes an ARC-4 byte[] argument
+func BytesArg(b []byte) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(b))), b...)
+}
+
+// ApplicationCallTxn is a NoOp application call transaction
+type ApplicationCallTxn struct {
+	Sender          [32]byte
+	Fee             uint64
+	FirstValid      uint64
+	LastValid       uint64
+	GenesisID       string
+	GenesisHash     [32]byte
+	ApplicationID   uint64
+	ApplicationArgs [][]byte
+	Accounts        [][32]byte
+	ForeignAssets   []uint64
+	Note            []byte
+}
+
+// Encode returns the canonical msgpack encoding of the transaction: keys
+// sorted and empty fields omitted, as the network requires for the
+// transaction ID and signature to match
+func (t *ApplicationCallTxn) Encode() []byte {
+	var fields []msgpackField
+	if len(t.ApplicationArgs) > 0 {
+		fields = append(fields, msgpackField{"apaa", func(w *msgpackWriter) {
+			w.arrayHeader(len(t.ApplicationArgs))
+			for _, arg := range t.ApplicationArgs {
+				w.bin(arg)
+			}
+		}})
+	}
+	if len(t.ForeignAssets) > 0 {
+		fields = append(fields, msgpackField{"apas", func(w *msgpackWriter) {
+			w.arrayHeader(len(t.ForeignAssets))
+			for _, asset := range t.ForeignAssets {
+				w.uint(asset)
+			}
+		}})
+	}
+	if len(t.Accounts) > 0 {
+		fields = append(fields, msgpackField{"apat", func(w *msgpackWriter) {
+			w.arrayHeader(len(t.Accounts))
+			for _, account := range t.Accounts {
+				w.bin(account[:])
+			}
+		}})
+	}
+	if t.ApplicationID != 0 {
+		fields = append(fields, msgpackField{"apid", func(w *msgpackWriter) { w.uint(t.ApplicationID) }})
+	}
+	if t.Fee != 0 {
+		fields = append(fields, msgpackField{"fee", func(w *msgpackWriter) { w.uint(t.Fee) }})
+	}
+	if t.FirstValid != 0 {
+		fields = append(fields, msgpackField{"fv", func(w *msgpackWriter) { w.uint(t.FirstValid) }})
+	}
+	if t.GenesisID != "" {
+		fields = append(fields, msgpackField{"gen", func(w *msgpackWriter) { w.str(t.GenesisID) }})
+	}
+	if t.GenesisHash != [32]byte{} {
+		fields = append(fields, msgpackField{"gh", func(w *msgpackWriter) { w.bin(t.GenesisHash[:]) }})
+	}
+	if t.LastValid != 0 {
+		fields = append(fields, msgpackField{"lv", func(w *msgpackWriter) { w.uint(t.LastValid) }})
+	}
+	if len(t.Note) > 0 {
+		fields = append(fields, msgpackField{"note", func(w *msgpackWriter) { w.bin(t.Note) }})
+	}
+	if t.Sender != [32]byte{} {
+		fields = append(fields, msgpackField{"snd", func(w *msgpackWriter) { w.bin(t.Sender[:]) }})
+	}
+	fields = append(fields, msgpackField{"type", func(w *msgpackWriter) { w.str("appl") }})
+
+	var w msgpackWriter
+	w.fields(fields)
+	return w.buf.Bytes()
+}
+
+// BytesToSign returns the bytes the sender signs
+func (t *ApplicationCallTxn) BytesToSign() []byte {
+	return append(append([]byte{}, transactionPrefix...), t.Encode()...)
+}
+
+// ID returns the transaction ID
+func (t *ApplicationCallTxn) ID() string {
+	hash := sha512.Sum512_256(t.BytesToSign())
+	return addressEncoding.EncodeToString(hash[:])
+}
+
+// EncodeSignedTxn encodes a transaction with the sender's signature for
+// submission
+func EncodeSignedTxn(txn *ApplicationCallTxn, signature []byte) []byte {
+	encoded := txn.Encode()
+
+	var w msgpackWriter
+	w.fields([]msgpackField{
+		{"sig", func(w *msgpackWriter) { w.bin(signature) }},
+		{"txn", func(w *msgpackWriter) { w.buf.Write(encoded) }},
+	})
+	return w.buf.Bytes()
+}
+
+// msgpackField is a map entry written by msgpackWriter.fields
+type msgpackField struct {
+	key   string
+	write func(w *msgpackWriter)
+}
+
+// msgpackWriter writes the subset of msgpack used by transactions, always
+// choosing the smallest encoding as canonical msgpack requires
+type msgpackWriter struct {
+	buf bytes.Buffer
+}
+
+// fields writes a map whose entries are already sorted by key
+func (w *msgpackWriter) fields(fields []msgpackField) {
+	w.mapHeader(len(fields))
+	for _, field := range fields {
+		w.str(field.key)
+		field.write(w)
+	}
+}
+
+func (w *msgpackWriter) mapHeader(n int) {
+	switch {
+	case n < 16:
+		w.buf.WriteByte(0x80 | byte(n))
+	default:
+		w.buf.WriteByte(0xde)
+		w.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	}
+}
+
+func (w *msgpackWriter) arrayHeader(n int) {
+	switch {
+	case n < 16:
+		w.buf.WriteByte(0x90 | byte(n))
+	default:
+		w.buf.WriteByte(0xdc)
+		w.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	}
+}
+
+func (w *msgpackWriter) str(s string) {
+	switch {
+	case len(s) < 32:
+		w.buf.WriteByte(0xa0 | byte(len(s)))
+	case len(s) < 1<<8:
+		w.buf.WriteByte(0xd9)
+		w.buf.WriteByte(byte(len(s)))
+	default:
+		w.buf.WriteByte(0xda)
+		w.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(s))))
+	}
+	w.buf.WriteString(s)
+}
+
+func (w *msgpackWriter) bin(b []byte) {
+	switch {
+	case len(b) < 1<<8:
+		w.buf.WriteByte(0xc4)
+		w.buf.WriteByte(byte(len(b)))
+	case len(b) < 1<<16:
+		w.buf.WriteByte(0xc5)
+		w.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(b))))
+	default:
+		w.buf.WriteByte(0xc6)
+		w.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(len(b))))
+	}
+	w.buf.Write(b)
+}
+
+func (w *msgpackWriter) uint(v uint64) {
+	switch {
+	case v < 1<<7:
+		w.buf.WriteByte(byte(v))
+	case v < 1<<8:
+		w.buf.WriteByte(0xcc)
+		w.buf.WriteByte(byte(v))
+	case v < 1<<16:
+		w.buf.WriteByte(0xcd)
+		w.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(v)))
+	case v < 1<<32:
+		w.buf.WriteByte(0xce)
+		w.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(v)))
+	default:
+		w.buf.WriteByte(0xcf)
+		w.buf.Write(binary.BigEndian.AppendUint64(nil, v))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/algorand"
@@ -59,26 +60,90 @@ func (a *AlgorandClientAdapter) GetConfirmationBlocks() uint64 {
 	return a.config.ConfirmationBlocks
 }
 
+// SendTransaction submits an encoded signed transaction
 func (a *AlgorandClientAdapter) SendTransaction(ctx context.Context, tx interface{}) (string, error) {
-	return "", fmt.Errorf("not implemented")
+	signedTxn, ok := tx.([]byte)
+	if !ok {
+		return "", fmt.Errorf("invalid transaction type: expected encoded signed transaction, got %T", tx)
+	}
+	return a.client.SendRawTransaction(ctx, signedTxn)
 }
 
 func (a *AlgorandClientAdapter) GetTransactionStatus(ctx context.Context, txHash string) (*types.TransactionStatus, error) {
-	return nil, fmt.Errorf("not implemented")
+	pending, err := a.client.GetPendingTransaction(ctx, txHash)
+	if err == nil {
+		// Confirmed transactions are final, and a transaction that fails
+		// is rejected by the pool rather than included
+		return &types.TransactionStatus{
+			Hash:        txHash,
+			BlockNumber: pending.ConfirmedRound,
+			Success:     pending.ConfirmedRound > 0,
+			Confirmed:   pending.ConfirmedRound > 0,
+			Finalized:   pending.ConfirmedRound > 0,
+			Error:       pending.PoolError,
+		}, nil
+	}
+	if !algorand.IsNotFound(err) {
+		return nil, err
+	}
+
+	// The node only remembers recent transactions
+	txn, err := a.client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TransactionStatus{
+		Hash:        txHash,
+		BlockNumber: txn.ConfirmedRound,
+		Success:     true,
+		Confirmed:   true,
+		Finalized:   true,
+		Timestamp:   time.Unix(txn.RoundTime, 0),
+	}, nil
 }
 
 func (a *AlgorandClientAdapter) WaitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) error {
-	return fmt.Errorf("not implemented")
+	_, err := a.client.WaitForConfirmation(ctx, txHash, timeout)
+	return err
 }
 
 func (a *AlgorandClientAdapter) GetNativeBalance(ctx context.Context, address string) (*big.Int, error) {
-	return nil, fmt.Errorf("not implemented")
+	account, err := a.client.GetAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(account.Amount), nil
 }
 
+// GetTokenBalance gets an account's balance of an ASA, identified by its
+// asset ID
 func (a *AlgorandClientAdapter) GetTokenBalance(ctx context.Context, address string, tokenAddress string) (*big.Int, error) {
-	return nil, fmt.Errorf("not implemented")
+	assetID, err := strconv.ParseUint(tokenAddress, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset ID: %s", tokenAddress)
+	}
+
+	holding, err := a.client.GetAssetHolding(ctx, address, assetID)
+	if err != nil {
+		if algorand.IsNotFound(err) {
+			// The account has not opted in to the asset
+			return big.NewInt(0), nil
+		}
+		return nil, err
+	}
+	return new(big.Int).SetUint64(holding.Amount), nil
 }
 
 func (a *AlgorandClientAdapter) SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error) {
-	return nil, fmt.Errorf("not implemented")
+	return nil, types.ErrSubscriptionNotSupported
+}
+
+// GetTransactionParams gets the suggested parameters for a new transaction
+func (a *AlgorandClientAdapter) GetTransactionParams(ctx context.Context) (*algorand.TransactionParams, error) {
+	return a.client.GetTransactionParams(ctx)
+}
+
+// GetUnderlyingClient returns the underlying Algorand client
+func (a *AlgorandClientAdapter) GetUnderlyingClient() *algorand.Client {
+	return a.client
 }
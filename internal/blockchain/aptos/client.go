@@ -1,51 +1,448 @@
 package aptos
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/rpcpool"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/rs/zerolog"
 )
 
-// Client represents an Aptos blockchain client
+// signedTransactionContentType is the content type of a BCS-encoded signed
+// transaction submitted to the REST API
+const signedTransactionContentType = "application/x.aptos.signed_transaction+bcs"
+
+// Client represents an Aptos blockchain client backed by the node REST API.
+// Endpoints include the API version, e.g. https://fullnode.testnet.aptoslabs.com/v1.
 type Client struct {
-	config *types.ChainConfig
-	logger zerolog.Logger
-	// TODO: Add actual Aptos SDK client when implementing
+	config     *types.ChainConfig
+	httpClient *http.Client
+	pool       *rpcpool.Pool
+	logger     zerolog.Logger
 }
 
 // NewClient creates a new Aptos client
 func NewClient(config *types.ChainConfig, logger zerolog.Logger) (*Client, error) {
+	if config.ChainType != types.ChainTypeAptos {
+		return nil, fmt.Errorf("invalid chain type: expected APTOS, got %s", config.ChainType)
+	}
+	if len(config.RPCEndpoints) == 0 {
+		return nil, fmt.Errorf("at least one REST endpoint is required")
+	}
+
 	client := &Client{
 		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
 		logger: logger.With().Str("chain", config.Name).Str("type", "aptos").Logger(),
 	}
 
-	client.logger.Info().Msg("Aptos client initialized (placeholder)")
+	// Lag is measured in blocks, since ledger versions advance by the
+	// number of transactions
+	pool, err := rpcpool.New(config.Name, config.RPCEndpoints, rpcpool.Config{
+		Quorum:     config.RPCQuorum,
+		MaxHeadLag: config.RPCMaxHeadLag,
+		Head: func(ctx context.Context, e *rpcpool.Endpoint) (uint64, error) {
+			var info LedgerInfo
+			if err := client.call(ctx, e, http.MethodGet, "/", nil, "", nil, &info); err != nil {
+				return 0, err
+			}
+			return uint64(info.BlockHeight), nil
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	client.pool = pool
+
+	client.logger.Info().
+		Int("endpoints", len(config.RPCEndpoints)).
+		Str("network", config.NetworkID).
+		Msg("Aptos client initialized")
+
 	return client, nil
 }
 
+// APIError is an error response from the REST API
+type APIError struct {
+	StatusCode int
+	Message    string `json:"message"`
+	ErrorCode  string `json:"error_code"`
+}
+
+func (e *APIError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("API error %d: %s (%s)", e.StatusCode, e.Message, e.ErrorCode)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err means the requested resource does not
+// exist, e.g. a transaction the node has not seen
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Uint64 is a u64 the REST API encodes as a JSON string
+type Uint64 uint64
+
+// UnmarshalJSON accepts a u64 encoded as a string or a number
+func (u *Uint64) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid u64 %s: %w", data, err)
+	}
+	*u = Uint64(v)
+	return nil
+}
+
+// LedgerInfo is the response of the API's index
+type LedgerInfo struct {
+	ChainID         uint8  `json:"chain_id"`
+	Epoch           Uint64 `json:"epoch"`
+	LedgerVersion   Uint64 `json:"ledger_version"`
+	LedgerTimestamp Uint64 `json:"ledger_timestamp"`
+	BlockHeight     Uint64 `json:"block_height"`
+}
+
+// Block is a block without its transactions
+type Block struct {
+	BlockHeight    Uint64 `json:"block_height"`
+	BlockHash      string `json:"block_hash"`
+	BlockTimestamp Uint64 `json:"block_timestamp"`
+	FirstVersion   Uint64 `json:"first_version"`
+	LastVersion    Uint64 `json:"last_version"`
+}
+
+// EventGUID identifies the event handle an event was emitted to
+type EventGUID struct {
+	CreationNumber Uint64 `json:"creation_number"`
+	AccountAddress string `json:"account_address"`
+}
+
+// Event is an event emitted to an event handle
+type Event struct {
+	Version        Uint64          `json:"version"`
+	GUID           EventGUID       `json:"guid"`
+	SequenceNumber Uint64          `json:"sequence_number"`
+	Type           string          `json:"type"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// Transaction is a pending or committed transaction
+type Transaction struct {
+	Type      string `json:"type"`
+	Hash      string `json:"hash"`
+	Version   Uint64 `json:"version"`
+	Success   bool   `json:"success"`
+	VMStatus  string `json:"vm_status"`
+	GasUsed   Uint64 `json:"gas_used"`
+	Timestamp Uint64 `json:"timestamp"`
+}
+
+// IsPending reports whether the transaction has not been committed yet
+func (t *Transaction) IsPending() bool {
+	return t.Type == "pending_transaction"
+}
+
+// Account is an account's sequence number and authentication key
+type Account struct {
+	SequenceNumber    Uint64 `json:"sequence_number"`
+	AuthenticationKey string `json:"authentication_key"`
+}
+
+// request performs a request against the best endpoint that answers
+func (c *Client) request(ctx context.Context, method, path string, query url.Values, contentType string, body []byte, out interface{}) error {
+	return c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		return c.call(ctx, e, method, path, query, contentType, body, out)
+	})
+}
+
+// call performs a request against an endpoint. Client errors other than
+// rate limiting are the endpoint's answer and are not retried on other
+// endpoints, since they would answer the same.
+func (c *Client) call(ctx context.Context, e *rpcpool.Endpoint, method, path string, query url.Values, contentType string, body []byte, out interface{}) error {
+	endpoint := e.URL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return rpcpool.Permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return rpcpool.Permanent(apiErr)
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return rpcpool.Permanent(fmt.Errorf("failed to unmarshal response: %w", err))
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.request(ctx, http.MethodGet, path, query, "", nil, out)
+}
+
+// GetLedgerInfo gets the latest ledger information
+func (c *Client) GetLedgerInfo(ctx context.Context) (*LedgerInfo, error) {
+	var info LedgerInfo
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		if err := c.call(ctx, e, http.MethodGet, "/", nil, "", nil, &info); err != nil {
+			return err
+		}
+		c.pool.ObserveHead(e, uint64(info.BlockHeight))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger info: %w", err)
+	}
+	return &info, nil
+}
+
 // GetLatestVersion gets the latest ledger version
 func (c *Client) GetLatestVersion(ctx context.Context) (uint64, error) {
-	// TODO: Implement actual Aptos API call
-	return 0, fmt.Errorf("not implemented yet")
+	info, err := c.GetLedgerInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.LedgerVersion), nil
+}
+
+// GetBlock gets the block containing a ledger version
+func (c *Client) GetBlock(ctx context.Context, version uint64) (*Block, error) {
+	var block Block
+	if err := c.get(ctx, fmt.Sprintf("/blocks/by_version/%d", version), nil, &block); err != nil {
+		return nil, fmt.Errorf("failed to get block by version %d: %w", version, err)
+	}
+	return &block, nil
 }
 
 // GetBlockByVersion gets block by version number
 func (c *Client) GetBlockByVersion(ctx context.Context, version uint64) (*types.BlockInfo, error) {
-	// TODO: Implement actual Aptos API call
-	return nil, fmt.Errorf("not implemented yet")
+	block, err := c.GetBlock(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.BlockInfo{
+		Number:    uint64(block.BlockHeight),
+		Hash:      block.BlockHash,
+		Timestamp: time.UnixMicro(int64(block.BlockTimestamp)),
+		TxCount:   int(block.LastVersion - block.FirstVersion + 1),
+	}, nil
+}
+
+// GetEventsByHandle gets the events emitted to an event handle, a field of
+// a resource stored under an account, starting at a sequence number
+func (c *Client) GetEventsByHandle(ctx context.Context, address, handleStruct, fieldName string, start uint64, limit int) ([]Event, error) {
+	query := url.Values{
+		"start": {strconv.FormatUint(start, 10)},
+		"limit": {strconv.Itoa(limit)},
+	}
+	path := fmt.Sprintf("/accounts/%s/events/%s/%s", address, url.PathEscape(handleStruct), fieldName)
+
+	var events []Event
+	if err := c.get(ctx, path, query, &events); err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return events, nil
 }
 
-// IsHealthy checks if the client is healthy
+// GetAccount gets an account's sequence number
+func (c *Client) GetAccount(ctx context.Context, address string) (*Account, error) {
+	var account Account
+	if err := c.get(ctx, "/accounts/"+address, nil, &account); err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return &account, nil
+}
+
+// EstimateGasPrice gets the suggested gas unit price
+func (c *Client) EstimateGasPrice(ctx context.Context) (uint64, error) {
+	var estimate struct {
+		GasEstimate uint64 `json:"gas_estimate"`
+	}
+	if err := c.get(ctx, "/estimate_gas_price", nil, &estimate); err != nil {
+		return 0, fmt.Errorf("failed to estimate gas price: %w", err)
+	}
+	return estimate.GasEstimate, nil
+}
+
+// TransactionParams are the parameters needed to build a sender's next
+// transaction
+type TransactionParams struct {
+	SequenceNumber uint64
+	GasUnitPrice   uint64
+	ChainID        uint8
+}
+
+// GetTransactionParams gets the parameters for a sender's next transaction
+func (c *Client) GetTransactionParams(ctx context.Context, sender string) (*TransactionParams, error) {
+	info, err := c.GetLedgerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := c.GetAccount(ctx, sender)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := c.EstimateGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionParams{
+		SequenceNumber: uint64(account.SequenceNumber),
+		GasUnitPrice:   gasPrice,
+		ChainID:        info.ChainID,
+	}, nil
+}
+
+// View calls a view function and returns its return values
+func (c *Client) View(ctx context.Context, function string, typeArgs []string, args []interface{}) ([]json.RawMessage, error) {
+	request := struct {
+		Function      string        `json:"function"`
+		TypeArguments []string      `json:"type_arguments"`
+		Arguments     []interface{} `json:"arguments"`
+	}{
+		Function:      function,
+		TypeArguments: typeArgs,
+		Arguments:     args,
+	}
+	if request.TypeArguments == nil {
+		request.TypeArguments = []string{}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal view request: %w", err)
+	}
+
+	var values []json.RawMessage
+	if err := c.request(ctx, http.MethodPost, "/view", nil, "application/json", body, &values); err != nil {
+		return nil, fmt.Errorf("failed to call view function %s: %w", function, err)
+	}
+	return values, nil
+}
+
+// GetTransactionByHash gets a pending or committed transaction
+func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (*Transaction, error) {
+	var txn Transaction
+	if err := c.get(ctx, "/transactions/by_hash/"+hash, nil, &txn); err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &txn, nil
+}
+
+// GetTransactionByVersion gets the committed transaction at a ledger
+// version
+func (c *Client) GetTransactionByVersion(ctx context.Context, version uint64) (*Transaction, error) {
+	var txn Transaction
+	if err := c.get(ctx, fmt.Sprintf("/transactions/by_version/%d", version), nil, &txn); err != nil {
+		return nil, fmt.Errorf("failed to get transaction by version %d: %w", version, err)
+	}
+	return &txn, nil
+}
+
+// SubmitTransaction submits a BCS-encoded signed transaction and returns
+// its hash
+func (c *Client) SubmitTransaction(ctx context.Context, signedTxn []byte) (string, error) {
+	var pending Transaction
+	if err := c.request(ctx, http.MethodPost, "/transactions", nil, signedTransactionContentType, signedTxn, &pending); err != nil {
+		return "", fmt.Errorf("failed to submit transaction: %w", err)
+	}
+	return pending.Hash, nil
+}
+
+// WaitForTransaction waits until a transaction is committed. Aptos commits
+// are final, so a committed transaction cannot be reverted.
+func (c *Client) WaitForTransaction(ctx context.Context, hash string, timeout time.Duration) (*Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(c.config.GetBlockTimeDuration())
+	defer ticker.Stop()
+
+	for {
+		txn, err := c.GetTransactionByHash(ctx, hash)
+		// The transaction may not have reached this node yet
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil && !txn.IsPending() {
+			if !txn.Success {
+				return txn, fmt.Errorf("transaction failed: %s", txn.VMStatus)
+			}
+			return txn, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout waiting for transaction: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsHealthy checks if the client is healthy. The node reports itself
+// unhealthy when its ledger is more than 30 seconds behind.
 func (c *Client) IsHealthy(ctx context.Context) bool {
-	// TODO: Implement actual health check
+	query := url.Values{"duration_secs": {"30"}}
+	if err := c.get(ctx, "/-/healthy", query, nil); err != nil {
+		c.logger.Warn().Err(err).Msg("Health check failed")
+		return false
+	}
 	return true
 }
 
 // Close closes the client
 func (c *Client) Close() error {
 	c.logger.Info().Msg("Closing Aptos client")
+	c.pool.Close()
+	c.httpClient.CloseIdleConnections()
 	return nil
 }
@@ -0,0 +1,208 @@
+package aptos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// AccountAddress is a 32-byte Aptos account address
+type AccountAddress [32]byte
+
+// ParseAddress parses a hex account address. Leading zeros may be omitted,
+// as in the short form of special addresses like 0x1.
+func ParseAddress(s string) (AccountAddress, error) {
+	var addr AccountAddress
+
+	raw := strings.TrimPrefix(s, "0x")
+	if raw == "" || len(raw) > 64 {
+		return addr, fmt.Errorf("invalid address length: %s", s)
+	}
+	if len(raw)%2 == 1 {
+		raw = "0" + raw
+	}
+
+	decoded, err := hex.DecodeString(raw)
+	if err != nil {
+		return addr, fmt.Errorf("invalid address %s: %w", s, err)
+	}
+	copy(addr[32-len(decoded):], decoded)
+	return addr, nil
+}
+
+// String returns the long form of the address
+func (a AccountAddress) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// TypeTag is a struct type argument, e.g. 0x1::aptos_coin::AptosCoin.
+// Generic struct types are not supported.
+type TypeTag struct {
+	Address AccountAddress
+	Module  string
+	Name    string
+}
+
+// ParseTypeTag parses a non-generic struct type
+func ParseTypeTag(s string) (TypeTag, error) {
+	parts := strings.Split(s, "::")
+	if len(parts) != 3 || strings.ContainsAny(s, "<>") {
+		return TypeTag{}, fmt.Errorf("unsupported type tag: %s", s)
+	}
+
+	addr, err := ParseAddress(parts[0])
+	if err != nil {
+		return TypeTag{}, err
+	}
+	return TypeTag{Address: addr, Module: parts[1], Name: parts[2]}, nil
+}
+
+// EntryFunction is a call of a public entry function
+type EntryFunction struct {
+	Module   AccountAddress
+	Name     string
+	Function string
+	TypeArgs []TypeTag
+	// Args are the BCS encodings of the function's arguments
+	Args [][]byte
+}
+
+// RawTransaction is an unsigned transaction calling an entry function
+type RawTransaction struct {
+	Sender                  AccountAddress
+	SequenceNumber          uint64
+	Payload                 EntryFunction
+	MaxGasAmount            uint64
+	GasUnitPrice            uint64
+	ExpirationTimestampSecs uint64
+	ChainID                 uint8
+}
+
+// BCS variant indexes of the enums used by transactions
+const (
+	payloadEntryFunction   = 2
+	typeTagStruct          = 7
+	authenticatorEd25519   = 0
+	transactionUserVariant = 0
+)
+
+var (
+	rawTransactionSalt = sha3Sum([]byte("APTOS::RawTransaction"))
+	transactionSalt    = sha3Sum([]byte("APTOS::Transaction"))
+)
+
+func sha3Sum(data ...[]byte) []byte {
+	hash := sha3.New256()
+	for _, d := range data {
+		hash.Write(d)
+	}
+	return hash.Sum(nil)
+}
+
+// Encode returns the BCS encoding of the transaction
+func (t *RawTransaction) Encode() []byte {
+	var e bcsEncoder
+	e.fixed(t.Sender[:])
+	e.u64(t.SequenceNumber)
+
+	e.uleb128(payloadEntryFunction)
+	e.fixed(t.Payload.Module[:])
+	e.str(t.Payload.Name)
+	e.str(t.Payload.Function)
+	e.uleb128(uint64(len(t.Payload.TypeArgs)))
+	for _, tag := range t.Payload.TypeArgs {
+		e.uleb128(typeTagStruct)
+		e.fixed(tag.Address[:])
+		e.str(tag.Module)
+		e.str(tag.Name)
+		e.uleb128(0) // type arguments of the struct
+	}
+	e.uleb128(uint64(len(t.Payload.Args)))
+	for _, arg := range t.Payload.Args {
+		e.bytes(arg)
+	}
+
+	e.u64(t.MaxGasAmount)
+	e.u64(t.GasUnitPrice)
+	e.u64(t.ExpirationTimestampSecs)
+	e.buf.WriteByte(t.ChainID)
+	return e.buf.Bytes()
+}
+
+// SigningMessage returns the bytes the sender signs
+func (t *RawTransaction) SigningMessage() []byte {
+	return append(append([]byte{}, rawTransactionSalt...), t.Encode()...)
+}
+
+// EncodeSignedTransaction encodes a transaction with the sender's Ed25519
+// signature for submission
+func EncodeSignedTransaction(txn *RawTransaction, publicKey, signature []byte) []byte {
+	var e bcsEncoder
+	e.fixed(txn.Encode())
+	e.uleb128(authenticatorEd25519)
+	e.bytes(publicKey)
+	e.bytes(signature)
+	return e.buf.Bytes()
+}
+
+// TransactionHash returns the hash of an encoded signed transaction
+func TransactionHash(signedTxn []byte) string {
+	return "0x" + hex.EncodeToString(sha3Sum(transactionSalt, []byte{transactionUserVariant}, signedTxn))
+}
+
+// U64Arg encodes a u64 argument
+func U64Arg(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
+
+// AddressArg encodes an address argument
+func AddressArg(addr AccountAddress) []byte {
+	return append([]byte{}, addr[:]...)
+}
+
+// BytesArg encodes a vector<u8> argument
+func BytesArg(b []byte) []byte {
+	var e bcsEncoder
+	e.bytes(b)
+	return e.buf.Bytes()
+}
+
+// BytesVectorArg encodes a vector<vector<u8>> argument
+func BytesVectorArg(items [][]byte) []byte {
+	var e bcsEncoder
+	e.uleb128(uint64(len(items)))
+	for _, item := range items {
+		e.bytes(item)
+	}
+	return e.buf.Bytes()
+}
+
+// bcsEncoder writes the subset of BCS used by transactions
+type bcsEncoder struct {
+	buf bytes.Buffer
+}
+
+func (e *bcsEncoder) fixed(b []byte) {
+	e.buf.Write(b)
+}
+
+func (e *bcsEncoder) u64(v uint64) {
+	e.buf.Write(binary.LittleEndian.AppendUint64(nil, v))
+}
+
+func (e *bcsEncoder) uleb128(v uint64) {
+	e.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (e *bcsEncoder) bytes(b []byte) {
+	e.uleb128(uint64(len(b)))
+	e.buf.Write(b)
+}
+
+func (e *bcsEncoder) str(s string) {
+	e.bytes([]byte(s))
+}
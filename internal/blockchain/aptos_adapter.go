@@ -2,8 +2,10 @@ package blockchain
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/aptos"
@@ -59,26 +61,85 @@ func (a *AptosClientAdapter) GetConfirmationBlocks() uint64 {
 	return a.config.ConfirmationBlocks
 }
 
+// SendTransaction submits a BCS-encoded signed transaction
 func (a *AptosClientAdapter) SendTransaction(ctx context.Context, tx interface{}) (string, error) {
-	return "", fmt.Errorf("not implemented")
+	signedTxn, ok := tx.([]byte)
+	if !ok {
+		return "", fmt.Errorf("invalid transaction type: expected BCS-encoded signed transaction, got %T", tx)
+	}
+	return a.client.SubmitTransaction(ctx, signedTxn)
 }
 
 func (a *AptosClientAdapter) GetTransactionStatus(ctx context.Context, txHash string) (*types.TransactionStatus, error) {
-	return nil, fmt.Errorf("not implemented")
+	txn, err := a.client.GetTransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Committed transactions are final
+	committed := !txn.IsPending()
+	status := &types.TransactionStatus{
+		Hash:        txHash,
+		BlockNumber: uint64(txn.Version),
+		Success:     committed && txn.Success,
+		Confirmed:   committed,
+		Finalized:   committed,
+		GasUsed:     uint64(txn.GasUsed),
+		Timestamp:   time.UnixMicro(int64(txn.Timestamp)),
+	}
+	if committed && !txn.Success {
+		status.Error = txn.VMStatus
+	}
+	return status, nil
 }
 
 func (a *AptosClientAdapter) WaitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) error {
-	return fmt.Errorf("not implemented")
+	_, err := a.client.WaitForTransaction(ctx, txHash, timeout)
+	return err
 }
 
+// GetNativeBalance gets an account's APT balance in octas
 func (a *AptosClientAdapter) GetNativeBalance(ctx context.Context, address string) (*big.Int, error) {
-	return nil, fmt.Errorf("not implemented")
+	return a.viewBalance(ctx, "0x1::coin::balance", []string{"0x1::aptos_coin::AptosCoin"}, []interface{}{address})
 }
 
+// GetTokenBalance gets an account's balance of a coin, identified by its
+// type, or of a fungible asset, identified by its metadata address
 func (a *AptosClientAdapter) GetTokenBalance(ctx context.Context, address string, tokenAddress string) (*big.Int, error) {
-	return nil, fmt.Errorf("not implemented")
+	if strings.Contains(tokenAddress, "::") {
+		return a.viewBalance(ctx, "0x1::coin::balance", []string{tokenAddress}, []interface{}{address})
+	}
+	return a.viewBalance(ctx, "0x1::primary_fungible_store::balance",
+		[]string{"0x1::fungible_asset::Metadata"}, []interface{}{address, tokenAddress})
+}
+
+// viewBalance calls a view function returning a u64 balance
+func (a *AptosClientAdapter) viewBalance(ctx context.Context, function string, typeArgs []string, args []interface{}) (*big.Int, error) {
+	values, err := a.client.View(ctx, function, typeArgs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected %s result: %d values", function, len(values))
+	}
+
+	var balance aptos.Uint64
+	if err := json.Unmarshal(values[0], &balance); err != nil {
+		return nil, fmt.Errorf("invalid %s result: %w", function, err)
+	}
+	return new(big.Int).SetUint64(uint64(balance)), nil
 }
 
 func (a *AptosClientAdapter) SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error) {
-	return nil, fmt.Errorf("not implemented")
+	return nil, types.ErrSubscriptionNotSupported
+}
+
+// GetTransactionParams gets the parameters for a sender's next transaction
+func (a *AptosClientAdapter) GetTransactionParams(ctx context.Context, sender string) (*aptos.TransactionParams, error) {
+	return a.client.GetTransactionParams(ctx, sender)
+}
+
+// GetUnderlyingClient returns the underlying Aptos client
+func (a *AptosClientAdapter) GetUnderlyingClient() *aptos.Client {
+	return a.client
 }
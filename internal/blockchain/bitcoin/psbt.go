@@ -0,0 +1,440 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// psbtMagic starts every BIP-174 PSBT
+var psbtMagic = []byte{'p', 's', 'b', 't', 0xff}
+
+// PSBT key types used by releases. Other keys are preserved as unknown.
+const (
+	psbtGlobalUnsignedTx     = 0x00
+	psbtInWitnessUTXO        = 0x01
+	psbtInPartialSig         = 0x02
+	psbtInSigHashType        = 0x03
+	psbtInWitnessScript      = 0x05
+	psbtInFinalScriptWitness = 0x08
+	psbtOutWitnessScript     = 0x01
+)
+
+// psbtEntry is a key/value pair of a PSBT map
+type psbtEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// PInput is the PSBT map of an input. Inputs spend P2WSH outputs.
+type PInput struct {
+	WitnessUTXO   *TxOut
+	WitnessScript []byte
+	SigHashType   uint32
+	// PartialSigs maps hex compressed public keys to DER signatures with
+	// the sighash type appended
+	PartialSigs        map[string][]byte
+	FinalScriptWitness [][]byte
+	Unknown            []psbtEntry
+}
+
+// POutput is the PSBT map of an output
+type POutput struct {
+	WitnessScript []byte
+	Unknown       []psbtEntry
+}
+
+// PSBT is a BIP-174 partially signed transaction
+type PSBT struct {
+	Tx      *MsgTx
+	Inputs  []PInput
+	Outputs []POutput
+	Unknown []psbtEntry
+}
+
+// NewPSBT creates a PSBT for an unsigned transaction
+func NewPSBT(tx *MsgTx) *PSBT {
+	p := &PSBT{
+		Tx:      tx,
+		Inputs:  make([]PInput, len(tx.TxIn)),
+		Outputs: make([]POutput, len(tx.TxOut)),
+	}
+	for i := range p.Inputs {
+		p.Inputs[i].PartialSigs = make(map[string][]byte)
+	}
+	return p
+}
+
+// Encode serializes the PSBT
+func (p *PSBT) Encode() []byte {
+	var buf bytes.Buffer
+	buf.Write(psbtMagic)
+
+	writePSBTEntry(&buf, []byte{psbtGlobalUnsignedTx}, p.Tx.SerializeNoWitness())
+	writeUnknown(&buf, p.Unknown)
+	buf.WriteByte(0x00)
+
+	for _, in := range p.Inputs {
+		if in.WitnessUTXO != nil {
+			var utxo bytes.Buffer
+			binary.Write(&utxo, binary.LittleEndian, in.WitnessUTXO.Value)
+			writeVarBytes(&utxo, in.WitnessUTXO.PkScript)
+			writePSBTEntry(&buf, []byte{psbtInWitnessUTXO}, utxo.Bytes())
+		}
+
+		pubKeys := make([]string, 0, len(in.PartialSigs))
+		for pubKey := range in.PartialSigs {
+			pubKeys = append(pubKeys, pubKey)
+		}
+		sort.Strings(pubKeys)
+		for _, pubKey := range pubKeys {
+			keyData, _ := hex.DecodeString(pubKey)
+			writePSBTEntry(&buf, append([]byte{psbtInPartialSig}, keyData...), in.PartialSigs[pubKey])
+		}
+
+		if in.SigHashType != 0 {
+			writePSBTEntry(&buf, []byte{psbtInSigHashType}, binary.LittleEndian.AppendUint32(nil, in.SigHashType))
+		}
+		if in.WitnessScript != nil {
+			writePSBTEntry(&buf, []byte{psbtInWitnessScript}, in.WitnessScript)
+		}
+		if in.FinalScriptWitness != nil {
+			var witness bytes.Buffer
+			writeVarInt(&witness, uint64(len(in.FinalScriptWitness)))
+			for _, item := range in.FinalScriptWitness {
+				writeVarBytes(&witness, item)
+			}
+			writePSBTEntry(&buf, []byte{psbtInFinalScriptWitness}, witness.Bytes())
+		}
+		writeUnknown(&buf, in.Unknown)
+		buf.WriteByte(0x00)
+	}
+
+	for _, out := range p.Outputs {
+		if out.WitnessScript != nil {
+			writePSBTEntry(&buf, []byte{psbtOutWitnessScript}, out.WitnessScript)
+		}
+		writeUnknown(&buf, out.Unknown)
+		buf.WriteByte(0x00)
+	}
+
+	return buf.Bytes()
+}
+
+// B64Encode serializes the PSBT as base64, the form wallets exchange
+func (p *PSBT) B64Encode() string {
+	return base64.StdEncoding.EncodeToString(p.Encode())
+}
+
+// DecodePSBT parses a serialized PSBT
+func DecodePSBT(data []byte) (*PSBT, error) {
+	if !bytes.HasPrefix(data, psbtMagic) {
+		return nil, fmt.Errorf("missing PSBT magic")
+	}
+	r := bytes.NewReader(data[len(psbtMagic):])
+
+	global, err := readPSBTMap(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global map: %w", err)
+	}
+
+	var p *PSBT
+	var unknown []psbtEntry
+	for _, entry := range global {
+		if len(entry.Key) == 1 && entry.Key[0] == psbtGlobalUnsignedTx {
+			tx, err := DeserializeTx(entry.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unsigned transaction: %w", err)
+			}
+			p = NewPSBT(tx)
+			continue
+		}
+		unknown = append(unknown, entry)
+	}
+	if p == nil {
+		return nil, fmt.Errorf("missing unsigned transaction")
+	}
+	p.Unknown = unknown
+
+	for i := range p.Inputs {
+		entries, err := readPSBTMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+		if err := p.Inputs[i].decode(entries); err != nil {
+			return nil, fmt.Errorf("invalid input %d: %w", i, err)
+		}
+	}
+
+	for i := range p.Outputs {
+		entries, err := readPSBTMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read output %d: %w", i, err)
+		}
+		for _, entry := range entries {
+			if len(entry.Key) == 1 && entry.Key[0] == psbtOutWitnessScript {
+				p.Outputs[i].WitnessScript = entry.Value
+				continue
+			}
+			p.Outputs[i].Unknown = append(p.Outputs[i].Unknown, entry)
+		}
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after PSBT", r.Len())
+	}
+	return p, nil
+}
+
+// DecodePSBTBase64 parses a base64 PSBT
+func DecodePSBTBase64(s string) (*PSBT, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PSBT base64: %w", err)
+	}
+	return DecodePSBT(data)
+}
+
+func (in *PInput) decode(entries []psbtEntry) error {
+	for _, entry := range entries {
+		keyType, keyData := entry.Key[0], entry.Key[1:]
+		switch {
+		case keyType == psbtInWitnessUTXO && len(keyData) == 0:
+			r := bytes.NewReader(entry.Value)
+			utxo := &TxOut{}
+			if err := binary.Read(r, binary.LittleEndian, &utxo.Value); err != nil {
+				return fmt.Errorf("invalid witness UTXO: %w", err)
+			}
+			script, err := readVarBytes(r)
+			if err != nil {
+				return fmt.Errorf("invalid witness UTXO: %w", err)
+			}
+			utxo.PkScript = script
+			in.WitnessUTXO = utxo
+		case keyType == psbtInPartialSig && len(keyData) == 33:
+			in.PartialSigs[hex.EncodeToString(keyData)] = entry.Value
+		case keyType == psbtInSigHashType && len(keyData) == 0 && len(entry.Value) == 4:
+			in.SigHashType = binary.LittleEndian.Uint32(entry.Value)
+		case keyType == psbtInWitnessScript && len(keyData) == 0:
+			in.WitnessScript = entry.Value
+		case keyType == psbtInFinalScriptWitness && len(keyData) == 0:
+			r := bytes.NewReader(entry.Value)
+			count, err := readVarInt(r)
+			if err != nil {
+				return fmt.Errorf("invalid final witness: %w", err)
+			}
+			in.FinalScriptWitness = make([][]byte, 0, count)
+			for i := uint64(0); i < count; i++ {
+				item, err := readVarBytes(r)
+				if err != nil {
+					return fmt.Errorf("invalid final witness: %w", err)
+				}
+				in.FinalScriptWitness = append(in.FinalScriptWitness, item)
+			}
+		default:
+			in.Unknown = append(in.Unknown, entry)
+		}
+	}
+	return nil
+}
+
+// SigHash returns the hash an input's signers sign
+func (p *PSBT) SigHash(idx int) ([]byte, error) {
+	in := &p.Inputs[idx]
+	if in.WitnessUTXO == nil || in.WitnessScript == nil {
+		return nil, fmt.Errorf("input %d is missing its witness UTXO or script", idx)
+	}
+	if !bytes.Equal(in.WitnessUTXO.PkScript, WitnessScriptHash(in.WitnessScript)) {
+		return nil, fmt.Errorf("input %d witness script does not match its UTXO", idx)
+	}
+	return p.Tx.WitnessSigHash(idx, in.WitnessScript, in.WitnessUTXO.Value, SigHashAll)
+}
+
+// AddPartialSig adds a signer's signature of an input after checking it
+// against the input's sighash. signature is DER with the sighash type
+// appended.
+func (p *PSBT) AddPartialSig(idx int, pubKey, signature []byte) error {
+	if idx < 0 || idx >= len(p.Inputs) {
+		return fmt.Errorf("input index %d out of range", idx)
+	}
+	if len(signature) < 2 || signature[len(signature)-1] != SigHashAll {
+		return fmt.Errorf("signature must use SIGHASH_ALL")
+	}
+
+	hash, err := p.SigHash(idx)
+	if err != nil {
+		return err
+	}
+	compact, err := parseDERSignature(signature[:len(signature)-1])
+	if err != nil {
+		return err
+	}
+	if !ethcrypto.VerifySignature(pubKey, hash, compact) {
+		return fmt.Errorf("invalid signature for input %d", idx)
+	}
+
+	p.Inputs[idx].PartialSigs[hex.EncodeToString(pubKey)] = signature
+	return nil
+}
+
+// HasSignature reports whether a public key signed every input
+func (p *PSBT) HasSignature(pubKey []byte) bool {
+	key := hex.EncodeToString(pubKey)
+	for _, in := range p.Inputs {
+		if _, ok := in.PartialSigs[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Finalize builds the witness of every multisig input from its partial
+// signatures. It fails if an input has fewer signatures than its
+// threshold, leaving the PSBT unchanged.
+func (p *PSBT) Finalize() error {
+	witnesses := make([][][]byte, len(p.Inputs))
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		threshold, pubKeys, err := ParseMultisigScript(in.WitnessScript)
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+
+		// CHECKMULTISIG consumes signatures in public key order, after
+		// the extra element its off-by-one bug pops
+		witness := [][]byte{{}}
+		for _, pubKey := range pubKeys {
+			if len(witness)-1 == threshold {
+				break
+			}
+			if sig, ok := in.PartialSigs[hex.EncodeToString(pubKey)]; ok {
+				witness = append(witness, sig)
+			}
+		}
+		if len(witness)-1 < threshold {
+			return fmt.Errorf("input %d has %d of %d signatures", i, len(witness)-1, threshold)
+		}
+		witnesses[i] = append(witness, in.WitnessScript)
+	}
+
+	for i := range p.Inputs {
+		p.Inputs[i].FinalScriptWitness = witnesses[i]
+	}
+	return nil
+}
+
+// Extract returns the signed transaction of a finalized PSBT
+func (p *PSBT) Extract() (*MsgTx, error) {
+	tx := *p.Tx
+	tx.TxIn = make([]*TxIn, len(p.Tx.TxIn))
+	for i, in := range p.Tx.TxIn {
+		if p.Inputs[i].FinalScriptWitness == nil {
+			return nil, fmt.Errorf("input %d is not finalized", i)
+		}
+		signed := *in
+		signed.Witness = p.Inputs[i].FinalScriptWitness
+		tx.TxIn[i] = &signed
+	}
+	return &tx, nil
+}
+
+// secp256k1HalfOrder is half the curve order; signatures with a larger S
+// are non-standard
+var (
+	secp256k1Order     = ethcrypto.S256().Params().N
+	secp256k1HalfOrder = new(big.Int).Rsh(secp256k1Order, 1)
+)
+
+// EncodeSignature converts a 64-byte R || S signature, optionally followed
+// by a recovery ID, to DER with the low S form and SIGHASH_ALL appended
+func EncodeSignature(signature []byte) ([]byte, error) {
+	if len(signature) != 64 && len(signature) != 65 {
+		return nil, fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	if s.Cmp(secp256k1HalfOrder) > 0 {
+		s.Sub(secp256k1Order, s)
+	}
+
+	encodeInt := func(v *big.Int) []byte {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0x00}, b...)
+		}
+		return append([]byte{0x02, byte(len(b))}, b...)
+	}
+	body := append(encodeInt(r), encodeInt(s)...)
+	der := append([]byte{0x30, byte(len(body))}, body...)
+	return append(der, SigHashAll), nil
+}
+
+// parseDERSignature converts a DER signature to 64-byte R || S
+func parseDERSignature(der []byte) ([]byte, error) {
+	invalid := fmt.Errorf("invalid DER signature")
+	if len(der) < 8 || der[0] != 0x30 || int(der[1]) != len(der)-2 {
+		return nil, invalid
+	}
+
+	compact := make([]byte, 64)
+	rest := der[2:]
+	for i := 0; i < 2; i++ {
+		if len(rest) < 2 || rest[0] != 0x02 || int(rest[1]) > len(rest)-2 {
+			return nil, invalid
+		}
+		size := int(rest[1])
+		v := bytes.TrimLeft(rest[2:2+size], "\x00")
+		if len(v) > 32 {
+			return nil, invalid
+		}
+		copy(compact[32*(i+1)-len(v):32*(i+1)], v)
+		rest = rest[2+size:]
+	}
+	if len(rest) != 0 {
+		return nil, invalid
+	}
+	return compact, nil
+}
+
+func writePSBTEntry(buf *bytes.Buffer, key, value []byte) {
+	writeVarBytes(buf, key)
+	writeVarBytes(buf, value)
+}
+
+func writeUnknown(buf *bytes.Buffer, entries []psbtEntry) {
+	for _, entry := range entries {
+		writePSBTEntry(buf, entry.Key, entry.Value)
+	}
+}
+
+// readPSBTMap reads key/value pairs up to a map's separator
+func readPSBTMap(r *bytes.Reader) ([]psbtEntry, error) {
+	var entries []psbtEntry
+	seen := make(map[string]bool)
+	for {
+		key, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 {
+			return entries, nil
+		}
+		if seen[string(key)] {
+			return nil, fmt.Errorf("duplicate key %x", key)
+		}
+		seen[string(key)] = true
+
+		value, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, psbtEntry{Key: key, Value: value})
+	}
+}
@@ -0,0 +1,178 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/crypto/bech32"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Custody is the bridge-controlled P2WSH address, a threshold multisig of
+// the validators' keys
+type Custody struct {
+	Address       string
+	HRP           string
+	Threshold     int
+	PubKeys       [][]byte
+	WitnessScript []byte
+	PkScript      []byte
+}
+
+// NewCustody creates the custody of a threshold of hex compressed public
+// keys and checks that it pays the configured address
+func NewCustody(address string, threshold int, pubKeysHex []string) (*Custody, error) {
+	hrp, version, _, err := bech32.DecodeSegWit(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custody address: %w", err)
+	}
+	if version != 0 {
+		return nil, fmt.Errorf("custody address must be P2WSH to release funds, got witness v%d", version)
+	}
+
+	pubKeys := make([][]byte, len(pubKeysHex))
+	for i, pubKeyHex := range pubKeysHex {
+		pubKey, err := hex.DecodeString(pubKeyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custody public key %d: %w", i, err)
+		}
+		pubKeys[i] = pubKey
+	}
+
+	witnessScript, err := MultisigScript(threshold, pubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	custody := &Custody{
+		Address:       address,
+		HRP:           hrp,
+		Threshold:     threshold,
+		PubKeys:       pubKeys,
+		WitnessScript: witnessScript,
+		PkScript:      WitnessScriptHash(witnessScript),
+	}
+
+	derived, err := ScriptAddress(custody.PkScript, hrp)
+	if err != nil {
+		return nil, err
+	}
+	if derived != address {
+		return nil, fmt.Errorf("custody keys pay %s, not the configured address %s", derived, address)
+	}
+	return custody, nil
+}
+
+// IsSigner reports whether a compressed public key is one of the custody
+// keys
+func (c *Custody) IsSigner(pubKey []byte) bool {
+	for _, key := range c.PubKeys {
+		if bytes.Equal(key, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// UTXO is an unspent custody output
+type UTXO struct {
+	TxID   string
+	Vout   uint32
+	Amount int64
+}
+
+// dustLimit is the smallest change output worth creating; smaller change
+// is left to the fee
+const dustLimit = 546
+
+// estimateVSize estimates the virtual size of a release spending inputs
+// custody outputs to outputs whose scripts have the given lengths
+func (c *Custody) estimateVSize(inputs int, outputScripts []int) int64 {
+	// version, locktime, counts and the SegWit marker
+	size := int64(11)
+	for _, scriptLen := range outputScripts {
+		size += 8 + 1 + int64(scriptLen)
+	}
+
+	// Outpoint, empty scriptSig and sequence; the witness counts a
+	// quarter: item count, empty element, signatures and the script
+	witness := int64(1 + 1 + c.Threshold*(1+73) + 3 + len(c.WitnessScript))
+	size += int64(inputs) * (41 + (witness+3)/4)
+	return size
+}
+
+// BuildRelease builds an unsigned PSBT paying amount satoshis to a SegWit
+// address from the custody's UTXOs, largest first, with change back to the
+// custody. feeRate is in sat/vB. It returns the PSBT and the UTXOs it
+// spends.
+func (c *Custody) BuildRelease(utxos []UTXO, recipient string, amount, feeRate int64) (*PSBT, []UTXO, error) {
+	if amount < dustLimit {
+		return nil, nil, fmt.Errorf("amount %d is below the dust limit", amount)
+	}
+	recipientScript, err := AddressScript(recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := append([]UTXO(nil), utxos...)
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Amount > candidates[j].Amount
+	})
+
+	var selected []UTXO
+	var total, fee int64
+	for _, utxo := range candidates {
+		selected = append(selected, utxo)
+		total += utxo.Amount
+
+		fee = feeRate * c.estimateVSize(len(selected), []int{len(recipientScript), len(c.PkScript)})
+		if total >= amount+fee {
+			break
+		}
+	}
+	if total < amount+fee {
+		return nil, nil, fmt.Errorf("insufficient custody funds: have %d, need %d plus fee %d", total, amount, fee)
+	}
+
+	tx := &MsgTx{Version: 2}
+	for _, utxo := range selected {
+		outPoint, err := NewOutPoint(utxo.TxID, utxo.Vout)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Final sequence with no relative lock time; the release is not
+		// replaceable once broadcast
+		tx.TxIn = append(tx.TxIn, &TxIn{PreviousOutPoint: outPoint, Sequence: 0xffffffff})
+	}
+	tx.TxOut = append(tx.TxOut, &TxOut{Value: amount, PkScript: recipientScript})
+
+	if change := total - amount - fee; change >= dustLimit {
+		tx.TxOut = append(tx.TxOut, &TxOut{Value: change, PkScript: c.PkScript})
+	}
+
+	packet := NewPSBT(tx)
+	for i, utxo := range selected {
+		packet.Inputs[i].WitnessUTXO = &TxOut{Value: utxo.Amount, PkScript: c.PkScript}
+		packet.Inputs[i].WitnessScript = c.WitnessScript
+		packet.Inputs[i].SigHashType = SigHashAll
+	}
+	if len(tx.TxOut) == 2 {
+		packet.Outputs[1].WitnessScript = c.WitnessScript
+	}
+
+	return packet, selected, nil
+}
+
+// CompressPublicKey compresses an uncompressed secp256k1 public key
+func CompressPublicKey(pubKey []byte) ([]byte, error) {
+	if len(pubKey) == 33 {
+		return pubKey, nil
+	}
+	key, err := ethcrypto.UnmarshalPubkey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return ethcrypto.CompressPubkey(key), nil
+}
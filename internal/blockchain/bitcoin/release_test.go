@@ -0,0 +1,166 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+func testKeys(t *testing.T, n int) ([]*ecdsa.PrivateKey, []string) {
+	t.Helper()
+
+	keys := make([]*ecdsa.PrivateKey, n)
+	pubKeys := make([]string, n)
+	for i := range keys {
+		key, err := ethcrypto.ToECDSA(bytes.Repeat([]byte{byte(i + 1)}, 32))
+		if err != nil {
+			t.Fatalf("ToECDSA: %v", err)
+		}
+		keys[i] = key
+		pubKeys[i] = hex.EncodeToString(ethcrypto.CompressPubkey(&key.PublicKey))
+	}
+	return keys, pubKeys
+}
+
+func TestBuildReleaseSignAndFinalize(t *testing.T) {
+	keys, pubKeys := testKeys(t, 3)
+
+	// Derive the regtest address of the 2-of-3 custody
+	pubKeyBytes := make([][]byte, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		pubKeyBytes[i], _ = hex.DecodeString(pubKey)
+	}
+	script, err := MultisigScript(2, pubKeyBytes)
+	if err != nil {
+		t.Fatalf("MultisigScript: %v", err)
+	}
+	address, err := ScriptAddress(WitnessScriptHash(script), "bcrt")
+	if err != nil {
+		t.Fatalf("ScriptAddress: %v", err)
+	}
+
+	custody, err := NewCustody(address, 2, pubKeys)
+	if err != nil {
+		t.Fatalf("NewCustody: %v", err)
+	}
+	if _, err := NewCustody(address, 1, pubKeys); err == nil {
+		t.Errorf("NewCustody accepted keys that do not pay the address")
+	}
+
+	utxos := []UTXO{
+		{TxID: "1111111111111111111111111111111111111111111111111111111111111111", Vout: 0, Amount: 40000},
+		{TxID: "2222222222222222222222222222222222222222222222222222222222222222", Vout: 1, Amount: 150000},
+		{TxID: "3333333333333333333333333333333333333333333333333333333333333333", Vout: 2, Amount: 90000},
+	}
+	recipient := "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080"
+
+	packet, spent, err := custody.BuildRelease(utxos, recipient, 200000, 2)
+	if err != nil {
+		t.Fatalf("BuildRelease: %v", err)
+	}
+
+	// Largest first: 150000 + 90000 covers 200000 plus the fee
+	if len(spent) != 2 || spent[0].Amount != 150000 || spent[1].Amount != 90000 {
+		t.Fatalf("spent %+v", spent)
+	}
+	if len(packet.Tx.TxOut) != 2 || packet.Tx.TxOut[0].Value != 200000 {
+		t.Fatalf("outputs %+v", packet.Tx.TxOut)
+	}
+	change := packet.Tx.TxOut[1]
+	fee := 240000 - 200000 - change.Value
+	if !bytes.Equal(change.PkScript, custody.PkScript) || fee <= 0 || fee > 2*500 {
+		t.Errorf("change %d to %x, fee %d", change.Value, change.PkScript, fee)
+	}
+	if _, _, err := custody.BuildRelease(utxos, recipient, 300000, 2); err == nil {
+		t.Errorf("BuildRelease spent more than the custody holds")
+	}
+
+	// Each cosigner signs a decoded copy, as relayers do through the
+	// database
+	packet = cosign(t, packet, keys[2])
+	if err := packet.Finalize(); err == nil {
+		t.Errorf("Finalize succeeded with one signature")
+	}
+	packet = cosign(t, packet, keys[0])
+
+	// A signature over another input's hash is rejected
+	wrongHash, _ := packet.SigHash(0)
+	compact, _ := ethcrypto.Sign(wrongHash, keys[1])
+	sig, _ := EncodeSignature(compact)
+	if err := packet.AddPartialSig(1, ethcrypto.CompressPubkey(&keys[1].PublicKey), sig); err == nil {
+		t.Errorf("AddPartialSig accepted a signature of the wrong input")
+	}
+
+	if err := packet.Finalize(); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	signed, err := packet.Extract()
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	// Signatures are ordered by the custody's key order
+	witness := signed.TxIn[0].Witness
+	if len(witness) != 4 || len(witness[0]) != 0 || !bytes.Equal(witness[3], custody.WitnessScript) {
+		t.Fatalf("witness has %d items", len(witness))
+	}
+	first := packet.Inputs[0].PartialSigs[pubKeys[0]]
+	third := packet.Inputs[0].PartialSigs[pubKeys[2]]
+	if !bytes.Equal(witness[1], first) || !bytes.Equal(witness[2], third) {
+		t.Errorf("witness signatures out of key order")
+	}
+
+	// The txid does not commit to the witness
+	if signed.TxID() != packet.Tx.TxID() {
+		t.Errorf("txid changed by signing: %s != %s", signed.TxID(), packet.Tx.TxID())
+	}
+	if raw := signed.Serialize(); raw[4] != 0x00 || raw[5] != 0x01 {
+		t.Errorf("signed transaction is not in the SegWit format")
+	}
+}
+
+// cosign signs every input of a decoded copy of a PSBT
+func cosign(t *testing.T, packet *PSBT, key *ecdsa.PrivateKey) *PSBT {
+	t.Helper()
+
+	decoded, err := DecodePSBTBase64(packet.B64Encode())
+	if err != nil {
+		t.Fatalf("DecodePSBT: %v", err)
+	}
+	pubKey := ethcrypto.CompressPubkey(&key.PublicKey)
+	for i := range decoded.Inputs {
+		hash, err := decoded.SigHash(i)
+		if err != nil {
+			t.Fatalf("SigHash: %v", err)
+		}
+		compact, err := ethcrypto.Sign(hash, key)
+		if err != nil {
+			t.Fatalf("Sign: %v", err)
+		}
+		sig, err := EncodeSignature(compact)
+		if err != nil {
+			t.Fatalf("EncodeSignature: %v", err)
+		}
+		if err := decoded.AddPartialSig(i, pubKey, sig); err != nil {
+			t.Fatalf("AddPartialSig: %v", err)
+		}
+	}
+	if !decoded.HasSignature(pubKey) {
+		t.Errorf("signature of %x not recorded", pubKey)
+	}
+	return decoded
+}
+
+func TestNullData(t *testing.T) {
+	script, _ := hex.DecodeString("6a0b706f6c79676f6e3a307831")
+	data, ok := NullData(script)
+	if !ok || string(data) != "polygon:0x1" {
+		t.Errorf("NullData = %q, %v", data, ok)
+	}
+	if _, ok := NullData([]byte{0x00, 0x14}); ok {
+		t.Errorf("NullData accepted a non-OP_RETURN script")
+	}
+}
@@ -0,0 +1,421 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/crypto/bech32"
+)
+
+// Script opcodes used by bridge scripts
+const (
+	opFalse         = 0x00
+	opPushData1     = 0x4c
+	opPushData2     = 0x4d
+	op1             = 0x51
+	op16            = 0x60
+	opReturn        = 0x6a
+	opCheckMultisig = 0xae
+)
+
+// SigHashAll signs all inputs and outputs
+const SigHashAll = 0x01
+
+// OutPoint identifies a transaction output. Hash is in the internal byte
+// order, the reverse of the displayed txid.
+type OutPoint struct {
+	Hash  [32]byte
+	Index uint32
+}
+
+// NewOutPoint creates an outpoint from a displayed txid
+func NewOutPoint(txid string, index uint32) (OutPoint, error) {
+	var op OutPoint
+	decoded, err := hex.DecodeString(txid)
+	if err != nil || len(decoded) != 32 {
+		return op, fmt.Errorf("invalid txid: %s", txid)
+	}
+	for i := range decoded {
+		op.Hash[i] = decoded[31-i]
+	}
+	op.Index = index
+	return op, nil
+}
+
+// TxID returns the displayed txid of the outpoint's transaction
+func (o OutPoint) TxID() string {
+	return reverseHex(o.Hash[:])
+}
+
+// TxIn is a transaction input
+type TxIn struct {
+	PreviousOutPoint OutPoint
+	Sequence         uint32
+	Witness          [][]byte
+}
+
+// TxOut is a transaction output
+type TxOut struct {
+	Value    int64
+	PkScript []byte
+}
+
+// MsgTx is a Bitcoin transaction
+type MsgTx struct {
+	Version  int32
+	TxIn     []*TxIn
+	TxOut    []*TxOut
+	LockTime uint32
+}
+
+// hasWitness reports whether any input carries witness data
+func (tx *MsgTx) hasWitness() bool {
+	for _, in := range tx.TxIn {
+		if len(in.Witness) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Serialize serializes the transaction, in the SegWit format if any input
+// carries witness data
+func (tx *MsgTx) Serialize() []byte {
+	return tx.serialize(tx.hasWitness())
+}
+
+// SerializeNoWitness serializes the transaction without witness data, as
+// hashed for its txid
+func (tx *MsgTx) SerializeNoWitness() []byte {
+	return tx.serialize(false)
+}
+
+func (tx *MsgTx) serialize(witness bool) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, tx.Version)
+	if witness {
+		buf.Write([]byte{0x00, 0x01}) // marker and flag
+	}
+
+	writeVarInt(&buf, uint64(len(tx.TxIn)))
+	for _, in := range tx.TxIn {
+		buf.Write(in.PreviousOutPoint.Hash[:])
+		binary.Write(&buf, binary.LittleEndian, in.PreviousOutPoint.Index)
+		writeVarInt(&buf, 0) // empty scriptSig, inputs are SegWit
+		binary.Write(&buf, binary.LittleEndian, in.Sequence)
+	}
+
+	writeVarInt(&buf, uint64(len(tx.TxOut)))
+	for _, out := range tx.TxOut {
+		binary.Write(&buf, binary.LittleEndian, out.Value)
+		writeVarBytes(&buf, out.PkScript)
+	}
+
+	if witness {
+		for _, in := range tx.TxIn {
+			writeVarInt(&buf, uint64(len(in.Witness)))
+			for _, item := range in.Witness {
+				writeVarBytes(&buf, item)
+			}
+		}
+	}
+
+	binary.Write(&buf, binary.LittleEndian, tx.LockTime)
+	return buf.Bytes()
+}
+
+// DeserializeTx parses a transaction serialized without witness data, as
+// stored in a PSBT. Inputs must have empty scriptSigs.
+func DeserializeTx(data []byte) (*MsgTx, error) {
+	r := bytes.NewReader(data)
+	tx := &MsgTx{}
+
+	if err := binary.Read(r, binary.LittleEndian, &tx.Version); err != nil {
+		return nil, fmt.Errorf("failed to read version: %w", err)
+	}
+
+	count, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("transaction has no inputs or uses the witness format")
+	}
+	for i := uint64(0); i < count; i++ {
+		in := &TxIn{}
+		if _, err := io.ReadFull(r, in.PreviousOutPoint.Hash[:]); err != nil {
+			return nil, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &in.PreviousOutPoint.Index); err != nil {
+			return nil, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+		scriptSig, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(scriptSig) != 0 {
+			return nil, fmt.Errorf("input %d has a scriptSig", i)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &in.Sequence); err != nil {
+			return nil, fmt.Errorf("failed to read input %d: %w", i, err)
+		}
+		tx.TxIn = append(tx.TxIn, in)
+	}
+
+	count, err = readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < count; i++ {
+		out := &TxOut{}
+		if err := binary.Read(r, binary.LittleEndian, &out.Value); err != nil {
+			return nil, fmt.Errorf("failed to read output %d: %w", i, err)
+		}
+		if out.PkScript, err = readVarBytes(r); err != nil {
+			return nil, err
+		}
+		tx.TxOut = append(tx.TxOut, out)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &tx.LockTime); err != nil {
+		return nil, fmt.Errorf("failed to read lock time: %w", err)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after transaction", r.Len())
+	}
+	return tx, nil
+}
+
+// TxID returns the displayed txid of the transaction
+func (tx *MsgTx) TxID() string {
+	return reverseHex(doubleSHA256(tx.SerializeNoWitness()))
+}
+
+// WitnessSigHash returns the BIP-143 signature hash of a SegWit v0 input
+// spending amount satoshis locked by witnessScript
+func (tx *MsgTx) WitnessSigHash(idx int, witnessScript []byte, amount int64, hashType uint32) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, fmt.Errorf("input index %d out of range", idx)
+	}
+	if hashType != SigHashAll {
+		return nil, fmt.Errorf("unsupported sighash type: %d", hashType)
+	}
+
+	var prevouts, sequences, outputs bytes.Buffer
+	for _, in := range tx.TxIn {
+		prevouts.Write(in.PreviousOutPoint.Hash[:])
+		binary.Write(&prevouts, binary.LittleEndian, in.PreviousOutPoint.Index)
+		binary.Write(&sequences, binary.LittleEndian, in.Sequence)
+	}
+	for _, out := range tx.TxOut {
+		binary.Write(&outputs, binary.LittleEndian, out.Value)
+		writeVarBytes(&outputs, out.PkScript)
+	}
+
+	in := tx.TxIn[idx]
+	var preimage bytes.Buffer
+	binary.Write(&preimage, binary.LittleEndian, tx.Version)
+	preimage.Write(doubleSHA256(prevouts.Bytes()))
+	preimage.Write(doubleSHA256(sequences.Bytes()))
+	preimage.Write(in.PreviousOutPoint.Hash[:])
+	binary.Write(&preimage, binary.LittleEndian, in.PreviousOutPoint.Index)
+	writeVarBytes(&preimage, witnessScript)
+	binary.Write(&preimage, binary.LittleEndian, amount)
+	binary.Write(&preimage, binary.LittleEndian, in.Sequence)
+	preimage.Write(doubleSHA256(outputs.Bytes()))
+	binary.Write(&preimage, binary.LittleEndian, tx.LockTime)
+	binary.Write(&preimage, binary.LittleEndian, hashType)
+
+	return doubleSHA256(preimage.Bytes()), nil
+}
+
+// MultisigScript returns the witness script of a threshold-of-n
+// CHECKMULTISIG over compressed public keys, in the given order
+func MultisigScript(threshold int, pubKeys [][]byte) ([]byte, error) {
+	if len(pubKeys) == 0 || len(pubKeys) > 16 {
+		return nil, fmt.Errorf("multisig needs 1 to 16 public keys, got %d", len(pubKeys))
+	}
+	if threshold < 1 || threshold > len(pubKeys) {
+		return nil, fmt.Errorf("invalid multisig threshold %d of %d", threshold, len(pubKeys))
+	}
+
+	var script bytes.Buffer
+	script.WriteByte(byte(op1 - 1 + threshold))
+	for _, pubKey := range pubKeys {
+		if len(pubKey) != 33 {
+			return nil, fmt.Errorf("public key must be 33 bytes compressed, got %d", len(pubKey))
+		}
+		script.WriteByte(byte(len(pubKey)))
+		script.Write(pubKey)
+	}
+	script.WriteByte(byte(op1 - 1 + len(pubKeys)))
+	script.WriteByte(opCheckMultisig)
+	return script.Bytes(), nil
+}
+
+// ParseMultisigScript returns the threshold and public keys of a witness
+// script built by MultisigScript
+func ParseMultisigScript(script []byte) (int, [][]byte, error) {
+	if len(script) < 3 || script[len(script)-1] != opCheckMultisig {
+		return 0, nil, fmt.Errorf("not a multisig script")
+	}
+	threshold := int(script[0]) - op1 + 1
+	n := int(script[len(script)-2]) - op1 + 1
+	if threshold < 1 || n < threshold || n > 16 || len(script) != 3+n*34 {
+		return 0, nil, fmt.Errorf("not a multisig script of compressed keys")
+	}
+
+	pubKeys := make([][]byte, n)
+	for i := range pubKeys {
+		offset := 1 + i*34
+		if script[offset] != 33 {
+			return 0, nil, fmt.Errorf("not a multisig script of compressed keys")
+		}
+		pubKeys[i] = script[offset+1 : offset+34]
+	}
+	return threshold, pubKeys, nil
+}
+
+// WitnessScriptHash returns the P2WSH output script paying a witness script
+func WitnessScriptHash(witnessScript []byte) []byte {
+	hash := sha256.Sum256(witnessScript)
+	return append([]byte{opFalse, 32}, hash[:]...)
+}
+
+// AddressScript returns the output script paying a SegWit address
+func AddressScript(address string) ([]byte, error) {
+	_, version, program, err := bech32.DecodeSegWit(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: %w", address, err)
+	}
+
+	versionOp := byte(opFalse)
+	if version > 0 {
+		versionOp = byte(op1 - 1 + int(version))
+	}
+	return append([]byte{versionOp, byte(len(program))}, program...), nil
+}
+
+// ScriptAddress returns the SegWit address of an output script
+func ScriptAddress(pkScript []byte, hrp string) (string, error) {
+	if len(pkScript) < 4 || int(pkScript[1]) != len(pkScript)-2 {
+		return "", fmt.Errorf("not a witness program")
+	}
+
+	var version byte
+	switch op := pkScript[0]; {
+	case op == opFalse:
+		version = 0
+	case op >= op1 && op <= op16:
+		version = op - op1 + 1
+	default:
+		return "", fmt.Errorf("not a witness program")
+	}
+	return bech32.EncodeSegWit(hrp, version, pkScript[2:])
+}
+
+// NullData returns the data pushed by an OP_RETURN output script
+func NullData(pkScript []byte) ([]byte, bool) {
+	if len(pkScript) < 1 || pkScript[0] != opReturn {
+		return nil, false
+	}
+
+	rest := pkScript[1:]
+	if len(rest) == 0 {
+		return nil, true
+	}
+
+	var size int
+	switch op := rest[0]; {
+	case op < opPushData1:
+		size, rest = int(op), rest[1:]
+	case op == opPushData1 && len(rest) >= 2:
+		size, rest = int(rest[1]), rest[2:]
+	case op == opPushData2 && len(rest) >= 3:
+		size, rest = int(binary.LittleEndian.Uint16(rest[1:3])), rest[3:]
+	default:
+		return nil, false
+	}
+	if size != len(rest) {
+		return nil, false
+	}
+	return rest, true
+}
+
+func doubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+func reverseHex(b []byte) string {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[i] = b[len(b)-1-i]
+	}
+	return hex.EncodeToString(reversed)
+}
+
+func writeVarInt(buf *bytes.Buffer, v uint64) {
+	switch {
+	case v < 0xfd:
+		buf.WriteByte(byte(v))
+	case v <= 0xffff:
+		buf.WriteByte(0xfd)
+		binary.Write(buf, binary.LittleEndian, uint16(v))
+	case v <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		binary.Write(buf, binary.LittleEndian, uint32(v))
+	default:
+		buf.WriteByte(0xff)
+		binary.Write(buf, binary.LittleEndian, v)
+	}
+}
+
+func writeVarBytes(buf *bytes.Buffer, b []byte) {
+	writeVarInt(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+func readVarInt(r *bytes.Reader) (uint64, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read varint: %w", err)
+	}
+
+	switch prefix {
+	case 0xfd:
+		var v uint16
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return uint64(v), err
+	case 0xfe:
+		var v uint32
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return uint64(v), err
+	case 0xff:
+		var v uint64
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	default:
+		return uint64(prefix), nil
+	}
+}
+
+func readVarBytes(r *bytes.Reader) ([]byte, error) {
+	size, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(r.Len()) {
+		return nil, fmt.Errorf("length %d exceeds remaining %d bytes", size, r.Len())
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,133 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/bitcoin"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// BitcoinClientAdapter adapts Bitcoin client to UniversalClient interface
+type BitcoinClientAdapter struct {
+	client *bitcoin.Client
+	config *types.ChainConfig
+	logger zerolog.Logger
+}
+
+func (a *BitcoinClientAdapter) GetChainType() types.ChainType {
+	return types.ChainTypeBitcoin
+}
+
+func (a *BitcoinClientAdapter) GetChainID() string {
+	return a.config.ChainID
+}
+
+func (a *BitcoinClientAdapter) GetChainInfo() types.ChainInfo {
+	return types.ChainInfo{
+		Name:        a.config.Name,
+		Type:        types.ChainTypeBitcoin,
+		ChainID:     a.config.ChainID,
+		Environment: a.config.Environment,
+	}
+}
+
+func (a *BitcoinClientAdapter) IsHealthy(ctx context.Context) bool {
+	return a.client.IsHealthy(ctx)
+}
+
+func (a *BitcoinClientAdapter) Close() error {
+	return a.client.Close()
+}
+
+func (a *BitcoinClientAdapter) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return a.client.GetBlockCount(ctx)
+}
+
+func (a *BitcoinClientAdapter) GetBlockByNumber(ctx context.Context, number uint64) (*types.BlockInfo, error) {
+	return a.client.GetBlockByNumber(ctx, number)
+}
+
+func (a *BitcoinClientAdapter) GetBlockTime() time.Duration {
+	return a.config.GetBlockTimeDuration()
+}
+
+func (a *BitcoinClientAdapter) GetConfirmationBlocks() uint64 {
+	return a.config.ConfirmationBlocks
+}
+
+// SendTransaction broadcasts a serialized signed transaction
+func (a *BitcoinClientAdapter) SendTransaction(ctx context.Context, tx interface{}) (string, error) {
+	raw, ok := tx.([]byte)
+	if !ok {
+		return "", fmt.Errorf("invalid transaction type: expected serialized transaction, got %T", tx)
+	}
+	return a.client.SendRawTransaction(ctx, raw)
+}
+
+// GetTransactionStatus gets a transaction's confirmations. A transaction is
+// final once it has the chain's confirmation blocks.
+func (a *BitcoinClientAdapter) GetTransactionStatus(ctx context.Context, txHash string) (*types.TransactionStatus, error) {
+	tx, err := a.client.GetRawTransaction(ctx, txHash)
+	if err != nil {
+		if bitcoin.IsNotFound(err) {
+			return &types.TransactionStatus{Hash: txHash}, nil
+		}
+		return nil, err
+	}
+
+	status := &types.TransactionStatus{
+		Hash:      tx.TxID,
+		Success:   true,
+		Confirmed: tx.Confirmations > 0,
+		Finalized: tx.Confirmations >= a.config.ConfirmationBlocks && tx.Confirmations > 0,
+	}
+	if tx.BlockTime > 0 {
+		status.Timestamp = time.Unix(tx.BlockTime, 0)
+	}
+	return status, nil
+}
+
+func (a *BitcoinClientAdapter) WaitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) error {
+	_, err := a.client.WaitForTransaction(ctx, txHash, 1, timeout)
+	return err
+}
+
+// GetNativeBalance gets an address's balance in satoshis
+func (a *BitcoinClientAdapter) GetNativeBalance(ctx context.Context, address string) (*big.Int, error) {
+	sats, err := a.client.ScanAddressBalance(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(sats), nil
+}
+
+// GetTokenBalance gets an address's BTC balance; Bitcoin has no tokens
+func (a *BitcoinClientAdapter) GetTokenBalance(ctx context.Context, address string, tokenAddress string) (*big.Int, error) {
+	if tokenAddress != "" && tokenAddress != "BTC" {
+		return nil, fmt.Errorf("unsupported Bitcoin token: %s", tokenAddress)
+	}
+	return a.GetNativeBalance(ctx, address)
+}
+
+func (a *BitcoinClientAdapter) SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error) {
+	return nil, types.ErrSubscriptionNotSupported
+}
+
+// EstimateFeeRate estimates the fee rate in sat/vB for confirmation within
+// the chain's confirmation blocks
+func (a *BitcoinClientAdapter) EstimateFeeRate(ctx context.Context) (int64, error) {
+	target := int(a.config.ConfirmationBlocks)
+	if target < 2 {
+		target = 2
+	}
+	return a.client.EstimateFeeRate(ctx, target)
+}
+
+// GetUnderlyingClient returns the underlying Bitcoin client
+func (a *BitcoinClientAdapter) GetUnderlyingClient() *bitcoin.Client {
+	return a.client
+}
@@ -0,0 +1,509 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/rpcpool"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// Client represents a Cosmos SDK chain client. Blocks, events and
+// broadcasts go through CometBFT RPC; accounts, balances and contract
+// queries go through the gRPC-gateway REST API.
+type Client struct {
+	config     *types.ChainConfig
+	httpClient *http.Client
+	pool       *rpcpool.Pool
+	rest       *rpcpool.Pool
+	logger     zerolog.Logger
+}
+
+// NewClient creates a new Cosmos client
+func NewClient(config *types.ChainConfig, logger zerolog.Logger) (*Client, error) {
+	if config.ChainType != types.ChainTypeCosmos {
+		return nil, fmt.Errorf("invalid chain type: expected COSMOS, got %s", config.ChainType)
+	}
+	if len(config.RPCEndpoints) == 0 {
+		return nil, fmt.Errorf("at least one RPC endpoint is required")
+	}
+
+	client := &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger.With().Str("chain", config.Name).Str("type", "cosmos").Logger(),
+	}
+
+	pool, err := rpcpool.New(config.Name, config.RPCEndpoints, rpcpool.Config{
+		Quorum:     config.RPCQuorum,
+		MaxHeadLag: config.RPCMaxHeadLag,
+		Head: func(ctx context.Context, e *rpcpool.Endpoint) (uint64, error) {
+			var status Status
+			if err := client.rpc(ctx, e, "status", nil, &status); err != nil {
+				return 0, err
+			}
+			return uint64(status.SyncInfo.LatestBlockHeight), nil
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	client.pool = pool
+
+	if config.RESTEndpoint != "" {
+		rest, err := rpcpool.New(config.Name, []string{config.RESTEndpoint}, rpcpool.Config{}, logger)
+		if err != nil {
+			return nil, err
+		}
+		client.rest = rest
+	}
+
+	client.logger.Info().
+		Int("endpoints", len(config.RPCEndpoints)).
+		Str("rest", config.RESTEndpoint).
+		Str("chain_id", config.ChainID).
+		Msg("Cosmos client initialized")
+
+	return client, nil
+}
+
+// RPCError is a CometBFT JSON-RPC error
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+func (e *RPCError) Error() string {
+	if e.Data != "" {
+		return fmt.Sprintf("RPC error %d: %s: %s", e.Code, e.Message, e.Data)
+	}
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// APIError is an error response from the REST API
+type APIError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err means the requested transaction or
+// account does not exist
+func IsNotFound(err error) bool {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return strings.Contains(rpcErr.Data, "not found")
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Uint64 is an integer CometBFT and the REST API encode as a JSON string
+type Uint64 uint64
+
+// UnmarshalJSON accepts an integer encoded as a string or a number
+func (u *Uint64) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	if s == "" {
+		*u = 0
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s: %w", data, err)
+	}
+	*u = Uint64(v)
+	return nil
+}
+
+// Status is the result of the status RPC
+type Status struct {
+	NodeInfo struct {
+		Network string `json:"network"`
+	} `json:"node_info"`
+	SyncInfo struct {
+		LatestBlockHeight Uint64    `json:"latest_block_height"`
+		LatestBlockTime   time.Time `json:"latest_block_time"`
+		CatchingUp        bool      `json:"catching_up"`
+	} `json:"sync_info"`
+}
+
+// Block is the result of the block RPC
+type Block struct {
+	BlockID struct {
+		Hash string `json:"hash"`
+	} `json:"block_id"`
+	Block struct {
+		Header struct {
+			ChainID string    `json:"chain_id"`
+			Height  Uint64    `json:"height"`
+			Time    time.Time `json:"time"`
+		} `json:"header"`
+		Data struct {
+			Txs [][]byte `json:"txs"`
+		} `json:"data"`
+	} `json:"block"`
+}
+
+// EventAttribute is a key/value attribute of an ABCI event. CometBFT 0.37
+// and later return attributes as plain strings.
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Event is an ABCI event emitted while executing a transaction
+type Event struct {
+	Type       string           `json:"type"`
+	Attributes []EventAttribute `json:"attributes"`
+}
+
+// Attribute returns the value of an event attribute
+func (e *Event) Attribute(key string) (string, bool) {
+	for _, attr := range e.Attributes {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// TxResult is the result of executing a transaction
+type TxResult struct {
+	Code      uint32  `json:"code"`
+	Codespace string  `json:"codespace"`
+	Log       string  `json:"log"`
+	GasUsed   Uint64  `json:"gas_used"`
+	Events    []Event `json:"events"`
+}
+
+// BlockResults is the result of the block_results RPC
+type BlockResults struct {
+	Height     Uint64     `json:"height"`
+	TxsResults []TxResult `json:"txs_results"`
+}
+
+// TxResponse is the result of the tx RPC
+type TxResponse struct {
+	Hash     string   `json:"hash"`
+	Height   Uint64   `json:"height"`
+	TxResult TxResult `json:"tx_result"`
+}
+
+// BroadcastResult is the result of the broadcast_tx_sync RPC, which
+// returns once the transaction passed CheckTx
+type BroadcastResult struct {
+	Code      uint32 `json:"code"`
+	Codespace string `json:"codespace"`
+	Log       string `json:"log"`
+	Hash      string `json:"hash"`
+}
+
+// Account is the number and sequence of a base account
+type Account struct {
+	Address       string `json:"address"`
+	AccountNumber Uint64 `json:"account_number"`
+	Sequence      Uint64 `json:"sequence"`
+}
+
+// TxHash returns the hash CometBFT identifies a transaction by
+func TxHash(tx []byte) string {
+	hash := sha256.Sum256(tx)
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// get performs a GET request against an endpoint. Client errors other
+// than rate limiting are the endpoint's answer and are not retried on other
+// endpoints, since they would answer the same.
+func (c *Client) get(ctx context.Context, e *rpcpool.Endpoint, path string, query url.Values) ([]byte, error) {
+	endpoint := strings.TrimSuffix(e.URL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, rpcpool.Permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// CometBFT answers JSON-RPC errors with a 500 and an error body
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusInternalServerError && bytes.Contains(data, []byte(`"jsonrpc"`)) {
+		return data, nil
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, rpcpool.Permanent(apiErr)
+	}
+	return nil, apiErr
+}
+
+// callRPC calls a CometBFT RPC method on the best endpoint that answers
+func (c *Client) callRPC(ctx context.Context, method string, params url.Values, out interface{}) error {
+	return c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		return c.rpc(ctx, e, method, params, out)
+	})
+}
+
+// rpc calls a CometBFT RPC method on an endpoint over its URI interface
+func (c *Client) rpc(ctx context.Context, e *rpcpool.Endpoint, method string, params url.Values, out interface{}) error {
+	data, err := c.get(ctx, e, "/"+method, params)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if resp.Error != nil {
+		return rpcpool.Permanent(resp.Error)
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return rpcpool.Permanent(fmt.Errorf("failed to unmarshal result: %w", err))
+	}
+	return nil
+}
+
+// getREST calls the gRPC-gateway REST API
+func (c *Client) getREST(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if c.rest == nil {
+		return fmt.Errorf("REST endpoint not configured")
+	}
+	var data []byte
+	err := c.rest.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		data, err = c.get(ctx, e, path, query)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// GetStatus gets the node status
+func (c *Client) GetStatus(ctx context.Context) (*Status, error) {
+	var status Status
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		if err := c.rpc(ctx, e, "status", nil, &status); err != nil {
+			return err
+		}
+		c.pool.ObserveHead(e, uint64(status.SyncInfo.LatestBlockHeight))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	return &status, nil
+}
+
+// GetLatestBlockNumber gets the latest block height
+func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(status.SyncInfo.LatestBlockHeight), nil
+}
+
+// GetBlock gets the block at a height
+func (c *Client) GetBlock(ctx context.Context, height uint64) (*Block, error) {
+	var block Block
+	params := url.Values{"height": {strconv.FormatUint(height, 10)}}
+	if err := c.callRPC(ctx, "block", params, &block); err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	return &block, nil
+}
+
+// GetBlockByNumber gets block information by height
+func (c *Client) GetBlockByNumber(ctx context.Context, height uint64) (*types.BlockInfo, error) {
+	block, err := c.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.BlockInfo{
+		Number:    uint64(block.Block.Header.Height),
+		Hash:      block.BlockID.Hash,
+		Timestamp: block.Block.Header.Time,
+		TxCount:   len(block.Block.Data.Txs),
+	}, nil
+}
+
+// GetBlockResults gets the results of the transactions in a block, in
+// block order
+func (c *Client) GetBlockResults(ctx context.Context, height uint64) (*BlockResults, error) {
+	var results BlockResults
+	params := url.Values{"height": {strconv.FormatUint(height, 10)}}
+	if err := c.callRPC(ctx, "block_results", params, &results); err != nil {
+		return nil, fmt.Errorf("failed to get block results %d: %w", height, err)
+	}
+	return &results, nil
+}
+
+// GetTx gets a committed transaction by hash
+func (c *Client) GetTx(ctx context.Context, hash string) (*TxResponse, error) {
+	var tx TxResponse
+	params := url.Values{"hash": {"0x" + strings.TrimPrefix(hash, "0x")}}
+	if err := c.callRPC(ctx, "tx", params, &tx); err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+// BroadcastTx broadcasts an encoded TxRaw and returns its hash once the
+// transaction passed CheckTx
+func (c *Client) BroadcastTx(ctx context.Context, tx []byte) (string, error) {
+	var result BroadcastResult
+	params := url.Values{"tx": {"0x" + hex.EncodeToString(tx)}}
+	if err := c.callRPC(ctx, "broadcast_tx_sync", params, &result); err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
+	}
+	if result.Code != 0 {
+		return "", fmt.Errorf("transaction rejected: %s code %d: %s", result.Codespace, result.Code, result.Log)
+	}
+	return result.Hash, nil
+}
+
+// WaitForTx waits until a transaction is committed. CometBFT blocks are
+// final once committed.
+func (c *Client) WaitForTx(ctx context.Context, hash string, timeout time.Duration) (*TxResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(c.config.GetBlockTimeDuration())
+	defer ticker.Stop()
+
+	for {
+		tx, err := c.GetTx(ctx, hash)
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			if tx.TxResult.Code != 0 {
+				return tx, fmt.Errorf("transaction failed: %s code %d: %s",
+					tx.TxResult.Codespace, tx.TxResult.Code, tx.TxResult.Log)
+			}
+			return tx, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout waiting for transaction: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetAccount gets the number and sequence of a base account
+func (c *Client) GetAccount(ctx context.Context, address string) (*Account, error) {
+	var resp struct {
+		Account Account `json:"account"`
+	}
+	if err := c.getREST(ctx, "/cosmos/auth/v1beta1/accounts/"+address, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return &resp.Account, nil
+}
+
+// GetBalance gets an account's bank balance of a denom
+func (c *Client) GetBalance(ctx context.Context, address, denom string) (string, error) {
+	var resp struct {
+		Balance struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	}
+	query := url.Values{"denom": {denom}}
+	if err := c.getREST(ctx, "/cosmos/bank/v1beta1/balances/"+address+"/by_denom", query, &resp); err != nil {
+		return "", fmt.Errorf("failed to get balance: %w", err)
+	}
+	if resp.Balance.Amount == "" {
+		return "0", nil
+	}
+	return resp.Balance.Amount, nil
+}
+
+// QueryContract runs a CosmWasm smart query and decodes its response
+func (c *Client) QueryContract(ctx context.Context, contract string, query interface{}, out interface{}) error {
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	path := fmt.Sprintf("/cosmwasm/wasm/v1/contract/%s/smart/%s", contract, base64.URLEncoding.EncodeToString(queryJSON))
+	if err := c.getREST(ctx, path, nil, &resp); err != nil {
+		return fmt.Errorf("failed to query contract: %w", err)
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal query response: %w", err)
+	}
+	return nil
+}
+
+// IsHealthy checks if the client is healthy. A node that is catching up
+// serves stale state and is reported unhealthy.
+func (c *Client) IsHealthy(ctx context.Context) bool {
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		c.logger.Warn().Err(err).Msg("Health check failed")
+		return false
+	}
+	return !status.SyncInfo.CatchingUp
+}
+
+// Close closes the client
+func (c *Client) Close() error {
+	c.logger.Info().Msg("Closing Cosmos client")
+	c.pool.Close()
+	if c.rest != nil {
+		c.rest.Close()
+	}
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
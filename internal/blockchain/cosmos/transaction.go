@@ -0,0 +1,215 @@
+package cosmos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/crypto/bech32"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// Type URLs of the messages and keys packed into Any
+const (
+	typeURLMsgExecuteContract = "/cosmwasm.wasm.v1.MsgExecuteContract"
+	typeURLSecp256k1PubKey    = "/cosmos.crypto.secp256k1.PubKey"
+)
+
+// signModeDirect signs the protobuf encoded body and auth info
+const signModeDirect = 1
+
+// Coin is an amount of a denom
+type Coin struct {
+	Denom  string
+	Amount string
+}
+
+// MsgExecuteContract executes a CosmWasm contract
+type MsgExecuteContract struct {
+	Sender   string
+	Contract string
+	// Msg is the contract's JSON execute message
+	Msg   []byte
+	Funds []Coin
+}
+
+// Tx is an unsigned transaction with a single secp256k1 signer
+type Tx struct {
+	Messages []MsgExecuteContract
+	Memo     string
+	Fee      []Coin
+	GasLimit uint64
+	// PubKey is the signer's compressed public key
+	PubKey   []byte
+	Sequence uint64
+}
+
+// BodyBytes returns the encoded TxBody
+func (t *Tx) BodyBytes() []byte {
+	var body protoEncoder
+	for _, msg := range t.Messages {
+		var m protoEncoder
+		m.str(1, msg.Sender)
+		m.str(2, msg.Contract)
+		m.bytes(3, msg.Msg)
+		for _, coin := range msg.Funds {
+			m.bytes(5, encodeCoin(coin))
+		}
+		body.bytes(1, encodeAny(typeURLMsgExecuteContract, m.buf.Bytes()))
+	}
+	body.str(2, t.Memo)
+	return body.buf.Bytes()
+}
+
+// AuthInfoBytes returns the encoded AuthInfo
+func (t *Tx) AuthInfoBytes() []byte {
+	var pubKey protoEncoder
+	pubKey.bytes(1, t.PubKey)
+
+	var single protoEncoder
+	single.uint(1, signModeDirect)
+	var modeInfo protoEncoder
+	modeInfo.bytes(1, single.buf.Bytes())
+
+	var signerInfo protoEncoder
+	signerInfo.bytes(1, encodeAny(typeURLSecp256k1PubKey, pubKey.buf.Bytes()))
+	signerInfo.bytes(2, modeInfo.buf.Bytes())
+	signerInfo.uint(3, t.Sequence)
+
+	var fee protoEncoder
+	for _, coin := range t.Fee {
+		fee.bytes(1, encodeCoin(coin))
+	}
+	fee.uint(2, t.GasLimit)
+
+	var authInfo protoEncoder
+	authInfo.bytes(1, signerInfo.buf.Bytes())
+	authInfo.bytes(2, fee.buf.Bytes())
+	return authInfo.buf.Bytes()
+}
+
+// SignBytes returns the encoded SignDoc the signer signs in direct mode
+func (t *Tx) SignBytes(chainID string, accountNumber uint64) []byte {
+	var doc protoEncoder
+	doc.bytes(1, t.BodyBytes())
+	doc.bytes(2, t.AuthInfoBytes())
+	doc.str(3, chainID)
+	doc.uint(4, accountNumber)
+	return doc.buf.Bytes()
+}
+
+// Encode returns the TxRaw to broadcast. The signature is the 64-byte
+// R || S secp256k1 signature of the SHA-256 hash of the sign bytes.
+func (t *Tx) Encode(signature []byte) []byte {
+	var raw protoEncoder
+	raw.bytes(1, t.BodyBytes())
+	raw.bytes(2, t.AuthInfoBytes())
+	raw.bytes(3, signature)
+	return raw.buf.Bytes()
+}
+
+// CompressPublicKey compresses an uncompressed secp256k1 public key
+func CompressPublicKey(pubKey []byte) ([]byte, error) {
+	if len(pubKey) == 33 {
+		return pubKey, nil
+	}
+	key, err := ethcrypto.UnmarshalPubkey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return ethcrypto.CompressPubkey(key), nil
+}
+
+// AccountAddress returns the bech32 account address of a compressed
+// secp256k1 public key
+func AccountAddress(pubKey []byte, prefix string) (string, error) {
+	sha := sha256.Sum256(pubKey)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return bech32.Encode(prefix, hasher.Sum(nil))
+}
+
+// ParseGasPrice parses a gas price such as 0.025uatom
+func ParseGasPrice(s string) (*big.Rat, string, error) {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i <= 0 {
+		return nil, "", fmt.Errorf("invalid gas price: %s", s)
+	}
+
+	price, ok := new(big.Rat).SetString(s[:i])
+	if !ok {
+		return nil, "", fmt.Errorf("invalid gas price amount: %s", s)
+	}
+	return price, s[i:], nil
+}
+
+// FeeForGas returns the fee for a gas limit at a gas price, rounded up
+func FeeForGas(gasPrice string, gasLimit uint64) (Coin, error) {
+	price, denom, err := ParseGasPrice(gasPrice)
+	if err != nil {
+		return Coin{}, err
+	}
+
+	total := new(big.Rat).Mul(price, new(big.Rat).SetInt64(int64(gasLimit)))
+	amount := new(big.Int).Quo(total.Num(), total.Denom())
+	if !total.IsInt() {
+		amount.Add(amount, big.NewInt(1))
+	}
+	return Coin{Denom: denom, Amount: amount.String()}, nil
+}
+
+func encodeCoin(coin Coin) []byte {
+	var e protoEncoder
+	e.str(1, coin.Denom)
+	e.str(2, coin.Amount)
+	return e.buf.Bytes()
+}
+
+func encodeAny(typeURL string, value []byte) []byte {
+	var e protoEncoder
+	e.str(1, typeURL)
+	e.bytes(2, value)
+	return e.buf.Bytes()
+}
+
+// protoEncoder writes the subset of protobuf used by transactions. Fields
+// holding default values are omitted, as proto3 requires for signing.
+type protoEncoder struct {
+	buf bytes.Buffer
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (e *protoEncoder) tag(field int, wireType int) {
+	e.buf.Write(binary.AppendUvarint(nil, uint64(field)<<3|uint64(wireType)))
+}
+
+func (e *protoEncoder) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (e *protoEncoder) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	e.tag(field, wireBytes)
+	e.buf.Write(binary.AppendUvarint(nil, uint64(len(b))))
+	e.buf.Write(b)
+}
+
+func (e *protoEncoder) str(field int, s string) {
+	e.bytes(field, []byte(s))
+}
@@ -0,0 +1,155 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/cosmos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/crypto/bech32"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// CosmosClientAdapter adapts Cosmos client to UniversalClient interface
+type CosmosClientAdapter struct {
+	client *cosmos.Client
+	config *types.ChainConfig
+	logger zerolog.Logger
+}
+
+func (a *CosmosClientAdapter) GetChainType() types.ChainType {
+	return types.ChainTypeCosmos
+}
+
+func (a *CosmosClientAdapter) GetChainID() string {
+	return a.config.ChainID
+}
+
+func (a *CosmosClientAdapter) GetChainInfo() types.ChainInfo {
+	return types.ChainInfo{
+		Name:        a.config.Name,
+		Type:        types.ChainTypeCosmos,
+		ChainID:     a.config.ChainID,
+		Environment: a.config.Environment,
+	}
+}
+
+func (a *CosmosClientAdapter) IsHealthy(ctx context.Context) bool {
+	return a.client.IsHealthy(ctx)
+}
+
+func (a *CosmosClientAdapter) Close() error {
+	return a.client.Close()
+}
+
+func (a *CosmosClientAdapter) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return a.client.GetLatestBlockNumber(ctx)
+}
+
+func (a *CosmosClientAdapter) GetBlockByNumber(ctx context.Context, number uint64) (*types.BlockInfo, error) {
+	return a.client.GetBlockByNumber(ctx, number)
+}
+
+func (a *CosmosClientAdapter) GetBlockTime() time.Duration {
+	return a.config.GetBlockTimeDuration()
+}
+
+func (a *CosmosClientAdapter) GetConfirmationBlocks() uint64 {
+	return a.config.ConfirmationBlocks
+}
+
+// SendTransaction broadcasts an encoded TxRaw
+func (a *CosmosClientAdapter) SendTransaction(ctx context.Context, tx interface{}) (string, error) {
+	txRaw, ok := tx.([]byte)
+	if !ok {
+		return "", fmt.Errorf("invalid transaction type: expected encoded TxRaw, got %T", tx)
+	}
+	return a.client.BroadcastTx(ctx, txRaw)
+}
+
+func (a *CosmosClientAdapter) GetTransactionStatus(ctx context.Context, txHash string) (*types.TransactionStatus, error) {
+	tx, err := a.client.GetTx(ctx, txHash)
+	if err != nil {
+		if cosmos.IsNotFound(err) {
+			return &types.TransactionStatus{Hash: txHash}, nil
+		}
+		return nil, err
+	}
+
+	// Committed blocks are final
+	status := &types.TransactionStatus{
+		Hash:        tx.Hash,
+		BlockNumber: uint64(tx.Height),
+		Success:     tx.TxResult.Code == 0,
+		Confirmed:   true,
+		Finalized:   true,
+		GasUsed:     uint64(tx.TxResult.GasUsed),
+	}
+	if tx.TxResult.Code != 0 {
+		status.Error = tx.TxResult.Log
+	}
+	return status, nil
+}
+
+func (a *CosmosClientAdapter) WaitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) error {
+	_, err := a.client.WaitForTx(ctx, txHash, timeout)
+	return err
+}
+
+// GetNativeBalance gets an account's balance of the fee denom
+func (a *CosmosClientAdapter) GetNativeBalance(ctx context.Context, address string) (*big.Int, error) {
+	_, denom, err := cosmos.ParseGasPrice(a.config.GasPrice)
+	if err != nil {
+		return nil, err
+	}
+	return a.bankBalance(ctx, address, denom)
+}
+
+// GetTokenBalance gets an account's balance of a CW20 token, identified
+// by its contract address, or of a bank denom such as ibc/... or factory/...
+func (a *CosmosClientAdapter) GetTokenBalance(ctx context.Context, address string, tokenAddress string) (*big.Int, error) {
+	if hrp, _, err := bech32.Decode(tokenAddress); err != nil || hrp != a.config.Bech32Prefix {
+		return a.bankBalance(ctx, address, tokenAddress)
+	}
+
+	var resp struct {
+		Balance string `json:"balance"`
+	}
+	query := map[string]interface{}{"balance": map[string]string{"address": address}}
+	if err := a.client.QueryContract(ctx, tokenAddress, query, &resp); err != nil {
+		return nil, err
+	}
+	return parseAmount(resp.Balance)
+}
+
+func (a *CosmosClientAdapter) bankBalance(ctx context.Context, address, denom string) (*big.Int, error) {
+	amount, err := a.client.GetBalance(ctx, address, denom)
+	if err != nil {
+		return nil, err
+	}
+	return parseAmount(amount)
+}
+
+func parseAmount(amount string) (*big.Int, error) {
+	balance, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance: %s", amount)
+	}
+	return balance, nil
+}
+
+func (a *CosmosClientAdapter) SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error) {
+	return nil, types.ErrSubscriptionNotSupported
+}
+
+// GetAccount gets the account number and sequence of a signer
+func (a *CosmosClientAdapter) GetAccount(ctx context.Context, address string) (*cosmos.Account, error) {
+	return a.client.GetAccount(ctx, address)
+}
+
+// GetUnderlyingClient returns the underlying Cosmos client
+func (a *CosmosClientAdapter) GetUnderlyingClient() *cosmos.Client {
+	return a.client
+}
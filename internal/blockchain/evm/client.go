@@ -2,17 +2,21 @@ package evm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/rpcpool"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/zerolog"
 )
 
@@ -20,8 +24,7 @@ import (
 type Client struct {
 	config        *types.ChainConfig
 	clients       []*ethclient.Client
-	wsClient      *ethclient.Client
-	currentIndex  int
+	pool          *rpcpool.Pool
 	mu            sync.RWMutex
 	logger        zerolog.Logger
 	chainInfo     types.ChainInfo
@@ -38,10 +41,9 @@ func NewClient(
 	}
 
 	client := &Client{
-		config:       config,
-		clients:      make([]*ethclient.Client, 0, len(config.RPCEndpoints)),
-		logger:       logger.With().Str("chain", config.Name).Logger(),
-		currentIndex: 0,
+		config:  config,
+		clients: make([]*ethclient.Client, 0, len(config.RPCEndpoints)),
+		logger:  logger.With().Str("chain", config.Name).Logger(),
 		chainInfo: types.ChainInfo{
 			Name:        config.Name,
 			Type:        types.ChainTypeEVM,
@@ -51,6 +53,7 @@ func NewClient(
 	}
 
 	// Connect to all RPC endpoints
+	var endpoints []string
 	for i, endpoint := range config.RPCEndpoints {
 		rpcClient, err := ethclient.Dial(endpoint)
 		if err != nil {
@@ -62,30 +65,29 @@ func NewClient(
 			continue
 		}
 		client.clients = append(client.clients, rpcClient)
+		endpoints = append(endpoints, endpoint)
 	}
 
 	if len(client.clients) == 0 {
 		return nil, fmt.Errorf("failed to connect to any RPC endpoint")
 	}
 
+	pool, err := rpcpool.New(config.Name, endpoints, rpcpool.Config{
+		Quorum:     config.RPCQuorum,
+		MaxHeadLag: config.RPCMaxHeadLag,
+		Head: func(ctx context.Context, e *rpcpool.Endpoint) (uint64, error) {
+			return client.clients[e.Index].BlockNumber(ctx)
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	client.pool = pool
+
 	client.logger.Info().
 		Int("connected_rpcs", len(client.clients)).
 		Msg("EVM client initialized")
 
-	// Connect to WebSocket endpoint if available
-	if config.WSEndpoint != "" {
-		wsClient, err := ethclient.Dial(config.WSEndpoint)
-		if err != nil {
-			client.logger.Warn().
-				Err(err).
-				Str("ws_endpoint", config.WSEndpoint).
-				Msg("Failed to connect to WebSocket endpoint")
-		} else {
-			client.wsClient = wsClient
-			client.logger.Info().Msg("WebSocket connection established")
-		}
-	}
-
 	// Initialize health checker
 	client.healthChecker = NewHealthChecker(client, logger)
 
@@ -117,8 +119,9 @@ func (c *Client) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	var errors []string
+	var errs []string
 
+	c.pool.Close()
 	for i, client := range c.clients {
 		if client != nil {
 			client.Close()
@@ -126,78 +129,31 @@ func (c *Client) Close() error {
 		}
 	}
 
-	if c.wsClient != nil {
-		c.wsClient.Close()
-		c.logger.Debug().Msg("Closed WebSocket client")
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("errors closing clients: %s", strings.Join(errors, "; "))
+	if len(errs) > 0 {
+		return fmt.Errorf("errors closing clients: %s", strings.Join(errs, "; "))
 	}
 
 	return nil
 }
 
-// getClient returns the current active client with failover
-func (c *Client) getClient() *ethclient.Client {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	if len(c.clients) == 0 {
-		return nil
-	}
-
-	return c.clients[c.currentIndex%len(c.clients)]
-}
-
-// rotateClient rotates to the next available client
-func (c *Client) rotateClient() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.currentIndex = (c.currentIndex + 1) % len(c.clients)
-	c.logger.Debug().
-		Int("new_index", c.currentIndex).
-		Msg("Rotated to next RPC client")
-}
-
-// executeWithFailover executes a function with automatic failover
+// executeWithFailover executes a function against the best RPC endpoint,
+// failing over to the next best on error
 func (c *Client) executeWithFailover(ctx context.Context, fn func(*ethclient.Client) error) error {
-	maxRetries := len(c.clients)
-	var lastErr error
-
-	for i := 0; i < maxRetries; i++ {
-		client := c.getClient()
-		if client == nil {
-			return fmt.Errorf("no available clients")
-		}
-
-		err := fn(client)
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-		c.logger.Warn().
-			Err(err).
-			Int("attempt", i+1).
-			Msg("RPC call failed, trying next endpoint")
-
-		c.rotateClient()
-	}
-
-	return fmt.Errorf("all RPC endpoints failed: %w", lastErr)
+	return c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		return fn(c.clients[e.Index])
+	})
 }
 
 // GetLatestBlockNumber returns the latest block number
 func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 	var blockNumber uint64
 
-	err := c.executeWithFailover(ctx, func(client *ethclient.Client) error {
-		bn, err := client.BlockNumber(ctx)
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		bn, err := c.clients[e.Index].BlockNumber(ctx)
 		if err != nil {
 			return err
 		}
+		c.pool.ObserveHead(e, bn)
 		blockNumber = bn
 		return nil
 	})
@@ -205,6 +161,41 @@ func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 	return blockNumber, err
 }
 
+// GetFinalizedBlockNumber returns the highest block that is final under the
+// chain's finality policy. The safe and finalized heads are read with the
+// configured quorum.
+func (c *Client) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	var tag rpc.BlockNumber
+	switch c.config.GetFinality() {
+	case types.FinalitySafe:
+		tag = rpc.SafeBlockNumber
+	case types.FinalityFinalized:
+		tag = rpc.FinalizedBlockNumber
+	default:
+		latestBlock, err := c.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if latestBlock < c.config.ConfirmationBlocks {
+			return 0, nil
+		}
+		return latestBlock - c.config.ConfirmationBlocks, nil
+	}
+
+	result, err := c.pool.Quorum(ctx, func(ctx context.Context, e *rpcpool.Endpoint) (interface{}, string, error) {
+		header, err := c.clients[e.Index].HeaderByNumber(ctx, big.NewInt(tag.Int64()))
+		if err != nil {
+			return nil, "", err
+		}
+		return header.Number.Uint64(), header.Hash().Hex(), nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s block: %w", tag, err)
+	}
+
+	return result.(uint64), nil
+}
+
 // GetBlockByNumber returns block information by number
 func (c *Client) GetBlockByNumber(ctx context.Context, number uint64) (*types.BlockInfo, error) {
 	var block *ethtypes.Header
@@ -265,43 +256,50 @@ func (c *Client) SendTransaction(ctx context.Context, tx interface{}) (string, e
 // GetTransactionStatus returns the status of a transaction
 func (c *Client) GetTransactionStatus(ctx context.Context, txHash string) (*types.TransactionStatus, error) {
 	hash := common.HexToHash(txHash)
-	var receipt *ethtypes.Receipt
 
-	err := c.executeWithFailover(ctx, func(client *ethclient.Client) error {
-		r, err := client.TransactionReceipt(ctx, hash)
+	// Receipts are read with the configured quorum; endpoints agree on a
+	// receipt's block and status, or on the transaction being pending
+	result, err := c.pool.Quorum(ctx, func(ctx context.Context, e *rpcpool.Endpoint) (interface{}, string, error) {
+		r, err := c.clients[e.Index].TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, "pending", nil
+		}
 		if err != nil {
-			return err
+			return nil, "", err
 		}
-		receipt = r
-		return nil
+		return r, fmt.Sprintf("%s:%d", r.BlockHash.Hex(), r.Status), nil
 	})
-
 	if err != nil {
-		// Check if transaction is pending
-		if strings.Contains(err.Error(), "not found") {
-			return &types.TransactionStatus{
-				Hash:      txHash,
-				Success:   false,
-				Confirmed: false,
-				Finalized: false,
-			}, nil
-		}
 		return nil, err
 	}
 
-	// Check if transaction is finalized (enough confirmations)
+	receipt, _ := result.(*ethtypes.Receipt)
+	if receipt == nil {
+		// Transaction is pending
+		return &types.TransactionStatus{
+			Hash:      txHash,
+			Success:   false,
+			Confirmed: false,
+			Finalized: false,
+		}, nil
+	}
+
 	latestBlock, err := c.GetLatestBlockNumber(ctx)
 	if err != nil {
 		return nil, err
 	}
+	finalizedBlock, err := c.GetFinalizedBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	confirmations := latestBlock - receipt.BlockNumber.Uint64()
-	confirmed := confirmations >= c.config.ConfirmationBlocks
-	finalized := confirmations >= c.config.ConfirmationBlocks*2
+	blockNumber := receipt.BlockNumber.Uint64()
+	confirmed := latestBlock >= blockNumber+c.config.ConfirmationBlocks
+	finalized := finalizedBlock >= blockNumber
 
 	return &types.TransactionStatus{
 		Hash:        txHash,
-		BlockNumber: receipt.BlockNumber.Uint64(),
+		BlockNumber: blockNumber,
 		Success:     receipt.Status == 1,
 		Confirmed:   confirmed,
 		Finalized:   finalized,
@@ -309,7 +307,8 @@ func (c *Client) GetTransactionStatus(ctx context.Context, txHash string) (*type
 	}, nil
 }
 
-// WaitForConfirmation waits for a transaction to be confirmed
+// WaitForConfirmation waits for a transaction to be final under the chain's
+// finality policy
 func (c *Client) WaitForConfirmation(ctx context.Context, txHash string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -331,15 +330,19 @@ func (c *Client) WaitForConfirmation(ctx context.Context, txHash string, timeout
 				continue
 			}
 
+			// Pending transactions have no block yet
+			if status.BlockNumber == 0 {
+				continue
+			}
 			if !status.Success {
 				return fmt.Errorf("transaction failed")
 			}
 
-			if status.Confirmed {
+			if status.Finalized {
 				c.logger.Info().
 					Str("tx_hash", txHash).
 					Uint64("block", status.BlockNumber).
-					Msg("Transaction confirmed")
+					Msg("Transaction finalized")
 				return nil
 			}
 		}
@@ -371,18 +374,123 @@ func (c *Client) GetTokenBalance(ctx context.Context, address string, tokenAddre
 	return nil, fmt.Errorf("GetTokenBalance not implemented - requires ERC20 contract integration")
 }
 
-// SubscribeToEvents subscribes to contract events
+// dialWS opens a connection to the WebSocket endpoint. Each subscription
+// owns its connection, so a dropped subscription is resumed by dialing again.
+func (c *Client) dialWS(ctx context.Context) (*ethclient.Client, error) {
+	if c.config.WSEndpoint == "" {
+		return nil, fmt.Errorf("no WebSocket endpoint configured")
+	}
+
+	wsClient, err := ethclient.DialContext(ctx, c.config.WSEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WebSocket endpoint: %w", err)
+	}
+
+	return wsClient, nil
+}
+
+// SubscribeToEvents streams the logs a contract emits as ethtypes.Log
+// values. eventSignature is an event signature such as
+// "TokenLocked(bytes32,address)" or its topic hash; empty matches every
+// event.
 func (c *Client) SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error) {
-	if c.wsClient == nil {
-		return nil, fmt.Errorf("WebSocket client not available")
+	if !common.IsHexAddress(contractAddress) {
+		return nil, fmt.Errorf("invalid contract address: %s", contractAddress)
+	}
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(contractAddress)},
+	}
+	if eventSignature != "" {
+		query.Topics = [][]common.Hash{{eventTopic(eventSignature)}}
+	}
+
+	wsClient, err := c.dialWS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make(chan ethtypes.Log, 100)
+	sub, err := wsClient.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		wsClient.Close()
+		return nil, fmt.Errorf("failed to subscribe to logs: %w", err)
+	}
+
+	events := make(chan interface{}, 100)
+	go func() {
+		defer close(events)
+		defer wsClient.Close()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				c.logger.Warn().Err(err).Msg("Log subscription dropped")
+				return
+			case vLog := <-logs:
+				select {
+				case events <- vLog:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// eventTopic returns the topic of an event signature, or the signature
+// itself if it already is a topic hash
+func eventTopic(signature string) common.Hash {
+	if len(signature) == 66 && strings.HasPrefix(signature, "0x") {
+		return common.HexToHash(signature)
 	}
+	return crypto.Keccak256Hash([]byte(signature))
+}
+
+// HeadSubscription delivers new block headers until it fails or is closed
+type HeadSubscription struct {
+	client  *ethclient.Client
+	sub     ethereum.Subscription
+	headers chan *ethtypes.Header
+}
+
+// Headers returns the new block headers
+func (s *HeadSubscription) Headers() <-chan *ethtypes.Header {
+	return s.headers
+}
+
+// Err returns the error that ends the subscription, e.g. a dropped
+// connection
+func (s *HeadSubscription) Err() <-chan error {
+	return s.sub.Err()
+}
 
-	eventChan := make(chan interface{}, 100)
+// Close ends the subscription and its connection
+func (s *HeadSubscription) Close() {
+	s.sub.Unsubscribe()
+	s.client.Close()
+}
+
+// SubscribeNewHeads subscribes to newHeads over the WebSocket endpoint
+func (c *Client) SubscribeNewHeads(ctx context.Context) (*HeadSubscription, error) {
+	wsClient, err := c.dialWS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(chan *ethtypes.Header, 16)
+	sub, err := wsClient.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		wsClient.Close()
+		return nil, fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
 
-	// This would set up event subscription using FilterLogs
-	// Implementation depends on specific event types
-	// For now, return error indicating not fully implemented
-	return eventChan, fmt.Errorf("SubscribeToEvents not fully implemented")
+	return &HeadSubscription{client: wsClient, sub: sub, headers: headers}, nil
 }
 
 // GetTransactionByHash retrieves a transaction by hash
@@ -519,3 +627,23 @@ func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]
 
 	return logs, nil
 }
+
+// CallContract executes a read-only contract call against the latest block
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	var result []byte
+
+	err := c.executeWithFailover(ctx, func(client *ethclient.Client) error {
+		res, err := client.CallContract(ctx, msg, nil)
+		if err != nil {
+			return err
+		}
+		result = res
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to call contract: %w", err)
+	}
+
+	return result, nil
+}
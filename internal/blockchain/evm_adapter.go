@@ -7,6 +7,7 @@ import (
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/evm"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/ethereum/go-ethereum"
 	"github.com/rs/zerolog"
 )
 
@@ -62,6 +63,11 @@ func (a *EVMClientAdapter) GetBlockTime() time.Duration {
 	return a.client.GetBlockTime()
 }
 
+// GetFinalizedBlockNumber returns the highest final block
+func (a *EVMClientAdapter) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	return a.client.GetFinalizedBlockNumber(ctx)
+}
+
 // GetConfirmationBlocks returns confirmation blocks
 func (a *EVMClientAdapter) GetConfirmationBlocks() uint64 {
 	return a.client.GetConfirmationBlocks()
@@ -97,6 +103,11 @@ func (a *EVMClientAdapter) SubscribeToEvents(ctx context.Context, contractAddres
 	return a.client.SubscribeToEvents(ctx, contractAddress, eventSignature)
 }
 
+// CallContract executes a read-only contract call
+func (a *EVMClientAdapter) CallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return a.client.CallContract(ctx, msg)
+}
+
 // GetUnderlyingClient returns the underlying EVM client for EVM-specific operations
 func (a *EVMClientAdapter) GetUnderlyingClient() *evm.Client {
 	return a.client
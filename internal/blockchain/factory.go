@@ -6,6 +6,8 @@ import (
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/algorand"
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/aptos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/bitcoin"
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/cosmos"
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/evm"
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/near"
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/solana"
@@ -47,6 +49,10 @@ func (f *ClientFactory) CreateClient(
 		return f.createAlgorandClient(ctx, config)
 	case types.ChainTypeAptos:
 		return f.createAptosClient(ctx, config)
+	case types.ChainTypeCosmos:
+		return f.createCosmosClient(ctx, config)
+	case types.ChainTypeBitcoin:
+		return f.createBitcoinClient(ctx, config)
 	default:
 		return nil, fmt.Errorf("unsupported chain type: %s", config.ChainType)
 	}
@@ -188,6 +194,42 @@ func (f *ClientFactory) createAptosClient(
 	}, nil
 }
 
+// createCosmosClient creates a Cosmos SDK blockchain client
+func (f *ClientFactory) createCosmosClient(
+	ctx context.Context,
+	config *types.ChainConfig,
+) (types.UniversalClient, error) {
+	cosmosClient, err := cosmos.NewClient(config, f.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cosmos client: %w", err)
+	}
+
+	// Wrap in adapter
+	return &CosmosClientAdapter{
+		client: cosmosClient,
+		config: config,
+		logger: f.logger,
+	}, nil
+}
+
+// createBitcoinClient creates a Bitcoin blockchain client
+func (f *ClientFactory) createBitcoinClient(
+	ctx context.Context,
+	config *types.ChainConfig,
+) (types.UniversalClient, error) {
+	bitcoinClient, err := bitcoin.NewClient(config, f.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Bitcoin client: %w", err)
+	}
+
+	// Wrap in adapter
+	return &BitcoinClientAdapter{
+		client: bitcoinClient,
+		config: config,
+		logger: f.logger,
+	}, nil
+}
+
 // CloseAllClients closes all blockchain clients
 func CloseAllClients(clients map[string]types.UniversalClient, logger zerolog.Logger) {
 	for name, client := range clients {
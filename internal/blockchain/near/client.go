@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/rpcpool"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/rs/zerolog"
 )
@@ -17,8 +19,8 @@ import (
 // Client represents a NEAR blockchain client
 type Client struct {
 	config     *types.ChainConfig
-	rpcClients []*http.Client
-	endpoints  []string
+	httpClient *http.Client
+	pool       *rpcpool.Pool
 	logger     zerolog.Logger
 	chainInfo  types.ChainInfo
 }
@@ -34,8 +36,7 @@ func NewClient(
 
 	client := &Client{
 		config:     config,
-		rpcClients: make([]*http.Client, 0),
-		endpoints:  config.RPCEndpoints,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
 		logger:     logger.With().Str("chain", config.Name).Logger(),
 		chainInfo: types.ChainInfo{
 			Name:        config.Name,
@@ -45,16 +46,24 @@ func NewClient(
 		},
 	}
 
-	// Create HTTP clients for each endpoint
-	for range config.RPCEndpoints {
-		httpClient := &http.Client{
-			Timeout: 30 * time.Second,
-		}
-		client.rpcClients = append(client.rpcClients, httpClient)
+	pool, err := rpcpool.New(config.Name, config.RPCEndpoints, rpcpool.Config{
+		Quorum:     config.RPCQuorum,
+		MaxHeadLag: config.RPCMaxHeadLag,
+		Head: func(ctx context.Context, e *rpcpool.Endpoint) (uint64, error) {
+			status, err := client.status(ctx, e)
+			if err != nil {
+				return 0, err
+			}
+			return status.SyncInfo.LatestBlockHeight, nil
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
 	}
+	client.pool = pool
 
 	client.logger.Info().
-		Int("endpoints", len(client.endpoints)).
+		Int("endpoints", len(config.RPCEndpoints)).
 		Str("network", config.NetworkID).
 		Msg("NEAR client initialized")
 
@@ -79,13 +88,60 @@ type RPCResponse struct {
 
 // RPCError represents a JSON-RPC error
 type RPCError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    string `json:"data,omitempty"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Name    string          `json:"name,omitempty"`
+	Cause   *RPCErrorCause  `json:"cause,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// RPCErrorCause identifies why a request failed, e.g. UNKNOWN_BLOCK
+type RPCErrorCause struct {
+	Name string `json:"name"`
+}
+
+func (e *RPCError) Error() string {
+	if e.Cause != nil && e.Cause.Name != "" {
+		return fmt.Sprintf("RPC error %d: %s (%s)", e.Code, e.Message, e.Cause.Name)
+	}
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// IsUnknownBlock reports whether err means the node has no block at the
+// requested height. NEAR skips heights when a block producer misses its
+// slot.
+func IsUnknownBlock(err error) bool {
+	return hasErrorCause(err, "UNKNOWN_BLOCK")
 }
 
-// callRPC calls the NEAR RPC API
+// IsUnknownChunk reports whether err means the node does not have the
+// requested chunk, usually because it was garbage collected
+func IsUnknownChunk(err error) bool {
+	return hasErrorCause(err, "UNKNOWN_CHUNK")
+}
+
+func hasErrorCause(err error, cause string) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Cause != nil && rpcErr.Cause.Name == cause
+}
+
+// callRPC calls the NEAR RPC API on the best endpoint that answers
 func (c *Client) callRPC(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		result, err = c.call(ctx, e, method, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// call calls the NEAR RPC API on an endpoint. RPC errors are the node's
+// answer and are not retried on other endpoints.
+func (c *Client) call(ctx context.Context, e *rpcpool.Endpoint, method string, params interface{}) (json.RawMessage, error) {
 	request := RPCRequest{
 		JSONRPC: "2.0",
 		ID:      "dontcare",
@@ -95,45 +151,36 @@ func (c *Client) callRPC(ctx context.Context, method string, params interface{})
 
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, rpcpool.Permanent(fmt.Errorf("failed to marshal request: %w", err))
 	}
 
-	// Try each endpoint
-	for i, endpoint := range c.endpoints {
-		resp, err := c.rpcClients[i].Post(
-			endpoint,
-			"application/json",
-			bytes.NewReader(requestBytes),
-		)
-		if err != nil {
-			c.logger.Warn().
-				Err(err).
-				Str("endpoint", endpoint).
-				Msg("RPC request failed")
-			continue
-		}
-		defer resp.Body.Close()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to read response body")
-			continue
-		}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RPC request failed: %w", err)
+	}
+	defer resp.Body.Close()
 
-		var rpcResp RPCResponse
-		if err := json.Unmarshal(body, &rpcResp); err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to unmarshal response")
-			continue
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-		if rpcResp.Error != nil {
-			return nil, fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
-		}
+	var rpcResp RPCResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
 
-		return rpcResp.Result, nil
+	if rpcResp.Error != nil {
+		return nil, rpcpool.Permanent(rpcResp.Error)
 	}
 
-	return nil, fmt.Errorf("all RPC endpoints failed")
+	return rpcResp.Result, nil
 }
 
 // GetChainType returns the chain type
@@ -159,6 +206,7 @@ func (c *Client) IsHealthy(ctx context.Context) bool {
 
 // Close closes all connections
 func (c *Client) Close() error {
+	c.pool.Close()
 	c.logger.Info().Msg("NEAR client closed")
 	return nil
 }
@@ -178,7 +226,24 @@ type StatusResponse struct {
 
 // GetStatus returns NEAR node status
 func (c *Client) GetStatus(ctx context.Context) (*StatusResponse, error) {
-	result, err := c.callRPC(ctx, "status", []interface{}{})
+	var status *StatusResponse
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		status, err = c.status(ctx, e)
+		if err == nil {
+			c.pool.ObserveHead(e, status.SyncInfo.LatestBlockHeight)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// status returns an endpoint's status
+func (c *Client) status(ctx context.Context, e *rpcpool.Endpoint) (*StatusResponse, error) {
+	result, err := c.call(ctx, e, "status", []interface{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -208,18 +273,82 @@ type BlockResponse struct {
 		Hash      string `json:"hash"`
 		Timestamp uint64 `json:"timestamp"`
 	} `json:"header"`
-	Chunks []struct {
-		ChunkHash string `json:"chunk_hash"`
-	} `json:"chunks"`
+	Chunks []ChunkHeader `json:"chunks"`
 }
 
-// GetBlockByNumber returns block information
-func (c *Client) GetBlockByNumber(ctx context.Context, height uint64) (*types.BlockInfo, error) {
-	params := map[string]interface{}{
-		"block_id": height,
+// ChunkHeader represents the header of a shard chunk. A shard that
+// produced no chunk at a height repeats its previous header, so
+// HeightIncluded is lower than the block height.
+type ChunkHeader struct {
+	ChunkHash      string `json:"chunk_hash"`
+	ShardID        uint64 `json:"shard_id"`
+	HeightIncluded uint64 `json:"height_included"`
+}
+
+// ChunkResponse represents a NEAR shard chunk
+type ChunkResponse struct {
+	Author       string             `json:"author"`
+	Header       ChunkHeader        `json:"header"`
+	Transactions []ChunkTransaction `json:"transactions"`
+	Receipts     []ChunkReceipt     `json:"receipts"`
+}
+
+// ChunkTransaction represents a transaction included in a chunk
+type ChunkTransaction struct {
+	Hash       string `json:"hash"`
+	SignerID   string `json:"signer_id"`
+	ReceiverID string `json:"receiver_id"`
+}
+
+// ChunkReceipt represents a receipt included in a chunk
+type ChunkReceipt struct {
+	ReceiptID     string `json:"receipt_id"`
+	PredecessorID string `json:"predecessor_id"`
+	ReceiverID    string `json:"receiver_id"`
+}
+
+// ExecutionOutcome represents the result of executing a transaction or
+// receipt
+type ExecutionOutcome struct {
+	Logs       []string        `json:"logs"`
+	ReceiptIDs []string        `json:"receipt_ids"`
+	ExecutorID string          `json:"executor_id"`
+	Status     json.RawMessage `json:"status"`
+}
+
+// Succeeded reports whether the execution succeeded. Logs of a failed
+// receipt describe state changes that were rolled back.
+func (o *ExecutionOutcome) Succeeded() bool {
+	var status map[string]json.RawMessage
+	if err := json.Unmarshal(o.Status, &status); err != nil {
+		return false
 	}
+	_, value := status["SuccessValue"]
+	_, receipt := status["SuccessReceiptId"]
+	return value || receipt
+}
+
+// ExecutionOutcomeWithID represents an execution outcome and the
+// transaction or receipt it belongs to
+type ExecutionOutcomeWithID struct {
+	ID        string           `json:"id"`
+	BlockHash string           `json:"block_hash"`
+	Outcome   ExecutionOutcome `json:"outcome"`
+}
 
-	result, err := c.callRPC(ctx, "block", params)
+// TxStatusResponse represents the outcomes of a transaction and every
+// receipt it produced
+type TxStatusResponse struct {
+	Status             json.RawMessage          `json:"status"`
+	TransactionOutcome ExecutionOutcomeWithID   `json:"transaction_outcome"`
+	ReceiptsOutcome    []ExecutionOutcomeWithID `json:"receipts_outcome"`
+}
+
+// GetBlock returns the block at height with its chunk headers
+func (c *Client) GetBlock(ctx context.Context, height uint64) (*BlockResponse, error) {
+	result, err := c.callRPC(ctx, "block", map[string]interface{}{
+		"block_id": height,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -229,6 +358,88 @@ func (c *Client) GetBlockByNumber(ctx context.Context, height uint64) (*types.Bl
 		return nil, fmt.Errorf("failed to unmarshal block: %w", err)
 	}
 
+	return &block, nil
+}
+
+// GetFinalizedBlockNumber returns the height of the highest block that is
+// final under the chain's finality policy
+func (c *Client) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	var finality string
+	switch c.config.GetFinality() {
+	case types.FinalitySafe:
+		finality = "near-final"
+	case types.FinalityFinalized:
+		finality = "final"
+	default:
+		latestBlock, err := c.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if latestBlock < c.config.ConfirmationBlocks {
+			return 0, nil
+		}
+		return latestBlock - c.config.ConfirmationBlocks, nil
+	}
+
+	result, err := c.callRPC(ctx, "block", map[string]interface{}{
+		"finality": finality,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s block: %w", finality, err)
+	}
+
+	var block BlockResponse
+	if err := json.Unmarshal(result, &block); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal block: %w", err)
+	}
+
+	return block.Header.Height, nil
+}
+
+// GetChunk returns a shard chunk with its transactions and receipts
+func (c *Client) GetChunk(ctx context.Context, chunkHash string) (*ChunkResponse, error) {
+	result, err := c.callRPC(ctx, "chunk", map[string]interface{}{
+		"chunk_id": chunkHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var chunk ChunkResponse
+	if err := json.Unmarshal(result, &chunk); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chunk: %w", err)
+	}
+
+	return &chunk, nil
+}
+
+// GetTransactionOutcomes returns the execution outcomes of a transaction and
+// its receipts once they are final
+func (c *Client) GetTransactionOutcomes(ctx context.Context, txHash string, senderID string) (*TxStatusResponse, error) {
+	result, err := c.callRPC(ctx, "EXPERIMENTAL_tx_status", map[string]interface{}{
+		"tx_hash":           txHash,
+		"sender_account_id": senderID,
+		"wait_until":        "FINAL",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var status TxStatusResponse
+	if err := json.Unmarshal(result, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// GetBlockByNumber returns block information
+func (c *Client) GetBlockByNumber(ctx context.Context, height uint64) (*types.BlockInfo, error) {
+	block, err := c.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.BlockInfo{
 		Number:    block.Header.Height,
 		Hash:      block.Header.Hash,
@@ -366,11 +577,10 @@ func (c *Client) GetTokenBalance(ctx context.Context, address string, tokenContr
 	return nil, fmt.Errorf("GetTokenBalance for NEAR not fully implemented")
 }
 
-// SubscribeToEvents subscribes to contract events
+// SubscribeToEvents is not supported: NEAR RPC nodes have no push
+// subscriptions, so events are read by polling receipt outcomes
 func (c *Client) SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error) {
-	// NEAR doesn't have built-in event subscriptions like EVM
-	// Would need to poll for receipts/logs
-	return nil, fmt.Errorf("SubscribeToEvents for NEAR not fully implemented")
+	return nil, types.ErrSubscriptionNotSupported
 }
 
 // ViewFunction calls a view function on a contract
@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 	"time"
 
@@ -62,6 +63,11 @@ func (a *NEARClientAdapter) GetBlockTime() time.Duration {
 	return a.client.GetBlockTime()
 }
 
+// GetFinalizedBlockNumber returns the highest final block
+func (a *NEARClientAdapter) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	return a.client.GetFinalizedBlockNumber(ctx)
+}
+
 // GetConfirmationBlocks returns confirmation blocks
 func (a *NEARClientAdapter) GetConfirmationBlocks() uint64 {
 	return a.client.GetConfirmationBlocks()
@@ -97,6 +103,11 @@ func (a *NEARClientAdapter) SubscribeToEvents(ctx context.Context, contractAddre
 	return a.client.SubscribeToEvents(ctx, contractAddress, eventSignature)
 }
 
+// ViewFunction calls a view function on a contract
+func (a *NEARClientAdapter) ViewFunction(ctx context.Context, contractID string, methodName string, args interface{}) (json.RawMessage, error) {
+	return a.client.ViewFunction(ctx, contractID, methodName, args)
+}
+
 // GetUnderlyingClient returns the underlying NEAR client
 func (a *NEARClientAdapter) GetUnderlyingClient() *near.Client {
 	return a.client
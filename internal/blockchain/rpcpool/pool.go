@@ -0,0 +1,407 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
+	"github.com/rs/zerolog"
+)
+
+// Scoring parameters. An endpoint's score is its latency EWMA in
+// milliseconds, scaled up and increased by its error rate, and increased
+// per block of head lag; the lowest score is preferred.
+const (
+	latencyAlpha   = 0.3
+	errorRateAlpha = 0.2
+	errorPenalty   = 10
+	errorPenaltyMs = 1000
+	lagPenaltyMs   = 100
+
+	// failureThreshold consecutive failures put an endpoint in cooldown,
+	// doubling from minCooldown up to maxCooldown while it keeps failing
+	failureThreshold = 3
+	minCooldown      = 5 * time.Second
+	maxCooldown      = time.Minute
+
+	defaultMaxHeadLag    = 3
+	defaultProbeInterval = 15 * time.Second
+	probeTimeout         = 5 * time.Second
+)
+
+// ErrNoQuorum is returned when too few endpoints agree on a quorum read
+var ErrNoQuorum = errors.New("RPC endpoints did not reach quorum")
+
+// permanentError is an error an endpoint answered with, such as an RPC
+// error, which the other endpoints would answer the same
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as the endpoint's answer rather than a failure
+// of the endpoint. The pool returns it without trying other endpoints and
+// without penalising the endpoint.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Endpoint is an RPC endpoint of a pool. Index is the endpoint's position
+// in the configured list, for clients keeping a connection per endpoint.
+type Endpoint struct {
+	Index int
+	URL   string
+	label string
+
+	// Guarded by the pool's mutex
+	latency       float64
+	errorRate     float64
+	failures      int
+	head          uint64
+	cooldownUntil time.Time
+}
+
+// Config configures a pool
+type Config struct {
+	// Quorum is the number of endpoints that must agree on quorum reads.
+	// 0 or 1 disables quorum.
+	Quorum int
+
+	// MaxHeadLag is the number of blocks an endpoint may trail the best
+	// head before it is only used when no current endpoint answers
+	MaxHeadLag uint64
+
+	// Head fetches an endpoint's head block for lag tracking. Pools of
+	// more than one endpoint probe every endpoint each ProbeInterval.
+	Head          func(ctx context.Context, e *Endpoint) (uint64, error)
+	ProbeInterval time.Duration
+}
+
+// Pool selects among a chain's RPC endpoints by latency, error rate and
+// head lag
+type Pool struct {
+	chain     string
+	config    Config
+	endpoints []*Endpoint
+	mu        sync.Mutex
+	now       func() time.Time
+	stopChan  chan struct{}
+	stopOnce  sync.Once
+	logger    zerolog.Logger
+}
+
+// New creates a pool of a chain's endpoints and starts probing their heads
+func New(chain string, urls []string, config Config, logger zerolog.Logger) (*Pool, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("at least one RPC endpoint is required")
+	}
+	if config.Quorum > len(urls) {
+		return nil, fmt.Errorf("quorum of %d exceeds the %d RPC endpoints", config.Quorum, len(urls))
+	}
+	if config.MaxHeadLag == 0 {
+		config.MaxHeadLag = defaultMaxHeadLag
+	}
+	if config.ProbeInterval == 0 {
+		config.ProbeInterval = defaultProbeInterval
+	}
+
+	p := &Pool{
+		chain:    chain,
+		config:   config,
+		now:      time.Now,
+		stopChan: make(chan struct{}),
+		logger:   logger.With().Str("chain", chain).Str("component", "rpc_pool").Logger(),
+	}
+	for i, rawURL := range urls {
+		p.endpoints = append(p.endpoints, &Endpoint{
+			Index: i,
+			URL:   rawURL,
+			label: endpointLabel(rawURL),
+		})
+	}
+
+	// A single endpoint has no peers to lag behind
+	if config.Head != nil && len(p.endpoints) > 1 {
+		go p.probe()
+	}
+
+	return p, nil
+}
+
+// endpointLabel identifies an endpoint in metrics and logs by its host
+func endpointLabel(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+	return u.Host
+}
+
+// Close stops probing
+func (p *Pool) Close() {
+	p.stopOnce.Do(func() { close(p.stopChan) })
+}
+
+// Endpoints returns the endpoints, best first
+func (p *Pool) Endpoints() []*Endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	best := p.bestHead()
+
+	ranked := append([]*Endpoint(nil), p.endpoints...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ti, tj := p.tier(ranked[i], now, best), p.tier(ranked[j], now, best)
+		if ti != tj {
+			return ti < tj
+		}
+		return p.score(ranked[i], best) < p.score(ranked[j], best)
+	})
+	return ranked
+}
+
+// tier groups endpoints by availability: current endpoints, then lagging
+// ones, then ones in cooldown
+func (p *Pool) tier(e *Endpoint, now time.Time, best uint64) int {
+	switch {
+	case now.Before(e.cooldownUntil):
+		return 2
+	case best-e.head > p.config.MaxHeadLag && e.head > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// score returns an endpoint's selection score; lower is better
+func (p *Pool) score(e *Endpoint, best uint64) float64 {
+	// Failed requests do not update the latency, so the error rate also
+	// adds a fixed penalty for endpoints that fail fast
+	score := e.latency*(1+errorPenalty*e.errorRate) + errorPenaltyMs*e.errorRate
+	if e.head > 0 {
+		score += float64(best-e.head) * lagPenaltyMs
+	}
+	return score
+}
+
+// bestHead returns the highest head reported by an endpoint. Only current
+// reports count, so an endpoint that once reported a bogus head does not
+// make the others lag forever.
+func (p *Pool) bestHead() uint64 {
+	var best uint64
+	for _, e := range p.endpoints {
+		if e.head > best {
+			best = e.head
+		}
+	}
+	return best
+}
+
+// Do calls fn against the endpoints, best first, until one succeeds
+func (p *Pool) Do(ctx context.Context, fn func(ctx context.Context, e *Endpoint) error) error {
+	var lastErr error
+	for _, e := range p.Endpoints() {
+		start := p.now()
+		err := fn(ctx, e)
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			p.record(e, p.now().Sub(start), nil)
+			return permanent.err
+		}
+		if err != nil && ctx.Err() != nil {
+			return err
+		}
+		p.record(e, p.now().Sub(start), err)
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+		p.logger.Warn().
+			Err(err).
+			Str("endpoint", e.label).
+			Msg("RPC request failed, trying next endpoint")
+	}
+
+	return fmt.Errorf("all RPC endpoints failed: %w", lastErr)
+}
+
+// QuorumFunc performs a read against an endpoint, returning the result
+// and a key identifying it. Endpoints agree when their keys are equal.
+type QuorumFunc func(ctx context.Context, e *Endpoint) (result interface{}, key string, err error)
+
+// Quorum reads from every endpoint concurrently and returns the first
+// result that Quorum endpoints agree on. Without a quorum configured it
+// reads from the best endpoint as Do does.
+func (p *Pool) Quorum(ctx context.Context, fn QuorumFunc) (interface{}, error) {
+	if p.config.Quorum <= 1 {
+		var result interface{}
+		err := p.Do(ctx, func(ctx context.Context, e *Endpoint) error {
+			var err error
+			result, _, err = fn(ctx, e)
+			return err
+		})
+		return result, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type answer struct {
+		result interface{}
+		key    string
+		err    error
+	}
+
+	endpoints := p.Endpoints()
+	answers := make(chan answer, len(endpoints))
+	for _, e := range endpoints {
+		go func(e *Endpoint) {
+			start := p.now()
+			result, key, err := fn(ctx, e)
+
+			// Reads cancelled once the quorum was reached say nothing
+			// about the endpoint
+			var permanent *permanentError
+			switch {
+			case errors.As(err, &permanent):
+				p.record(e, p.now().Sub(start), nil)
+			case err == nil || ctx.Err() == nil:
+				p.record(e, p.now().Sub(start), err)
+			}
+			answers <- answer{result: result, key: key, err: err}
+		}(e)
+	}
+
+	votes := make(map[string]int)
+	var lastErr error
+	for range endpoints {
+		a := <-answers
+		if a.err != nil {
+			lastErr = a.err
+			continue
+		}
+		votes[a.key]++
+		if votes[a.key] >= p.config.Quorum {
+			monitoring.RPCQuorumReads.WithLabelValues(p.chain, "agreed").Inc()
+			return a.result, nil
+		}
+	}
+
+	monitoring.RPCQuorumReads.WithLabelValues(p.chain, "failed").Inc()
+	p.logger.Warn().
+		Err(lastErr).
+		Int("answers", len(votes)).
+		Int("quorum", p.config.Quorum).
+		Msg("RPC endpoints did not reach quorum")
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("%w: %d distinct answers, last error: %v", ErrNoQuorum, len(votes), lastErr)
+	}
+	return nil, fmt.Errorf("%w: %d distinct answers", ErrNoQuorum, len(votes))
+}
+
+// ObserveHead records an endpoint's head block
+func (p *Pool) ObserveHead(e *Endpoint, head uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.head = head
+	best := p.bestHead()
+	for _, other := range p.endpoints {
+		if other.head > 0 {
+			monitoring.RPCEndpointHeadLag.WithLabelValues(p.chain, other.label).Set(float64(best - other.head))
+		}
+	}
+}
+
+// record updates an endpoint's latency and error rate after a request
+func (p *Pool) record(e *Endpoint, elapsed time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	monitoring.RPCRequestsTotal.WithLabelValues(p.chain, e.label, result).Inc()
+	monitoring.RPCRequestDuration.WithLabelValues(p.chain, e.label).Observe(elapsed.Seconds())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		e.errorRate = errorRateAlpha + (1-errorRateAlpha)*e.errorRate
+		e.failures++
+		if e.failures >= failureThreshold {
+			cooldown := minCooldown << uint(e.failures-failureThreshold)
+			if cooldown > maxCooldown || cooldown <= 0 {
+				cooldown = maxCooldown
+			}
+			e.cooldownUntil = p.now().Add(cooldown)
+		}
+	} else {
+		ms := float64(elapsed) / float64(time.Millisecond)
+		if e.latency == 0 {
+			e.latency = ms
+		} else {
+			e.latency = latencyAlpha*ms + (1-latencyAlpha)*e.latency
+		}
+		e.errorRate = (1 - errorRateAlpha) * e.errorRate
+		e.failures = 0
+		e.cooldownUntil = time.Time{}
+	}
+
+	monitoring.RPCEndpointScore.WithLabelValues(p.chain, e.label).Set(p.score(e, p.bestHead()))
+}
+
+// probe periodically fetches every endpoint's head
+func (p *Pool) probe() {
+	p.probeHeads()
+
+	ticker := time.NewTicker(p.config.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			p.probeHeads()
+		}
+	}
+}
+
+// probeHeads fetches every endpoint's head concurrently. Probes also keep
+// the latency of endpoints that are not being selected current.
+func (p *Pool) probeHeads() {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *Endpoint) {
+			defer wg.Done()
+
+			start := p.now()
+			head, err := p.config.Head(ctx, e)
+			p.record(e, p.now().Sub(start), err)
+			if err != nil {
+				p.logger.Debug().Err(err).Str("endpoint", e.label).Msg("Head probe failed")
+				return
+			}
+			p.ObserveHead(e, head)
+		}(e)
+	}
+	wg.Wait()
+}
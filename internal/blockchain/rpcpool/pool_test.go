@@ -0,0 +1,272 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeEndpoint scripts an endpoint's latency, failures, head and answers
+type fakeEndpoint struct {
+	latency time.Duration
+	fail    bool
+	head    uint64
+	answer  string
+}
+
+// fakeNetwork serves scripted endpoints on a fake clock that advances by
+// each request's latency
+type fakeNetwork struct {
+	mu        sync.Mutex
+	now       time.Time
+	endpoints []*fakeEndpoint
+	calls     []int
+}
+
+func (n *fakeNetwork) clock() time.Time {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.now
+}
+
+// call performs a request against endpoint i
+func (n *fakeNetwork) call(i int) (*fakeEndpoint, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	endpoint := n.endpoints[i]
+	n.calls = append(n.calls, i)
+	n.now = n.now.Add(endpoint.latency)
+	if endpoint.fail {
+		return nil, fmt.Errorf("endpoint %d unavailable", i)
+	}
+	return endpoint, nil
+}
+
+func (n *fakeNetwork) takeCalls() []int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	calls := n.calls
+	n.calls = nil
+	return calls
+}
+
+func newTestPool(t *testing.T, quorum int, endpoints ...*fakeEndpoint) (*Pool, *fakeNetwork) {
+	t.Helper()
+
+	network := &fakeNetwork{now: time.Unix(1792396800, 0), endpoints: endpoints}
+	urls := make([]string, len(endpoints))
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://rpc%d.example.com", i)
+	}
+
+	// Probes are run by the tests rather than in the background
+	pool, err := New("testnet", urls, Config{Quorum: quorum}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	pool.now = network.clock
+	pool.config.Head = func(ctx context.Context, e *Endpoint) (uint64, error) {
+		endpoint, err := network.call(e.Index)
+		if err != nil {
+			return 0, err
+		}
+		return endpoint.head, nil
+	}
+	t.Cleanup(pool.Close)
+
+	return pool, network
+}
+
+// get reads an answer from the best endpoint
+func get(pool *Pool, network *fakeNetwork) (string, error) {
+	var answer string
+	err := pool.Do(context.Background(), func(ctx context.Context, e *Endpoint) error {
+		endpoint, err := network.call(e.Index)
+		if err != nil {
+			return err
+		}
+		answer = endpoint.answer
+		return nil
+	})
+	return answer, err
+}
+
+func TestDoPrefersLowLatency(t *testing.T) {
+	pool, network := newTestPool(t, 0,
+		&fakeEndpoint{latency: 80 * time.Millisecond, answer: "slow"},
+		&fakeEndpoint{latency: 10 * time.Millisecond, answer: "fast"},
+		&fakeEndpoint{latency: 40 * time.Millisecond, answer: "medium"},
+	)
+
+	// Untried endpoints are tried first, once each
+	for i := 0; i < 3; i++ {
+		if _, err := get(pool, network); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+	network.takeCalls()
+
+	for i := 0; i < 5; i++ {
+		answer, err := get(pool, network)
+		if err != nil || answer != "fast" {
+			t.Fatalf("get = %q, %v; want the fast endpoint", answer, err)
+		}
+	}
+}
+
+func TestDoFailsOverAndCoolsDown(t *testing.T) {
+	pool, network := newTestPool(t, 0,
+		&fakeEndpoint{latency: time.Millisecond, fail: true},
+		&fakeEndpoint{latency: 20 * time.Millisecond, answer: "ok"},
+	)
+
+	answer, err := get(pool, network)
+	if err != nil || answer != "ok" {
+		t.Fatalf("get = %q, %v; want failover", answer, err)
+	}
+	if calls := network.takeCalls(); len(calls) != 2 || calls[0] != 0 || calls[1] != 1 {
+		t.Fatalf("calls %v, want [0 1]", calls)
+	}
+
+	// A failing endpoint's error rate outweighs its low latency
+	if _, err := get(pool, network); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if calls := network.takeCalls(); len(calls) != 1 || calls[0] != 1 {
+		t.Errorf("calls %v, want the healthy endpoint only", calls)
+	}
+
+	// Once every endpoint fails the failing one goes into cooldown
+	network.endpoints[1].fail = true
+	for i := 0; i < failureThreshold; i++ {
+		get(pool, network)
+	}
+	if !network.clock().Before(pool.endpoints[0].cooldownUntil) {
+		t.Errorf("endpoint not in cooldown after %d failures", pool.endpoints[0].failures)
+	}
+
+	// Endpoints in cooldown are still tried when nothing else answers
+	network.endpoints[0].fail = false
+	network.endpoints[0].answer = "recovered"
+	answer, err = get(pool, network)
+	if err != nil || answer != "recovered" {
+		t.Errorf("get = %q, %v; want the recovered endpoint", answer, err)
+	}
+	if pool.endpoints[0].failures != 0 || !pool.endpoints[0].cooldownUntil.IsZero() {
+		t.Errorf("success did not reset the cooldown")
+	}
+}
+
+func TestDoPermanentError(t *testing.T) {
+	pool, network := newTestPool(t, 0,
+		&fakeEndpoint{latency: time.Millisecond},
+		&fakeEndpoint{latency: time.Millisecond},
+	)
+
+	notFound := errors.New("transaction not found")
+	err := pool.Do(context.Background(), func(ctx context.Context, e *Endpoint) error {
+		network.call(e.Index)
+		return Permanent(notFound)
+	})
+	if err != notFound {
+		t.Errorf("Do = %v, want the endpoint's answer", err)
+	}
+	if calls := network.takeCalls(); len(calls) != 1 {
+		t.Errorf("calls %v, want no failover", calls)
+	}
+	if pool.endpoints[0].errorRate != 0 {
+		t.Errorf("endpoint penalised for its answer")
+	}
+}
+
+func TestLaggingEndpointDeprioritized(t *testing.T) {
+	pool, network := newTestPool(t, 0,
+		&fakeEndpoint{latency: 5 * time.Millisecond, head: 1000, answer: "stale"},
+		&fakeEndpoint{latency: 50 * time.Millisecond, head: 1010, answer: "current"},
+		&fakeEndpoint{latency: 60 * time.Millisecond, head: 1009, answer: "current"},
+	)
+
+	pool.probeHeads()
+	network.takeCalls()
+
+	ranked := pool.Endpoints()
+	if ranked[0].Index != 1 || ranked[2].Index != 0 {
+		t.Errorf("ranked %d, %d, %d; want the lagging endpoint last", ranked[0].Index, ranked[1].Index, ranked[2].Index)
+	}
+	answer, err := get(pool, network)
+	if err != nil || answer != "current" {
+		t.Errorf("get = %q, %v; want a current endpoint", answer, err)
+	}
+
+	// The lagging endpoint is used once it catches up
+	network.endpoints[0].head = 1010
+	pool.probeHeads()
+	if ranked := pool.Endpoints(); ranked[0].Index != 0 {
+		t.Errorf("best endpoint %d after catching up, want 0", ranked[0].Index)
+	}
+
+	// And still answers when the current endpoints fail
+	network.endpoints[0].head = 1000
+	pool.probeHeads()
+	network.endpoints[1].fail = true
+	network.endpoints[2].fail = true
+	answer, err = get(pool, network)
+	if err != nil || answer != "stale" {
+		t.Errorf("get = %q, %v; want the lagging endpoint as a last resort", answer, err)
+	}
+}
+
+func TestQuorum(t *testing.T) {
+	read := func(pool *Pool, network *fakeNetwork) (interface{}, error) {
+		return pool.Quorum(context.Background(), func(ctx context.Context, e *Endpoint) (interface{}, string, error) {
+			endpoint, err := network.call(e.Index)
+			if err != nil {
+				return nil, "", err
+			}
+			return endpoint.answer, endpoint.answer, nil
+		})
+	}
+
+	tests := []struct {
+		name    string
+		answers []string
+		fail    int
+		want    string
+	}{
+		{name: "agreement despite a divergent endpoint", answers: []string{"a", "b", "a"}, fail: -1, want: "a"},
+		{name: "agreement despite a failed endpoint", answers: []string{"a", "a", "a"}, fail: 1, want: "a"},
+		{name: "no agreement", answers: []string{"a", "b", "c"}, fail: -1},
+		{name: "too few answers", answers: []string{"a", "a", "b"}, fail: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := make([]*fakeEndpoint, len(tt.answers))
+			for i, answer := range tt.answers {
+				endpoints[i] = &fakeEndpoint{latency: time.Millisecond, answer: answer, fail: i == tt.fail}
+			}
+			pool, network := newTestPool(t, 2, endpoints...)
+
+			result, err := read(pool, network)
+			if tt.want == "" {
+				if !errors.Is(err, ErrNoQuorum) {
+					t.Errorf("Quorum = %v, %v; want ErrNoQuorum", result, err)
+				}
+				return
+			}
+			if err != nil || result != tt.want {
+				t.Errorf("Quorum = %v, %v; want %s", result, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := New("testnet", []string{"https://rpc.example.com"}, Config{Quorum: 2}, zerolog.Nop()); err == nil {
+		t.Errorf("New accepted a quorum larger than the pool")
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/rpcpool"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
 	"github.com/rs/zerolog"
 )
 
@@ -16,6 +18,7 @@ import (
 type Client struct {
 	config     *types.ChainConfig
 	rpcClients []*rpc.Client
+	pool       *rpcpool.Pool
 	wsClient   *rpc.Client
 	logger     zerolog.Logger
 	chainInfo  types.ChainInfo
@@ -52,6 +55,18 @@ func NewClient(
 		return nil, fmt.Errorf("no RPC clients initialized")
 	}
 
+	pool, err := rpcpool.New(config.Name, config.RPCEndpoints, rpcpool.Config{
+		Quorum:     config.RPCQuorum,
+		MaxHeadLag: config.RPCMaxHeadLag,
+		Head: func(ctx context.Context, e *rpcpool.Endpoint) (uint64, error) {
+			return client.rpcClients[e.Index].GetSlot(ctx, client.getCommitment())
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	client.pool = pool
+
 	// Connect to WebSocket endpoint if available
 	if config.WSEndpoint != "" {
 		wsClient := rpc.New(config.WSEndpoint)
@@ -88,25 +103,51 @@ func (c *Client) IsHealthy(ctx context.Context) bool {
 
 // Close closes all connections
 func (c *Client) Close() error {
-	// Solana client doesn't require explicit closing
+	// Solana RPC clients don't require explicit closing
+	c.pool.Close()
 	c.logger.Info().Msg("Solana client closed")
 	return nil
 }
 
 // GetSlot returns the current slot
 func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
-	commitment := c.getCommitment()
+	return c.getSlot(ctx, c.getCommitment())
+}
+
+// GetFinalizedBlockNumber returns the highest slot that is final under the
+// chain's finality policy
+func (c *Client) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	if c.config.GetFinality() != types.FinalityDepth {
+		return c.getSlot(ctx, c.finalityCommitment())
+	}
 
-	for _, client := range c.rpcClients {
-		slot, err := client.GetSlot(ctx, commitment)
+	slot, err := c.GetSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if slot < c.config.ConfirmationBlocks {
+		return 0, nil
+	}
+	return slot - c.config.ConfirmationBlocks, nil
+}
+
+// getSlot returns the latest slot at a commitment
+func (c *Client) getSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	var slot uint64
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		s, err := c.rpcClients[e.Index].GetSlot(ctx, commitment)
 		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to get slot from endpoint")
-			continue
+			return err
 		}
-		return slot, nil
+		c.pool.ObserveHead(e, s)
+		slot = s
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get slot: %w", err)
 	}
 
-	return 0, fmt.Errorf("failed to get slot from all endpoints")
+	return slot, nil
 }
 
 // GetLatestBlockNumber returns the latest slot (Solana's equivalent of block number)
@@ -116,29 +157,28 @@ func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 
 // GetBlockByNumber returns block information by slot
 func (c *Client) GetBlockByNumber(ctx context.Context, slot uint64) (*types.BlockInfo, error) {
-	for _, client := range c.rpcClients {
-		block, err := client.GetBlock(ctx, slot)
+	var block *rpc.GetBlockResult
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		b, err := c.rpcClients[e.Index].GetBlock(ctx, slot)
 		if err != nil {
-			c.logger.Warn().
-				Err(err).
-				Uint64("slot", slot).
-				Msg("Failed to get block")
-			continue
+			return err
 		}
-
-		if block == nil {
-			continue
+		if b == nil {
+			return fmt.Errorf("block %d not available", slot)
 		}
-
-		return &types.BlockInfo{
-			Number:    slot,
-			Hash:      block.Blockhash.String(),
-			Timestamp: time.Unix(int64(*block.BlockTime), 0),
-			TxCount:   len(block.Transactions),
-		}, nil
+		block = b
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", slot, err)
 	}
 
-	return nil, fmt.Errorf("failed to get block %d from all endpoints", slot)
+	return &types.BlockInfo{
+		Number:    slot,
+		Hash:      block.Blockhash.String(),
+		Timestamp: time.Unix(int64(*block.BlockTime), 0),
+		TxCount:   len(block.Transactions),
+	}, nil
 }
 
 // GetBlockTime returns the expected slot time
@@ -161,8 +201,10 @@ func (c *Client) SendTransaction(ctx context.Context, tx interface{}) (string, e
 		return "", fmt.Errorf("invalid transaction type: expected *solana.Transaction")
 	}
 
-	for _, client := range c.rpcClients {
-		sig, err := client.SendTransactionWithOpts(
+	var sig solana.Signature
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		sig, err = c.rpcClients[e.Index].SendTransactionWithOpts(
 			ctx,
 			solTx,
 			rpc.TransactionOpts{
@@ -170,19 +212,17 @@ func (c *Client) SendTransaction(ctx context.Context, tx interface{}) (string, e
 				PreflightCommitment: c.getCommitment(),
 			},
 		)
-		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to send transaction")
-			continue
-		}
-
-		c.logger.Info().
-			Str("signature", sig.String()).
-			Msg("Solana transaction sent")
-
-		return sig.String(), nil
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
 
-	return "", fmt.Errorf("failed to send transaction to all endpoints")
+	c.logger.Info().
+		Str("signature", sig.String()).
+		Msg("Solana transaction sent")
+
+	return sig.String(), nil
 }
 
 // GetTransactionStatus returns transaction status
@@ -192,46 +232,56 @@ func (c *Client) GetTransactionStatus(ctx context.Context, signature string) (*t
 		return nil, fmt.Errorf("invalid signature: %w", err)
 	}
 
-	for _, client := range c.rpcClients {
-		result, err := client.GetSignatureStatuses(ctx, true, sig)
+	// Statuses are read with the configured quorum; endpoints agree on a
+	// transaction's slot, outcome and confirmation status, or on it being
+	// unknown
+	result, err := c.pool.Quorum(ctx, func(ctx context.Context, e *rpcpool.Endpoint) (interface{}, string, error) {
+		result, err := c.rpcClients[e.Index].GetSignatureStatuses(ctx, true, sig)
 		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to get signature status")
-			continue
+			return nil, "", err
 		}
-
 		if result == nil || len(result.Value) == 0 || result.Value[0] == nil {
-			return &types.TransactionStatus{
-				Hash:      signature,
-				Success:   false,
-				Confirmed: false,
-				Finalized: false,
-			}, nil
+			return nil, "unknown", nil
 		}
-
 		status := result.Value[0]
+		return status, fmt.Sprintf("%d:%t:%s", status.Slot, status.Err == nil, status.ConfirmationStatus), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction status: %w", err)
+	}
 
-		var success bool
-		if status.Err != nil {
-			success = false
-		} else {
-			success = true
-		}
+	status, _ := result.(*rpc.SignatureStatusesResult)
+	if status == nil {
+		return &types.TransactionStatus{
+			Hash:      signature,
+			Success:   false,
+			Confirmed: false,
+			Finalized: false,
+		}, nil
+	}
 
-		confirmed := status.ConfirmationStatus == rpc.ConfirmationStatusConfirmed ||
-			status.ConfirmationStatus == rpc.ConfirmationStatusFinalized
+	var success bool
+	if status.Err != nil {
+		success = false
+	} else {
+		success = true
+	}
 
-		finalized := status.ConfirmationStatus == rpc.ConfirmationStatusFinalized
+	confirmed := status.ConfirmationStatus == rpc.ConfirmationStatusConfirmed ||
+		status.ConfirmationStatus == rpc.ConfirmationStatusFinalized
 
-		return &types.TransactionStatus{
-			Hash:        signature,
-			BlockNumber: status.Slot,
-			Success:     success,
-			Confirmed:   confirmed,
-			Finalized:   finalized,
-		}, nil
+	finalized := status.ConfirmationStatus == rpc.ConfirmationStatusFinalized
+	if c.finalityCommitment() == rpc.CommitmentConfirmed {
+		finalized = confirmed
 	}
 
-	return nil, fmt.Errorf("failed to get transaction status from all endpoints")
+	return &types.TransactionStatus{
+		Hash:        signature,
+		BlockNumber: status.Slot,
+		Success:     success,
+		Confirmed:   confirmed,
+		Finalized:   finalized,
+	}, nil
 }
 
 // WaitForConfirmation waits for transaction confirmation
@@ -280,17 +330,17 @@ func (c *Client) GetNativeBalance(ctx context.Context, address string) (*big.Int
 
 	commitment := c.getCommitment()
 
-	for _, client := range c.rpcClients {
-		balance, err := client.GetBalance(ctx, pubkey, commitment)
-		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to get balance")
-			continue
-		}
-
-		return big.NewInt(int64(balance.Value)), nil
+	var balance *rpc.GetBalanceResult
+	err = c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		balance, err = c.rpcClients[e.Index].GetBalance(ctx, pubkey, commitment)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
-	return nil, fmt.Errorf("failed to get balance from all endpoints")
+	return big.NewInt(int64(balance.Value)), nil
 }
 
 // GetTokenBalance returns SPL token balance
@@ -300,11 +350,50 @@ func (c *Client) GetTokenBalance(ctx context.Context, address string, tokenAddre
 	return nil, fmt.Errorf("GetTokenBalance for Solana not fully implemented")
 }
 
-// SubscribeToEvents subscribes to program events
+// SubscribeToEvents streams the logs of transactions that mention a program
+// as *ws.LogResult values. Log subscriptions cannot filter by event, so
+// eventSignature is unused.
 func (c *Client) SubscribeToEvents(ctx context.Context, programAddress string, eventSignature string) (chan interface{}, error) {
-	// This would use WebSocket subscriptions to monitor program logs
-	// Placeholder implementation
-	return nil, fmt.Errorf("SubscribeToEvents for Solana not fully implemented")
+	programID, err := solana.PublicKeyFromBase58(programAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid program address: %w", err)
+	}
+
+	sub, err := c.SubscribeProgramLogs(ctx, programID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan interface{}, 100)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		sub.Close()
+	}()
+	go func() {
+		defer close(events)
+		defer close(done)
+		for {
+			result, err := sub.Recv()
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Warn().Err(err).Msg("Program log subscription dropped")
+				}
+				return
+			}
+
+			select {
+			case events <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
 }
 
 // getCommitment returns the configured commitment level
@@ -323,37 +412,178 @@ func (c *Client) getCommitment() rpc.CommitmentType {
 
 // GetProgramAccounts retrieves accounts owned by a program
 func (c *Client) GetProgramAccounts(ctx context.Context, programID solana.PublicKey) (rpc.GetProgramAccountsResult, error) {
-	for _, client := range c.rpcClients {
-		accounts, err := client.GetProgramAccounts(ctx, programID)
-		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to get program accounts")
-			continue
-		}
-
-		return accounts, nil
+	var accounts rpc.GetProgramAccountsResult
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		accounts, err = c.rpcClients[e.Index].GetProgramAccounts(ctx, programID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program accounts: %w", err)
 	}
 
-	return nil, fmt.Errorf("failed to get program accounts from all endpoints")
+	return accounts, nil
 }
 
 // GetRecentBlockhash retrieves a recent blockhash for transaction building
 func (c *Client) GetRecentBlockhash(ctx context.Context) (solana.Hash, error) {
 	commitment := c.getCommitment()
 
-	for _, client := range c.rpcClients {
-		result, err := client.GetRecentBlockhash(ctx, commitment)
+	var blockhash solana.Hash
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		result, err := c.rpcClients[e.Index].GetRecentBlockhash(ctx, commitment)
 		if err != nil {
-			c.logger.Warn().Err(err).Msg("Failed to get recent blockhash")
-			continue
+			return err
 		}
+		if result == nil || result.Value == nil {
+			return fmt.Errorf("empty recent blockhash result")
+		}
+		blockhash = result.Value.Blockhash
+		return nil
+	})
+	if err != nil {
+		return solana.Hash{}, fmt.Errorf("failed to get recent blockhash: %w", err)
+	}
 
-		if result != nil && result.Value != nil {
-			c.logger.Debug().
-				Str("blockhash", result.Value.Blockhash.String()).
-				Msg("Retrieved recent blockhash")
-			return result.Value.Blockhash, nil
+	c.logger.Debug().
+		Str("blockhash", blockhash.String()).
+		Msg("Retrieved recent blockhash")
+
+	return blockhash, nil
+}
+
+// GetAccountData retrieves the raw data stored in an account
+func (c *Client) GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, error) {
+	var data []byte
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		result, err := c.rpcClients[e.Index].GetAccountInfo(ctx, account)
+		if err != nil {
+			return err
 		}
+		if result == nil || result.Value == nil || result.Value.Data == nil {
+			return rpcpool.Permanent(fmt.Errorf("account not found: %s", account))
+		}
+		data = result.Value.Data.GetBinary()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// finalityCommitment returns the commitment at which a transaction is
+// final under the chain's finality policy. Without a finality policy the
+// configured commitment applies.
+func (c *Client) finalityCommitment() rpc.CommitmentType {
+	switch c.config.GetFinality() {
+	case types.FinalitySafe:
+		return rpc.CommitmentConfirmed
+	case types.FinalityFinalized:
+		return rpc.CommitmentFinalized
+	default:
+		return c.getCommitment()
+	}
+}
+
+// historyCommitment returns the commitment for history queries, which do not
+// support "processed"
+func (c *Client) historyCommitment() rpc.CommitmentType {
+	commitment := c.getCommitment()
+	if commitment == rpc.CommitmentProcessed {
+		return rpc.CommitmentConfirmed
+	}
+	return commitment
+}
+
+// GetSignaturesForAddress returns up to limit signatures of transactions
+// that mention address, newest first. The page starts before the before
+// signature and stops at the until signature; either may be zero.
+func (c *Client) GetSignaturesForAddress(
+	ctx context.Context,
+	address solana.PublicKey,
+	before solana.Signature,
+	until solana.Signature,
+	limit int,
+) ([]*rpc.TransactionSignature, error) {
+	opts := &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Before:     before,
+		Until:      until,
+		Commitment: c.historyCommitment(),
+	}
+
+	var signatures []*rpc.TransactionSignature
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		signatures, err = c.rpcClients[e.Index].GetSignaturesForAddressWithOpts(ctx, address, opts)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signatures for address %s: %w", address, err)
+	}
+
+	return signatures, nil
+}
+
+// GetTransaction returns a transaction with its execution logs
+func (c *Client) GetTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	maxVersion := uint64(0)
+	opts := &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     c.historyCommitment(),
+		MaxSupportedTransactionVersion: &maxVersion,
+	}
+
+	var result *rpc.GetTransactionResult
+	err := c.pool.Do(ctx, func(ctx context.Context, e *rpcpool.Endpoint) error {
+		var err error
+		result, err = c.rpcClients[e.Index].GetTransaction(ctx, signature, opts)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", signature, err)
+	}
+
+	return result, nil
+}
+
+// LogSubscription receives the logs of transactions as they are confirmed
+type LogSubscription struct {
+	client *ws.Client
+	sub    *ws.LogSubscription
+}
+
+// Recv blocks until the next transaction's logs arrive or the subscription
+// fails
+func (s *LogSubscription) Recv() (*ws.LogResult, error) {
+	return s.sub.Recv()
+}
+
+// Close ends the subscription by closing its connection, which makes a
+// pending Recv return an error
+func (s *LogSubscription) Close() {
+	s.client.Close()
+}
+
+// SubscribeProgramLogs subscribes over the WebSocket endpoint to the logs of
+// transactions that mention programID
+func (c *Client) SubscribeProgramLogs(ctx context.Context, programID solana.PublicKey) (*LogSubscription, error) {
+	if c.config.WSEndpoint == "" {
+		return nil, fmt.Errorf("no WebSocket endpoint configured")
+	}
+
+	client, err := ws.Connect(ctx, c.config.WSEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WebSocket endpoint: %w", err)
+	}
+
+	sub, err := client.LogsSubscribeMentions(programID, c.finalityCommitment())
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to subscribe to program logs: %w", err)
 	}
 
-	return solana.Hash{}, fmt.Errorf("failed to get recent blockhash from all endpoints")
+	return &LogSubscription{client: client, sub: sub}, nil
 }
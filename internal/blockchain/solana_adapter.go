@@ -7,6 +7,7 @@ import (
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/solana"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	solanago "github.com/gagliardetto/solana-go"
 	"github.com/rs/zerolog"
 )
 
@@ -62,6 +63,11 @@ func (a *SolanaClientAdapter) GetBlockTime() time.Duration {
 	return a.client.GetBlockTime()
 }
 
+// GetFinalizedBlockNumber returns the highest final block
+func (a *SolanaClientAdapter) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	return a.client.GetFinalizedBlockNumber(ctx)
+}
+
 // GetConfirmationBlocks returns confirmation slots
 func (a *SolanaClientAdapter) GetConfirmationBlocks() uint64 {
 	return a.client.GetConfirmationBlocks()
@@ -97,6 +103,11 @@ func (a *SolanaClientAdapter) SubscribeToEvents(ctx context.Context, programAddr
 	return a.client.SubscribeToEvents(ctx, programAddress, eventSignature)
 }
 
+// GetAccountData retrieves the raw data stored in an account
+func (a *SolanaClientAdapter) GetAccountData(ctx context.Context, account solanago.PublicKey) ([]byte, error) {
+	return a.client.GetAccountData(ctx, account)
+}
+
 // GetUnderlyingClient returns the underlying Solana client
 func (a *SolanaClientAdapter) GetUnderlyingClient() *solana.Client {
 	return a.client
@@ -51,6 +51,7 @@ type DatabaseConfig struct {
 
 // QueueConfig represents message queue configuration
 type QueueConfig struct {
+	Enabled    bool     `mapstructure:"enabled"`
 	Type       string   `mapstructure:"type"` // nats, kafka, redis
 	URLs       []string `mapstructure:"urls"`
 	Subject    string   `mapstructure:"subject"`
@@ -115,13 +116,17 @@ type MonitoringConfig struct {
 
 // AlertingConfig represents alerting configuration
 type AlertingConfig struct {
-	Enabled                   bool   `mapstructure:"enabled"`
-	SlackWebhook              string `mapstructure:"slack_webhook"`
-	PagerDutyKey              string `mapstructure:"pagerduty_key"`
-	AlertOnFailureThreshold   int    `mapstructure:"alert_on_failure_threshold"`
-	AlertOnHighGas            bool   `mapstructure:"alert_on_high_gas"`
-	AlertOnLargeTransaction   bool   `mapstructure:"alert_on_large_transaction"`
-	LargeTransactionThreshold string `mapstructure:"large_transaction_threshold"`
+	Enabled                 bool    `mapstructure:"enabled"`
+	SlackWebhook            string  `mapstructure:"slack_webhook"`
+	PagerDutyKey            string  `mapstructure:"pagerduty_key"`
+	AlertOnFailureThreshold int     `mapstructure:"alert_on_failure_threshold"`
+	AlertOnHighGas          bool    `mapstructure:"alert_on_high_gas"`
+	AlertOnLargeTransaction bool    `mapstructure:"alert_on_large_transaction"`
+	WebhookURL              string  `mapstructure:"webhook_url"`
+	PagerDutyEndpoint       string  `mapstructure:"pagerduty_endpoint"`
+	DedupeWindow            string  `mapstructure:"dedupe_window"`
+	MaxAlertsPerMinute      int     `mapstructure:"max_alerts_per_minute"`
+	GasSpikeMultiplier      float64 `mapstructure:"gas_spike_multiplier"`
 }
 
 // LoadConfig loads configuration from file and environment variables
@@ -151,6 +156,11 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Fall back to the security alerting webhook for generic webhook alerts
+	if config.Alerting.WebhookURL == "" {
+		config.Alerting.WebhookURL = config.Security.AlertingWebhook
+	}
+
 	// Validate configuration
 	if err := ValidateConfig(&config); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -183,8 +193,10 @@ func ValidateConfig(config *Config) error {
 		return fmt.Errorf("at least one chain must be configured")
 	}
 
-	for i, chain := range config.Chains {
-		if err := validateChainConfig(&chain, config.Environment); err != nil {
+	// Chains are validated in place so the defaults validation fills in stick
+	for i := range config.Chains {
+		chain := &config.Chains[i]
+		if err := validateChainConfig(chain, config.Environment); err != nil {
 			return fmt.Errorf("invalid chain config at index %d (%s): %w", i, chain.Name, err)
 		}
 	}
@@ -232,6 +244,20 @@ func validateChainConfig(chain *types.ChainConfig, env types.Environment) error
 		return fmt.Errorf("at least one RPC endpoint is required")
 	}
 
+	if chain.RPCQuorum < 0 || chain.RPCQuorum > len(chain.RPCEndpoints) {
+		return fmt.Errorf("rpc_quorum must be between 0 and the number of rpc_endpoints")
+	}
+
+	switch chain.Finality {
+	case "", types.FinalityDepth:
+	case types.FinalitySafe, types.FinalityFinalized:
+		if chain.ChainType != types.ChainTypeEVM && chain.ChainType != types.ChainTypeSolana && chain.ChainType != types.ChainTypeNEAR {
+			return fmt.Errorf("finality %q is not supported on %s chains", chain.Finality, chain.ChainType)
+		}
+	default:
+		return fmt.Errorf("invalid finality: %s (must be depth, safe or finalized)", chain.Finality)
+	}
+
 	// Validate chain-specific fields
 	switch chain.ChainType {
 	case types.ChainTypeEVM:
@@ -241,6 +267,11 @@ func validateChainConfig(chain *types.ChainConfig, env types.Environment) error
 		if chain.BridgeContract == "" {
 			return fmt.Errorf("EVM chain must have bridge_contract")
 		}
+		// The safe and finalized tags are not served by every EVM chain,
+		// so they must be chosen explicitly
+		if chain.Finality == "" {
+			chain.Finality = types.FinalityDepth // default
+		}
 
 	case types.ChainTypeSolana:
 		if chain.BridgeProgram == "" {
@@ -249,6 +280,9 @@ func validateChainConfig(chain *types.ChainConfig, env types.Environment) error
 		if chain.Commitment == "" {
 			chain.Commitment = "finalized" // default
 		}
+		if chain.Finality == "" {
+			chain.Finality = types.FinalityFinalized // default
+		}
 
 	case types.ChainTypeNEAR:
 		if chain.NetworkID == "" {
@@ -257,6 +291,9 @@ func validateChainConfig(chain *types.ChainConfig, env types.Environment) error
 		if chain.BridgeContract == "" {
 			return fmt.Errorf("NEAR chain must have bridge_contract")
 		}
+		if chain.Finality == "" {
+			chain.Finality = types.FinalityFinalized // default
+		}
 
 	case types.ChainTypeAlgorand:
 		if chain.NetworkID == "" {
@@ -265,6 +302,9 @@ func validateChainConfig(chain *types.ChainConfig, env types.Environment) error
 		if chain.BridgeContract == "" {
 			return fmt.Errorf("Algorand chain must have bridge_contract (application ID)")
 		}
+		if chain.IndexerEndpoint == "" {
+			return fmt.Errorf("Algorand chain must have indexer_endpoint")
+		}
 
 	case types.ChainTypeAptos:
 		if chain.NetworkID == "" {
@@ -274,6 +314,34 @@ func validateChainConfig(chain *types.ChainConfig, env types.Environment) error
 			return fmt.Errorf("Aptos chain must have bridge_contract (module address)")
 		}
 
+	case types.ChainTypeCosmos:
+		if chain.ChainID == "" {
+			return fmt.Errorf("Cosmos chain must have chain_id")
+		}
+		if chain.BridgeContract == "" {
+			return fmt.Errorf("Cosmos chain must have bridge_contract (CosmWasm contract address)")
+		}
+		if chain.RESTEndpoint == "" {
+			return fmt.Errorf("Cosmos chain must have rest_endpoint")
+		}
+		if chain.Bech32Prefix == "" {
+			return fmt.Errorf("Cosmos chain must have bech32_prefix")
+		}
+		if chain.GasPrice == "" {
+			return fmt.Errorf("Cosmos chain must have gas_price (e.g. 0.025uatom)")
+		}
+
+	case types.ChainTypeBitcoin:
+		if chain.BridgeContract == "" {
+			return fmt.Errorf("Bitcoin chain must have bridge_contract (custody address)")
+		}
+		if chain.CustodyThreshold < 1 || chain.CustodyThreshold > len(chain.CustodyPubKeys) {
+			return fmt.Errorf("Bitcoin chain must have custody_threshold between 1 and the number of custody_pubkeys")
+		}
+		if chain.ConfirmationBlocks == 0 {
+			return fmt.Errorf("Bitcoin chain must have confirmation_blocks")
+		}
+
 	default:
 		return fmt.Errorf("unsupported chain type: %s", chain.ChainType)
 	}
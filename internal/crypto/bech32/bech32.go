@@ -0,0 +1,175 @@
+// Package bech32 implements the BIP-173 bech32 encoding used by Cosmos SDK
+// addresses and the BIP-350 bech32m variant used by SegWit v1+ addresses
+package bech32
+
+import (
+	"fmt"
+	"strings"
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// maxLength is the longest string Decode accepts. BIP-173 limits strings
+// to 90 characters, but Cosmos SDK allows longer addresses for 32-byte
+// module and contract accounts.
+const maxLength = 1023
+
+var generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// Variant is the checksum constant distinguishing bech32 from bech32m
+type Variant uint32
+
+const (
+	Bech32  Variant = 1
+	Bech32m Variant = 0x2bc830a3
+)
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func checksum(hrp string, data []byte, variant Variant) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := polymod(values) ^ uint32(variant)
+
+	sum := make([]byte, 6)
+	for i := range sum {
+		sum[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+	return sum
+}
+
+// Encode encodes bytes with a human-readable prefix, e.g. an account
+// address with "cosmos"
+func Encode(hrp string, data []byte) (string, error) {
+	if hrp == "" {
+		return "", fmt.Errorf("empty human-readable part")
+	}
+	hrp = strings.ToLower(hrp)
+
+	converted, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return encode(hrp, converted, Bech32), nil
+}
+
+// encode encodes 5-bit groups with a checksum of the given variant
+func encode(hrp string, data []byte, variant Variant) string {
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range append(data, checksum(hrp, data, variant)...) {
+		sb.WriteByte(charset[v])
+	}
+	return sb.String()
+}
+
+// Decode decodes a bech32 string to its human-readable prefix and bytes
+func Decode(s string) (string, []byte, error) {
+	hrp, data, variant, err := decode(s, maxLength)
+	if err != nil {
+		return "", nil, err
+	}
+	if variant != Bech32 {
+		return "", nil, fmt.Errorf("invalid bech32 checksum")
+	}
+
+	decoded, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, decoded, nil
+}
+
+// decode decodes a string to its human-readable prefix and 5-bit groups,
+// without the checksum, and reports which variant's checksum it carries
+func decode(s string, limit int) (string, []byte, Variant, error) {
+	if len(s) < 8 || len(s) > limit {
+		return "", nil, 0, fmt.Errorf("invalid bech32 length: %d", len(s))
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, fmt.Errorf("mixed case bech32 string")
+	}
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, fmt.Errorf("invalid bech32 separator position")
+	}
+
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, fmt.Errorf("invalid character in human-readable part")
+		}
+	}
+
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, c := range s[sep+1:] {
+		v := strings.IndexRune(charset, c)
+		if v < 0 {
+			return "", nil, 0, fmt.Errorf("invalid bech32 character: %c", c)
+		}
+		data = append(data, byte(v))
+	}
+
+	variant := Variant(polymod(append(hrpExpand(hrp), data...)))
+	if variant != Bech32 && variant != Bech32m {
+		return "", nil, 0, fmt.Errorf("invalid bech32 checksum")
+	}
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+// convertBits regroups bits, e.g. bytes into the 5-bit groups bech32
+// encodes
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	maxv := uint(1)<<to - 1
+
+	converted := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, b := range data {
+		if uint(b)>>from != 0 {
+			return nil, fmt.Errorf("invalid data value: %d", b)
+		}
+		acc = acc<<from | uint(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			converted = append(converted, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			converted = append(converted, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return converted, nil
+}
@@ -0,0 +1,72 @@
+package bech32
+
+import (
+	"fmt"
+	"strings"
+)
+
+// segwitMaxLength is the BIP-173 length limit that applies to SegWit
+// addresses
+const segwitMaxLength = 90
+
+// EncodeSegWit encodes a SegWit address. Version 0 programs use bech32
+// and later versions bech32m.
+func EncodeSegWit(hrp string, version byte, program []byte) (string, error) {
+	if err := validateProgram(version, program); err != nil {
+		return "", err
+	}
+
+	converted, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	variant := Bech32m
+	if version == 0 {
+		variant = Bech32
+	}
+	return encode(strings.ToLower(hrp), append([]byte{version}, converted...), variant), nil
+}
+
+// DecodeSegWit decodes a SegWit address to its human-readable prefix,
+// witness version and witness program
+func DecodeSegWit(addr string) (string, byte, []byte, error) {
+	hrp, data, variant, err := decode(addr, segwitMaxLength)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(data) == 0 {
+		return "", 0, nil, fmt.Errorf("missing witness version")
+	}
+
+	version := data[0]
+	if version > 16 {
+		return "", 0, nil, fmt.Errorf("invalid witness version: %d", version)
+	}
+	if (version == 0) != (variant == Bech32) {
+		return "", 0, nil, fmt.Errorf("invalid checksum variant for witness version %d", version)
+	}
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if err := validateProgram(version, program); err != nil {
+		return "", 0, nil, err
+	}
+	return hrp, version, program, nil
+}
+
+// validateProgram checks the program length rules of BIP-141
+func validateProgram(version byte, program []byte) error {
+	if version > 16 {
+		return fmt.Errorf("invalid witness version: %d", version)
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return fmt.Errorf("invalid witness program length: %d", len(program))
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("invalid witness v0 program length: %d", len(program))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package bech32
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDecodeSegWit(t *testing.T) {
+	// Vectors from BIP-173 and BIP-350
+	valid := []struct {
+		addr    string
+		version byte
+		program string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", 0, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", 0, "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", 1, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	}
+	for _, tc := range valid {
+		_, version, program, err := DecodeSegWit(tc.addr)
+		if err != nil {
+			t.Errorf("DecodeSegWit(%s): %v", tc.addr, err)
+			continue
+		}
+		if version != tc.version || hex.EncodeToString(program) != tc.program {
+			t.Errorf("DecodeSegWit(%s) = v%d %x", tc.addr, version, program)
+		}
+	}
+
+	program20, _ := convertBits(make([]byte, 20), 8, 5, true)
+	program32, _ := convertBits(make([]byte, 32), 8, 5, true)
+	program16, _ := convertBits(make([]byte, 16), 8, 5, true)
+	invalid := []string{
+		// v0 program with a bech32m checksum
+		encode("bc", append([]byte{0}, program20...), Bech32m),
+		// v1 program with a bech32 checksum
+		encode("bc", append([]byte{1}, program32...), Bech32),
+		// v0 program of 16 bytes
+		encode("bc", append([]byte{0}, program16...), Bech32),
+	}
+	for _, addr := range invalid {
+		if _, _, _, err := DecodeSegWit(addr); err == nil {
+			t.Errorf("DecodeSegWit(%s) succeeded", addr)
+		}
+	}
+
+	program, _ := hex.DecodeString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	if addr, err := EncodeSegWit("bc", 1, program); err != nil || addr != "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0" {
+		t.Errorf("EncodeSegWit = %s, %v", addr, err)
+	}
+}
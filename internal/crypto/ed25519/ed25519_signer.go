@@ -3,6 +3,8 @@ package ed25519
 import (
 	"context"
 	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/base32"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -10,9 +12,10 @@ import (
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/mr-tron/base58"
+	"golang.org/x/crypto/sha3"
 )
 
-// Ed25519Signer implements UniversalSigner for Solana and NEAR chains
+// Ed25519Signer implements UniversalSigner for Solana, NEAR, Algorand and Aptos chains
 type Ed25519Signer struct {
 	privateKey ed25519.PrivateKey
 	publicKey  ed25519.PublicKey
@@ -123,6 +126,22 @@ func (s *Ed25519Signer) GetAddress(chainType types.ChainType) (string, error) {
 		// NEAR uses hex encoded public key with "ed25519:" prefix
 		return fmt.Sprintf("ed25519:%s", hex.EncodeToString(s.publicKey)), nil
 
+	case types.ChainTypeAlgorand:
+		// Algorand address is base32 of the public key and a 4-byte checksum
+		checksum := sha512.Sum512_256(s.publicKey)
+		addr := make([]byte, 0, len(s.publicKey)+4)
+		addr = append(addr, s.publicKey...)
+		addr = append(addr, checksum[28:]...)
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(addr), nil
+
+	case types.ChainTypeAptos:
+		// Aptos address is the SHA3-256 of the public key and the Ed25519
+		// scheme byte
+		hash := sha3.New256()
+		hash.Write(s.publicKey)
+		hash.Write([]byte{0x00})
+		return "0x" + hex.EncodeToString(hash.Sum(nil)), nil
+
 	default:
 		return "", fmt.Errorf("Ed25519 signer does not support chain type: %s", chainType)
 	}
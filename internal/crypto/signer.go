@@ -49,9 +49,9 @@ func (f *SignerFactory) CreateSigner(
 	password string,
 ) (UniversalSigner, error) {
 	switch chainType {
-	case types.ChainTypeEVM:
+	case types.ChainTypeEVM, types.ChainTypeCosmos, types.ChainTypeBitcoin:
 		return NewECDSASigner(f.keystorePath, password)
-	case types.ChainTypeSolana, types.ChainTypeNEAR:
+	case types.ChainTypeSolana, types.ChainTypeNEAR, types.ChainTypeAlgorand, types.ChainTypeAptos:
 		return NewEd25519Signer(f.keystorePath, password)
 	default:
 		return nil, fmt.Errorf("unsupported chain type for signer: %s", chainType)
@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// auditChainLockID serializes audit inserts so each row links to the one
+// before it
+const auditChainLockID = 0x61756469
+
+// AuditEntry is a row of the hash-chained audit log
+type AuditEntry struct {
+	ID         int64             `json:"id"`
+	EventType  string            `json:"event_type"`
+	Success    bool              `json:"success"`
+	ActorID    string            `json:"actor_id,omitempty"`
+	ActorEmail string            `json:"actor_email,omitempty"`
+	APIKeyID   string            `json:"api_key_id,omitempty"`
+	OrgID      string            `json:"org_id,omitempty"`
+	TargetType string            `json:"target_type,omitempty"`
+	TargetID   string            `json:"target_id,omitempty"`
+	IPAddress  string            `json:"ip_address,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+// AuditFilter selects audit entries. Zero values match everything.
+type AuditFilter struct {
+	EventType  string
+	ActorID    string
+	OrgID      string
+	TargetType string
+	TargetID   string
+	Success    *bool
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// AuditVerification is the result of checking the audit hash chain
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// AppendAuditEntry appends an entry to the audit log, linking it to the
+// previous entry's hash. ID, CreatedAt, PrevHash and Hash are set on entry.
+func (db *DB) AppendAuditEntry(ctx context.Context, entry *AuditEntry) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockID); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	var prevHash sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT hash FROM auth_audit_log
+		WHERE hash IS NOT NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get previous audit hash: %w", err)
+	}
+
+	// Postgres stores microseconds, so truncate before hashing
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.PrevHash = prevHash.String
+	entry.Hash, err = entry.ComputeHash()
+	if err != nil {
+		return err
+	}
+
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	query := `
+		INSERT INTO auth_audit_log (
+			event_type, success, user_id, email, api_key_id, org_id,
+			target_type, target_id, ip_address, user_agent, error_message,
+			details, created_at, prev_hash, hash
+		) VALUES (
+			$1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''),
+			NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''),
+			$12, $13, $14, $15
+		)
+		RETURNING id
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		entry.EventType,
+		entry.Success,
+		entry.ActorID,
+		entry.ActorEmail,
+		entry.APIKeyID,
+		entry.OrgID,
+		entry.TargetType,
+		entry.TargetID,
+		entry.IPAddress,
+		entry.UserAgent,
+		entry.Error,
+		details,
+		entry.CreatedAt,
+		entry.PrevHash,
+		entry.Hash,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// QueryAuditLog returns audit entries matching the filter, newest first
+func (db *DB) QueryAuditLog(ctx context.Context, filter *AuditFilter) ([]*AuditEntry, error) {
+	query := auditSelect + ` WHERE hash IS NOT NULL`
+	args := []interface{}{}
+
+	addFilter := func(clause string, value interface{}) {
+		args = append(args, value)
+		query += fmt.Sprintf(" AND "+clause, len(args))
+	}
+
+	if filter.EventType != "" {
+		addFilter("event_type = $%d", filter.EventType)
+	}
+	if filter.ActorID != "" {
+		addFilter("user_id = $%d", filter.ActorID)
+	}
+	if filter.OrgID != "" {
+		addFilter("org_id = $%d", filter.OrgID)
+	}
+	if filter.TargetType != "" {
+		addFilter("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		addFilter("target_id = $%d", filter.TargetID)
+	}
+	if filter.Success != nil {
+		addFilter("success = $%d", *filter.Success)
+	}
+	if filter.From != nil {
+		addFilter("created_at >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		addFilter("created_at <= $%d", filter.To.UTC())
+	}
+
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// VerifyAuditChain recomputes every hash in the audit log, oldest first,
+// and reports the first entry whose hash or link does not match
+func (db *DB) VerifyAuditChain(ctx context.Context) (*AuditVerification, error) {
+	rows, err := db.QueryContext(ctx, auditSelect+` WHERE hash IS NOT NULL ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer rows.Close()
+
+	result := &AuditVerification{Valid: true}
+	var prevHash string
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		// The oldest remaining entry may link to a pruned one
+		if result.Checked > 0 && entry.PrevHash != prevHash {
+			return brokenChain(result, entry.ID, "previous hash does not match"), nil
+		}
+
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return nil, err
+		}
+		if hash != entry.Hash {
+			return brokenChain(result, entry.ID, "entry hash does not match contents"), nil
+		}
+
+		prevHash = entry.Hash
+		result.Checked++
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return result, nil
+}
+
+// ComputeHash returns the SHA-256 of the entry's previous hash and
+// contents. The ID is excluded since it is assigned on insert; order is
+// covered by the chain.
+func (e *AuditEntry) ComputeHash() (string, error) {
+	content, err := json.Marshal(struct {
+		PrevHash   string            `json:"prev_hash"`
+		CreatedAt  string            `json:"created_at"`
+		EventType  string            `json:"event_type"`
+		Success    bool              `json:"success"`
+		ActorID    string            `json:"actor_id"`
+		ActorEmail string            `json:"actor_email"`
+		APIKeyID   string            `json:"api_key_id"`
+		OrgID      string            `json:"org_id"`
+		TargetType string            `json:"target_type"`
+		TargetID   string            `json:"target_id"`
+		IPAddress  string            `json:"ip_address"`
+		UserAgent  string            `json:"user_agent"`
+		Error      string            `json:"error"`
+		Details    map[string]string `json:"details"`
+	}{
+		PrevHash:   e.PrevHash,
+		CreatedAt:  e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		EventType:  e.EventType,
+		Success:    e.Success,
+		ActorID:    e.ActorID,
+		ActorEmail: e.ActorEmail,
+		APIKeyID:   e.APIKeyID,
+		OrgID:      e.OrgID,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		IPAddress:  e.IPAddress,
+		UserAgent:  e.UserAgent,
+		Error:      e.Error,
+		Details:    e.Details,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+const auditSelect = `
+	SELECT id, event_type, success, COALESCE(user_id, ''), COALESCE(email, ''),
+		COALESCE(api_key_id, ''), COALESCE(org_id, ''), COALESCE(target_type, ''),
+		COALESCE(target_id, ''), COALESCE(ip_address, ''), COALESCE(user_agent, ''),
+		COALESCE(error_message, ''), details, created_at, COALESCE(prev_hash, ''), hash
+	FROM auth_audit_log`
+
+func scanAuditEntry(row rowScanner) (*AuditEntry, error) {
+	var entry AuditEntry
+	var details []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.EventType,
+		&entry.Success,
+		&entry.ActorID,
+		&entry.ActorEmail,
+		&entry.APIKeyID,
+		&entry.OrgID,
+		&entry.TargetType,
+		&entry.TargetID,
+		&entry.IPAddress,
+		&entry.UserAgent,
+		&entry.Error,
+		&details,
+		&entry.CreatedAt,
+		&entry.PrevHash,
+		&entry.Hash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(details) > 0 {
+		if err := json.Unmarshal(details, &entry.Details); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+		}
+	}
+
+	return &entry, nil
+}
+
+func brokenChain(result *AuditVerification, id int64, reason string) *AuditVerification {
+	result.Valid = false
+	result.BrokenAt = id
+	result.Reason = reason
+	return result
+}
@@ -0,0 +1,68 @@
+-- Audit Log Schema
+
+-- Extends auth_audit_log into an append-only, hash-chained audit trail of
+-- logins, API key changes, permission denials and admin actions. Each row's
+-- hash covers its contents and the previous row's hash, so editing,
+-- reordering or removing a row breaks the chain.
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS org_id VARCHAR(100);
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS api_key_id VARCHAR(100);
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS target_type VARCHAR(50);
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS target_id VARCHAR(255);
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS details JSONB;
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64);
+ALTER TABLE auth_audit_log ADD COLUMN IF NOT EXISTS hash VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_auth_audit_org_id ON auth_audit_log(org_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_target ON auth_audit_log(target_type, target_id);
+
+-- Audit rows cannot be updated. Rows can only be deleted by
+-- prune_auth_audit_log, which removes the oldest rows so the remaining chain
+-- stays verifiable.
+CREATE OR REPLACE FUNCTION protect_auth_audit_log()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND current_setting('articium.audit_prune', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'auth_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_protect_auth_audit_log ON auth_audit_log;
+CREATE TRIGGER trigger_protect_auth_audit_log
+    BEFORE UPDATE OR DELETE ON auth_audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION protect_auth_audit_log();
+
+-- Deletes audit rows older than the retention period. Only a prefix of the
+-- chain is removed, so verification starts from the oldest remaining row.
+CREATE OR REPLACE FUNCTION prune_auth_audit_log(p_retention INTERVAL)
+RETURNS INTEGER AS $$
+DECLARE
+    cutoff_id INTEGER;
+    deleted INTEGER;
+BEGIN
+    SELECT MIN(id) INTO cutoff_id
+    FROM auth_audit_log
+    WHERE created_at >= NOW() - p_retention;
+
+    PERFORM set_config('articium.audit_prune', 'on', true);
+
+    IF cutoff_id IS NULL THEN
+        DELETE FROM auth_audit_log;
+    ELSE
+        DELETE FROM auth_audit_log WHERE id < cutoff_id;
+    END IF;
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+
+    PERFORM set_config('articium.audit_prune', 'off', true);
+    RETURN deleted;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Per-event cleanup would punch holes in the hash chain
+DROP FUNCTION IF EXISTS cleanup_old_auth_logs();
+
+-- Inserts must go through the hash-chained writer, which this function
+-- bypasses
+DROP FUNCTION IF EXISTS log_auth_event(VARCHAR, VARCHAR, VARCHAR, VARCHAR, TEXT, BOOLEAN, TEXT);
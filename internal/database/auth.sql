@@ -53,6 +53,33 @@ CREATE INDEX idx_auth_audit_user_id ON auth_audit_log(user_id);
 CREATE INDEX idx_auth_audit_created_at ON auth_audit_log(created_at DESC);
 CREATE INDEX idx_auth_audit_event_type ON auth_audit_log(event_type);
 
+-- Revoked access tokens, keyed by jti. Rows can be deleted once expired.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(100) PRIMARY KEY,
+    user_id VARCHAR(100),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Refresh tokens (hashed). Each refresh rotates the token within its family;
+-- reuse of a rotated token revokes the whole family.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(100) PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
 -- Function to update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_users_updated_at()
 RETURNS TRIGGER AS $$
@@ -35,6 +35,18 @@ type BatchStats struct {
 	TotalGasSaved    string    `json:"total_gas_saved"`
 }
 
+// batchInOrg is a filter on batches b that is true when the batch holds a
+// message owned by the organization in the given parameter, or when the
+// parameter is empty. Batches are built from messages of many organizations,
+// so a tenant sees a batch only through its own messages.
+func batchInOrg(param string) string {
+	return `(` + param + ` = '' OR EXISTS (
+			SELECT 1 FROM batch_messages bm
+			JOIN messages m ON m.id = bm.message_id
+			WHERE bm.batch_id = b.id AND m.org_id = ` + param + `
+		))`
+}
+
 // SaveBatch saves a batch to the database
 func (db *DB) SaveBatch(ctx context.Context, batch *Batch) error {
 	query := `
@@ -74,20 +86,20 @@ func (db *DB) SaveBatch(ctx context.Context, batch *Batch) error {
 	return nil
 }
 
-// GetBatch retrieves a batch by ID
-func (db *DB) GetBatch(ctx context.Context, batchID string) (*Batch, error) {
+// GetBatch retrieves a batch by ID. If orgID is set, batches holding no
+// messages of that organization are reported as not found.
+func (db *DB) GetBatch(ctx context.Context, batchID, orgID string) (*Batch, error) {
 	query := `
 		SELECT
 			id, status, source_chain, destination_chain, message_count,
 			total_gas_saved, tx_hash, created_at, confirmed_at
-		FROM batches
-		WHERE id = $1
-	`
+		FROM batches b
+		WHERE id = $1 AND ` + batchInOrg("$2")
 
 	var batch Batch
 	var confirmedAt sql.NullTime
 
-	err := db.QueryRowContext(ctx, query, batchID).Scan(
+	err := db.QueryRowContext(ctx, query, batchID, orgID).Scan(
 		&batch.ID,
 		&batch.Status,
 		&batch.SourceChain,
@@ -113,19 +125,20 @@ func (db *DB) GetBatch(ctx context.Context, batchID string) (*Batch, error) {
 	return &batch, nil
 }
 
-// GetBatchesByStatus retrieves batches by status
-func (db *DB) GetBatchesByStatus(ctx context.Context, status string, limit, offset int) ([]Batch, error) {
+// GetBatchesByStatus retrieves batches by status, limited to batches holding
+// messages of orgID if it is set
+func (db *DB) GetBatchesByStatus(ctx context.Context, status, orgID string, limit, offset int) ([]Batch, error) {
 	query := `
 		SELECT
 			id, status, source_chain, destination_chain, message_count,
 			total_gas_saved, tx_hash, created_at, confirmed_at
-		FROM batches
-		WHERE status = $1
+		FROM batches b
+		WHERE status = $1 AND ` + batchInOrg("$4") + `
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := db.QueryContext(ctx, query, status, limit, offset)
+	rows, err := db.QueryContext(ctx, query, status, limit, offset, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query batches: %w", err)
 	}
@@ -166,18 +179,20 @@ func (db *DB) GetBatchesByStatus(ctx context.Context, status string, limit, offs
 	return batches, nil
 }
 
-// GetAllBatches retrieves all batches
-func (db *DB) GetAllBatches(ctx context.Context, limit, offset int) ([]Batch, error) {
+// GetAllBatches retrieves all batches, limited to batches holding messages
+// of orgID if it is set
+func (db *DB) GetAllBatches(ctx context.Context, orgID string, limit, offset int) ([]Batch, error) {
 	query := `
 		SELECT
 			id, status, source_chain, destination_chain, message_count,
 			total_gas_saved, tx_hash, created_at, confirmed_at
-		FROM batches
+		FROM batches b
+		WHERE ` + batchInOrg("$3") + `
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := db.QueryContext(ctx, query, limit, offset)
+	rows, err := db.QueryContext(ctx, query, limit, offset, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query batches: %w", err)
 	}
@@ -218,12 +233,13 @@ func (db *DB) GetAllBatches(ctx context.Context, limit, offset int) ([]Batch, er
 	return batches, nil
 }
 
-// GetBatchesCount returns the total count of batches
-func (db *DB) GetBatchesCount(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM batches`
+// GetBatchesCount returns the total count of batches, limited to batches
+// holding messages of orgID if it is set
+func (db *DB) GetBatchesCount(ctx context.Context, orgID string) (int64, error) {
+	query := `SELECT COUNT(*) FROM batches b WHERE ` + batchInOrg("$1")
 
 	var count int64
-	err := db.QueryRowContext(ctx, query).Scan(&count)
+	err := db.QueryRowContext(ctx, query, orgID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get batches count: %w", err)
 	}
@@ -328,16 +344,18 @@ func (db *DB) AddMessageToBatch(ctx context.Context, batchID, messageID string)
 	return nil
 }
 
-// GetBatchMessages retrieves all messages in a batch
-func (db *DB) GetBatchMessages(ctx context.Context, batchID string) ([]BatchMessage, error) {
+// GetBatchMessages retrieves the messages in a batch. If orgID is set, only
+// messages owned by that organization are returned.
+func (db *DB) GetBatchMessages(ctx context.Context, batchID, orgID string) ([]BatchMessage, error) {
 	query := `
-		SELECT batch_id, message_id, added_at
-		FROM batch_messages
-		WHERE batch_id = $1
-		ORDER BY added_at ASC
+		SELECT bm.batch_id, bm.message_id, bm.added_at
+		FROM batch_messages bm
+		JOIN messages m ON m.id = bm.message_id
+		WHERE bm.batch_id = $1 AND ($2 = '' OR m.org_id = $2)
+		ORDER BY bm.added_at ASC
 	`
 
-	rows, err := db.QueryContext(ctx, query, batchID)
+	rows, err := db.QueryContext(ctx, query, batchID, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query batch messages: %w", err)
 	}
@@ -0,0 +1,273 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Bitcoin UTXO statuses
+const (
+	BitcoinUTXOUnspent  = "UNSPENT"
+	BitcoinUTXOReserved = "RESERVED"
+	BitcoinUTXOSpent    = "SPENT"
+)
+
+// Bitcoin release statuses
+const (
+	BitcoinReleaseSigning   = "SIGNING"
+	BitcoinReleaseBroadcast = "BROADCAST"
+)
+
+// ErrBitcoinReleaseConflict is returned when a release was created or
+// updated concurrently by another relayer. Callers re-read and retry.
+var ErrBitcoinReleaseConflict = errors.New("bitcoin release changed concurrently")
+
+// BitcoinUTXO is a confirmed output paying a custody address
+type BitcoinUTXO struct {
+	Chain        string    `json:"chain"`
+	TxID         string    `json:"txid"`
+	Vout         uint32    `json:"vout"`
+	AmountSats   int64     `json:"amount_sats"`
+	ScriptPubKey string    `json:"script_pubkey"`
+	BlockHeight  uint64    `json:"block_height"`
+	Status       string    `json:"status"`
+	ReservedBy   string    `json:"reserved_by,omitempty"`
+	SpentTxID    string    `json:"spent_txid,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BitcoinRelease is a release PSBT being co-signed
+type BitcoinRelease struct {
+	MessageID string    `json:"message_id"`
+	Chain     string    `json:"chain"`
+	PSBT      string    `json:"psbt"`
+	Status    string    `json:"status"`
+	TxID      string    `json:"txid,omitempty"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SaveBitcoinUTXO records a custody output. Recording an output twice,
+// e.g. after a listener restart, leaves the first record untouched.
+func (db *DB) SaveBitcoinUTXO(ctx context.Context, utxo *BitcoinUTXO) error {
+	query := `
+		INSERT INTO bitcoin_utxos (
+			chain, txid, vout, amount_sats, script_pubkey, block_height
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (chain, txid, vout) DO NOTHING
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		utxo.Chain,
+		utxo.TxID,
+		utxo.Vout,
+		utxo.AmountSats,
+		utxo.ScriptPubKey,
+		utxo.BlockHeight,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save bitcoin utxo: %w", err)
+	}
+
+	return nil
+}
+
+// MarkBitcoinUTXOSpent marks a custody output spent by a confirmed
+// transaction
+func (db *DB) MarkBitcoinUTXOSpent(ctx context.Context, chain, txid string, vout uint32, spentTxID string) error {
+	query := `
+		UPDATE bitcoin_utxos
+		SET status = 'SPENT', spent_txid = $4, updated_at = NOW()
+		WHERE chain = $1 AND txid = $2 AND vout = $3
+	`
+
+	if _, err := db.ExecContext(ctx, query, chain, txid, vout, spentTxID); err != nil {
+		return fmt.Errorf("failed to mark bitcoin utxo spent: %w", err)
+	}
+
+	return nil
+}
+
+// ListUnspentBitcoinUTXOs lists the custody outputs available to releases,
+// largest first
+func (db *DB) ListUnspentBitcoinUTXOs(ctx context.Context, chain string) ([]BitcoinUTXO, error) {
+	query := `
+		SELECT chain, txid, vout, amount_sats, script_pubkey, block_height,
+			status, created_at
+		FROM bitcoin_utxos
+		WHERE chain = $1 AND status = 'UNSPENT'
+		ORDER BY amount_sats DESC
+	`
+
+	rows, err := db.QueryContext(ctx, query, chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bitcoin utxos: %w", err)
+	}
+	defer rows.Close()
+
+	var utxos []BitcoinUTXO
+	for rows.Next() {
+		var utxo BitcoinUTXO
+		if err := rows.Scan(
+			&utxo.Chain,
+			&utxo.TxID,
+			&utxo.Vout,
+			&utxo.AmountSats,
+			&utxo.ScriptPubKey,
+			&utxo.BlockHeight,
+			&utxo.Status,
+			&utxo.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bitcoin utxo: %w", err)
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	return utxos, rows.Err()
+}
+
+// GetBitcoinRelease returns the release of a message, or nil if none has
+// been created
+func (db *DB) GetBitcoinRelease(ctx context.Context, messageID string) (*BitcoinRelease, error) {
+	query := `
+		SELECT message_id, chain, psbt, status, COALESCE(txid, ''), version,
+			created_at, updated_at
+		FROM bitcoin_releases
+		WHERE message_id = $1
+	`
+
+	var release BitcoinRelease
+	err := db.QueryRowContext(ctx, query, messageID).Scan(
+		&release.MessageID,
+		&release.Chain,
+		&release.PSBT,
+		&release.Status,
+		&release.TxID,
+		&release.Version,
+		&release.CreatedAt,
+		&release.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bitcoin release: %w", err)
+	}
+
+	return &release, nil
+}
+
+// ListSigningBitcoinReleases returns the message IDs of a chain's releases
+// still collecting custody signatures, least recently updated first
+func (db *DB) ListSigningBitcoinReleases(ctx context.Context, chain string, limit int) ([]string, error) {
+	query := `
+		SELECT message_id
+		FROM bitcoin_releases
+		WHERE chain = $1 AND status = 'SIGNING'
+		ORDER BY updated_at
+		LIMIT $2
+	`
+
+	rows, err := db.QueryContext(ctx, query, chain, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bitcoin releases: %w", err)
+	}
+	defer rows.Close()
+
+	var messageIDs []string
+	for rows.Next() {
+		var messageID string
+		if err := rows.Scan(&messageID); err != nil {
+			return nil, fmt.Errorf("failed to scan bitcoin release: %w", err)
+		}
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	return messageIDs, rows.Err()
+}
+
+// CreateBitcoinRelease reserves the outputs a release spends and saves the
+// release in a single transaction. It returns ErrBitcoinReleaseConflict if
+// another relayer created the message's release or reserved one of the
+// outputs first.
+func (db *DB) CreateBitcoinRelease(ctx context.Context, release *BitcoinRelease, spends []BitcoinUTXO) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO bitcoin_releases (message_id, chain, psbt, status)
+		VALUES ($1, $2, $3, 'SIGNING')
+		ON CONFLICT (message_id) DO NOTHING
+	`, release.MessageID, release.Chain, release.PSBT)
+	if err != nil {
+		return fmt.Errorf("failed to save bitcoin release: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrBitcoinReleaseConflict
+	}
+
+	for _, utxo := range spends {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE bitcoin_utxos
+			SET status = 'RESERVED', reserved_by = $4, updated_at = NOW()
+			WHERE chain = $1 AND txid = $2 AND vout = $3 AND status = 'UNSPENT'
+		`, release.Chain, utxo.TxID, utxo.Vout, release.MessageID)
+		if err != nil {
+			return fmt.Errorf("failed to reserve bitcoin utxo: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrBitcoinReleaseConflict
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	release.Status = BitcoinReleaseSigning
+	release.Version = 1
+
+	db.logger.Debug().
+		Str("message_id", release.MessageID).
+		Int("inputs", len(spends)).
+		Msg("Bitcoin release created")
+
+	return nil
+}
+
+// UpdateBitcoinRelease saves a release's PSBT, status and txid if it is
+// unchanged since it was read. It returns ErrBitcoinReleaseConflict
+// otherwise.
+func (db *DB) UpdateBitcoinRelease(ctx context.Context, release *BitcoinRelease) error {
+	query := `
+		UPDATE bitcoin_releases
+		SET psbt = $2, status = $3, txid = NULLIF($4, ''),
+			version = version + 1, updated_at = NOW()
+		WHERE message_id = $1 AND version = $5
+	`
+
+	result, err := db.ExecContext(ctx, query,
+		release.MessageID,
+		release.PSBT,
+		release.Status,
+		release.TxID,
+		release.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update bitcoin release: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrBitcoinReleaseConflict
+	}
+
+	release.Version++
+	return nil
+}
@@ -0,0 +1,41 @@
+-- Bitcoin Custody Schema
+
+-- UTXO index: outputs paying a bridge custody address, recorded by the
+-- listener once confirmed. Releases reserve the outputs they spend; the
+-- listener marks them spent when the release confirms.
+CREATE TABLE IF NOT EXISTS bitcoin_utxos (
+    chain VARCHAR(100) NOT NULL,
+    txid VARCHAR(64) NOT NULL,
+    vout INTEGER NOT NULL,
+    amount_sats BIGINT NOT NULL CHECK (amount_sats > 0),
+    script_pubkey TEXT NOT NULL,
+    block_height BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'UNSPENT' CHECK (status IN ('UNSPENT', 'RESERVED', 'SPENT')),
+    reserved_by VARCHAR(100),
+    spent_txid VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chain, txid, vout)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bitcoin_utxos_unspent ON bitcoin_utxos(chain, amount_sats) WHERE status = 'UNSPENT';
+CREATE INDEX IF NOT EXISTS idx_bitcoin_utxos_reserved_by ON bitcoin_utxos(reserved_by);
+
+COMMENT ON TABLE bitcoin_utxos IS 'Confirmed outputs held by Bitcoin custody addresses';
+
+-- Releases: one PSBT per message, co-signed by each validator's relayer
+-- until the custody threshold is met. version guards concurrent updates.
+CREATE TABLE IF NOT EXISTS bitcoin_releases (
+    message_id VARCHAR(100) PRIMARY KEY,
+    chain VARCHAR(100) NOT NULL,
+    psbt TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'SIGNING' CHECK (status IN ('SIGNING', 'BROADCAST')),
+    txid VARCHAR(64),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bitcoin_releases_status ON bitcoin_releases(chain, status);
+
+COMMENT ON TABLE bitcoin_releases IS 'Bitcoin release PSBTs awaiting custody signatures';
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestListSigningBitcoinReleases(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectQuery("FROM bitcoin_releases WHERE chain = \\$1 AND status = 'SIGNING' ORDER BY updated_at").
+		WithArgs("bitcoin-testnet", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1").AddRow("msg-2"))
+
+	ids, err := db.ListSigningBitcoinReleases(context.Background(), "bitcoin-testnet", 100)
+	if err != nil {
+		t.Fatalf("ListSigningBitcoinReleases failed: %v", err)
+	}
+	if fmt.Sprint(ids) != "[msg-1 msg-2]" {
+		t.Errorf("Unexpected releases: %v", ids)
+	}
+}
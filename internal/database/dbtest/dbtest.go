@@ -0,0 +1,81 @@
+// Package dbtest opens scratch Postgres schemas for tests that depend on
+// Postgres semantics such as SKIP LOCKED and ON CONFLICT.
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// DatabaseURLEnv names a scratch Postgres database for tests. Tests that
+// need it are skipped when it is unset.
+const DatabaseURLEnv = "ARTICIUM_TEST_DATABASE_URL"
+
+// Open applies the schema files in dir, in order, to a fresh Postgres schema
+// in the test database and returns a connection bound to it. The schema is
+// dropped when the test finishes.
+func Open(t *testing.T, dir string, files []string) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(DatabaseURLEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", DatabaseURLEnv)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Logf("Failed to drop test schema %s: %v", schema, err)
+		}
+	})
+
+	conn, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("Failed to open test schema: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	for _, filename := range files {
+		ddl, err := os.ReadFile(filepath.Join(dir, filename))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", filename, err)
+		}
+		if _, err := conn.Exec(string(ddl)); err != nil {
+			t.Fatalf("Failed to apply %s: %v", filename, err)
+		}
+	}
+
+	return conn
+}
+
+// withSearchPath sets the search_path run-time parameter on a URL or
+// key=value connection string
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			q := u.Query()
+			q.Set("search_path", schema)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return dsn + " search_path=" + schema
+}
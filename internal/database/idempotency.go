@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+)
+
+// IdempotencyRecord is a stored response for a client idempotency key
+type IdempotencyRecord struct {
+	Scope       string          `json:"scope"`
+	Key         string          `json:"key"`
+	RequestHash string          `json:"request_hash"`
+	MessageID   string          `json:"message_id"`
+	StatusCode  int             `json:"status_code"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// GetIdempotencyRecord returns the live record for a key, or nil if the key
+// has not been used or has expired
+func (db *DB) GetIdempotencyRecord(ctx context.Context, scope, key string) (*IdempotencyRecord, error) {
+	query := `
+		SELECT scope, idempotency_key, request_hash, message_id, status_code,
+			response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2 AND expires_at > NOW()
+	`
+
+	var rec IdempotencyRecord
+	err := db.QueryRowContext(ctx, query, scope, key).Scan(
+		&rec.Scope,
+		&rec.Key,
+		&rec.RequestHash,
+		&rec.MessageID,
+		&rec.StatusCode,
+		&rec.Response,
+		&rec.CreatedAt,
+		&rec.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// SaveMessageWithIdempotency claims an idempotency key and saves the message
+// and its outbox entry in a single transaction. If the key is already held by
+// another request, nothing is saved and the existing record is returned.
+func (db *DB) SaveMessageWithIdempotency(ctx context.Context, msg *types.CrossChainMessage, rec *IdempotencyRecord) (*IdempotencyRecord, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Expired keys are reclaimed in place; live keys are left untouched and
+	// return no row
+	query := `
+		INSERT INTO idempotency_keys (
+			scope, idempotency_key, request_hash, message_id, status_code,
+			response, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			message_id = EXCLUDED.message_id,
+			status_code = EXCLUDED.status_code,
+			response = EXCLUDED.response,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+		RETURNING created_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		rec.Scope,
+		rec.Key,
+		rec.RequestHash,
+		msg.ID,
+		rec.StatusCode,
+		[]byte(rec.Response),
+		rec.ExpiresAt,
+	).Scan(&rec.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+
+		existing, err := db.GetIdempotencyRecord(ctx, rec.Scope, rec.Key)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("idempotency key %s is in use", rec.Key)
+		}
+		return existing, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+	rec.MessageID = msg.ID
+
+	if err := saveMessageWithOutbox(ctx, tx, msg); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	db.logger.Debug().
+		Str("message_id", msg.ID).
+		Str("idempotency_key", rec.Key).
+		Msg("Message saved with idempotency key")
+
+	return nil, nil
+}
+
+// PurgeExpiredIdempotencyKeys deletes expired idempotency keys
+func (db *DB) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`
+
+	result, err := db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
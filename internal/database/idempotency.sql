@@ -0,0 +1,35 @@
+-- Idempotency and Deduplication Schema
+
+-- Idempotency keys: maps a client-supplied Idempotency-Key to the message it
+-- created, so retried submissions replay the original response
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    message_id VARCHAR(100) NOT NULL,
+    status_code INTEGER NOT NULL,
+    response JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_message_id ON idempotency_keys(message_id);
+
+COMMENT ON TABLE idempotency_keys IS 'Client idempotency keys for bridge submissions';
+
+-- Source events: one row per on-chain event, so an event observed twice
+-- (listener restart, reorg replay) only ever produces one message
+CREATE TABLE IF NOT EXISTS message_source_events (
+    source_chain VARCHAR(100) NOT NULL,
+    tx_hash VARCHAR(255) NOT NULL,
+    log_index BIGINT NOT NULL,
+    message_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source_chain, tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_source_events_message_id ON message_source_events(message_id);
+
+COMMENT ON TABLE message_source_events IS 'Source chain events already recorded as messages';
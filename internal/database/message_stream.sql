@@ -0,0 +1,20 @@
+-- Live Message Tracking Stream
+
+-- Message status transitions and timeline events, in the order they were
+-- published. API servers tail this table to push events to tracking
+-- streams, and clients resume a stream from the last id they received.
+-- Rows are pruned once they are older than the resume window.
+CREATE TABLE IF NOT EXISTS message_stream_events (
+    id BIGSERIAL PRIMARY KEY,
+    message_id VARCHAR(100) NOT NULL,
+    org_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    sender VARCHAR(255) NOT NULL DEFAULT '',
+    source_chain VARCHAR(100) NOT NULL DEFAULT '',
+    dest_chain VARCHAR(100) NOT NULL DEFAULT '',
+    timeline JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_stream_events_created_at ON message_stream_events(created_at);
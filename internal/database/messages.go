@@ -4,29 +4,121 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 )
 
-// SaveMessage saves a cross-chain message to the database
+// ErrDuplicateSourceEvent is returned when a message is saved for a source
+// chain event that is already recorded under a different message ID
+var ErrDuplicateSourceEvent = errors.New("duplicate source event")
+
+// dbtx is implemented by both *sql.DB and *sql.Tx
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SaveMessage saves a cross-chain message to the database. Messages that
+// carry a source transaction hash are deduplicated on (source chain, tx hash,
+// log index); a second message for the same event returns
+// ErrDuplicateSourceEvent.
 func (db *DB) SaveMessage(ctx context.Context, msg *types.CrossChainMessage) error {
-	query := `
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := saveMessage(ctx, tx, msg); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	db.logger.Debug().
+		Str("message_id", msg.ID).
+		Str("status", string(msg.Status)).
+		Msg("Message saved to database")
+
+	return nil
+}
+
+// insertMessageQuery inserts a message; callers append the conflict clause
+const insertMessageQuery = `
 		INSERT INTO messages (
 			id, type, source_chain_id, source_chain_name, destination_chain_id,
-			destination_chain_name, sender, recipient, payload, status, nonce, timestamp
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			destination_chain_name, sender, recipient, payload, status, nonce, timestamp,
+			org_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''))
+`
+
+// saveMessage records the message's source event and upserts the message
+// using the given executor. A completed or failed message keeps its status,
+// so saving it again cannot queue it for a second release; false is
+// returned when nothing was written.
+func saveMessage(ctx context.Context, ex dbtx, msg *types.CrossChainMessage) (bool, error) {
+	if err := recordSourceEvent(ctx, ex, msg); err != nil {
+		return false, err
+	}
+
+	query := insertMessageQuery + `
 		ON CONFLICT (id) DO UPDATE SET
 			status = EXCLUDED.status,
 			updated_at = CURRENT_TIMESTAMP
+		WHERE messages.status NOT IN ('COMPLETED', 'FAILED')
+	`
+
+	result, err := execMessageInsert(ctx, ex, query, msg)
+	if err != nil {
+		return false, fmt.Errorf("failed to save message: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to save message: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// insertMessage inserts the message and records its source event using the
+// given executor. An existing message is left untouched and false is
+// returned.
+func insertMessage(ctx context.Context, ex dbtx, msg *types.CrossChainMessage) (bool, error) {
+	query := insertMessageQuery + `
+		ON CONFLICT (id) DO NOTHING
 	`
 
+	result, err := execMessageInsert(ctx, ex, query, msg)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert message: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to insert message: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if err := recordSourceEvent(ctx, ex, msg); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// execMessageInsert runs an insertMessageQuery based query for msg
+func execMessageInsert(ctx context.Context, ex dbtx, query string, msg *types.CrossChainMessage) (sql.Result, error) {
 	payloadJSON, err := json.Marshal(msg.Payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	_, err = db.ExecContext(ctx, query,
+	return ex.ExecContext(ctx, query,
 		msg.ID,
 		msg.Type,
 		msg.SourceChain.ChainID,
@@ -39,35 +131,92 @@ func (db *DB) SaveMessage(ctx context.Context, msg *types.CrossChainMessage) err
 		msg.Status,
 		msg.Nonce,
 		msg.CreatedAt,
+		msg.OrgID,
 	)
+}
+
+// recordSourceEvent claims the message's source event. Re-saving the same
+// message is allowed; a different message for the same event is not.
+func recordSourceEvent(ctx context.Context, ex dbtx, msg *types.CrossChainMessage) error {
+	// Messages submitted through the API have no source event yet
+	if msg.SourceTxHash == "" {
+		return nil
+	}
 
+	query := `
+		INSERT INTO message_source_events (source_chain, tx_hash, log_index, message_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source_chain, tx_hash, log_index) DO UPDATE SET
+			message_id = message_source_events.message_id
+		RETURNING message_id
+	`
+
+	var existingID string
+	err := ex.QueryRowContext(ctx, query,
+		msg.SourceChain.Name,
+		msg.SourceTxHash,
+		msg.SourceLogIndex,
+		msg.ID,
+	).Scan(&existingID)
 	if err != nil {
-		return fmt.Errorf("failed to save message: %w", err)
+		return fmt.Errorf("failed to record source event: %w", err)
 	}
 
-	db.logger.Debug().
-		Str("message_id", msg.ID).
-		Str("status", string(msg.Status)).
-		Msg("Message saved to database")
+	if existingID != msg.ID {
+		return fmt.Errorf("%w: %s tx %s log %d already recorded as message %s",
+			ErrDuplicateSourceEvent, msg.SourceChain.Name, msg.SourceTxHash, msg.SourceLogIndex, existingID)
+	}
 
 	return nil
 }
 
-// GetMessage retrieves a message by ID
-func (db *DB) GetMessage(ctx context.Context, messageID string) (*types.CrossChainMessage, error) {
+// GetSourceEventMessageID returns the ID of the message recorded for a
+// source chain event, or an empty string if the event is not recorded
+func (db *DB) GetSourceEventMessageID(ctx context.Context, sourceChain, txHash string, logIndex uint64) (string, error) {
+	query := `
+		SELECT message_id FROM message_source_events
+		WHERE source_chain = $1 AND tx_hash = $2 AND log_index = $3
+	`
+
+	var messageID string
+	err := db.QueryRowContext(ctx, query, sourceChain, txHash, logIndex).Scan(&messageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get source event: %w", err)
+	}
+
+	return messageID, nil
+}
+
+// MessageExists reports whether a message with the given ID is recorded
+func (db *DB) MessageExists(ctx context.Context, messageID string) (bool, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM messages WHERE id = $1)`, messageID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check message: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetMessage retrieves a message by ID. If orgID is set, messages owned by
+// other organizations are reported as not found.
+func (db *DB) GetMessage(ctx context.Context, messageID, orgID string) (*types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, type, source_chain_id, source_chain_name, destination_chain_id,
 			destination_chain_name, sender, recipient, payload, status, nonce,
-			timestamp
+			timestamp, COALESCE(org_id, '')
 		FROM messages
-		WHERE id = $1
+		WHERE id = $1 AND ($2 = '' OR org_id = $2)
 	`
 
 	var msg types.CrossChainMessage
 	var payloadJSON []byte
 
-	err := db.QueryRowContext(ctx, query, messageID).Scan(
+	err := db.QueryRowContext(ctx, query, messageID, orgID).Scan(
 		&msg.ID,
 		&msg.Type,
 		&msg.SourceChain.ChainID,
@@ -80,6 +229,7 @@ func (db *DB) GetMessage(ctx context.Context, messageID string) (*types.CrossCha
 		&msg.Status,
 		&msg.Nonce,
 		&msg.CreatedAt,
+		&msg.OrgID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -148,7 +298,7 @@ func (db *DB) GetPendingMessages(ctx context.Context, limit int) ([]types.CrossC
 		SELECT
 			id, type, source_chain_id, source_chain_name, destination_chain_id,
 			destination_chain_name, sender, recipient, payload, status, nonce,
-			timestamp
+			timestamp, COALESCE(org_id, '')
 		FROM messages
 		WHERE status = $1
 		ORDER BY timestamp ASC
@@ -180,6 +330,7 @@ func (db *DB) GetPendingMessages(ctx context.Context, limit int) ([]types.CrossC
 			&msg.Status,
 			&msg.Nonce,
 			&msg.CreatedAt,
+			&msg.OrgID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
@@ -235,20 +386,35 @@ func (db *DB) GetFailedMessagesCount(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-// GetMessagesByStatus retrieves messages by status
-func (db *DB) GetMessagesByStatus(ctx context.Context, status types.MessageStatus, limit int, offset int) ([]types.CrossChainMessage, error) {
+// CountMessagesByStatus returns the number of messages with a status. If
+// orgID is set, only that organization's messages are counted.
+func (db *DB) CountMessagesByStatus(ctx context.Context, orgID string, status types.MessageStatus) (int64, error) {
+	query := `SELECT COUNT(*) FROM messages WHERE status = $1 AND ($2 = '' OR org_id = $2)`
+
+	var count int64
+	err := db.QueryRowContext(ctx, query, status, orgID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetMessagesByStatus retrieves messages by status. If orgID is set, only
+// that organization's messages are returned.
+func (db *DB) GetMessagesByStatus(ctx context.Context, orgID string, status types.MessageStatus, limit int, offset int) ([]types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, type, source_chain_id, source_chain_name, destination_chain_id,
 			destination_chain_name, sender, recipient, payload, status, nonce,
-			timestamp
+			timestamp, COALESCE(org_id, '')
 		FROM messages
-		WHERE status = $1
+		WHERE status = $1 AND ($4 = '' OR org_id = $4)
 		ORDER BY timestamp DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := db.QueryContext(ctx, query, status, limit, offset)
+	rows, err := db.QueryContext(ctx, query, status, limit, offset, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query messages: %w", err)
 	}
@@ -273,6 +439,7 @@ func (db *DB) GetMessagesByStatus(ctx context.Context, status types.MessageStatu
 			&msg.Status,
 			&msg.Nonce,
 			&msg.CreatedAt,
+			&msg.OrgID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
@@ -289,20 +456,22 @@ func (db *DB) GetMessagesByStatus(ctx context.Context, status types.MessageStatu
 	return messages, nil
 }
 
-// GetMessagesByChains retrieves messages between specific chains
-func (db *DB) GetMessagesByChains(ctx context.Context, sourceChain, destChain string, limit int) ([]types.CrossChainMessage, error) {
+// GetMessagesByChains retrieves messages between specific chains. If orgID is
+// set, only that organization's messages are returned.
+func (db *DB) GetMessagesByChains(ctx context.Context, orgID, sourceChain, destChain string, limit int) ([]types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, type, source_chain_id, source_chain_name, destination_chain_id,
 			destination_chain_name, sender, recipient, payload, status, nonce,
-			timestamp
+			timestamp, COALESCE(org_id, '')
 		FROM messages
 		WHERE source_chain_name = $1 AND destination_chain_name = $2
+		AND ($4 = '' OR org_id = $4)
 		ORDER BY timestamp DESC
 		LIMIT $3
 	`
 
-	rows, err := db.QueryContext(ctx, query, sourceChain, destChain, limit)
+	rows, err := db.QueryContext(ctx, query, sourceChain, destChain, limit, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query messages: %w", err)
 	}
@@ -327,6 +496,7 @@ func (db *DB) GetMessagesByChains(ctx context.Context, sourceChain, destChain st
 			&msg.Status,
 			&msg.Nonce,
 			&msg.CreatedAt,
+			&msg.OrgID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// newMockDB returns a DB backed by sqlmock
+func newMockDB(t *testing.T) (*DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+
+	return &DB{DB: conn, logger: zerolog.Nop()}, mock
+}
+
+func sourceEventMessage(id string) *types.CrossChainMessage {
+	return &types.CrossChainMessage{
+		ID:             id,
+		Type:           types.MessageTypeTokenTransfer,
+		SourceChain:    types.ChainInfo{Name: "ethereum"},
+		SourceTxHash:   "0xabc",
+		SourceLogIndex: 3,
+		Status:         types.MessageStatusPending,
+	}
+}
+
+func TestSaveMessage_ResaveSameMessage(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-1")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO message_source_events").
+		WithArgs("ethereum", "0xabc", uint64(3), "msg-1").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1"))
+	mock.ExpectExec("INSERT INTO messages").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.SaveMessage(context.Background(), msg); err != nil {
+		t.Fatalf("Expected re-saving the same message to succeed, got %v", err)
+	}
+}
+
+func TestSaveMessageWithOutbox_KeepsTerminalStatus(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-1")
+
+	// The message has completed, so the upsert matches no row: it must not
+	// be reset to PENDING or queued for a second release
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO message_source_events").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1"))
+	mock.ExpectExec("ON CONFLICT \\(id\\) DO UPDATE .* WHERE messages.status NOT IN \\('COMPLETED', 'FAILED'\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := db.SaveMessageWithOutbox(context.Background(), msg); err != nil {
+		t.Fatalf("SaveMessageWithOutbox failed: %v", err)
+	}
+}
+
+func TestSaveMessage_DuplicateSourceEvent(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-2")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO message_source_events").
+		WithArgs("ethereum", "0xabc", uint64(3), "msg-2").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1"))
+	mock.ExpectRollback()
+
+	err := db.SaveMessage(context.Background(), msg)
+	if !errors.Is(err, ErrDuplicateSourceEvent) {
+		t.Fatalf("Expected ErrDuplicateSourceEvent, got %v", err)
+	}
+}
+
+func TestSaveMessageWithOutbox_DuplicateSourceEventWritesNoOutbox(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-2")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO message_source_events").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1"))
+	mock.ExpectRollback()
+
+	err := db.SaveMessageWithOutbox(context.Background(), msg)
+	if !errors.Is(err, ErrDuplicateSourceEvent) {
+		t.Fatalf("Expected ErrDuplicateSourceEvent, got %v", err)
+	}
+}
+
+func TestSaveMessage_WithoutSourceEvent(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-api")
+	msg.SourceTxHash = ""
+
+	// API submissions have no on-chain event to claim yet
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO messages").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.SaveMessage(context.Background(), msg); err != nil {
+		t.Fatalf("SaveMessage failed: %v", err)
+	}
+}
+
+func TestInsertMessageWithOutbox_ExistingMessageUntouched(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-1")
+
+	// The message predates source event tracking, so only its ID matches.
+	// It must not be reset to PENDING or published again.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO messages .* ON CONFLICT \\(id\\) DO NOTHING").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	inserted, err := db.InsertMessageWithOutbox(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("InsertMessageWithOutbox failed: %v", err)
+	}
+	if inserted {
+		t.Error("Expected existing message not to be inserted")
+	}
+}
+
+func TestInsertMessageWithOutbox_NewMessage(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-1")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO messages .* ON CONFLICT \\(id\\) DO NOTHING").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO message_source_events").
+		WithArgs("ethereum", "0xabc", uint64(3), "msg-1").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1"))
+	mock.ExpectExec("INSERT INTO message_outbox").
+		WithArgs("msg-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	inserted, err := db.InsertMessageWithOutbox(context.Background(), msg)
+	if err != nil || !inserted {
+		t.Fatalf("Expected message to be inserted, got %v, %v", inserted, err)
+	}
+}
+
+func TestInsertMessageWithOutbox_DuplicateSourceEvent(t *testing.T) {
+	db, mock := newMockDB(t)
+	msg := sourceEventMessage("msg-2")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO messages").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO message_source_events").
+		WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow("msg-1"))
+	mock.ExpectRollback()
+
+	_, err := db.InsertMessageWithOutbox(context.Background(), msg)
+	if !errors.Is(err, ErrDuplicateSourceEvent) {
+		t.Fatalf("Expected ErrDuplicateSourceEvent, got %v", err)
+	}
+}
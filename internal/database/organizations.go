@@ -0,0 +1,258 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultOrganizationID is the operator's own organization. Users created
+// before organizations existed belong to it.
+const DefaultOrganizationID = "org-default"
+
+var (
+	// ErrOrganizationNotFound is returned for unknown organizations
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrQuotaExceeded is returned when an organization is at a quota limit
+	ErrQuotaExceeded = errors.New("organization quota exceeded")
+)
+
+// QuotaResource is a resource limited by an organization quota
+type QuotaResource string
+
+const (
+	QuotaUsers         QuotaResource = "users"
+	QuotaAPIKeys       QuotaResource = "api_keys"
+	QuotaWebhooks      QuotaResource = "webhooks"
+	QuotaDailyMessages QuotaResource = "daily_messages"
+)
+
+// Organization is a tenant owning users, API keys, webhooks and messages.
+// Quotas of 0 are unlimited.
+type Organization struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Slug             string    `json:"slug"`
+	Active           bool      `json:"active"`
+	MaxUsers         int       `json:"max_users"`
+	MaxAPIKeys       int       `json:"max_api_keys"`
+	MaxWebhooks      int       `json:"max_webhooks"`
+	MaxDailyMessages int       `json:"max_daily_messages"`
+	PlanID           string    `json:"plan_id,omitempty"` // rate limit plan, empty for none
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// OrganizationUsage is an organization's current usage of each quota
+type OrganizationUsage struct {
+	Users         int `json:"users"`
+	APIKeys       int `json:"api_keys"`
+	Webhooks      int `json:"webhooks"`
+	DailyMessages int `json:"daily_messages"`
+}
+
+// quotaUsageQueries count the current usage of each quota resource
+var quotaUsageQueries = map[QuotaResource]string{
+	QuotaUsers: `SELECT COUNT(*) FROM users WHERE org_id = $1 AND active = true`,
+	QuotaAPIKeys: `SELECT COUNT(*) FROM api_keys
+		WHERE org_id = $1 AND active = true AND (expires_at IS NULL OR expires_at > NOW())`,
+	QuotaWebhooks:      `SELECT COUNT(*) FROM webhooks WHERE org_id = $1`,
+	QuotaDailyMessages: `SELECT COUNT(*) FROM messages WHERE org_id = $1 AND created_at >= date_trunc('day', NOW())`,
+}
+
+// CreateOrganization creates a new organization
+func (db *DB) CreateOrganization(ctx context.Context, org *Organization) error {
+	query := `
+		INSERT INTO organizations (
+			id, name, slug, active, max_users, max_api_keys,
+			max_webhooks, max_daily_messages, plan_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
+		RETURNING created_at, updated_at
+	`
+
+	err := db.QueryRowContext(ctx, query,
+		org.ID,
+		org.Name,
+		org.Slug,
+		org.Active,
+		org.MaxUsers,
+		org.MaxAPIKeys,
+		org.MaxWebhooks,
+		org.MaxDailyMessages,
+		org.PlanID,
+	).Scan(&org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (db *DB) GetOrganization(ctx context.Context, orgID string) (*Organization, error) {
+	query := `
+		SELECT id, name, slug, active, max_users, max_api_keys,
+			max_webhooks, max_daily_messages, COALESCE(plan_id, ''), created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	org, err := scanOrganization(db.QueryRowContext(ctx, query, orgID))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return org, nil
+}
+
+// ListOrganizations lists all organizations
+func (db *DB) ListOrganizations(ctx context.Context) ([]*Organization, error) {
+	query := `
+		SELECT id, name, slug, active, max_users, max_api_keys,
+			max_webhooks, max_daily_messages, COALESCE(plan_id, ''), created_at, updated_at
+		FROM organizations
+		ORDER BY created_at ASC
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := []*Organization{}
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// UpdateOrganization updates an organization's name, status and quotas
+func (db *DB) UpdateOrganization(ctx context.Context, org *Organization) error {
+	query := `
+		UPDATE organizations
+		SET name = $2, active = $3, max_users = $4, max_api_keys = $5,
+			max_webhooks = $6, max_daily_messages = $7, plan_id = NULLIF($8, '')
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := db.QueryRowContext(ctx, query,
+		org.ID,
+		org.Name,
+		org.Active,
+		org.MaxUsers,
+		org.MaxAPIKeys,
+		org.MaxWebhooks,
+		org.MaxDailyMessages,
+		org.PlanID,
+	).Scan(&org.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrOrganizationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrganizationUsage returns an organization's current quota usage
+func (db *DB) GetOrganizationUsage(ctx context.Context, orgID string) (*OrganizationUsage, error) {
+	var usage OrganizationUsage
+	counts := map[QuotaResource]*int{
+		QuotaUsers:         &usage.Users,
+		QuotaAPIKeys:       &usage.APIKeys,
+		QuotaWebhooks:      &usage.Webhooks,
+		QuotaDailyMessages: &usage.DailyMessages,
+	}
+
+	for resource, count := range counts {
+		if err := db.QueryRowContext(ctx, quotaUsageQueries[resource], orgID).Scan(count); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", resource, err)
+		}
+	}
+
+	return &usage, nil
+}
+
+// CheckQuota returns ErrQuotaExceeded if the organization cannot create
+// another resource of the given kind. Quotas are checked before creation and
+// are not transactional, so concurrent requests may briefly exceed them.
+func (db *DB) CheckQuota(ctx context.Context, orgID string, resource QuotaResource) error {
+	org, err := db.GetOrganization(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	limit := org.Quota(resource)
+	if limit <= 0 {
+		return nil
+	}
+
+	var used int
+	if err := db.QueryRowContext(ctx, quotaUsageQueries[resource], orgID).Scan(&used); err != nil {
+		return fmt.Errorf("failed to count %s: %w", resource, err)
+	}
+
+	if used >= limit {
+		return fmt.Errorf("%w: %s limit is %d", ErrQuotaExceeded, resource, limit)
+	}
+
+	return nil
+}
+
+// Quota returns the organization's limit for a resource, 0 if unlimited
+func (o *Organization) Quota(resource QuotaResource) int {
+	switch resource {
+	case QuotaUsers:
+		return o.MaxUsers
+	case QuotaAPIKeys:
+		return o.MaxAPIKeys
+	case QuotaWebhooks:
+		return o.MaxWebhooks
+	case QuotaDailyMessages:
+		return o.MaxDailyMessages
+	default:
+		return 0
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrganization(row rowScanner) (*Organization, error) {
+	var org Organization
+	err := row.Scan(
+		&org.ID,
+		&org.Name,
+		&org.Slug,
+		&org.Active,
+		&org.MaxUsers,
+		&org.MaxAPIKeys,
+		&org.MaxWebhooks,
+		&org.MaxDailyMessages,
+		&org.PlanID,
+		&org.CreatedAt,
+		&org.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
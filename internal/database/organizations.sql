@@ -0,0 +1,64 @@
+-- Organizations (Tenants) Schema
+
+-- Organizations own users, API keys, webhooks and messages. Quotas of 0 are
+-- unlimited.
+CREATE TABLE IF NOT EXISTS organizations (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    max_users INTEGER NOT NULL DEFAULT 0,
+    max_api_keys INTEGER NOT NULL DEFAULT 0,
+    max_webhooks INTEGER NOT NULL DEFAULT 0,
+    max_daily_messages INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- The operator's own organization. Existing users, keys and webhooks are
+-- assigned to it.
+INSERT INTO organizations (id, name, slug)
+VALUES ('org-default', 'Default Organization', 'default')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id VARCHAR(100) NOT NULL DEFAULT 'org-default' REFERENCES organizations(id);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS org_id VARCHAR(100) NOT NULL DEFAULT 'org-default' REFERENCES organizations(id);
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS org_id VARCHAR(100) NOT NULL DEFAULT 'org-default' REFERENCES organizations(id);
+
+-- Messages submitted through the API belong to the caller's organization.
+-- Messages observed on-chain by listeners have no organization.
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS org_id VARCHAR(100) REFERENCES organizations(id);
+
+-- Routes belong to the organization that discovered them.
+ALTER TABLE routes ADD COLUMN IF NOT EXISTS org_id VARCHAR(100) REFERENCES organizations(id);
+
+CREATE INDEX IF NOT EXISTS idx_users_org_id ON users(org_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_org_id ON api_keys(org_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_org_id ON webhooks(org_id);
+CREATE INDEX IF NOT EXISTS idx_messages_org_id ON messages(org_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_routes_org_id ON routes(org_id);
+
+DROP TRIGGER IF EXISTS trigger_update_organizations_updated_at ON organizations;
+CREATE TRIGGER trigger_update_organizations_updated_at
+    BEFORE UPDATE ON organizations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_users_updated_at();
+
+-- Password tokens (hashed), issued by admins to invite a user or reset a
+-- password. Each token can be used once.
+CREATE TABLE IF NOT EXISTS password_tokens (
+    id VARCHAR(100) PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('invite', 'reset')),
+    created_by VARCHAR(100),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_tokens_user_id ON password_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_password_tokens_expires_at ON password_tokens(expires_at);
+
+COMMENT ON TABLE organizations IS 'Tenants owning users, API keys, webhooks and messages';
+COMMENT ON TABLE password_tokens IS 'Single-use invite and password reset tokens';
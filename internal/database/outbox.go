@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+)
+
+// OutboxEntry represents a message waiting to be published to the queue
+type OutboxEntry struct {
+	ID        int64                    `json:"id"`
+	MessageID string                   `json:"message_id"`
+	Message   *types.CrossChainMessage `json:"message"`
+	Attempts  int                      `json:"attempts"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+// SaveMessageWithOutbox saves a message and its outbox entry in a single
+// transaction, so a saved message is always eventually published
+func (db *DB) SaveMessageWithOutbox(ctx context.Context, msg *types.CrossChainMessage) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveMessageWithOutbox(ctx, tx, msg); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	db.logger.Debug().
+		Str("message_id", msg.ID).
+		Msg("Message and outbox entry saved to database")
+
+	return nil
+}
+
+// InsertMessageWithOutbox saves a new message and its outbox entry in a
+// single transaction. Unlike SaveMessageWithOutbox it never updates an
+// existing message: if one with the same ID is recorded, nothing is written
+// and false is returned, so a message that has already been relayed is not
+// published again.
+func (db *DB) InsertMessageWithOutbox(ctx context.Context, msg *types.CrossChainMessage) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	inserted, err := insertMessage(ctx, tx, msg)
+	if err != nil || !inserted {
+		return false, err
+	}
+
+	if err := saveOutboxEntry(ctx, tx, msg); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	db.logger.Debug().
+		Str("message_id", msg.ID).
+		Msg("Message and outbox entry inserted into database")
+
+	return true, nil
+}
+
+// saveMessageWithOutbox saves a message and queues its outbox entry using
+// the given executor. A message that was not written, because it has
+// already completed or failed, is not queued again.
+func saveMessageWithOutbox(ctx context.Context, ex dbtx, msg *types.CrossChainMessage) error {
+	saved, err := saveMessage(ctx, ex, msg)
+	if err != nil || !saved {
+		return err
+	}
+
+	return saveOutboxEntry(ctx, ex, msg)
+}
+
+// saveOutboxEntry queues the message for publishing using the given executor
+func saveOutboxEntry(ctx context.Context, ex dbtx, msg *types.CrossChainMessage) error {
+	messageJSON, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	query := `
+		INSERT INTO message_outbox (message_id, payload)
+		VALUES ($1, $2)
+	`
+
+	if _, err := ex.ExecContext(ctx, query, msg.ID, messageJSON); err != nil {
+		return fmt.Errorf("failed to save outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimOutboxEntries claims up to limit unpublished entries for publishing.
+// Claimed entries are hidden from other publishers for the lease duration;
+// if they are not marked published by then they become available again.
+func (db *DB) ClaimOutboxEntries(ctx context.Context, limit int, lease time.Duration) ([]*OutboxEntry, error) {
+	query := `
+		UPDATE message_outbox
+		SET attempts = attempts + 1,
+			available_at = NOW() + ($2 * INTERVAL '1 millisecond')
+		WHERE id IN (
+			SELECT id FROM message_outbox
+			WHERE published_at IS NULL AND available_at <= NOW()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, message_id, payload, attempts, created_at
+	`
+
+	rows, err := db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*OutboxEntry
+	undecodable := make(map[int64]string)
+	for rows.Next() {
+		var entry OutboxEntry
+		var payload []byte
+
+		if err := rows.Scan(&entry.ID, &entry.MessageID, &payload, &entry.Attempts, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+
+		var msg types.CrossChainMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			undecodable[entry.ID] = fmt.Sprintf("failed to unmarshal outbox message %s: %v", entry.MessageID, err)
+			continue
+		}
+		entry.Message = &msg
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox entries: %w", err)
+	}
+	rows.Close()
+
+	// An entry that cannot be decoded would be claimed first after every
+	// lease, so it is set aside for an operator and the rest are published
+	for id, errMsg := range undecodable {
+		db.logger.Error().
+			Int64("outbox_id", id).
+			Str("error", errMsg).
+			Msg("Setting aside undecodable outbox entry")
+		if err := db.deadLetterOutboxEntry(ctx, id, errMsg); err != nil {
+			db.logger.Error().Err(err).Int64("outbox_id", id).Msg("Failed to set aside outbox entry")
+		}
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	return entries, nil
+}
+
+// MarkOutboxPublished marks an outbox entry as published
+func (db *DB) MarkOutboxPublished(ctx context.Context, id int64) error {
+	query := `
+		UPDATE message_outbox
+		SET published_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox entry published: %w", err)
+	}
+
+	return nil
+}
+
+// MarkOutboxFailed records a publish failure and schedules the next attempt
+func (db *DB) MarkOutboxFailed(ctx context.Context, id int64, errMsg string, retryAfter time.Duration) error {
+	query := `
+		UPDATE message_outbox
+		SET last_error = $2,
+			available_at = NOW() + ($3 * INTERVAL '1 millisecond')
+		WHERE id = $1
+	`
+
+	if _, err := db.ExecContext(ctx, query, id, errMsg, retryAfter.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to mark outbox entry failed: %w", err)
+	}
+
+	return nil
+}
+
+// deadLetterOutboxEntry records why an entry cannot be published and stops
+// it from being claimed again. It stays unpublished, so it is still counted
+// in the backlog.
+func (db *DB) deadLetterOutboxEntry(ctx context.Context, id int64, errMsg string) error {
+	query := `
+		UPDATE message_outbox
+		SET last_error = $2, available_at = 'infinity'
+		WHERE id = $1
+	`
+
+	if _, err := db.ExecContext(ctx, query, id, errMsg); err != nil {
+		return fmt.Errorf("failed to dead-letter outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetOutboxBacklog returns the number of unpublished outbox entries
+func (db *DB) GetOutboxBacklog(ctx context.Context) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM message_outbox WHERE published_at IS NULL`
+
+	if err := db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to get outbox backlog: %w", err)
+	}
+
+	return count, nil
+}
+
+// PurgePublishedOutbox deletes entries published before the retention period
+func (db *DB) PurgePublishedOutbox(ctx context.Context, retention time.Duration) (int64, error) {
+	query := `
+		DELETE FROM message_outbox
+		WHERE published_at IS NOT NULL
+		AND published_at < NOW() - ($1 * INTERVAL '1 millisecond')
+	`
+
+	result, err := db.ExecContext(ctx, query, retention.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", err)
+	}
+
+	return result.RowsAffected()
+}
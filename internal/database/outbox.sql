@@ -0,0 +1,20 @@
+-- Transactional Outbox Schema
+
+-- Outbox table: written in the same transaction as the message row and
+-- drained to the message queue by the outbox publisher
+CREATE TABLE IF NOT EXISTS message_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    message_id VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_outbox_pending ON message_outbox(available_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_message_outbox_message_id ON message_outbox(message_id);
+CREATE INDEX IF NOT EXISTS idx_message_outbox_published_at ON message_outbox(published_at) WHERE published_at IS NOT NULL;
+
+COMMENT ON TABLE message_outbox IS 'Transactional outbox of messages awaiting publication to the queue';
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// insertOutboxEntries writes n outbox rows directly
+func insertOutboxEntries(t *testing.T, db *DB, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		_, err := db.Exec(
+			`INSERT INTO message_outbox (message_id, payload) VALUES ($1, $2)`,
+			fmt.Sprintf("msg-%d", i), fmt.Sprintf(`{"id":"msg-%d"}`, i),
+		)
+		if err != nil {
+			t.Fatalf("Failed to insert outbox entry: %v", err)
+		}
+	}
+}
+
+func TestClaimOutboxEntries_LeaseHidesEntries(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	insertOutboxEntries(t, db, 1)
+
+	lease := 300 * time.Millisecond
+	entries, err := db.ClaimOutboxEntries(ctx, 10, lease)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one claimed entry, got %d: %v", len(entries), err)
+	}
+	if entries[0].Attempts != 1 || entries[0].Message.ID != "msg-0" {
+		t.Errorf("Unexpected claimed entry: %+v", entries[0])
+	}
+
+	if again, _ := db.ClaimOutboxEntries(ctx, 10, lease); len(again) != 0 {
+		t.Fatalf("Expected leased entry to be hidden, got %d", len(again))
+	}
+
+	time.Sleep(lease + 100*time.Millisecond)
+	reclaimed, err := db.ClaimOutboxEntries(ctx, 10, lease)
+	if err != nil || len(reclaimed) != 1 || reclaimed[0].Attempts != 2 {
+		t.Fatalf("Expected expired lease to be reclaimed on attempt 2, got %+v: %v", reclaimed, err)
+	}
+
+	if err := db.MarkOutboxPublished(ctx, reclaimed[0].ID); err != nil {
+		t.Fatalf("MarkOutboxPublished failed: %v", err)
+	}
+	time.Sleep(lease + 100*time.Millisecond)
+	if after, _ := db.ClaimOutboxEntries(ctx, 10, lease); len(after) != 0 {
+		t.Errorf("Expected published entry never to be claimed, got %d", len(after))
+	}
+}
+
+func TestClaimOutboxEntries_ConcurrentClaimsAreDisjoint(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	insertOutboxEntries(t, db, 20)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed = make(map[int64]int)
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries, err := db.ClaimOutboxEntries(ctx, 5, time.Minute)
+			if err != nil {
+				t.Errorf("ClaimOutboxEntries failed: %v", err)
+				return
+			}
+			mu.Lock()
+			for _, e := range entries {
+				claimed[e.ID]++
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(claimed) != 20 {
+		t.Errorf("Expected all 20 entries claimed once, got %d", len(claimed))
+	}
+	for id, n := range claimed {
+		if n != 1 {
+			t.Errorf("Entry %d claimed %d times", id, n)
+		}
+	}
+}
+
+func TestMarkOutboxFailedDelaysRetry(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	insertOutboxEntries(t, db, 1)
+
+	entries, _ := db.ClaimOutboxEntries(ctx, 1, 0)
+	if len(entries) != 1 {
+		t.Fatal("Expected entry to be claimed")
+	}
+	if err := db.MarkOutboxFailed(ctx, entries[0].ID, "queue unavailable", 300*time.Millisecond); err != nil {
+		t.Fatalf("MarkOutboxFailed failed: %v", err)
+	}
+
+	if again, _ := db.ClaimOutboxEntries(ctx, 1, 0); len(again) != 0 {
+		t.Fatal("Expected failed entry to wait for its retry delay")
+	}
+	time.Sleep(400 * time.Millisecond)
+	if again, _ := db.ClaimOutboxEntries(ctx, 1, 0); len(again) != 1 {
+		t.Error("Expected failed entry to be retried after the delay")
+	}
+}
+
+func TestPurgePublishedOutbox(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	insertOutboxEntries(t, db, 2)
+
+	entries, _ := db.ClaimOutboxEntries(ctx, 1, time.Minute)
+	if len(entries) != 1 {
+		t.Fatal("Expected entry to be claimed")
+	}
+	if err := db.MarkOutboxPublished(ctx, entries[0].ID); err != nil {
+		t.Fatalf("MarkOutboxPublished failed: %v", err)
+	}
+
+	if deleted, _ := db.PurgePublishedOutbox(ctx, time.Hour); deleted != 0 {
+		t.Fatalf("Expected nothing purged within retention, got %d", deleted)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	deleted, err := db.PurgePublishedOutbox(ctx, 10*time.Millisecond)
+	if err != nil || deleted != 1 {
+		t.Fatalf("Expected published entry purged, got %d: %v", deleted, err)
+	}
+	if backlog, _ := db.GetOutboxBacklog(ctx); backlog != 1 {
+		t.Errorf("Expected unpublished entry kept, got backlog %d", backlog)
+	}
+}
+
+func TestClaimOutboxEntries_SetsAsideUndecodableEntry(t *testing.T) {
+	db, mock := newMockDB(t)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE message_outbox").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message_id", "payload", "attempts", "created_at"}).
+			AddRow(1, "msg-1", []byte(`{"id":"msg-1"}`), 1, now).
+			AddRow(2, "msg-2", []byte(`{"id":2}`), 1, now).
+			AddRow(3, "msg-3", []byte(`{"id":"msg-3"}`), 1, now))
+	mock.ExpectExec("SET last_error = \\$2, available_at = 'infinity'").
+		WithArgs(int64(2), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The undecodable entry must not hold back the rest of the batch
+	entries, err := db.ClaimOutboxEntries(context.Background(), 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimOutboxEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != 1 || entries[1].ID != 3 {
+		t.Errorf("Expected entries 1 and 3, got %+v", entries)
+	}
+}
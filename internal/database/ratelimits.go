@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPlanNotFound is returned for unknown rate limit plans
+var ErrPlanNotFound = errors.New("rate limit plan not found")
+
+// RateLimitPlan sets request limits for read and write routes. Burst is the
+// number of requests allowed at once; 0 means one minute's worth.
+type RateLimitPlan struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	ReadPerMinute  int       `json:"read_per_minute"`
+	WritePerMinute int       `json:"write_per_minute"`
+	Burst          int       `json:"burst"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+const planColumns = `p.id, p.name, p.read_per_minute, p.write_per_minute, p.burst, p.created_at, p.updated_at`
+
+// CreateRateLimitPlan creates a new rate limit plan
+func (db *DB) CreateRateLimitPlan(ctx context.Context, plan *RateLimitPlan) error {
+	query := `
+		INSERT INTO rate_limit_plans (id, name, read_per_minute, write_per_minute, burst)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at
+	`
+
+	err := db.QueryRowContext(ctx, query,
+		plan.ID,
+		plan.Name,
+		plan.ReadPerMinute,
+		plan.WritePerMinute,
+		plan.Burst,
+	).Scan(&plan.CreatedAt, &plan.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create rate limit plan: %w", err)
+	}
+
+	return nil
+}
+
+// GetRateLimitPlan retrieves a rate limit plan by ID
+func (db *DB) GetRateLimitPlan(ctx context.Context, planID string) (*RateLimitPlan, error) {
+	query := `SELECT ` + planColumns + ` FROM rate_limit_plans p WHERE p.id = $1`
+	return db.getRateLimitPlan(ctx, query, planID)
+}
+
+// ListRateLimitPlans lists all rate limit plans
+func (db *DB) ListRateLimitPlans(ctx context.Context) ([]*RateLimitPlan, error) {
+	query := `SELECT ` + planColumns + ` FROM rate_limit_plans p ORDER BY p.read_per_minute ASC`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate limit plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*RateLimitPlan{}
+	for rows.Next() {
+		plan, err := scanRateLimitPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rate limit plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rate limit plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// UpdateRateLimitPlan updates a plan's name and limits
+func (db *DB) UpdateRateLimitPlan(ctx context.Context, plan *RateLimitPlan) error {
+	query := `
+		UPDATE rate_limit_plans
+		SET name = $2, read_per_minute = $3, write_per_minute = $4, burst = $5
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := db.QueryRowContext(ctx, query,
+		plan.ID,
+		plan.Name,
+		plan.ReadPerMinute,
+		plan.WritePerMinute,
+		plan.Burst,
+	).Scan(&plan.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrPlanNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update rate limit plan: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPIKeyRateLimitPlan returns the plan assigned to an API key, or nil if
+// it has none
+func (db *DB) GetAPIKeyRateLimitPlan(ctx context.Context, apiKeyID string) (*RateLimitPlan, error) {
+	query := `
+		SELECT ` + planColumns + `
+		FROM api_keys k
+		JOIN rate_limit_plans p ON k.plan_id = p.id
+		WHERE k.id = $1
+	`
+	return db.getAssignedRateLimitPlan(ctx, query, apiKeyID)
+}
+
+// GetOrganizationRateLimitPlan returns the plan assigned to an
+// organization, or nil if it has none
+func (db *DB) GetOrganizationRateLimitPlan(ctx context.Context, orgID string) (*RateLimitPlan, error) {
+	query := `
+		SELECT ` + planColumns + `
+		FROM organizations o
+		JOIN rate_limit_plans p ON o.plan_id = p.id
+		WHERE o.id = $1
+	`
+	return db.getAssignedRateLimitPlan(ctx, query, orgID)
+}
+
+// SetAPIKeyRateLimitPlan assigns a plan to an API key. An empty planID
+// removes the key's plan.
+func (db *DB) SetAPIKeyRateLimitPlan(ctx context.Context, apiKeyID, planID string) error {
+	query := `
+		UPDATE api_keys
+		SET plan_id = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := db.ExecContext(ctx, query, apiKeyID, planID)
+	if err != nil {
+		return fmt.Errorf("failed to set API key plan: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to set API key plan: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("API key not found: %s", apiKeyID)
+	}
+
+	return nil
+}
+
+func (db *DB) getRateLimitPlan(ctx context.Context, query string, arg interface{}) (*RateLimitPlan, error) {
+	plan, err := scanRateLimitPlan(db.QueryRowContext(ctx, query, arg))
+	if err == sql.ErrNoRows {
+		return nil, ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (db *DB) getAssignedRateLimitPlan(ctx context.Context, query string, arg interface{}) (*RateLimitPlan, error) {
+	plan, err := db.getRateLimitPlan(ctx, query, arg)
+	if errors.Is(err, ErrPlanNotFound) {
+		return nil, nil
+	}
+	return plan, err
+}
+
+func scanRateLimitPlan(row rowScanner) (*RateLimitPlan, error) {
+	var plan RateLimitPlan
+	err := row.Scan(
+		&plan.ID,
+		&plan.Name,
+		&plan.ReadPerMinute,
+		&plan.WritePerMinute,
+		&plan.Burst,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
@@ -0,0 +1,57 @@
+-- Rate Limiting Schema
+
+-- Plans set request limits for read (GET, HEAD, OPTIONS) and write routes.
+-- A plan assigned to an API key limits that key; a plan assigned to an
+-- organization limits the organization's combined traffic. Burst is the
+-- number of requests allowed at once; 0 means one minute's worth.
+CREATE TABLE IF NOT EXISTS rate_limit_plans (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    read_per_minute INTEGER NOT NULL CHECK (read_per_minute > 0),
+    write_per_minute INTEGER NOT NULL CHECK (write_per_minute > 0),
+    burst INTEGER NOT NULL DEFAULT 0 CHECK (burst >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO rate_limit_plans (id, name, read_per_minute, write_per_minute) VALUES
+    ('plan-free', 'free', 60, 10),
+    ('plan-standard', 'standard', 600, 120),
+    ('plan-enterprise', 'enterprise', 6000, 1200)
+ON CONFLICT (id) DO NOTHING;
+
+DROP TRIGGER IF EXISTS trigger_update_rate_limit_plans_updated_at ON rate_limit_plans;
+CREATE TRIGGER trigger_update_rate_limit_plans_updated_at
+    BEFORE UPDATE ON rate_limit_plans
+    FOR EACH ROW
+    EXECUTE FUNCTION update_users_updated_at();
+
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS plan_id VARCHAR(100) REFERENCES rate_limit_plans(id);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS plan_id VARCHAR(100) REFERENCES rate_limit_plans(id);
+
+-- Rate limiter state, used when Redis is unavailable. tat is the GCRA
+-- theoretical arrival time in microseconds since the Unix epoch.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(255) PRIMARY KEY,
+    tat BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_expires_at ON rate_limit_buckets(expires_at);
+
+-- Hourly request counts per rate limit subject, used when Redis is
+-- unavailable
+CREATE TABLE IF NOT EXISTS rate_limit_usage (
+    subject VARCHAR(255) NOT NULL,
+    class VARCHAR(10) NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    allowed BIGINT NOT NULL DEFAULT 0,
+    rejected BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (subject, class, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_usage_window_start ON rate_limit_usage(window_start);
+
+COMMENT ON TABLE rate_limit_plans IS 'Read and write request limits for API keys and organizations';
+COMMENT ON TABLE rate_limit_buckets IS 'GCRA rate limiter state (Postgres fallback)';
+COMMENT ON TABLE rate_limit_usage IS 'Hourly request counts per rate limit subject (Postgres fallback)';
@@ -0,0 +1,21 @@
+package database
+
+// SchemaFiles lists the schema SQL files in the order they must be applied
+var SchemaFiles = []string{
+	"schema.sql",          // Main tables (chains, messages, validators, etc.)
+	"auth.sql",            // Authentication tables (users, api_keys)
+	"batches.sql",         // Batch processing tables
+	"routes.sql",          // Multi-hop routing tables
+	"webhooks.sql",        // Webhook integration tables
+	"outbox.sql",          // Transactional message outbox
+	"idempotency.sql",     // Idempotency keys and source event dedupe
+	"organizations.sql",   // Tenants, user lifecycle and tenant ownership
+	"audit.sql",           // Hash-chained audit log
+	"ratelimits.sql",      // Rate limit plans and fallback limiter state
+	"webhook_signing.sql", // Webhook secret rotation and signed bodies
+	"webhook_queue.sql",   // Durable webhook delivery queue
+	"webhook_health.sql",  // Webhook endpoint health and suspension
+	"webhook_replay.sql",  // Webhook event replay
+	"message_stream.sql",  // Live message tracking stream
+	"bitcoin.sql",         // Bitcoin custody UTXO index and releases
+}
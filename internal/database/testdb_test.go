@@ -0,0 +1,14 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database/dbtest"
+	"github.com/rs/zerolog"
+)
+
+// openTestDB returns a DB bound to a fresh schema in the test database
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	return &DB{DB: dbtest.Open(t, ".", SchemaFiles), logger: zerolog.Nop()}
+}
@@ -0,0 +1,15 @@
+-- Webhook Endpoint Health
+
+-- health_score is an exponentially weighted success rate of recent
+-- delivery attempts, from 0 (all failing) to 1 (all succeeding). After
+-- too many consecutive failures a webhook is suspended (status FAILED)
+-- until a background probe succeeds.
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS health_score DOUBLE PRECISION NOT NULL DEFAULT 1;
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS last_probe_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_suspended ON webhooks(last_probe_at) WHERE status = 'FAILED';
+
+-- Set while an event is leased to a replica, so deliveries in flight to an
+-- endpoint can be counted across replicas
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
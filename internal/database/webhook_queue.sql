@@ -0,0 +1,40 @@
+-- Durable Webhook Delivery Queue
+
+-- Each webhook event is a queue entry. Replicas claim due PENDING events
+-- with SKIP LOCKED and lease them by pushing available_at forward; an
+-- event whose replica crashes mid-delivery is claimed again once its lease
+-- expires. Failed attempts are rescheduled through available_at.
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+    CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED'));
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS available_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS last_error TEXT;
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+-- Carry over the state of events delivered before the queue existed. Only
+-- events not yet claimed from the queue are touched, so this is safe to
+-- re-run.
+UPDATE webhook_events we
+SET attempts = a.attempts,
+    status = CASE
+        WHEN a.delivered THEN 'DELIVERED'
+        WHEN a.next_retry_at IS NULL THEN 'FAILED'
+        ELSE 'PENDING'
+    END,
+    available_at = COALESCE(a.next_retry_at, we.available_at),
+    completed_at = CASE WHEN a.delivered OR a.next_retry_at IS NULL THEN a.last_attempt_at END
+FROM (
+    SELECT
+        event_id,
+        MAX(attempt_number) AS attempts,
+        BOOL_OR(success) AS delivered,
+        MAX(attempted_at) AS last_attempt_at,
+        (ARRAY_AGG(next_retry_at ORDER BY attempted_at DESC))[1] AS next_retry_at
+    FROM webhook_attempts
+    GROUP BY event_id
+) a
+WHERE we.id = a.event_id
+AND we.attempts = 0
+AND we.status = 'PENDING';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_pending ON webhook_events(available_at) WHERE status = 'PENDING';
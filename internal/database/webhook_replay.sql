@@ -0,0 +1,10 @@
+-- Webhook Event Replay
+
+-- A replayed event is queued as a new event that copies the original's
+-- body. replay_of is the original event and replay_id groups the events
+-- queued by one replay request.
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS replay_of VARCHAR(100);
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS replay_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_webhook_timestamp ON webhook_events(webhook_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_replay_id ON webhook_events(replay_id) WHERE replay_id IS NOT NULL;
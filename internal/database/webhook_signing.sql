@@ -0,0 +1,16 @@
+-- Webhook signing: secret rotation and signed request bodies
+
+-- During a rotation's grace period, deliveries are signed with both the new
+-- secret and the previous one
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS previous_secret VARCHAR(255);
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMP;
+
+-- The exact request body sent for an event, so retries send and sign the
+-- same bytes
+ALTER TABLE webhook_events ADD COLUMN IF NOT EXISTS body TEXT;
+
+-- Deliveries to webhooks with legacy_signature set also carry the deprecated
+-- X-Webhook-Signature header until 2027-04-01. Webhooks registered before
+-- the column existed keep receiving it; new webhooks must opt in.
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS legacy_signature BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE webhooks ALTER COLUMN legacy_signature SET DEFAULT FALSE;
@@ -0,0 +1,92 @@
+package algorand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/algorand"
+)
+
+// tokenLockedSelector identifies the bridge application's ARC-28
+// TokenLocked event in its logs
+var tokenLockedSelector = algorand.MethodSelector(
+	"TokenLocked(byte[32],address,uint64,uint64,string,string,uint64,uint64)")
+
+// tokenLockedHeadSize is the size of the static part of an encoded
+// TokenLocked event
+const tokenLockedHeadSize = 32 + 32 + 8 + 8 + 2 + 2 + 8 + 8
+
+// TokenLockedEvent is the ARC-28 TokenLocked event logged by the bridge
+// application. AssetID is 0 for ALGO.
+type TokenLockedEvent struct {
+	MessageID          [32]byte
+	Sender             [32]byte
+	AssetID            uint64
+	Amount             uint64
+	DestinationChain   string
+	DestinationAddress string
+	Nonce              uint64
+	Timestamp          uint64
+
+	TxID     string
+	Round    uint64
+	LogIndex uint64
+}
+
+// applicationLogs returns the logs an application wrote in a transaction,
+// including through inner transactions. Logs written by other applications
+// the transaction group called are ignored.
+func applicationLogs(txn *algorand.Transaction, appID uint64) [][]byte {
+	var logs [][]byte
+	if txn.ApplicationTransaction != nil && txn.ApplicationTransaction.ApplicationID == appID {
+		logs = append(logs, txn.Logs...)
+	}
+	for i := range txn.InnerTxns {
+		logs = append(logs, applicationLogs(&txn.InnerTxns[i], appID)...)
+	}
+	return logs
+}
+
+// decodeTokenLockedEvent decodes a log if it is a TokenLocked event
+func decodeTokenLockedEvent(log []byte) (*TokenLockedEvent, bool, error) {
+	if len(log) < 4 || !bytes.Equal(log[:4], tokenLockedSelector[:]) {
+		return nil, false, nil
+	}
+
+	data := log[4:]
+	if len(data) < tokenLockedHeadSize {
+		return nil, true, fmt.Errorf("event too short: %d bytes", len(data))
+	}
+
+	var event TokenLockedEvent
+	copy(event.MessageID[:], data[0:32])
+	copy(event.Sender[:], data[32:64])
+	event.AssetID = binary.BigEndian.Uint64(data[64:72])
+	event.Amount = binary.BigEndian.Uint64(data[72:80])
+	event.Nonce = binary.BigEndian.Uint64(data[84:92])
+	event.Timestamp = binary.BigEndian.Uint64(data[92:100])
+
+	var err error
+	if event.DestinationChain, err = abiString(data, binary.BigEndian.Uint16(data[80:82])); err != nil {
+		return nil, true, fmt.Errorf("invalid destination_chain: %w", err)
+	}
+	if event.DestinationAddress, err = abiString(data, binary.BigEndian.Uint16(data[82:84])); err != nil {
+		return nil, true, fmt.Errorf("invalid destination_address: %w", err)
+	}
+
+	return &event, true, nil
+}
+
+// abiString reads an ARC-4 string stored at offset in an encoded tuple
+func abiString(data []byte, offset uint16) (string, error) {
+	if int(offset)+2 > len(data) {
+		return "", fmt.Errorf("offset %d out of range", offset)
+	}
+	length := binary.BigEndian.Uint16(data[offset:])
+	start := int(offset) + 2
+	if start+int(length) > len(data) {
+		return "", fmt.Errorf("length %d out of range", length)
+	}
+	return string(data[start : start+int(length)]), nil
+}
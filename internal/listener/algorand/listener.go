@@ -0,0 +1,287 @@
+package algorand
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/algorand"
+	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// roundBatchSize is the number of rounds searched per indexer query. The
+// search is filtered by application ID, so ranges can be wide.
+const roundBatchSize = uint64(10000)
+
+// Listener listens for events on Algorand blockchain
+type Listener struct {
+	client    *algorand.Client
+	config    *types.ChainConfig
+	chains    []types.ChainConfig
+	logger    zerolog.Logger
+	eventChan chan *types.CrossChainMessage
+	stopChan  chan struct{}
+	lastRound uint64
+	appID     uint64
+}
+
+// NewListener creates a new Algorand event listener
+func NewListener(
+	client *algorand.Client,
+	config *types.ChainConfig,
+	chains []types.ChainConfig,
+	logger zerolog.Logger,
+) (*Listener, error) {
+	appID, err := strconv.ParseUint(config.BridgeContract, 10, 64)
+	if err != nil || appID == 0 {
+		return nil, fmt.Errorf("invalid bridge application ID: %s", config.BridgeContract)
+	}
+
+	return &Listener{
+		client:    client,
+		config:    config,
+		chains:    chains,
+		logger:    logger.With().Str("chain", config.Name).Str("component", "listener").Logger(),
+		eventChan: make(chan *types.CrossChainMessage, 100),
+		stopChan:  make(chan struct{}),
+		lastRound: config.StartBlock,
+		appID:     appID,
+	}, nil
+}
+
+// Start starts the listener
+func (l *Listener) Start(ctx context.Context) error {
+	l.logger.Info().
+		Uint64("start_round", l.lastRound).
+		Uint64("app_id", l.appID).
+		Msg("Starting Algorand listener")
+
+	go l.listen(ctx)
+
+	return nil
+}
+
+// Stop stops the listener
+func (l *Listener) Stop() error {
+	l.logger.Info().Msg("Stopping Algorand listener")
+	close(l.stopChan)
+	close(l.eventChan)
+	return nil
+}
+
+// EventChan returns the channel for receiving events
+func (l *Listener) EventChan() <-chan *types.CrossChainMessage {
+	return l.eventChan
+}
+
+// listen is the main listening loop
+func (l *Listener) listen(ctx context.Context) {
+	ticker := time.NewTicker(l.config.GetPollIntervalDuration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.logger.Info().Msg("Context cancelled, stopping listener")
+			return
+		case <-l.stopChan:
+			l.logger.Info().Msg("Stop signal received")
+			return
+		case <-ticker.C:
+			if err := l.processRounds(ctx); err != nil {
+				l.logger.Error().Err(err).Msg("Error processing rounds")
+			}
+		}
+	}
+}
+
+// processRounds processes the rounds the indexer has imported since the
+// last poll. Algorand blocks are final once produced, so no confirmation
+// depth is applied.
+func (l *Listener) processRounds(ctx context.Context) error {
+	indexerRound, err := l.client.GetIndexerRound(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get indexer round: %w", err)
+	}
+
+	monitoring.UpdateChainBlockNumber(l.config.Name, indexerRound)
+	monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(l.lastRound))
+
+	for l.lastRound <= indexerRound {
+		toRound := l.lastRound + roundBatchSize - 1
+		if toRound > indexerRound {
+			toRound = indexerRound
+		}
+
+		if err := l.processRoundRange(ctx, l.lastRound, toRound); err != nil {
+			return fmt.Errorf("failed to process rounds %d-%d: %w", l.lastRound, toRound, err)
+		}
+
+		monitoring.ListenerBlocksProcessed.WithLabelValues(l.config.Name).Add(float64(toRound - l.lastRound + 1))
+		l.lastRound = toRound + 1
+	}
+
+	return nil
+}
+
+// ProcessRange processes the rounds between fromRound and toRound,
+// inclusive, without moving the listener's cursor. It is used to backfill
+// past rounds.
+func (l *Listener) ProcessRange(ctx context.Context, fromRound, toRound uint64) error {
+	return l.processRoundRange(ctx, fromRound, toRound)
+}
+
+// processRoundRange processes the bridge application's transactions
+// between two rounds, inclusive
+func (l *Listener) processRoundRange(ctx context.Context, fromRound, toRound uint64) error {
+	txns, err := l.client.SearchApplicationTransactions(ctx, l.appID, fromRound, toRound)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range l.transactionEvents(txns) {
+		if err := l.processEvent(event); err != nil {
+			l.logger.Error().
+				Err(err).
+				Str("tx_id", event.TxID).
+				Msg("Error processing event")
+		}
+	}
+
+	l.logger.Debug().
+		Uint64("from", fromRound).
+		Uint64("to", toRound).
+		Int("transactions", len(txns)).
+		Msg("Round range processed")
+
+	return nil
+}
+
+// transactionEvents returns the bridge events logged in transactions
+func (l *Listener) transactionEvents(txns []algorand.Transaction) []*TokenLockedEvent {
+	var events []*TokenLockedEvent
+	for i := range txns {
+		txn := &txns[i]
+
+		var logIndex uint64
+		for _, log := range applicationLogs(txn, l.appID) {
+			event, ok, err := decodeTokenLockedEvent(log)
+			if !ok {
+				continue
+			}
+			if err != nil {
+				l.logger.Error().
+					Err(err).
+					Str("tx_id", txn.ID).
+					Msg("Failed to decode TokenLocked event")
+				continue
+			}
+
+			event.TxID = txn.ID
+			event.Round = txn.ConfirmedRound
+			event.LogIndex = logIndex
+			logIndex++
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// processEvent turns a bridge event into a message and queues it
+func (l *Listener) processEvent(event *TokenLockedEvent) error {
+	msg, err := l.parseTokenLockedEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to parse event: %w", err)
+	}
+
+	msg.SourceTxHash = event.TxID
+	msg.SourceBlock = event.Round
+	msg.SourceLogIndex = event.LogIndex
+
+	select {
+	case l.eventChan <- msg:
+		l.logger.Info().
+			Str("message_id", msg.ID).
+			Str("type", string(msg.Type)).
+			Msg("Message detected and queued")
+
+		monitoring.ListenerEventsDetected.WithLabelValues(l.config.Name, string(msg.Type)).Inc()
+	default:
+		l.logger.Warn().Msg("Event channel full, message dropped")
+	}
+
+	return nil
+}
+
+// parseTokenLockedEvent parses a token locked event
+func (l *Listener) parseTokenLockedEvent(event *TokenLockedEvent) (*types.CrossChainMessage, error) {
+	if event.DestinationChain == "" {
+		return nil, fmt.Errorf("missing destination_chain")
+	}
+	if event.Amount == 0 {
+		return nil, fmt.Errorf("invalid amount: 0")
+	}
+
+	// ASAs are identified by asset ID rather than an address
+	payload := types.TokenTransferPayload{
+		TokenAddress: types.Address{
+			Raw:       strconv.FormatUint(event.AssetID, 10),
+			ChainType: types.ChainTypeAlgorand,
+		},
+		Amount:        strconv.FormatUint(event.Amount, 10),
+		TokenStandard: "ASA",
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	senderAddr, err := types.NewAddress(algorand.EncodeAddress(event.Sender), types.ChainTypeAlgorand)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	recipientAddr, err := types.NewDestinationAddress(event.DestinationAddress, event.DestinationChain, l.chains)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	messageID := hex.EncodeToString(event.MessageID[:])
+	msg := &types.CrossChainMessage{
+		ID:   messageID,
+		Type: types.MessageTypeTokenTransfer,
+		SourceChain: types.ChainInfo{
+			Name:    l.config.Name,
+			Type:    types.ChainTypeAlgorand,
+			ChainID: l.config.NetworkID,
+		},
+		DestinationChain: types.ChainInfo{
+			Name: event.DestinationChain,
+		},
+		Sender:    senderAddr,
+		Recipient: recipientAddr,
+		Payload:   payloadBytes,
+		Nonce:     event.Nonce,
+		Status:    types.MessageStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	l.logger.Info().
+		Str("message_id", messageID).
+		Str("sender", senderAddr.Raw).
+		Str("recipient", event.DestinationAddress).
+		Uint64("asset_id", event.AssetID).
+		Uint64("amount", event.Amount).
+		Str("dest_chain", event.DestinationChain).
+		Msg("Parsed token locked event")
+
+	return msg, nil
+}
@@ -0,0 +1,144 @@
+package algorand
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/algorand"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+const testAppID = 724617463
+
+// encodeTokenLocked encodes a TokenLocked event as the bridge application
+// logs it
+func encodeTokenLocked(event *TokenLockedEvent) []byte {
+	head := make([]byte, 0, tokenLockedHeadSize)
+	head = append(head, event.MessageID[:]...)
+	head = append(head, event.Sender[:]...)
+	head = binary.BigEndian.AppendUint64(head, event.AssetID)
+	head = binary.BigEndian.AppendUint64(head, event.Amount)
+	head = binary.BigEndian.AppendUint16(head, tokenLockedHeadSize)
+	head = binary.BigEndian.AppendUint16(head, uint16(tokenLockedHeadSize+2+len(event.DestinationChain)))
+	head = binary.BigEndian.AppendUint64(head, event.Nonce)
+	head = binary.BigEndian.AppendUint64(head, event.Timestamp)
+
+	log := append(tokenLockedSelector[:], head...)
+	log = append(log, algorand.BytesArg([]byte(event.DestinationChain))...)
+	log = append(log, algorand.BytesArg([]byte(event.DestinationAddress))...)
+	return log
+}
+
+func TestProcessRoundsQueuesApplicationEvents(t *testing.T) {
+	locked := &TokenLockedEvent{
+		MessageID:          [32]byte{0xab, 0xcd},
+		Sender:             [32]byte{0x01},
+		AssetID:            10458941,
+		Amount:             2500000,
+		DestinationChain:   "ethereum-sepolia",
+		DestinationAddress: "0x9f8f72aA9304c8B593d555F12eF6589cC3A579A2",
+		Nonce:              7,
+		Timestamp:          1760000000,
+	}
+	// Logged by another application in the same group
+	foreign := *locked
+	foreign.Nonce = 8
+
+	txns := []algorand.Transaction{
+		{
+			ID:                     "TXLOCK",
+			TxType:                 "appl",
+			ConfirmedRound:         1205,
+			ApplicationTransaction: &algorand.ApplicationTransaction{ApplicationID: 1},
+			Logs:                   [][]byte{encodeTokenLocked(&foreign)},
+			InnerTxns: []algorand.Transaction{
+				{
+					TxType:                 "appl",
+					ApplicationTransaction: &algorand.ApplicationTransaction{ApplicationID: testAppID},
+					Logs:                   [][]byte{[]byte("lock"), encodeTokenLocked(locked)},
+				},
+			},
+		},
+	}
+
+	var searched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			json.NewEncoder(w).Encode(map[string]uint64{"round": 1210})
+		case "/v2/transactions":
+			query := r.URL.Query()
+			searched = append(searched, query.Get("application-id")+":"+query.Get("min-round")+"-"+query.Get("max-round"))
+			json.NewEncoder(w).Encode(map[string]interface{}{"transactions": txns})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &types.ChainConfig{
+		Name:            "algorand-testnet",
+		ChainType:       types.ChainTypeAlgorand,
+		NetworkID:       "testnet",
+		RPCEndpoints:    []string{server.URL},
+		IndexerEndpoint: server.URL,
+		BridgeContract:  "724617463",
+		StartBlock:      1200,
+	}
+
+	client, err := algorand.NewClient(cfg, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destinations := []types.ChainConfig{{Name: "ethereum-sepolia", ChainType: types.ChainTypeEVM}}
+	listener, err := NewListener(client, cfg, destinations, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewListener: %v", err)
+	}
+
+	if err := listener.processRounds(context.Background()); err != nil {
+		t.Fatalf("processRounds: %v", err)
+	}
+
+	if len(searched) != 1 || searched[0] != "724617463:1200-1210" {
+		t.Errorf("searched %v, want [724617463:1200-1210]", searched)
+	}
+	if listener.lastRound != 1211 {
+		t.Errorf("lastRound = %d, want 1211", listener.lastRound)
+	}
+
+	var msgs []*types.CrossChainMessage
+	for len(listener.eventChan) > 0 {
+		msgs = append(msgs, <-listener.eventChan)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("queued %d messages, want 1", len(msgs))
+	}
+
+	msg := msgs[0]
+	if msg.ID != "abcd000000000000000000000000000000000000000000000000000000000000" {
+		t.Errorf("ID = %s", msg.ID)
+	}
+	if msg.Nonce != 7 || msg.SourceTxHash != "TXLOCK" || msg.SourceBlock != 1205 || msg.SourceLogIndex != 0 {
+		t.Errorf("source = nonce %d tx %s round %d log %d", msg.Nonce, msg.SourceTxHash, msg.SourceBlock, msg.SourceLogIndex)
+	}
+	if msg.DestinationChain.Name != "ethereum-sepolia" || msg.Recipient.ChainType != types.ChainTypeEVM {
+		t.Errorf("destination = %s %s", msg.DestinationChain.Name, msg.Recipient.ChainType)
+	}
+	if msg.Sender.Raw != algorand.EncodeAddress(locked.Sender) {
+		t.Errorf("sender = %s", msg.Sender.Raw)
+	}
+
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.TokenAddress.Raw != "10458941" || payload.Amount != "2500000" || payload.TokenStandard != "ASA" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
@@ -0,0 +1,331 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/aptos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// eventPageSize is the number of events requested per page
+const eventPageSize = 100
+
+// The bridge module stores a BridgeEvents resource under its address whose
+// token_locked_events handle receives a TokenLockedEvent per lock
+const (
+	bridgeModule       = "bridge"
+	eventsResource     = "BridgeEvents"
+	tokenLockedHandle  = "token_locked_events"
+	tokenLockedEventID = "TokenLockedEvent"
+)
+
+// TokenLockedEvent is the data of the bridge module's TokenLockedEvent.
+// Token is a coin type, e.g. 0x1::aptos_coin::AptosCoin, or the metadata
+// address of a fungible asset.
+type TokenLockedEvent struct {
+	MessageID          string       `json:"message_id"`
+	Sender             string       `json:"sender"`
+	Token              string       `json:"token"`
+	Amount             aptos.Uint64 `json:"amount"`
+	DestinationChain   string       `json:"destination_chain"`
+	DestinationAddress string       `json:"destination_address"`
+	Nonce              aptos.Uint64 `json:"nonce"`
+	Timestamp          aptos.Uint64 `json:"timestamp"`
+}
+
+// Listener listens for events on Aptos blockchain
+type Listener struct {
+	client       *aptos.Client
+	config       *types.ChainConfig
+	chains       []types.ChainConfig
+	logger       zerolog.Logger
+	eventChan    chan *types.CrossChainMessage
+	stopChan     chan struct{}
+	bridge       string
+	nextSequence uint64
+}
+
+// NewListener creates a new Aptos event listener
+func NewListener(
+	client *aptos.Client,
+	config *types.ChainConfig,
+	chains []types.ChainConfig,
+	logger zerolog.Logger,
+) (*Listener, error) {
+	bridge, err := aptos.ParseAddress(config.BridgeContract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bridge module address: %w", err)
+	}
+
+	return &Listener{
+		client:    client,
+		config:    config,
+		chains:    chains,
+		logger:    logger.With().Str("chain", config.Name).Str("component", "listener").Logger(),
+		eventChan: make(chan *types.CrossChainMessage, 100),
+		stopChan:  make(chan struct{}),
+		bridge:    bridge.String(),
+	}, nil
+}
+
+// Start starts the listener
+func (l *Listener) Start(ctx context.Context) error {
+	l.logger.Info().
+		Uint64("start_version", l.config.StartBlock).
+		Str("module", l.bridge).
+		Msg("Starting Aptos listener")
+
+	go l.listen(ctx)
+
+	return nil
+}
+
+// Stop stops the listener
+func (l *Listener) Stop() error {
+	l.logger.Info().Msg("Stopping Aptos listener")
+	close(l.stopChan)
+	close(l.eventChan)
+	return nil
+}
+
+// EventChan returns the channel for receiving events
+func (l *Listener) EventChan() <-chan *types.CrossChainMessage {
+	return l.eventChan
+}
+
+// listen is the main listening loop
+func (l *Listener) listen(ctx context.Context) {
+	ticker := time.NewTicker(l.config.GetPollIntervalDuration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.logger.Info().Msg("Context cancelled, stopping listener")
+			return
+		case <-l.stopChan:
+			l.logger.Info().Msg("Stop signal received")
+			return
+		case <-ticker.C:
+			if err := l.processEvents(ctx); err != nil {
+				l.logger.Error().Err(err).Msg("Error processing events")
+			}
+		}
+	}
+}
+
+// processEvents processes the lock events emitted since the last poll. The
+// handle's sequence numbers are the cursor; events committed before
+// StartBlock, a ledger version, are skipped. Committed transactions are
+// final, so no confirmation depth is applied.
+func (l *Listener) processEvents(ctx context.Context) error {
+	handleStruct := fmt.Sprintf("%s::%s::%s", l.bridge, bridgeModule, eventsResource)
+
+	for {
+		events, err := l.client.GetEventsByHandle(ctx, l.bridge, handleStruct, tokenLockedHandle, l.nextSequence, eventPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if uint64(event.Version) < l.config.StartBlock {
+				l.nextSequence = uint64(event.SequenceNumber) + 1
+				continue
+			}
+			if err := l.processEvent(ctx, &event); err != nil {
+				return fmt.Errorf("failed to process event %d: %w", event.SequenceNumber, err)
+			}
+			l.nextSequence = uint64(event.SequenceNumber) + 1
+			monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(event.Version))
+		}
+
+		if len(events) < eventPageSize {
+			return nil
+		}
+	}
+}
+
+// ProcessRange processes the lock events committed in the ledger versions
+// between fromVersion and toVersion, inclusive, without moving the
+// listener's cursor. It is used to backfill past versions. The handle is
+// read from its first event, since sequence numbers do not map to versions.
+func (l *Listener) ProcessRange(ctx context.Context, fromVersion, toVersion uint64) error {
+	handleStruct := fmt.Sprintf("%s::%s::%s", l.bridge, bridgeModule, eventsResource)
+
+	for start := uint64(0); ; start += eventPageSize {
+		events, err := l.client.GetEventsByHandle(ctx, l.bridge, handleStruct, tokenLockedHandle, start, eventPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			version := uint64(event.Version)
+			if version < fromVersion {
+				continue
+			}
+			if version > toVersion {
+				return nil
+			}
+			if err := l.processEvent(ctx, &event); err != nil {
+				return fmt.Errorf("failed to process event %d: %w", event.SequenceNumber, err)
+			}
+		}
+
+		if len(events) < eventPageSize {
+			return nil
+		}
+	}
+}
+
+// processEvent queues the message of a lock event. Only failures to reach
+// the node are returned, so that the event is retried; malformed events
+// are logged and skipped.
+func (l *Listener) processEvent(ctx context.Context, event *aptos.Event) error {
+	expectedType := fmt.Sprintf("%s::%s::%s", l.bridge, bridgeModule, tokenLockedEventID)
+	if !sameEventType(event.Type, expectedType) {
+		l.logger.Warn().Str("type", event.Type).Msg("Unexpected event type on lock handle")
+		return nil
+	}
+
+	// Events read by handle do not carry their transaction's hash
+	txn, err := l.client.GetTransactionByVersion(ctx, uint64(event.Version))
+	if err != nil {
+		return err
+	}
+
+	var data TokenLockedEvent
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		l.logger.Error().Err(err).Uint64("sequence", uint64(event.SequenceNumber)).Msg("Failed to decode TokenLockedEvent")
+		return nil
+	}
+
+	msg, err := l.parseTokenLockedEvent(&data)
+	if err != nil {
+		l.logger.Error().
+			Err(err).
+			Str("tx_hash", txn.Hash).
+			Msg("Error processing event")
+		return nil
+	}
+
+	msg.SourceTxHash = txn.Hash
+	msg.SourceBlock = uint64(event.Version)
+	// Sequence numbers are unique within the handle
+	msg.SourceLogIndex = uint64(event.SequenceNumber)
+
+	select {
+	case l.eventChan <- msg:
+		l.logger.Info().
+			Str("message_id", msg.ID).
+			Str("type", string(msg.Type)).
+			Msg("Message detected and queued")
+
+		monitoring.ListenerEventsDetected.WithLabelValues(l.config.Name, string(msg.Type)).Inc()
+	default:
+		l.logger.Warn().Msg("Event channel full, message dropped")
+	}
+
+	return nil
+}
+
+// sameEventType compares Move type names, whose address the API may
+// abbreviate by dropping leading zeros
+func sameEventType(a, b string) bool {
+	normalize := func(t string) string {
+		addr, rest, ok := strings.Cut(t, "::")
+		if !ok {
+			return t
+		}
+		parsed, err := aptos.ParseAddress(addr)
+		if err != nil {
+			return t
+		}
+		return parsed.String() + "::" + rest
+	}
+	return normalize(a) == normalize(b)
+}
+
+// parseTokenLockedEvent parses a token locked event
+func (l *Listener) parseTokenLockedEvent(event *TokenLockedEvent) (*types.CrossChainMessage, error) {
+	if event.MessageID == "" {
+		return nil, fmt.Errorf("missing message_id")
+	}
+	if event.DestinationChain == "" {
+		return nil, fmt.Errorf("missing destination_chain")
+	}
+	if event.Amount == 0 {
+		return nil, fmt.Errorf("invalid amount: 0")
+	}
+
+	// Coins are identified by their type rather than an address
+	tokenAddr := types.Address{Raw: event.Token, ChainType: types.ChainTypeAptos}
+	standard := "COIN"
+	if !strings.Contains(event.Token, "::") {
+		addr, err := types.NewAddress(event.Token, types.ChainTypeAptos)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token: %w", err)
+		}
+		tokenAddr = addr
+		standard = "FA"
+	}
+
+	payload := types.TokenTransferPayload{
+		TokenAddress:  tokenAddr,
+		Amount:        new(big.Int).SetUint64(uint64(event.Amount)).String(),
+		TokenStandard: standard,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	senderAddr, err := types.NewAddress(event.Sender, types.ChainTypeAptos)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	recipientAddr, err := types.NewDestinationAddress(event.DestinationAddress, event.DestinationChain, l.chains)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	messageID := strings.TrimPrefix(event.MessageID, "0x")
+	msg := &types.CrossChainMessage{
+		ID:   messageID,
+		Type: types.MessageTypeTokenTransfer,
+		SourceChain: types.ChainInfo{
+			Name:    l.config.Name,
+			Type:    types.ChainTypeAptos,
+			ChainID: l.config.NetworkID,
+		},
+		DestinationChain: types.ChainInfo{
+			Name: event.DestinationChain,
+		},
+		Sender:    senderAddr,
+		Recipient: recipientAddr,
+		Payload:   payloadBytes,
+		Nonce:     uint64(event.Nonce),
+		Status:    types.MessageStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	l.logger.Info().
+		Str("message_id", messageID).
+		Str("sender", event.Sender).
+		Str("recipient", event.DestinationAddress).
+		Str("token", event.Token).
+		Uint64("amount", uint64(event.Amount)).
+		Str("dest_chain", event.DestinationChain).
+		Msg("Parsed token locked event")
+
+	return msg, nil
+}
@@ -0,0 +1,132 @@
+package aptos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/aptos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+const testBridge = "0x6c3b1e5d9f2a4b7c8d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e"
+
+// replayREST serves Aptos REST responses captured in testdata, named after
+// the event handle's start sequence number or the transaction's version
+func replayREST(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requested []string
+
+	eventsPath := "/v1/accounts/" + testBridge + "/events/" + testBridge + "::bridge::BridgeEvents/token_locked_events"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		switch {
+		case r.URL.Path == eventsPath:
+			name = "events_" + r.URL.Query().Get("start") + ".json"
+		case strings.HasPrefix(r.URL.Path, "/v1/transactions/by_version/"):
+			name = "txn_" + strings.TrimPrefix(r.URL.Path, "/v1/transactions/by_version/") + ".json"
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		mu.Lock()
+		requested = append(requested, name)
+		mu.Unlock()
+
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Errorf("no captured response %s", name)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func TestProcessEventsReplay(t *testing.T) {
+	server, requested := replayREST(t)
+
+	config := &types.ChainConfig{
+		Name:           "aptos-testnet",
+		ChainType:      types.ChainTypeAptos,
+		NetworkID:      "testnet",
+		RPCEndpoints:   []string{server.URL + "/v1"},
+		BridgeContract: testBridge,
+		StartBlock:     2000000,
+	}
+	client, err := aptos.NewClient(config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	destinations := []types.ChainConfig{{Name: "ethereum-sepolia", ChainType: types.ChainTypeEVM}}
+	listener, err := NewListener(client, config, destinations, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewListener: %v", err)
+	}
+
+	if err := listener.processEvents(context.Background()); err != nil {
+		t.Fatalf("processEvents: %v", err)
+	}
+
+	// The event before StartBlock is skipped without fetching its
+	// transaction; the malformed one is fetched, then dropped
+	want := []string{"events_0.json", "txn_2054311.json", "txn_2054390.json"}
+	if got := requested(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("requested %v, want %v", got, want)
+	}
+	if listener.nextSequence != 3 {
+		t.Errorf("nextSequence = %d, want 3", listener.nextSequence)
+	}
+
+	if len(listener.eventChan) != 1 {
+		t.Fatalf("queued %d messages, want 1", len(listener.eventChan))
+	}
+	msg := <-listener.eventChan
+
+	if msg.ID != "5de1a0c8b97f4e3d2c1b0a99887766554433221100ffeeddccbbaa9988776655" {
+		t.Errorf("ID = %s", msg.ID)
+	}
+	if msg.SourceTxHash != "0x3f1b9c2e7a6d5840b1c9e2f3a4d5b6c7e8f90123456789abcdef0123456789ab" ||
+		msg.SourceBlock != 2054311 || msg.SourceLogIndex != 1 || msg.Nonce != 1 {
+		t.Errorf("source = tx %s version %d index %d nonce %d",
+			msg.SourceTxHash, msg.SourceBlock, msg.SourceLogIndex, msg.Nonce)
+	}
+	if msg.Sender.Format != types.AddressFormatHex32 || msg.Recipient.ChainType != types.ChainTypeEVM {
+		t.Errorf("sender format %s, recipient chain %s", msg.Sender.Format, msg.Recipient.ChainType)
+	}
+
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.TokenAddress.Raw != "0x1::aptos_coin::AptosCoin" || payload.Amount != "250000000" || payload.TokenStandard != "COIN" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// The next poll resumes after the last processed event
+	if err := listener.processEvents(context.Background()); err != nil {
+		t.Fatalf("processEvents: %v", err)
+	}
+	if got := requested(); got[len(got)-1] != "events_3.json" {
+		t.Errorf("resumed with %s, want events_3.json", got[len(got)-1])
+	}
+}
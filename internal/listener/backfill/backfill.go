@@ -0,0 +1,190 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// Source is a listener that can process a bounded range of its chain.
+// Ranges are in the unit the listener's cursor uses: blocks, slots, rounds
+// or ledger versions.
+type Source interface {
+	ProcessRange(ctx context.Context, from, to uint64) error
+	EventChan() <-chan *types.CrossChainMessage
+	Stop() error
+}
+
+// Store records the messages a backfill finds missing
+type Store interface {
+	GetSourceEventMessageID(ctx context.Context, sourceChain, txHash string, logIndex uint64) (string, error)
+	MessageExists(ctx context.Context, messageID string) (bool, error)
+	InsertMessageWithOutbox(ctx context.Context, msg *types.CrossChainMessage) (bool, error)
+}
+
+// Config configures a backfill
+type Config struct {
+	Chain string
+	From  uint64
+	To    uint64
+
+	// BatchSize is the number of blocks processed per batch
+	BatchSize uint64
+
+	// Rate is the number of batches processed per second; 0 is unlimited
+	Rate float64
+
+	// DryRun reports missing events without recording them
+	DryRun bool
+}
+
+// Report summarizes a backfill
+type Report struct {
+	Events   int
+	Recorded int
+	Missing  []*types.CrossChainMessage
+}
+
+// Run reprocesses a range of a chain with a listener that is not running
+// and records the lock events missing from the database. Events are
+// deduplicated against recorded messages by source tx hash and log index,
+// and by message ID for messages recorded before source events were
+// tracked. Recorded messages are never modified. The listener is stopped
+// when Run returns.
+func Run(ctx context.Context, source Source, store Store, config Config, logger zerolog.Logger) (*Report, error) {
+	if config.To < config.From {
+		return nil, fmt.Errorf("invalid range: %d-%d", config.From, config.To)
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 1
+	}
+
+	logger = logger.With().Str("chain", config.Chain).Str("component", "backfill").Logger()
+
+	// Events are drained while the range is processed, since listeners drop
+	// events when their channel is full
+	report := &Report{}
+	done := make(chan error, 1)
+	go func() {
+		done <- record(ctx, source.EventChan(), store, config, report, logger)
+	}()
+
+	err := processRange(ctx, source, config, logger)
+	source.Stop()
+	if recordErr := <-done; err == nil {
+		err = recordErr
+	}
+
+	logger.Info().
+		Int("events", report.Events).
+		Int("missing", len(report.Missing)).
+		Int("recorded", report.Recorded).
+		Bool("dry_run", config.DryRun).
+		Msg("Backfill finished")
+
+	return report, err
+}
+
+// processRange processes the range in batches, at most Rate batches per
+// second
+func processRange(ctx context.Context, source Source, config Config, logger zerolog.Logger) error {
+	var ticker *time.Ticker
+	if config.Rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / config.Rate))
+		defer ticker.Stop()
+	}
+
+	for from := config.From; from <= config.To; from += config.BatchSize {
+		to := from + config.BatchSize - 1
+		if to > config.To || to < from {
+			to = config.To
+		}
+
+		if err := source.ProcessRange(ctx, from, to); err != nil {
+			return fmt.Errorf("failed to process %d-%d: %w", from, to, err)
+		}
+
+		logger.Debug().
+			Uint64("from", from).
+			Uint64("to", to).
+			Msg("Batch processed")
+
+		if to == config.To {
+			break
+		}
+		if ticker != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	}
+
+	return nil
+}
+
+// record records the events missing from the store until the event channel
+// is closed
+func record(ctx context.Context, events <-chan *types.CrossChainMessage, store Store, config Config, report *Report, logger zerolog.Logger) error {
+	var firstErr error
+	for msg := range events {
+		report.Events++
+		if firstErr != nil {
+			continue
+		}
+
+		messageID, err := store.GetSourceEventMessageID(ctx, msg.SourceChain.Name, msg.SourceTxHash, msg.SourceLogIndex)
+		if err != nil {
+			firstErr = err
+			continue
+		}
+		if messageID != "" {
+			continue
+		}
+
+		// Messages recorded before source events were tracked have no
+		// source event row
+		exists, err := store.MessageExists(ctx, msg.ID)
+		if err != nil {
+			firstErr = err
+			continue
+		}
+		if exists {
+			continue
+		}
+
+		report.Missing = append(report.Missing, msg)
+		logger.Warn().
+			Str("message_id", msg.ID).
+			Str("tx_hash", msg.SourceTxHash).
+			Uint64("log_index", msg.SourceLogIndex).
+			Uint64("block", msg.SourceBlock).
+			Msg("Lock event missing from database")
+
+		if config.DryRun {
+			continue
+		}
+
+		// The running listener may have recorded the event since it was
+		// looked up
+		inserted, err := store.InsertMessageWithOutbox(ctx, msg)
+		if errors.Is(err, database.ErrDuplicateSourceEvent) {
+			continue
+		}
+		if err != nil {
+			firstErr = fmt.Errorf("failed to record message %s: %w", msg.ID, err)
+			continue
+		}
+		if inserted {
+			report.Recorded++
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,170 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// fakeSource emits one lock event per block
+type fakeSource struct {
+	events  chan *types.CrossChainMessage
+	batches [][2]uint64
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{events: make(chan *types.CrossChainMessage, 100)}
+}
+
+func (s *fakeSource) ProcessRange(ctx context.Context, from, to uint64) error {
+	s.batches = append(s.batches, [2]uint64{from, to})
+	for block := from; block <= to; block++ {
+		s.events <- &types.CrossChainMessage{
+			ID:           fmt.Sprintf("msg-%d", block),
+			SourceChain:  types.ChainInfo{Name: "ethereum"},
+			SourceTxHash: fmt.Sprintf("0x%d", block),
+			SourceBlock:  block,
+		}
+	}
+	return nil
+}
+
+func (s *fakeSource) EventChan() <-chan *types.CrossChainMessage {
+	return s.events
+}
+
+func (s *fakeSource) Stop() error {
+	close(s.events)
+	return nil
+}
+
+// fakeStore records messages by source tx hash and log index. legacy holds
+// message IDs recorded without a source event.
+type fakeStore struct {
+	messages map[string]string
+	legacy   map[string]bool
+	outbox   []string
+}
+
+func sourceKey(txHash string, logIndex uint64) string {
+	return fmt.Sprintf("%s:%d", txHash, logIndex)
+}
+
+func (s *fakeStore) GetSourceEventMessageID(ctx context.Context, sourceChain, txHash string, logIndex uint64) (string, error) {
+	return s.messages[sourceKey(txHash, logIndex)], nil
+}
+
+func (s *fakeStore) MessageExists(ctx context.Context, messageID string) (bool, error) {
+	if s.legacy[messageID] {
+		return true, nil
+	}
+	for _, id := range s.messages {
+		if id == messageID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *fakeStore) InsertMessageWithOutbox(ctx context.Context, msg *types.CrossChainMessage) (bool, error) {
+	if exists, _ := s.MessageExists(ctx, msg.ID); exists {
+		return false, nil
+	}
+	key := sourceKey(msg.SourceTxHash, msg.SourceLogIndex)
+	if _, ok := s.messages[key]; ok {
+		return false, database.ErrDuplicateSourceEvent
+	}
+	s.messages[key] = msg.ID
+	s.outbox = append(s.outbox, msg.ID)
+	return true, nil
+}
+
+func TestRunRecordsMissingEvents(t *testing.T) {
+	source := newFakeSource()
+	store := &fakeStore{messages: map[string]string{
+		sourceKey("0x11", 0): "msg-11",
+		sourceKey("0x13", 0): "msg-13",
+	}}
+
+	report, err := Run(context.Background(), source, store, Config{
+		Chain:     "ethereum",
+		From:      10,
+		To:        14,
+		BatchSize: 2,
+	}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if got := fmt.Sprint(source.batches); got != "[[10 11] [12 13] [14 14]]" {
+		t.Errorf("expected batches [[10 11] [12 13] [14 14]], got %s", got)
+	}
+	if report.Events != 5 {
+		t.Errorf("expected 5 events, got %d", report.Events)
+	}
+	if len(report.Missing) != 3 || report.Recorded != 3 {
+		t.Fatalf("expected 3 missing and recorded events, got %d and %d", len(report.Missing), report.Recorded)
+	}
+	for i, block := range []uint64{10, 12, 14} {
+		if report.Missing[i].SourceBlock != block {
+			t.Errorf("expected missing event %d at block %d, got %d", i, block, report.Missing[i].SourceBlock)
+		}
+	}
+	if len(store.messages) != 5 {
+		t.Errorf("expected 5 stored messages, got %d", len(store.messages))
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	source := newFakeSource()
+	store := &fakeStore{messages: map[string]string{}}
+
+	report, err := Run(context.Background(), source, store, Config{
+		Chain:     "ethereum",
+		From:      1,
+		To:        3,
+		BatchSize: 10,
+		DryRun:    true,
+	}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Missing) != 3 || report.Recorded != 0 {
+		t.Errorf("expected 3 missing and no recorded events, got %d and %d", len(report.Missing), report.Recorded)
+	}
+	if len(store.messages) != 0 {
+		t.Errorf("expected no stored messages, got %d", len(store.messages))
+	}
+}
+
+func TestRunSkipsMessagesWithoutSourceEvent(t *testing.T) {
+	source := newFakeSource()
+	store := &fakeStore{
+		messages: map[string]string{},
+		legacy:   map[string]bool{"msg-2": true},
+	}
+
+	report, err := Run(context.Background(), source, store, Config{
+		Chain:     "ethereum",
+		From:      1,
+		To:        3,
+		BatchSize: 10,
+	}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// msg-2 was recorded before source events were tracked; it must not be
+	// reported missing or published again
+	if len(report.Missing) != 2 || report.Recorded != 2 {
+		t.Fatalf("expected 2 missing and recorded events, got %d and %d", len(report.Missing), report.Recorded)
+	}
+	if got := fmt.Sprint(store.outbox); got != "[msg-1 msg-3]" {
+		t.Errorf("expected outbox entries [msg-1 msg-3], got %s", got)
+	}
+}
@@ -0,0 +1,368 @@
+package bitcoin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/bitcoin"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// blockBatchSize is the number of blocks processed between cursor updates
+const blockBatchSize = 10
+
+// UTXOStore records the custody's outputs for releases to spend
+type UTXOStore interface {
+	SaveBitcoinUTXO(ctx context.Context, utxo *database.BitcoinUTXO) error
+	MarkBitcoinUTXOSpent(ctx context.Context, chain, txid string, vout uint32, spentTxID string) error
+}
+
+// Listener listens for deposits to the bridge's custody address. A deposit
+// is a transaction paying the custody with an OP_RETURN memo
+// "destination_chain:destination_address".
+type Listener struct {
+	client    *bitcoin.Client
+	config    *types.ChainConfig
+	chains    []types.ChainConfig
+	store     UTXOStore
+	logger    zerolog.Logger
+	eventChan chan *types.CrossChainMessage
+	stopChan  chan struct{}
+	lastBlock uint64
+	custody   string
+}
+
+// NewListener creates a new Bitcoin deposit listener
+func NewListener(
+	client *bitcoin.Client,
+	config *types.ChainConfig,
+	chains []types.ChainConfig,
+	store UTXOStore,
+	logger zerolog.Logger,
+) (*Listener, error) {
+	if _, err := types.NewAddress(config.BridgeContract, types.ChainTypeBitcoin); err != nil {
+		return nil, fmt.Errorf("invalid custody address: %w", err)
+	}
+
+	return &Listener{
+		client:    client,
+		config:    config,
+		chains:    chains,
+		store:     store,
+		logger:    logger.With().Str("chain", config.Name).Str("component", "listener").Logger(),
+		eventChan: make(chan *types.CrossChainMessage, 100),
+		stopChan:  make(chan struct{}),
+		lastBlock: config.StartBlock,
+		custody:   config.BridgeContract,
+	}, nil
+}
+
+// Start starts the listener
+func (l *Listener) Start(ctx context.Context) error {
+	l.logger.Info().
+		Uint64("start_block", l.lastBlock).
+		Str("custody", l.custody).
+		Msg("Starting Bitcoin listener")
+
+	go l.listen(ctx)
+
+	return nil
+}
+
+// Stop stops the listener
+func (l *Listener) Stop() error {
+	l.logger.Info().Msg("Stopping Bitcoin listener")
+	close(l.stopChan)
+	close(l.eventChan)
+	return nil
+}
+
+// EventChan returns the channel for receiving events
+func (l *Listener) EventChan() <-chan *types.CrossChainMessage {
+	return l.eventChan
+}
+
+// listen is the main listening loop
+func (l *Listener) listen(ctx context.Context) {
+	ticker := time.NewTicker(l.config.GetPollIntervalDuration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.logger.Info().Msg("Context cancelled, stopping listener")
+			return
+		case <-l.stopChan:
+			l.logger.Info().Msg("Stop signal received")
+			return
+		case <-ticker.C:
+			if err := l.processBlocks(ctx); err != nil {
+				l.logger.Error().Err(err).Msg("Error processing blocks")
+			}
+		}
+	}
+}
+
+// processBlocks processes the blocks with ConfirmationBlocks confirmations.
+// The tip has one confirmation.
+func (l *Listener) processBlocks(ctx context.Context) error {
+	latestBlock, err := l.client.GetBlockCount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	monitoring.UpdateChainBlockNumber(l.config.Name, latestBlock)
+
+	confirmations := l.config.ConfirmationBlocks
+	if confirmations == 0 {
+		confirmations = 1
+	}
+	if latestBlock+1 < confirmations {
+		return nil
+	}
+	safeBlock := latestBlock + 1 - confirmations
+	if l.lastBlock > safeBlock {
+		return nil
+	}
+
+	for fromBlock := l.lastBlock; fromBlock <= safeBlock; fromBlock = l.lastBlock {
+		toBlock := fromBlock + blockBatchSize - 1
+		if toBlock > safeBlock {
+			toBlock = safeBlock
+		}
+
+		if err := l.processBlockRange(ctx, fromBlock, toBlock); err != nil {
+			return err
+		}
+
+		monitoring.ListenerBlocksProcessed.WithLabelValues(l.config.Name).Add(float64(toBlock - fromBlock + 1))
+		monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(toBlock))
+	}
+
+	return nil
+}
+
+// ProcessRange processes the blocks between fromBlock and toBlock,
+// inclusive, without moving the listener's cursor. It is used to backfill
+// past blocks.
+func (l *Listener) ProcessRange(ctx context.Context, fromBlock, toBlock uint64) error {
+	lastBlock := l.lastBlock
+	defer func() { l.lastBlock = lastBlock }()
+
+	return l.processBlockRange(ctx, fromBlock, toBlock)
+}
+
+// processBlockRange processes a range of blocks. It stops at the first
+// block that fails so the block is retried on the next poll.
+func (l *Listener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64) error {
+	l.logger.Debug().
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("Processing block range")
+
+	for height := fromBlock; height <= toBlock; height++ {
+		if err := l.processBlock(ctx, height); err != nil {
+			return fmt.Errorf("failed to process block %d: %w", height, err)
+		}
+		l.lastBlock = height + 1
+	}
+
+	return nil
+}
+
+// processBlock updates the custody's UTXOs from a block and queues its
+// deposits. Transactions spending the custody are releases and their
+// change, not deposits.
+func (l *Listener) processBlock(ctx context.Context, height uint64) error {
+	block, err := l.client.GetBlockByHeight(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	for i := range block.Tx {
+		tx := &block.Tx[i]
+
+		spendsCustody := false
+		for _, in := range tx.Vin {
+			if in.Prevout == nil || in.Prevout.ScriptPubKey.Address != l.custody {
+				continue
+			}
+			spendsCustody = true
+			if err := l.store.MarkBitcoinUTXOSpent(ctx, l.config.Name, in.TxID, in.Vout, tx.TxID); err != nil {
+				return err
+			}
+		}
+
+		var deposits []*bitcoin.TxOutput
+		for j := range tx.Vout {
+			out := &tx.Vout[j]
+			if out.ScriptPubKey.Address != l.custody {
+				continue
+			}
+			if err := l.store.SaveBitcoinUTXO(ctx, &database.BitcoinUTXO{
+				Chain:        l.config.Name,
+				TxID:         tx.TxID,
+				Vout:         out.N,
+				AmountSats:   int64(out.Value),
+				ScriptPubKey: out.ScriptPubKey.Hex,
+				BlockHeight:  height,
+			}); err != nil {
+				return err
+			}
+			deposits = append(deposits, out)
+		}
+
+		if spendsCustody || len(deposits) == 0 {
+			continue
+		}
+
+		for _, out := range deposits {
+			msg, err := l.parseDeposit(tx, out)
+			if err != nil {
+				// The funds are in custody but cannot be bridged without a
+				// destination; they need manual handling
+				l.logger.Error().
+					Err(err).
+					Str("tx_hash", tx.TxID).
+					Uint32("vout", out.N).
+					Msg("Error processing deposit")
+				continue
+			}
+
+			msg.SourceBlock = height
+			l.queue(msg)
+		}
+	}
+
+	return nil
+}
+
+// queue sends a message to the event channel without blocking
+func (l *Listener) queue(msg *types.CrossChainMessage) {
+	select {
+	case l.eventChan <- msg:
+		l.logger.Info().
+			Str("message_id", msg.ID).
+			Str("type", string(msg.Type)).
+			Msg("Message detected and queued")
+
+		monitoring.ListenerEventsDetected.WithLabelValues(l.config.Name, string(msg.Type)).Inc()
+	default:
+		l.logger.Warn().Msg("Event channel full, message dropped")
+	}
+}
+
+// parseDeposit parses a deposit output of a transaction carrying a
+// destination memo
+func (l *Listener) parseDeposit(tx *bitcoin.Transaction, out *bitcoin.TxOutput) (*types.CrossChainMessage, error) {
+	memo, err := depositMemo(tx)
+	if err != nil {
+		return nil, err
+	}
+	destChain, destAddress, ok := strings.Cut(memo, ":")
+	if !ok || destChain == "" {
+		return nil, fmt.Errorf("invalid deposit memo: %q", memo)
+	}
+
+	recipientAddr, err := types.NewDestinationAddress(destAddress, destChain, l.chains)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	// Inputs spending outputs other than SegWit ones have no sender address
+	// the bridge can represent
+	var senderAddr types.Address
+	if len(tx.Vin) > 0 && tx.Vin[0].Prevout != nil {
+		if addr, err := types.NewAddress(tx.Vin[0].Prevout.ScriptPubKey.Address, types.ChainTypeBitcoin); err == nil {
+			senderAddr = addr
+		}
+	}
+
+	messageID, err := depositID(tx.TxID, out.N)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := types.TokenTransferPayload{
+		TokenAddress:  types.Address{Raw: "BTC", ChainType: types.ChainTypeBitcoin},
+		Amount:        strconv.FormatInt(int64(out.Value), 10),
+		TokenStandard: "BTC",
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	msg := &types.CrossChainMessage{
+		ID:   messageID,
+		Type: types.MessageTypeTokenTransfer,
+		SourceChain: types.ChainInfo{
+			Name:    l.config.Name,
+			Type:    types.ChainTypeBitcoin,
+			ChainID: l.config.ChainID,
+		},
+		DestinationChain: types.ChainInfo{
+			Name: destChain,
+		},
+		Sender:         senderAddr,
+		Recipient:      recipientAddr,
+		Payload:        payloadBytes,
+		Nonce:          uint64(out.N),
+		SourceTxHash:   tx.TxID,
+		SourceLogIndex: uint64(out.N),
+		Status:         types.MessageStatusPending,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	l.logger.Info().
+		Str("message_id", messageID).
+		Str("sender", senderAddr.Raw).
+		Str("recipient", recipientAddr.Raw).
+		Int64("amount_sats", int64(out.Value)).
+		Str("dest_chain", destChain).
+		Msg("Parsed deposit")
+
+	return msg, nil
+}
+
+// depositMemo returns the data of a transaction's first OP_RETURN output
+func depositMemo(tx *bitcoin.Transaction) (string, error) {
+	for _, out := range tx.Vout {
+		if out.ScriptPubKey.Type != "nulldata" {
+			continue
+		}
+		script, err := hex.DecodeString(out.ScriptPubKey.Hex)
+		if err != nil {
+			return "", fmt.Errorf("invalid output script: %w", err)
+		}
+		if data, ok := bitcoin.NullData(script); ok {
+			return string(data), nil
+		}
+	}
+	return "", fmt.Errorf("deposit has no OP_RETURN memo")
+}
+
+// depositID derives a message ID from the deposit's outpoint, which is
+// unique across the chain
+func depositID(txid string, vout uint32) (string, error) {
+	outPoint, err := bitcoin.NewOutPoint(txid, vout)
+	if err != nil {
+		return "", err
+	}
+	var index [4]byte
+	binary.LittleEndian.PutUint32(index[:], outPoint.Index)
+	hash := sha256.Sum256(append(outPoint.Hash[:], index[:]...))
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,202 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/bitcoin"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+const testCustody = "bcrt1qvwhgrayg38e7hn8f9lpelwuluclyp9uquzz4ajp6flflh27mrktsd0x29z"
+
+// regtestRPC fakes a regtest bitcoind whose chain ends at tip, serving the
+// blocks captured in testdata at verbosity 3
+func regtestRPC(t *testing.T, tip uint64) *httptest.Server {
+	t.Helper()
+
+	blocks := make(map[string]json.RawMessage)
+	hashes := make(map[uint64]string)
+	files, _ := filepath.Glob(filepath.Join("testdata", "block_*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		var header struct {
+			Hash   string `json:"hash"`
+			Height uint64 `json:"height"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		blocks[header.Hash] = data
+		hashes[header.Height] = header.Hash
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64        `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+
+		var result interface{}
+		var rpcErr *bitcoin.RPCError
+		switch req.Method {
+		case "getblockcount":
+			result = tip
+		case "getblockhash":
+			height := uint64(req.Params[0].(float64))
+			if hash, ok := hashes[height]; ok && height <= tip {
+				result = hash
+			} else {
+				rpcErr = &bitcoin.RPCError{Code: -8, Message: "Block height out of range"}
+			}
+		case "getblock":
+			if block, ok := blocks[req.Params[0].(string)]; ok {
+				result = block
+			} else {
+				rpcErr = &bitcoin.RPCError{Code: -5, Message: "Block not found"}
+			}
+		default:
+			rpcErr = &bitcoin.RPCError{Code: -32601, Message: fmt.Sprintf("Method not found: %s", req.Method)}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if rpcErr != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": result, "error": rpcErr, "id": req.ID})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// memoryStore is an in-memory UTXO index
+type memoryStore struct {
+	utxos map[string]*database.BitcoinUTXO
+}
+
+func (s *memoryStore) SaveBitcoinUTXO(ctx context.Context, utxo *database.BitcoinUTXO) error {
+	key := fmt.Sprintf("%s:%d", utxo.TxID, utxo.Vout)
+	if _, ok := s.utxos[key]; !ok {
+		saved := *utxo
+		saved.Status = database.BitcoinUTXOUnspent
+		s.utxos[key] = &saved
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkBitcoinUTXOSpent(ctx context.Context, chain, txid string, vout uint32, spentTxID string) error {
+	if utxo, ok := s.utxos[fmt.Sprintf("%s:%d", txid, vout)]; ok {
+		utxo.Status = database.BitcoinUTXOSpent
+		utxo.SpentTxID = spentTxID
+	}
+	return nil
+}
+
+func TestProcessBlocksRegtest(t *testing.T) {
+	server := regtestRPC(t, 103)
+
+	config := &types.ChainConfig{
+		Name:               "bitcoin-regtest",
+		ChainType:          types.ChainTypeBitcoin,
+		ChainID:            "regtest",
+		RPCEndpoints:       []string{server.URL},
+		BridgeContract:     testCustody,
+		ConfirmationBlocks: 2,
+		StartBlock:         101,
+	}
+	client, err := bitcoin.NewClient(config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	store := &memoryStore{utxos: make(map[string]*database.BitcoinUTXO)}
+	destinations := []types.ChainConfig{{Name: "ethereum-sepolia", ChainType: types.ChainTypeEVM}}
+	listener, err := NewListener(client, config, destinations, store, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewListener: %v", err)
+	}
+
+	// Block 103 has a single confirmation and is left for the next poll
+	if err := listener.processBlocks(context.Background()); err != nil {
+		t.Fatalf("processBlocks: %v", err)
+	}
+	if listener.lastBlock != 103 {
+		t.Errorf("lastBlock = %d, want 103", listener.lastBlock)
+	}
+
+	// Only the deposit with a valid memo is bridged; the release in block
+	// 102 spends it and returns change to the custody
+	var messages []*types.CrossChainMessage
+	for len(listener.eventChan) > 0 {
+		messages = append(messages, <-listener.eventChan)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+
+	msg := messages[0]
+	depositTx := "a1c3e5f7092b4d6f8a1c3e5f7092b4d6f8a1c3e5f7092b4d6f8a1c3e5f7092b4"
+	if msg.SourceTxHash != depositTx || msg.SourceLogIndex != 0 || msg.SourceBlock != 101 {
+		t.Errorf("source %s:%d in block %d", msg.SourceTxHash, msg.SourceLogIndex, msg.SourceBlock)
+	}
+	if msg.DestinationChain.Name != "ethereum-sepolia" {
+		t.Errorf("destination chain %q", msg.DestinationChain.Name)
+	}
+	if msg.Recipient.ChainType != types.ChainTypeEVM || msg.Recipient.Raw != "0x742d35Cc6634C0532925a3b844Bc454e4438f44e" {
+		t.Errorf("recipient %+v", msg.Recipient)
+	}
+	if msg.Sender.Raw != "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080" {
+		t.Errorf("sender %q", msg.Sender.Raw)
+	}
+	id, _ := depositID(depositTx, 0)
+	if msg.ID != id {
+		t.Errorf("message ID %s, want %s", msg.ID, id)
+	}
+
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Amount != "150000" || payload.TokenStandard != "BTC" {
+		t.Errorf("payload %+v", payload)
+	}
+
+	want := map[string]string{
+		depositTx + ":0": database.BitcoinUTXOSpent,
+		"b2d4f6a8c0e2b4d6f8a0c2e4b6d8f0a2c4e6b8d0f2a4c6e8b0d2f4a6c8e0b2d4:0": database.BitcoinUTXOUnspent,
+		"c3e5a7c9e1b3d5f7a9c1e3b5d7f9a1c3e5b7d9f1a3c5e7b9d1f3a5c7e9b1d3f5:0": database.BitcoinUTXOUnspent,
+		"d4f6b8e0a2c4f6d8b0e2a4c6f8d0b2e4a6c8f0d2b4e6a8c0f2d4b6e8a0c2f4d6:1": database.BitcoinUTXOUnspent,
+	}
+	if len(store.utxos) != len(want) {
+		t.Errorf("indexed %d UTXOs, want %d", len(store.utxos), len(want))
+	}
+	for key, status := range want {
+		utxo, ok := store.utxos[key]
+		if !ok {
+			t.Errorf("UTXO %s not indexed", key)
+			continue
+		}
+		if utxo.Status != status {
+			t.Errorf("UTXO %s is %s, want %s", key, utxo.Status, status)
+		}
+	}
+	if change := store.utxos["d4f6b8e0a2c4f6d8b0e2a4c6f8d0b2e4a6c8f0d2b4e6a8c0f2d4b6e8a0c2f4d6:1"]; change != nil && change.AmountSats != 49000 {
+		t.Errorf("change amount %d", change.AmountSats)
+	}
+}
@@ -0,0 +1,338 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/cosmos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// The bridge contract emits a wasm-token_locked event per lock. wasmd
+// prefixes custom event types with "wasm-" and adds the emitting contract.
+const (
+	tokenLockedEventType = "wasm-token_locked"
+	contractAttribute    = "_contract_address"
+)
+
+// blockBatchSize is the number of blocks processed between cursor updates
+const blockBatchSize = 50
+
+// Listener listens for events on a Cosmos SDK chain
+type Listener struct {
+	client         *cosmos.Client
+	config         *types.ChainConfig
+	chains         []types.ChainConfig
+	logger         zerolog.Logger
+	eventChan      chan *types.CrossChainMessage
+	stopChan       chan struct{}
+	lastBlock      uint64
+	bridgeContract string
+}
+
+// NewListener creates a new Cosmos event listener
+func NewListener(
+	client *cosmos.Client,
+	config *types.ChainConfig,
+	chains []types.ChainConfig,
+	logger zerolog.Logger,
+) (*Listener, error) {
+	if _, err := types.NewAddress(config.BridgeContract, types.ChainTypeCosmos); err != nil {
+		return nil, fmt.Errorf("invalid bridge contract address: %w", err)
+	}
+
+	return &Listener{
+		client:         client,
+		config:         config,
+		chains:         chains,
+		logger:         logger.With().Str("chain", config.Name).Str("component", "listener").Logger(),
+		eventChan:      make(chan *types.CrossChainMessage, 100),
+		stopChan:       make(chan struct{}),
+		lastBlock:      config.StartBlock,
+		bridgeContract: config.BridgeContract,
+	}, nil
+}
+
+// Start starts the listener
+func (l *Listener) Start(ctx context.Context) error {
+	l.logger.Info().
+		Uint64("start_block", l.lastBlock).
+		Str("contract", l.bridgeContract).
+		Msg("Starting Cosmos listener")
+
+	go l.listen(ctx)
+
+	return nil
+}
+
+// Stop stops the listener
+func (l *Listener) Stop() error {
+	l.logger.Info().Msg("Stopping Cosmos listener")
+	close(l.stopChan)
+	close(l.eventChan)
+	return nil
+}
+
+// EventChan returns the channel for receiving events
+func (l *Listener) EventChan() <-chan *types.CrossChainMessage {
+	return l.eventChan
+}
+
+// listen is the main listening loop
+func (l *Listener) listen(ctx context.Context) {
+	ticker := time.NewTicker(l.config.GetPollIntervalDuration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.logger.Info().Msg("Context cancelled, stopping listener")
+			return
+		case <-l.stopChan:
+			l.logger.Info().Msg("Stop signal received")
+			return
+		case <-ticker.C:
+			if err := l.processBlocks(ctx); err != nil {
+				l.logger.Error().Err(err).Msg("Error processing blocks")
+			}
+		}
+	}
+}
+
+// processBlocks processes new blocks. CometBFT blocks are final once
+// committed; ConfirmationBlocks still applies for operators who want a
+// margin against RPC nodes serving uncommitted state.
+func (l *Listener) processBlocks(ctx context.Context) error {
+	latestBlock, err := l.client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	monitoring.UpdateChainBlockNumber(l.config.Name, latestBlock)
+
+	safeBlock := latestBlock
+	if latestBlock > l.config.ConfirmationBlocks {
+		safeBlock = latestBlock - l.config.ConfirmationBlocks
+	}
+	if l.lastBlock > safeBlock {
+		return nil
+	}
+
+	for fromBlock := l.lastBlock; fromBlock <= safeBlock; fromBlock = l.lastBlock {
+		toBlock := fromBlock + blockBatchSize - 1
+		if toBlock > safeBlock {
+			toBlock = safeBlock
+		}
+
+		if err := l.processBlockRange(ctx, fromBlock, toBlock); err != nil {
+			return err
+		}
+
+		monitoring.ListenerBlocksProcessed.WithLabelValues(l.config.Name).Add(float64(toBlock - fromBlock + 1))
+		monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(toBlock))
+	}
+
+	return nil
+}
+
+// ProcessRange processes the blocks between fromBlock and toBlock,
+// inclusive, without moving the listener's cursor. It is used to backfill
+// past blocks.
+func (l *Listener) ProcessRange(ctx context.Context, fromBlock, toBlock uint64) error {
+	lastBlock := l.lastBlock
+	defer func() { l.lastBlock = lastBlock }()
+
+	return l.processBlockRange(ctx, fromBlock, toBlock)
+}
+
+// processBlockRange processes a range of blocks. It stops at the first
+// block that fails so the block is retried on the next poll.
+func (l *Listener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64) error {
+	l.logger.Debug().
+		Uint64("from", fromBlock).
+		Uint64("to", toBlock).
+		Msg("Processing block range")
+
+	for height := fromBlock; height <= toBlock; height++ {
+		if err := l.processBlock(ctx, height); err != nil {
+			return fmt.Errorf("failed to process block %d: %w", height, err)
+		}
+		l.lastBlock = height + 1
+	}
+
+	return nil
+}
+
+// processBlock processes the bridge events of a block's transactions.
+// Transaction results are in block order, so the transaction hashes are
+// taken from the block.
+func (l *Listener) processBlock(ctx context.Context, height uint64) error {
+	block, err := l.client.GetBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+	if len(block.Block.Data.Txs) == 0 {
+		return nil
+	}
+
+	results, err := l.client.GetBlockResults(ctx, height)
+	if err != nil {
+		return err
+	}
+	if len(results.TxsResults) != len(block.Block.Data.Txs) {
+		return fmt.Errorf("block has %d transactions but %d results", len(block.Block.Data.Txs), len(results.TxsResults))
+	}
+
+	for i, tx := range block.Block.Data.Txs {
+		result := &results.TxsResults[i]
+		// Events of failed transactions were reverted
+		if result.Code != 0 {
+			continue
+		}
+
+		txHash := cosmos.TxHash(tx)
+		var logIndex uint64
+		for j := range result.Events {
+			event := &result.Events[j]
+			if event.Type != tokenLockedEventType {
+				continue
+			}
+			if contract, _ := event.Attribute(contractAttribute); contract != l.bridgeContract {
+				continue
+			}
+
+			msg, err := l.parseTokenLockedEvent(event)
+			if err != nil {
+				l.logger.Error().
+					Err(err).
+					Str("tx_hash", txHash).
+					Msg("Error processing event")
+				logIndex++
+				continue
+			}
+
+			msg.SourceTxHash = txHash
+			msg.SourceBlock = height
+			// Index among the bridge's events in the transaction
+			msg.SourceLogIndex = logIndex
+			logIndex++
+
+			l.queue(msg)
+		}
+	}
+
+	return nil
+}
+
+// queue sends a message to the event channel without blocking
+func (l *Listener) queue(msg *types.CrossChainMessage) {
+	select {
+	case l.eventChan <- msg:
+		l.logger.Info().
+			Str("message_id", msg.ID).
+			Str("type", string(msg.Type)).
+			Msg("Message detected and queued")
+
+		monitoring.ListenerEventsDetected.WithLabelValues(l.config.Name, string(msg.Type)).Inc()
+	default:
+		l.logger.Warn().Msg("Event channel full, message dropped")
+	}
+}
+
+// parseTokenLockedEvent parses a token locked event. Token is a CW20
+// contract address or a bank denom.
+func (l *Listener) parseTokenLockedEvent(event *cosmos.Event) (*types.CrossChainMessage, error) {
+	attr := func(key string) string {
+		value, _ := event.Attribute(key)
+		return value
+	}
+
+	messageID := strings.TrimPrefix(attr("message_id"), "0x")
+	if messageID == "" {
+		return nil, fmt.Errorf("missing message_id")
+	}
+	destChain := attr("destination_chain")
+	if destChain == "" {
+		return nil, fmt.Errorf("missing destination_chain")
+	}
+
+	amount, ok := new(big.Int).SetString(attr("amount"), 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %q", attr("amount"))
+	}
+
+	nonce, err := strconv.ParseUint(attr("nonce"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+
+	token := attr("token")
+	if token == "" {
+		return nil, fmt.Errorf("missing token")
+	}
+	tokenAddr := types.Address{Raw: token, ChainType: types.ChainTypeCosmos}
+	standard := "BANK"
+	if addr, err := types.NewAddress(token, types.ChainTypeCosmos); err == nil {
+		tokenAddr = addr
+		standard = "CW20"
+	}
+
+	payload := types.TokenTransferPayload{
+		TokenAddress:  tokenAddr,
+		Amount:        amount.String(),
+		TokenStandard: standard,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	senderAddr, err := types.NewAddress(attr("sender"), types.ChainTypeCosmos)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	recipientAddr, err := types.NewDestinationAddress(attr("destination_address"), destChain, l.chains)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	msg := &types.CrossChainMessage{
+		ID:   messageID,
+		Type: types.MessageTypeTokenTransfer,
+		SourceChain: types.ChainInfo{
+			Name:    l.config.Name,
+			Type:    types.ChainTypeCosmos,
+			ChainID: l.config.ChainID,
+		},
+		DestinationChain: types.ChainInfo{
+			Name: destChain,
+		},
+		Sender:    senderAddr,
+		Recipient: recipientAddr,
+		Payload:   payloadBytes,
+		Nonce:     nonce,
+		Status:    types.MessageStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	l.logger.Info().
+		Str("message_id", messageID).
+		Str("sender", senderAddr.Raw).
+		Str("recipient", recipientAddr.Raw).
+		Str("token", token).
+		Str("amount", amount.String()).
+		Str("dest_chain", destChain).
+		Msg("Parsed token locked event")
+
+	return msg, nil
+}
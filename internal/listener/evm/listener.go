@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/evm"
@@ -15,6 +17,10 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// headResubscribeDelay is how long to wait before resubscribing to new
+// heads after the subscription drops
+const headResubscribeDelay = 5 * time.Second
+
 // Listener listens for events on an EVM blockchain
 type Listener struct {
 	client        *evm.Client
@@ -22,8 +28,14 @@ type Listener struct {
 	logger        zerolog.Logger
 	eventChan     chan *types.CrossChainMessage
 	stopChan      chan struct{}
-	lastBlock     uint64
 	bridgeAddress common.Address
+
+	// mu guards lastBlock, which polling and the head subscription share
+	mu        sync.Mutex
+	lastBlock uint64
+	following atomic.Bool
+
+	resubscribeDelay time.Duration
 }
 
 // NewListener creates a new EVM event listener
@@ -46,19 +58,28 @@ func NewListener(
 		stopChan:      make(chan struct{}),
 		lastBlock:     config.StartBlock,
 		bridgeAddress: bridgeAddress,
+
+		resubscribeDelay: headResubscribeDelay,
 	}, nil
 }
 
-// Start starts the listener
+// Start starts the listener. With a WebSocket endpoint configured it
+// processes blocks as new heads arrive and polls only while the head
+// subscription is down.
 func (l *Listener) Start(ctx context.Context) error {
 	l.logger.Info().
 		Uint64("start_block", l.lastBlock).
 		Str("bridge", l.bridgeAddress.Hex()).
+		Bool("head_subscription", l.config.WSEndpoint != "").
 		Msg("Starting EVM listener")
 
 	// Start listening in a goroutine
 	go l.listen(ctx)
 
+	if l.config.WSEndpoint != "" {
+		go l.followHeads(ctx)
+	}
+
 	return nil
 }
 
@@ -89,6 +110,9 @@ func (l *Listener) listen(ctx context.Context) {
 			l.logger.Info().Msg("Stop signal received")
 			return
 		case <-ticker.C:
+			if l.following.Load() {
+				continue
+			}
 			if err := l.processBlocks(ctx); err != nil {
 				l.logger.Error().Err(err).Msg("Error processing blocks")
 			}
@@ -104,13 +128,28 @@ func (l *Listener) processBlocks(ctx context.Context) error {
 		return fmt.Errorf("failed to get latest block: %w", err)
 	}
 
+	return l.processUpTo(ctx, latestBlock)
+}
+
+// processUpTo processes the blocks final at latestBlock that have not been
+// processed yet
+func (l *Listener) processUpTo(ctx context.Context, latestBlock uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	// Update metrics
 	monitoring.UpdateChainBlockNumber(l.config.Name, latestBlock)
 	monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(l.lastBlock))
 
-	// Calculate safe block (with confirmations)
+	// Calculate safe block under the chain's finality policy
 	safeBlock := latestBlock
-	if latestBlock > l.config.ConfirmationBlocks {
+	if l.config.GetFinality() != types.FinalityDepth {
+		finalizedBlock, err := l.client.GetFinalizedBlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get finalized block: %w", err)
+		}
+		safeBlock = finalizedBlock
+	} else if latestBlock > l.config.ConfirmationBlocks {
 		safeBlock = latestBlock - l.config.ConfirmationBlocks
 	}
 
@@ -146,6 +185,71 @@ func (l *Listener) processBlocks(ctx context.Context) error {
 	return nil
 }
 
+// followHeads processes blocks as new heads arrive, resubscribing when the
+// subscription drops. Polling takes over while it is down, and the blocks
+// missed in between are processed as a range when it resumes.
+func (l *Listener) followHeads(ctx context.Context) {
+	for {
+		if err := l.followHeadSubscription(ctx); err != nil && ctx.Err() == nil {
+			l.logger.Warn().Err(err).Msg("Head subscription ended, polling until it resumes")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.stopChan:
+			return
+		case <-time.After(l.resubscribeDelay):
+		}
+	}
+}
+
+// followHeadSubscription runs a single head subscription until it fails
+func (l *Listener) followHeadSubscription(ctx context.Context) error {
+	sub, err := l.client.SubscribeNewHeads(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	// Catch up on the blocks produced while not subscribed
+	if err := l.processBlocks(ctx); err != nil {
+		return fmt.Errorf("failed to catch up before following heads: %w", err)
+	}
+
+	l.following.Store(true)
+	defer l.following.Store(false)
+	l.logger.Info().Msg("Following new heads")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-l.stopChan:
+			return nil
+		case err := <-sub.Err():
+			return err
+		case header := <-sub.Headers():
+			if err := l.processUpTo(ctx, header.Number.Uint64()); err != nil {
+				l.logger.Error().
+					Err(err).
+					Uint64("head", header.Number.Uint64()).
+					Msg("Error processing blocks for new head")
+			}
+		}
+	}
+}
+
+// ProcessRange processes the blocks between fromBlock and toBlock,
+// inclusive, without moving the listener's cursor. It is used to backfill
+// past blocks.
+func (l *Listener) ProcessRange(ctx context.Context, fromBlock, toBlock uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.processBlockRange(ctx, fromBlock, toBlock)
+}
+
 // processBlockRange processes a range of blocks
 func (l *Listener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64) error {
 	l.logger.Debug().
@@ -0,0 +1,256 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/evm"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+)
+
+// fakeEth serves the eth methods the listener uses
+type fakeEth struct {
+	mu        sync.Mutex
+	head      uint64
+	finalized uint64
+	ranges    []string
+	subs      chan *headSub
+}
+
+type headSub struct {
+	notifier *rpc.Notifier
+	sub      *rpc.Subscription
+}
+
+func (f *fakeEth) BlockNumber() hexutil.Uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return hexutil.Uint64(f.head)
+}
+
+func (f *fakeEth) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) *ethtypes.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	height := uint64(number.Int64())
+	switch number {
+	case rpc.LatestBlockNumber:
+		height = f.head
+	case rpc.FinalizedBlockNumber:
+		height = f.finalized
+	}
+	return &ethtypes.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Difficulty: big.NewInt(0),
+	}
+}
+
+func (f *fakeEth) GetLogs(crit map[string]interface{}) []ethtypes.Log {
+	from, _ := hexutil.DecodeUint64(crit["fromBlock"].(string))
+	to, _ := hexutil.DecodeUint64(crit["toBlock"].(string))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ranges = append(f.ranges, fmt.Sprintf("%d-%d", from, to))
+	return []ethtypes.Log{}
+}
+
+func (f *fakeEth) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	f.subs <- &headSub{notifier: notifier, sub: sub}
+	return sub, nil
+}
+
+func (f *fakeEth) setHead(head uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.head = head
+}
+
+func (f *fakeEth) hasRanges() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.ranges) > 0
+}
+
+func (f *fakeEth) takeRanges() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ranges := f.ranges
+	f.ranges = nil
+	return ranges
+}
+
+func (s *headSub) push(t *testing.T, number uint64) {
+	t.Helper()
+	header := &ethtypes.Header{
+		Number:     new(big.Int).SetUint64(number),
+		Difficulty: big.NewInt(0),
+		Time:       uint64(time.Now().Unix()),
+	}
+	if err := s.notifier.Notify(s.sub.ID, header); err != nil {
+		t.Fatalf("failed to push head %d: %v", number, err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFollowHeadsFillsGapAfterReconnect(t *testing.T) {
+	eth := &fakeEth{head: 20, subs: make(chan *headSub, 4)}
+	newServer := func() *rpc.Server {
+		server := rpc.NewServer()
+		if err := server.RegisterName("eth", eth); err != nil {
+			t.Fatalf("failed to register fake eth service: %v", err)
+		}
+		return server
+	}
+
+	// Each WebSocket connection gets its own server so the test can drop it
+	httpRPC := newServer()
+	var wsMu sync.Mutex
+	var wsRPC *rpc.Server
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			wsMu.Lock()
+			wsRPC = newServer()
+			handler := wsRPC.WebsocketHandler([]string{"*"})
+			wsMu.Unlock()
+			handler.ServeHTTP(w, r)
+			return
+		}
+		httpRPC.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+	dropWebSocket := func() {
+		wsMu.Lock()
+		defer wsMu.Unlock()
+		wsRPC.Stop()
+	}
+
+	config := &types.ChainConfig{
+		Name:               "fake-evm",
+		ChainType:          types.ChainTypeEVM,
+		ChainID:            "31337",
+		RPCEndpoints:       []string{httpServer.URL},
+		WSEndpoint:         "ws" + strings.TrimPrefix(httpServer.URL, "http"),
+		BridgeContract:     "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		ConfirmationBlocks: 2,
+		StartBlock:         10,
+		PollInterval:       "1h",
+	}
+
+	client, err := evm.NewClient(config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	listener, err := NewListener(client, config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewListener failed: %v", err)
+	}
+	listener.resubscribeDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listener.Start(ctx)
+
+	// Subscribing catches up to the confirmed block
+	var sub *headSub
+	select {
+	case sub = <-eth.subs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for head subscription")
+	}
+	waitFor(t, "head subscription", listener.following.Load)
+	if got := eth.takeRanges(); len(got) != 1 || got[0] != "10-18" {
+		t.Fatalf("expected catch-up of blocks 10-18, got %v", got)
+	}
+
+	// Each new head pulls the logs of the block it confirms
+	sub.push(t, 21)
+	waitFor(t, "block 19", eth.hasRanges)
+	if got := eth.takeRanges(); got[0] != "19-19" {
+		t.Fatalf("expected logs of block 19, got %v", got)
+	}
+
+	// Blocks produced while disconnected are filled as a range
+	eth.setHead(30)
+	dropWebSocket()
+
+	select {
+	case <-eth.subs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for resubscription")
+	}
+	waitFor(t, "resubscription", eth.hasRanges)
+	if got := eth.takeRanges(); got[0] != "20-28" {
+		t.Fatalf("expected gap fill of blocks 20-28, got %v", got)
+	}
+}
+
+func TestProcessBlocksFollowsFinalizedTag(t *testing.T) {
+	eth := &fakeEth{head: 100, finalized: 68}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", eth); err != nil {
+		t.Fatalf("failed to register fake eth service: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	config := &types.ChainConfig{
+		Name:               "fake-evm",
+		ChainType:          types.ChainTypeEVM,
+		ChainID:            "31337",
+		RPCEndpoints:       []string{httpServer.URL},
+		BridgeContract:     "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		ConfirmationBlocks: 2,
+		Finality:           types.FinalityFinalized,
+		StartBlock:         60,
+	}
+
+	client, err := evm.NewClient(config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	listener, err := NewListener(client, config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewListener failed: %v", err)
+	}
+
+	// The finalized tag replaces the confirmation depth
+	if err := listener.processBlocks(context.Background()); err != nil {
+		t.Fatalf("processBlocks failed: %v", err)
+	}
+	if got := eth.takeRanges(); len(got) != 1 || got[0] != "60-68" {
+		t.Fatalf("expected blocks 60-68, got %v", got)
+	}
+
+	// Nothing is processed until the finalized block advances
+	eth.setHead(110)
+	if err := listener.processBlocks(context.Background()); err != nil {
+		t.Fatalf("processBlocks failed: %v", err)
+	}
+	if got := eth.takeRanges(); len(got) != 0 {
+		t.Fatalf("expected no blocks before finality advances, got %v", got)
+	}
+}
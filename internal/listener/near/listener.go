@@ -4,7 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/near"
@@ -17,6 +18,7 @@ import (
 type Listener struct {
 	client         *near.Client
 	config         *types.ChainConfig
+	chains         []types.ChainConfig
 	logger         zerolog.Logger
 	eventChan      chan *types.CrossChainMessage
 	stopChan       chan struct{}
@@ -28,6 +30,7 @@ type Listener struct {
 func NewListener(
 	client *near.Client,
 	config *types.ChainConfig,
+	chains []types.ChainConfig,
 	logger zerolog.Logger,
 ) (*Listener, error) {
 	if config.BridgeContract == "" {
@@ -37,6 +40,7 @@ func NewListener(
 	return &Listener{
 		client:         client,
 		config:         config,
+		chains:         chains,
 		logger:         logger.With().Str("chain", config.Name).Str("component", "listener").Logger(),
 		eventChan:      make(chan *types.CrossChainMessage, 100),
 		stopChan:       make(chan struct{}),
@@ -104,9 +108,15 @@ func (l *Listener) processBlocks(ctx context.Context) error {
 	monitoring.UpdateChainBlockNumber(l.config.Name, latestBlock)
 	monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(l.lastBlock))
 
-	// Calculate safe block (with confirmations)
+	// Calculate safe block under the chain's finality policy
 	safeBlock := latestBlock
-	if latestBlock > l.config.ConfirmationBlocks {
+	if l.config.GetFinality() != types.FinalityDepth {
+		finalBlock, err := l.client.GetFinalizedBlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get final block: %w", err)
+		}
+		safeBlock = finalBlock
+	} else if latestBlock > l.config.ConfirmationBlocks {
 		safeBlock = latestBlock - l.config.ConfirmationBlocks
 	}
 
@@ -142,22 +152,29 @@ func (l *Listener) processBlocks(ctx context.Context) error {
 	return nil
 }
 
-// processBlockRange processes a range of blocks
+// ProcessRange processes the blocks between fromBlock and toBlock,
+// inclusive, without moving the listener's cursor. It is used to backfill
+// past blocks.
+func (l *Listener) ProcessRange(ctx context.Context, fromBlock, toBlock uint64) error {
+	lastBlock := l.lastBlock
+	defer func() { l.lastBlock = lastBlock }()
+
+	return l.processBlockRange(ctx, fromBlock, toBlock)
+}
+
+// processBlockRange processes a range of blocks. It stops at the first
+// block that fails so the block is retried on the next poll.
 func (l *Listener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64) error {
 	l.logger.Debug().
 		Uint64("from", fromBlock).
 		Uint64("to", toBlock).
 		Msg("Processing block range")
 
-	// For each block in range
 	for blockHeight := fromBlock; blockHeight <= toBlock; blockHeight++ {
 		if err := l.processBlock(ctx, blockHeight); err != nil {
-			l.logger.Error().
-				Err(err).
-				Uint64("block", blockHeight).
-				Msg("Error processing block")
-			continue
+			return fmt.Errorf("failed to process block %d: %w", blockHeight, err)
 		}
+		l.lastBlock = blockHeight + 1
 	}
 
 	return nil
@@ -165,32 +182,25 @@ func (l *Listener) processBlockRange(ctx context.Context, fromBlock, toBlock uin
 
 // processBlock processes a single block
 func (l *Listener) processBlock(ctx context.Context, blockHeight uint64) error {
-	// Get block details
-	block, err := l.client.GetBlockByNumber(ctx, blockHeight)
+	block, err := l.client.GetBlock(ctx, blockHeight)
 	if err != nil {
+		if near.IsUnknownBlock(err) {
+			l.logger.Debug().Uint64("block", blockHeight).Msg("No block produced at height")
+			return nil
+		}
 		return fmt.Errorf("failed to get block: %w", err)
 	}
 
-	// Query for transaction receipts involving bridge contract
-	// NEAR doesn't have event logs like EVM, so we need to query receipts
-	// This is a simplified approach - in production you'd use indexer
-
-	// Get account activity (simplified)
-	// In production, use NEAR Indexer or Lake Framework
-	events, err := l.queryContractEvents(ctx, blockHeight)
+	events, err := l.blockEvents(ctx, block)
 	if err != nil {
-		l.logger.Warn().
-			Err(err).
-			Uint64("block", blockHeight).
-			Msg("Failed to query contract events")
-		return nil // Don't fail - continue to next block
+		return err
 	}
 
-	// Process each event
 	for _, event := range events {
 		if err := l.processEvent(ctx, event); err != nil {
 			l.logger.Error().
 				Err(err).
+				Str("tx_hash", event.TxHash).
 				Msg("Error processing event")
 			continue
 		}
@@ -198,58 +208,133 @@ func (l *Listener) processBlock(ctx context.Context, blockHeight uint64) error {
 
 	l.logger.Debug().
 		Uint64("block", blockHeight).
-		Str("hash", block.Hash).
+		Str("hash", block.Header.Hash).
 		Int("events", len(events)).
 		Msg("Block processed")
 
 	return nil
 }
 
-// NEAREvent represents a NEAR contract event
+// eventLogPrefix marks a NEP-297 event in a receipt's logs
+const eventLogPrefix = "EVENT_JSON:"
+
+// bridgeEventStandard is the NEP-297 standard of the bridge contract's
+// events (contracts/near/src/events.rs)
+const bridgeEventStandard = "articium"
+
+// NEAREvent represents a NEP-297 event logged by the bridge contract
 type NEAREvent struct {
-	Standard string                 `json:"standard"`
-	Version  string                 `json:"version"`
-	Event    string                 `json:"event"`
-	Data     map[string]interface{} `json:"data"`
+	Standard string          `json:"standard"`
+	Version  string          `json:"version"`
+	Event    string          `json:"event"`
+	Data     json.RawMessage `json:"data"`
+
+	TxHash      string `json:"-"`
+	BlockHeight uint64 `json:"-"`
+	LogIndex    uint64 `json:"-"`
 }
 
-// queryContractEvents queries events from bridge contract
-func (l *Listener) queryContractEvents(ctx context.Context, blockHeight uint64) ([]NEAREvent, error) {
-	// In production, you would:
-	// 1. Use NEAR Indexer for Explorer
-	// 2. Use NEAR Lake Framework
-	// 3. Query transaction outcomes for the bridge contract
-	// 4. Parse execution outcomes for events
+// TokenLockedEvent is the data of the bridge contract's token_locked event
+type TokenLockedEvent struct {
+	MessageID          string      `json:"message_id"`
+	Sender             string      `json:"sender"`
+	TokenContract      string      `json:"token_contract"`
+	Amount             json.Number `json:"amount"`
+	DestinationChain   string      `json:"destination_chain"`
+	DestinationAddress string      `json:"destination_address"`
+	Nonce              uint64      `json:"nonce"`
+	Timestamp          uint64      `json:"timestamp"`
+}
 
-	// For this example, we'll demonstrate the event structure
-	// Real implementation would use near.callRPC with proper queries
+// blockEvents returns the bridge events of the chunks included in block
+func (l *Listener) blockEvents(ctx context.Context, block *near.BlockResponse) ([]NEAREvent, error) {
+	var events []NEAREvent
+
+	for _, header := range block.Chunks {
+		// A shard that missed its chunk repeats the chunk of an earlier
+		// block, which was processed at that height
+		if header.HeightIncluded != block.Header.Height {
+			l.logger.Debug().
+				Uint64("block", block.Header.Height).
+				Uint64("shard", header.ShardID).
+				Msg("Skipping missing chunk")
+			continue
+		}
 
-	// Placeholder - would query actual events from indexer
-	events := []NEAREvent{}
+		chunkEvents, err := l.chunkEvents(ctx, block.Header.Height, header.ChunkHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process chunk %s: %w", header.ChunkHash, err)
+		}
+		events = append(events, chunkEvents...)
+	}
 
-	// Example of querying view function to get pending events
-	// This assumes bridge contract stores events that can be queried
-	result, err := l.client.ViewFunction(ctx, l.bridgeContract, "get_events", map[string]interface{}{
-		"from_block": blockHeight,
-		"to_block":   blockHeight,
-	})
+	return events, nil
+}
 
+// chunkEvents returns the bridge events of the transactions sent to the
+// bridge contract in a chunk. The events are logged by the receipts the
+// transaction produced, which may execute in later blocks, so they are read
+// from the transaction's final outcomes.
+func (l *Listener) chunkEvents(ctx context.Context, blockHeight uint64, chunkHash string) ([]NEAREvent, error) {
+	chunk, err := l.client.GetChunk(ctx, chunkHash)
 	if err != nil {
-		return events, err
+		return nil, err
 	}
 
-	// Parse result
-	if err := json.Unmarshal(result, &events); err != nil {
-		return events, fmt.Errorf("failed to parse events: %w", err)
+	var events []NEAREvent
+	for _, tx := range chunk.Transactions {
+		if tx.ReceiverID != l.bridgeContract {
+			continue
+		}
+
+		status, err := l.client.GetTransactionOutcomes(ctx, tx.Hash, tx.SignerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get outcomes of transaction %s: %w", tx.Hash, err)
+		}
+
+		var logIndex uint64
+		for _, receipt := range status.ReceiptsOutcome {
+			if receipt.Outcome.ExecutorID != l.bridgeContract || !receipt.Outcome.Succeeded() {
+				continue
+			}
+
+			for _, log := range receipt.Outcome.Logs {
+				event, ok := parseEventLog(log)
+				if !ok {
+					continue
+				}
+
+				event.TxHash = tx.Hash
+				event.BlockHeight = blockHeight
+				event.LogIndex = logIndex
+				logIndex++
+				events = append(events, *event)
+			}
+		}
 	}
 
 	return events, nil
 }
 
+// parseEventLog parses a NEP-297 event log line
+func parseEventLog(log string) (*NEAREvent, bool) {
+	data, ok := strings.CutPrefix(log, eventLogPrefix)
+	if !ok {
+		return nil, false
+	}
+
+	var event NEAREvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return nil, false
+	}
+
+	return &event, true
+}
+
 // processEvent processes a single contract event
 func (l *Listener) processEvent(ctx context.Context, event NEAREvent) error {
 	// Check if this is a bridge event
-	if event.Standard != "bridge" {
+	if event.Standard != bridgeEventStandard {
 		return nil
 	}
 
@@ -258,9 +343,17 @@ func (l *Listener) processEvent(ctx context.Context, event NEAREvent) error {
 
 	switch event.Event {
 	case "token_locked":
-		msg, err = l.parseTokenLockedEvent(event.Data)
+		var data TokenLockedEvent
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return fmt.Errorf("failed to decode token_locked event: %w", err)
+		}
+		msg, err = l.parseTokenLockedEvent(&data)
 	case "nft_locked":
-		msg, err = l.parseNFTLockedEvent(event.Data)
+		var data map[string]interface{}
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return fmt.Errorf("failed to decode nft_locked event: %w", err)
+		}
+		msg, err = l.parseNFTLockedEvent(data)
 	default:
 		return nil // Unknown event
 	}
@@ -270,6 +363,10 @@ func (l *Listener) processEvent(ctx context.Context, event NEAREvent) error {
 	}
 
 	if msg != nil {
+		msg.SourceTxHash = event.TxHash
+		msg.SourceBlock = event.BlockHeight
+		msg.SourceLogIndex = event.LogIndex
+
 		// Send message to channel
 		select {
 		case l.eventChan <- msg:
@@ -289,57 +386,30 @@ func (l *Listener) processEvent(ctx context.Context, event NEAREvent) error {
 }
 
 // parseTokenLockedEvent parses a token locked event
-func (l *Listener) parseTokenLockedEvent(data map[string]interface{}) (*types.CrossChainMessage, error) {
-	// Extract fields from event data
-	messageID, ok := data["message_id"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid message_id")
-	}
-
-	sender, ok := data["sender"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid sender")
-	}
-
-	recipient, ok := data["recipient"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid recipient")
-	}
-
-	token, ok := data["token"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid token")
+func (l *Listener) parseTokenLockedEvent(event *TokenLockedEvent) (*types.CrossChainMessage, error) {
+	if event.MessageID == "" {
+		return nil, fmt.Errorf("missing message_id")
 	}
-
-	// Amount might be string in NEAR
-	var amount uint64
-	switch v := data["amount"].(type) {
-	case string:
-		parsed, err := strconv.ParseUint(v, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid amount: %w", err)
-		}
-		amount = parsed
-	case float64:
-		amount = uint64(v)
-	default:
-		return nil, fmt.Errorf("invalid amount type")
+	if event.DestinationChain == "" {
+		return nil, fmt.Errorf("missing destination_chain")
 	}
 
-	destChain, ok := data["destination_chain"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid destination_chain")
+	// Balances are u128 and may not fit in a uint64
+	amount, ok := new(big.Int).SetString(event.Amount.String(), 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %s", event.Amount)
 	}
 
 	// Build payload
-	tokenAddr, err := types.NewAddress(token, types.ChainTypeNEAR)
+	tokenAddr, err := types.NewAddress(event.TokenContract, types.ChainTypeNEAR)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token address: %w", err)
 	}
 
 	payload := types.TokenTransferPayload{
-		TokenAddress: tokenAddr,
-		Amount:       fmt.Sprintf("%d", amount),
+		TokenAddress:  tokenAddr,
+		Amount:        amount.String(),
+		TokenStandard: "NEP141",
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -348,19 +418,19 @@ func (l *Listener) parseTokenLockedEvent(data map[string]interface{}) (*types.Cr
 	}
 
 	// Build addresses
-	senderAddr, err := types.NewAddress(sender, types.ChainTypeNEAR)
+	senderAddr, err := types.NewAddress(event.Sender, types.ChainTypeNEAR)
 	if err != nil {
 		return nil, fmt.Errorf("invalid sender address: %w", err)
 	}
 
-	recipientAddr, err := types.NewAddress(recipient, types.ChainTypeNEAR)
+	recipientAddr, err := types.NewDestinationAddress(event.DestinationAddress, event.DestinationChain, l.chains)
 	if err != nil {
 		return nil, fmt.Errorf("invalid recipient address: %w", err)
 	}
 
 	// Build cross-chain message
 	msg := &types.CrossChainMessage{
-		ID:   messageID,
+		ID:   event.MessageID,
 		Type: types.MessageTypeTokenTransfer,
 		SourceChain: types.ChainInfo{
 			Name:    l.config.Name,
@@ -368,24 +438,24 @@ func (l *Listener) parseTokenLockedEvent(data map[string]interface{}) (*types.Cr
 			ChainID: l.config.NetworkID,
 		},
 		DestinationChain: types.ChainInfo{
-			Name: destChain,
+			Name: event.DestinationChain,
 		},
 		Sender:    senderAddr,
 		Recipient: recipientAddr,
 		Payload:   payloadBytes,
-		Nonce:     0, // Would extract from event
+		Nonce:     event.Nonce,
 		Status:    types.MessageStatusPending,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	l.logger.Info().
-		Str("message_id", messageID).
-		Str("sender", sender).
-		Str("recipient", recipient).
-		Str("token", token).
-		Uint64("amount", amount).
-		Str("dest_chain", destChain).
+		Str("message_id", event.MessageID).
+		Str("sender", event.Sender).
+		Str("recipient", event.DestinationAddress).
+		Str("token", event.TokenContract).
+		Str("amount", amount.String()).
+		Str("dest_chain", event.DestinationChain).
 		Msg("Parsed token locked event")
 
 	return msg, nil
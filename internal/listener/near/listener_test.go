@@ -0,0 +1,179 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/near"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// replayRPC serves NEAR RPC responses captured in testdata, named after
+// the method and its block height, chunk hash or transaction hash
+func replayRPC(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requested []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                     `json:"method"`
+			Params map[string]json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode RPC request: %v", err)
+			return
+		}
+
+		var name string
+		switch req.Method {
+		case "block":
+			name = "block_" + string(req.Params["block_id"]) + ".json"
+		case "chunk":
+			name = "chunk_" + unquote(req.Params["chunk_id"]) + ".json"
+		case "EXPERIMENTAL_tx_status":
+			name = "tx_" + unquote(req.Params["tx_hash"]) + ".json"
+		default:
+			t.Errorf("unexpected RPC method %s", req.Method)
+			return
+		}
+
+		mu.Lock()
+		requested = append(requested, name)
+		mu.Unlock()
+
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Errorf("no captured response %s", name)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func unquote(raw json.RawMessage) string {
+	var s string
+	json.Unmarshal(raw, &s)
+	return s
+}
+
+func newTestListener(t *testing.T, endpoint string) *Listener {
+	t.Helper()
+
+	config := &types.ChainConfig{
+		Name:           "near-testnet",
+		ChainType:      types.ChainTypeNEAR,
+		NetworkID:      "testnet",
+		RPCEndpoints:   []string{endpoint},
+		BridgeContract: "bridge.articium.testnet",
+		StartBlock:     183000100,
+	}
+
+	client, err := near.NewClient(config, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	destinations := []types.ChainConfig{{Name: "ethereum-sepolia", ChainType: types.ChainTypeEVM}}
+	listener, err := NewListener(client, config, destinations, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewListener failed: %v", err)
+	}
+	return listener
+}
+
+func TestProcessBlockRangeReplay(t *testing.T) {
+	server, requested := replayRPC(t)
+	listener := newTestListener(t, server.URL)
+
+	if err := listener.processBlockRange(context.Background(), 183000100, 183000101); err != nil {
+		t.Fatalf("processBlockRange failed: %v", err)
+	}
+	if listener.lastBlock != 183000102 {
+		t.Errorf("expected last block 183000102, got %d", listener.lastBlock)
+	}
+
+	for _, name := range requested() {
+		switch name {
+		case "chunk_7QzcbdLgf1fG2SzzX8rZQ5Wg3HhVBVo4kWVGfNGoBd3w.json":
+			t.Error("expected the missing chunk of shard 1 not to be fetched")
+		case "tx_9TEU1RGjfbHfm7gcoFNEhUvC5xVYkBsXumtDpyqDZQsM.json":
+			t.Error("expected transaction to another contract not to be fetched")
+		}
+	}
+
+	// Only the successful lock is detected: the nep141 log belongs to the
+	// token contract and the second lock failed
+	var messages []*types.CrossChainMessage
+	for len(listener.eventChan) > 0 {
+		messages = append(messages, <-listener.eventChan)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+
+	msg := messages[0]
+	if msg.ID != "8c5e1f0a3b7d4e2f9a6c1b0d5e8f7a2c4b3d6e9f0a1c2b5d8e7f4a3c6b9d0e1f" {
+		t.Errorf("unexpected message ID %s", msg.ID)
+	}
+	if msg.SourceTxHash != "F4hCcZRGiKkFV9c7ysYwjB6ZEbtxGmDrvQp6PXg8AJQ3" || msg.SourceBlock != 183000100 {
+		t.Errorf("unexpected source %s at %d", msg.SourceTxHash, msg.SourceBlock)
+	}
+	if msg.Nonce != 42 {
+		t.Errorf("expected nonce 42, got %d", msg.Nonce)
+	}
+	if msg.Sender.Raw != "alice.testnet" {
+		t.Errorf("unexpected sender %s", msg.Sender.Raw)
+	}
+	if msg.Recipient.ChainType != types.ChainTypeEVM {
+		t.Errorf("expected EVM recipient, got %s", msg.Recipient.ChainType)
+	}
+	if msg.DestinationChain.Name != "ethereum-sepolia" {
+		t.Errorf("unexpected destination chain %s", msg.DestinationChain.Name)
+	}
+
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	// The amount does not fit in a uint64
+	if payload.Amount != "250000000000000000000000000" {
+		t.Errorf("unexpected amount %s", payload.Amount)
+	}
+	if payload.TokenAddress.Raw != "usdc.testnet" {
+		t.Errorf("unexpected token %s", payload.TokenAddress.Raw)
+	}
+}
+
+func TestParseEventLog(t *testing.T) {
+	if _, ok := parseEventLog("Tokens locked: amount=1, destination=ethereum-sepolia"); ok {
+		t.Error("expected plain log not to parse as an event")
+	}
+	if _, ok := parseEventLog("EVENT_JSON:{not json"); ok {
+		t.Error("expected malformed event not to parse")
+	}
+
+	event, ok := parseEventLog(`EVENT_JSON:{"standard":"articium","version":"1.0.0","event":"token_unlocked","data":{}}`)
+	if !ok {
+		t.Fatal("expected event to parse")
+	}
+	if event.Standard != bridgeEventStandard || event.Event != "token_unlocked" {
+		t.Errorf("unexpected event %s/%s", event.Standard, event.Event)
+	}
+}
@@ -0,0 +1,94 @@
+package solana
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	solanago "github.com/gagliardetto/solana-go"
+)
+
+// programDataPrefix marks an event emitted with Anchor's emit! in a
+// transaction's logs
+const programDataPrefix = "Program data: "
+
+// tokenLockedDiscriminator identifies TokenLockedEvent in emitted event
+// data (contracts/solana/programs/solana-bridge/src/instructions/lock_token.rs)
+var tokenLockedDiscriminator = eventDiscriminator("TokenLockedEvent")
+
+// TokenLockedEvent is the Borsh layout of the bridge program's
+// TokenLockedEvent
+type TokenLockedEvent struct {
+	MessageID          [32]byte
+	Sender             solanago.PublicKey
+	TokenMint          solanago.PublicKey
+	Amount             uint64
+	DestinationChain   string
+	DestinationAddress string
+	Nonce              uint64
+	Timestamp          int64
+}
+
+// eventDiscriminator returns the 8-byte prefix Anchor gives the data of an
+// event
+func eventDiscriminator(name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte("event:" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+// programEvents returns the data of the events a program emitted in a
+// transaction's logs. Logs are attributed to the program executing at the
+// time, so events emitted by other programs in the same transaction, or by
+// programs the bridge calls, are ignored.
+func programEvents(logs []string, programID solanago.PublicKey) [][]byte {
+	program := programID.String()
+
+	var events [][]byte
+	var stack []string
+	for _, log := range logs {
+		if data, ok := strings.CutPrefix(log, programDataPrefix); ok {
+			if len(stack) == 0 || stack[len(stack)-1] != program {
+				continue
+			}
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				continue
+			}
+			events = append(events, decoded)
+			continue
+		}
+
+		fields := strings.Fields(log)
+		if len(fields) < 3 || fields[0] != "Program" {
+			continue
+		}
+		switch {
+		case fields[2] == "invoke":
+			stack = append(stack, fields[1])
+		case fields[2] == "success" || fields[2] == "failed:":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return events
+}
+
+// decodeTokenLockedEvent decodes event data if it is a TokenLockedEvent
+func decodeTokenLockedEvent(data []byte) (*TokenLockedEvent, bool, error) {
+	if len(data) < 8 || !bytes.Equal(data[:8], tokenLockedDiscriminator[:]) {
+		return nil, false, nil
+	}
+
+	var event TokenLockedEvent
+	if err := bin.NewBorshDecoder(data[8:]).Decode(&event); err != nil {
+		return nil, true, err
+	}
+
+	return &event, true, nil
+}
@@ -0,0 +1,81 @@
+package solana
+
+import (
+	"encoding/hex"
+	"testing"
+
+	solanago "github.com/gagliardetto/solana-go"
+)
+
+const (
+	testBridgeProgram = "BRGEkuiVDx1NwAqSJt2xwsTJD1LmCvWRQuqhTbxbgEFS"
+
+	// Captured TokenLockedEvent and TokenUnlockedEvent data
+	testTokenLockedData   = "8arMVYbgHtQDChEYHyYtNDtCSVBXXmVsc3qBiI+WnaSrsrnAx87V3H6MCIdgv94d3c8ywX8gm4JC7lKq8TH6zYjQ6ixtCwbyO0Qss5EhV/E6kz0BNCgtAytf/s0Botvxt3kGCN8ALqegJSYAAAAAABAAAABldGhlcmV1bS1zZXBvbGlhKgAAADB4NzQyZDM1Q2M2NjM0QzA1MzI5MjVhM2I4NDRCYzQ1NGU0NDM4ZjQ0ZREAAAAAAAAAworzaAAAAAA="
+	testTokenUnlockedData = "twVTmn6PR3sAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=="
+)
+
+func TestProgramEventsDecodeTokenLocked(t *testing.T) {
+	programID := solanago.MustPublicKeyFromBase58(testBridgeProgram)
+
+	logs := []string{
+		"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+		"Program ComputeBudget111111111111111111111111111111 success",
+		"Program " + testBridgeProgram + " invoke [1]",
+		"Program log: Instruction: LockToken",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [2]",
+		"Program log: Instruction: Transfer",
+		// Emitted by the token program, not the bridge
+		"Program data: " + testTokenLockedData,
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA consumed 4645 of 180000 compute units",
+		"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA success",
+		"Program data: " + testTokenLockedData,
+		"Program data: " + testTokenUnlockedData,
+		"Program " + testBridgeProgram + " consumed 52311 of 200000 compute units",
+		"Program " + testBridgeProgram + " success",
+	}
+
+	events := programEvents(logs, programID)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 bridge events, got %d", len(events))
+	}
+
+	if _, ok, _ := decodeTokenLockedEvent(events[1]); ok {
+		t.Error("expected TokenUnlockedEvent not to decode as TokenLockedEvent")
+	}
+
+	event, ok, err := decodeTokenLockedEvent(events[0])
+	if err != nil || !ok {
+		t.Fatalf("failed to decode TokenLockedEvent: ok=%v err=%v", ok, err)
+	}
+
+	if got := hex.EncodeToString(event.MessageID[:4]); got != "030a1118" {
+		t.Errorf("unexpected message ID prefix %s", got)
+	}
+	if event.Sender.String() != "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM" {
+		t.Errorf("unexpected sender %s", event.Sender)
+	}
+	if event.TokenMint.String() != "4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU" {
+		t.Errorf("unexpected token mint %s", event.TokenMint)
+	}
+	if event.Amount != 2500000 || event.Nonce != 17 || event.Timestamp != 1760791234 {
+		t.Errorf("unexpected amount %d, nonce %d or timestamp %d", event.Amount, event.Nonce, event.Timestamp)
+	}
+	if event.DestinationChain != "ethereum-sepolia" || event.DestinationAddress != "0x742d35Cc6634C0532925a3b844Bc454e4438f44e" {
+		t.Errorf("unexpected destination %s/%s", event.DestinationChain, event.DestinationAddress)
+	}
+}
+
+func TestProgramEventsIgnoresFailedInvocation(t *testing.T) {
+	programID := solanago.MustPublicKeyFromBase58(testBridgeProgram)
+
+	logs := []string{
+		"Program " + testBridgeProgram + " invoke [1]",
+		"Program " + testBridgeProgram + " failed: custom program error: 0x1770",
+		"Program data: " + testTokenLockedData,
+	}
+
+	if events := programEvents(logs, programID); len(events) != 0 {
+		t.Errorf("expected no events outside the bridge invocation, got %d", len(events))
+	}
+}
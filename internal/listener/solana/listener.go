@@ -2,9 +2,11 @@ package solana
 
 import (
 	"context"
-	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/solana"
@@ -12,24 +14,46 @@ import (
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	solanago "github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
 	"github.com/rs/zerolog"
 )
 
+const (
+	// signaturePageSize is the most signatures getSignaturesForAddress
+	// returns at once
+	signaturePageSize = 1000
+	// recentSignatureSlots is how many slots a processed signature is
+	// remembered, so a transaction seen by both polling and the log
+	// subscription is processed once
+	recentSignatureSlots = 300
+	// logSubscriptionRetry is how long to wait before resubscribing to
+	// program logs
+	logSubscriptionRetry = 10 * time.Second
+)
+
 // Listener listens for events on Solana blockchain
 type Listener struct {
 	client          *solana.Client
 	config          *types.ChainConfig
+	chains          []types.ChainConfig
 	logger          zerolog.Logger
 	eventChan       chan *types.CrossChainMessage
 	stopChan        chan struct{}
-	lastSlot        uint64
 	bridgeProgramID solanago.PublicKey
+
+	// mu guards the cursor, which polling and the log subscription share
+	mu            sync.Mutex
+	lastSlot      uint64
+	lastSignature solanago.Signature
+	recent        map[solanago.Signature]uint64
+	streaming     atomic.Bool
 }
 
 // NewListener creates a new Solana event listener
 func NewListener(
 	client *solana.Client,
 	config *types.ChainConfig,
+	chains []types.ChainConfig,
 	logger zerolog.Logger,
 ) (*Listener, error) {
 	// Check BridgeProgram for Solana chains
@@ -50,24 +74,33 @@ func NewListener(
 	return &Listener{
 		client:          client,
 		config:          config,
+		chains:          chains,
 		logger:          logger.With().Str("chain", config.Name).Str("component", "listener").Logger(),
 		eventChan:       make(chan *types.CrossChainMessage, 100),
 		stopChan:        make(chan struct{}),
 		lastSlot:        config.StartBlock,
+		recent:          make(map[solanago.Signature]uint64),
 		bridgeProgramID: bridgeProgramID,
 	}, nil
 }
 
-// Start starts the listener
+// Start starts the listener. With a WebSocket endpoint configured it
+// follows the bridge program's logs and polls only while the subscription
+// is down.
 func (l *Listener) Start(ctx context.Context) error {
 	l.logger.Info().
 		Uint64("start_slot", l.lastSlot).
 		Str("program", l.bridgeProgramID.String()).
+		Bool("log_subscription", l.config.WSEndpoint != "").
 		Msg("Starting Solana listener")
 
 	// Start listening in a goroutine
 	go l.listen(ctx)
 
+	if l.config.WSEndpoint != "" {
+		go l.subscribeLogs(ctx)
+	}
+
 	return nil
 }
 
@@ -98,6 +131,9 @@ func (l *Listener) listen(ctx context.Context) {
 			l.logger.Info().Msg("Stop signal received")
 			return
 		case <-ticker.C:
+			if l.streaming.Load() {
+				continue
+			}
 			if err := l.processSlots(ctx); err != nil {
 				l.logger.Error().Err(err).Msg("Error processing slots")
 			}
@@ -105,8 +141,12 @@ func (l *Listener) listen(ctx context.Context) {
 	}
 }
 
-// processSlots processes new slots
+// processSlots processes the bridge program's transactions up to the
+// latest final slot
 func (l *Listener) processSlots(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	// Get latest slot
 	latestSlot, err := l.client.GetSlot(ctx)
 	if err != nil {
@@ -117,190 +157,303 @@ func (l *Listener) processSlots(ctx context.Context) error {
 	monitoring.UpdateChainBlockNumber(l.config.Name, latestSlot)
 	monitoring.ListenerLastBlockProcessed.WithLabelValues(l.config.Name).Set(float64(l.lastSlot))
 
-	// Calculate safe slot (with confirmations)
+	// Calculate safe slot under the chain's finality policy
 	safeSlot := latestSlot
-	if latestSlot > l.config.ConfirmationBlocks {
+	if l.config.GetFinality() != types.FinalityDepth {
+		finalizedSlot, err := l.client.GetFinalizedBlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get finalized slot: %w", err)
+		}
+		safeSlot = finalizedSlot
+	} else if latestSlot > l.config.ConfirmationBlocks {
 		safeSlot = latestSlot - l.config.ConfirmationBlocks
 	}
 
-	// Process slots from lastSlot to safeSlot
-	if l.lastSlot > safeSlot {
-		return nil // No new confirmed slots
+	return l.processSignatures(ctx, safeSlot)
+}
+
+// processSignatures processes the bridge program's transactions since the
+// last one processed, oldest first, stopping at the first transaction after
+// safeSlot. getSignaturesForAddress pages newest first, so the pages are
+// collected before processing.
+func (l *Listener) processSignatures(ctx context.Context, safeSlot uint64) error {
+	var pending []*rpc.TransactionSignature
+	var before solanago.Signature
+	for {
+		page, err := l.client.GetSignaturesForAddress(ctx, l.bridgeProgramID, before, l.lastSignature, signaturePageSize)
+		if err != nil {
+			return fmt.Errorf("failed to get program signatures: %w", err)
+		}
+
+		done := len(page) < signaturePageSize
+		for _, sig := range page {
+			// Without a last signature the listener starts at StartBlock
+			if sig.Slot < l.lastSlot {
+				done = true
+				break
+			}
+			pending = append(pending, sig)
+		}
+		if done {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
+
+	if len(pending) == 0 {
+		return nil
 	}
 
-	// Process in batches
-	batchSize := uint64(50) // Smaller batches for Solana
-	for fromSlot := l.lastSlot; fromSlot <= safeSlot; {
-		toSlot := fromSlot + batchSize - 1
-		if toSlot > safeSlot {
-			toSlot = safeSlot
+	l.logger.Debug().
+		Int("transactions", len(pending)).
+		Uint64("safe_slot", safeSlot).
+		Msg("Processing program transactions")
+
+	for i := len(pending) - 1; i >= 0; i-- {
+		sig := pending[i]
+		if sig.Slot > safeSlot {
+			break
 		}
 
-		if err := l.processSlotRange(ctx, fromSlot, toSlot); err != nil {
-			l.logger.Error().
-				Err(err).
-				Uint64("from", fromSlot).
-				Uint64("to", toSlot).
-				Msg("Error processing slot range")
-			return err
+		if sig.Err == nil {
+			if err := l.processTransaction(ctx, sig.Signature, sig.Slot); err != nil {
+				return err
+			}
+		}
+
+		l.markProcessed(sig.Signature, sig.Slot)
+		monitoring.ListenerBlocksProcessed.WithLabelValues(l.config.Name).Inc()
+	}
+
+	return nil
+}
+
+// ProcessRange processes the bridge program's transactions in the slots
+// between fromSlot and toSlot, inclusive, without moving the listener's
+// cursor. It is used to backfill past slots. Signatures are paged from the
+// newest, so every later transaction of the program is paged through.
+func (l *Listener) ProcessRange(ctx context.Context, fromSlot, toSlot uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var pending []*rpc.TransactionSignature
+	var before solanago.Signature
+	for {
+		page, err := l.client.GetSignaturesForAddress(ctx, l.bridgeProgramID, before, solanago.Signature{}, signaturePageSize)
+		if err != nil {
+			return fmt.Errorf("failed to get program signatures: %w", err)
 		}
 
-		fromSlot = toSlot + 1
-		l.lastSlot = toSlot + 1
+		done := len(page) < signaturePageSize
+		for _, sig := range page {
+			if sig.Slot < fromSlot {
+				done = true
+				break
+			}
+			if sig.Slot <= toSlot {
+				pending = append(pending, sig)
+			}
+		}
+		if done {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
 
-		// Update metrics
-		monitoring.ListenerBlocksProcessed.WithLabelValues(l.config.Name).Add(float64(toSlot - fromSlot + 1))
+	for i := len(pending) - 1; i >= 0; i-- {
+		sig := pending[i]
+		if sig.Err != nil {
+			continue
+		}
+		if err := l.fetchTransaction(ctx, sig.Signature, sig.Slot); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// processSlotRange processes a range of slots
-func (l *Listener) processSlotRange(ctx context.Context, fromSlot, toSlot uint64) error {
-	l.logger.Debug().
-		Uint64("from", fromSlot).
-		Uint64("to", toSlot).
-		Msg("Processing slot range")
+// processTransaction fetches a transaction not processed recently and
+// processes the events in its logs
+func (l *Listener) processTransaction(ctx context.Context, signature solanago.Signature, slot uint64) error {
+	if _, ok := l.recent[signature]; ok {
+		return nil
+	}
+	return l.fetchTransaction(ctx, signature, slot)
+}
 
-	// Get program accounts with filters
-	accounts, err := l.client.GetProgramAccounts(ctx, l.bridgeProgramID)
+// fetchTransaction fetches a transaction and processes the events in its
+// logs
+func (l *Listener) fetchTransaction(ctx context.Context, signature solanago.Signature, slot uint64) error {
+	tx, err := l.client.GetTransaction(ctx, signature)
 	if err != nil {
-		l.logger.Warn().Err(err).Msg("Failed to get program accounts")
-		// Don't fail - continue to next batch
+		return fmt.Errorf("failed to get transaction %s: %w", signature, err)
+	}
+	if tx == nil || tx.Meta == nil || tx.Meta.Err != nil {
 		return nil
 	}
 
-	l.logger.Debug().
-		Int("accounts", len(accounts)).
-		Msg("Program accounts retrieved")
+	l.processLogs(signature, slot, tx.Meta.LogMessages)
+	return nil
+}
 
-	// Process each account for events
-	for _, result := range accounts {
-		if err := l.processAccount(ctx, result.Pubkey, result.Account); err != nil {
+// processLogs queues a message for each TokenLockedEvent the bridge
+// program emitted in a transaction's logs
+func (l *Listener) processLogs(signature solanago.Signature, slot uint64, logs []string) {
+	for i, data := range programEvents(logs, l.bridgeProgramID) {
+		event, ok, err := decodeTokenLockedEvent(data)
+		if err != nil {
 			l.logger.Error().
 				Err(err).
-				Str("account", result.Pubkey.String()).
-				Msg("Error processing account")
+				Str("signature", signature.String()).
+				Msg("Failed to decode token locked event")
+			continue
+		}
+		if !ok {
 			continue
 		}
-	}
-
-	return nil
-}
 
-// processAccount processes a single program account
-func (l *Listener) processAccount(ctx context.Context, pubkey solanago.PublicKey, account *rpc.Account) error {
-	// Parse account data to detect lock events
-	// Account data structure depends on your Solana program implementation
+		msg, err := l.parseTokenLockedEvent(event)
+		if err != nil {
+			l.logger.Error().
+				Err(err).
+				Str("signature", signature.String()).
+				Msg("Error processing event")
+			continue
+		}
 
-	// For this example, we'll assume the account data contains:
-	// - Discriminator (8 bytes)
-	// - Event type (1 byte)
-	// - Message data
+		msg.SourceTxHash = signature.String()
+		msg.SourceBlock = slot
+		msg.SourceLogIndex = uint64(i)
 
-	if len(account.Data.GetBinary()) < 9 {
-		return nil // Not enough data
+		l.queueMessage(msg)
 	}
+}
 
-	data := account.Data.GetBinary()
-
-	// Check discriminator (first 8 bytes)
-	// This would match your Solana program's event discriminator
-	discriminator := data[0:8]
-	_ = discriminator // Would check if this is a lock event
+// markProcessed advances the cursor past a transaction
+func (l *Listener) markProcessed(signature solanago.Signature, slot uint64) {
+	l.lastSignature = signature
+	if slot > l.lastSlot {
+		l.lastSlot = slot
+	}
 
-	// Event type
-	eventType := data[8]
+	l.recent[signature] = slot
+	for sig, seen := range l.recent {
+		if seen+recentSignatureSlots < l.lastSlot {
+			delete(l.recent, sig)
+		}
+	}
+}
 
-	// Parse based on event type
-	var msg *types.CrossChainMessage
-	var err error
+// queueMessage sends a detected message to the event channel
+func (l *Listener) queueMessage(msg *types.CrossChainMessage) {
+	select {
+	case l.eventChan <- msg:
+		l.logger.Info().
+			Str("message_id", msg.ID).
+			Str("type", string(msg.Type)).
+			Msg("Message detected and queued")
 
-	switch eventType {
-	case 0x01: // Token locked event
-		msg, err = l.parseTokenLockedEvent(data[9:], pubkey)
-	case 0x02: // NFT locked event
-		msg, err = l.parseNFTLockedEvent(data[9:], pubkey)
+		// Update metrics
+		monitoring.ListenerEventsDetected.WithLabelValues(l.config.Name, string(msg.Type)).Inc()
 	default:
-		return nil // Unknown event type
+		l.logger.Warn().Msg("Event channel full, message dropped")
 	}
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to parse event: %w", err)
-	}
+// subscribeLogs follows the bridge program's logs over the WebSocket
+// endpoint, resubscribing when the subscription drops. Polling takes over
+// while it is down.
+func (l *Listener) subscribeLogs(ctx context.Context) {
+	for {
+		if err := l.followLogs(ctx); err != nil && ctx.Err() == nil {
+			l.logger.Warn().Err(err).Msg("Program log subscription ended, polling until it resumes")
+		}
 
-	if msg != nil {
-		// Send message to channel
 		select {
-		case l.eventChan <- msg:
-			l.logger.Info().
-				Str("message_id", msg.ID).
-				Str("type", string(msg.Type)).
-				Msg("Message detected and queued")
-
-			// Update metrics
-			monitoring.ListenerEventsDetected.WithLabelValues(l.config.Name, string(msg.Type)).Inc()
-		default:
-			l.logger.Warn().Msg("Event channel full, message dropped")
+		case <-ctx.Done():
+			return
+		case <-l.stopChan:
+			return
+		case <-time.After(logSubscriptionRetry):
 		}
 	}
-
-	return nil
 }
 
-// parseTokenLockedEvent parses a token locked event from account data
-func (l *Listener) parseTokenLockedEvent(data []byte, account solanago.PublicKey) (*types.CrossChainMessage, error) {
-	// Expected data structure (simplified):
-	// - message_id (32 bytes)
-	// - sender (32 bytes)
-	// - recipient (variable, prefixed with length)
-	// - token_mint (32 bytes)
-	// - amount (8 bytes)
-	// - destination_chain (variable, prefixed with length)
+// followLogs processes the bridge program's logs as transactions become
+// final. It first catches up on the transactions missed
+// while not subscribed.
+func (l *Listener) followLogs(ctx context.Context) error {
+	sub, err := l.client.SubscribeProgramLogs(ctx, l.bridgeProgramID)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	results := make(chan *ws.LogResult)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			result, err := sub.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			results <- result
+		}
+	}()
 
-	if len(data) < 104 { // Minimum size
-		return nil, fmt.Errorf("insufficient data for token locked event")
+	if err := l.processSlots(ctx); err != nil {
+		return fmt.Errorf("failed to catch up before following logs: %w", err)
 	}
 
-	offset := 0
+	l.streaming.Store(true)
+	defer l.streaming.Store(false)
+	l.logger.Info().Msg("Following program logs")
 
-	// Message ID (32 bytes)
-	messageID := base64.StdEncoding.EncodeToString(data[offset : offset+32])
-	offset += 32
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-l.stopChan:
+			return nil
+		case err := <-errs:
+			return err
+		case result := <-results:
+			l.handleLogResult(result)
+		}
+	}
+}
 
-	// Sender (32 bytes Solana public key)
-	senderBytes := data[offset : offset+32]
-	sender := solanago.PublicKeyFromBytes(senderBytes).String()
-	offset += 32
+// handleLogResult processes a transaction received from the log
+// subscription
+func (l *Listener) handleLogResult(result *ws.LogResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	// Recipient (length-prefixed string)
-	recipientLen := int(data[offset])
-	offset++
-	if offset+recipientLen > len(data) {
-		return nil, fmt.Errorf("invalid recipient length")
+	signature := result.Value.Signature
+	if _, ok := l.recent[signature]; ok {
+		return
 	}
-	recipient := string(data[offset : offset+recipientLen])
-	offset += recipientLen
 
-	// Token mint (32 bytes)
-	tokenMintBytes := data[offset : offset+32]
-	tokenMint := solanago.PublicKeyFromBytes(tokenMintBytes).String()
-	offset += 32
-
-	// Amount (8 bytes, little endian)
-	amount := uint64(0)
-	for i := 0; i < 8 && offset+i < len(data); i++ {
-		amount |= uint64(data[offset+i]) << (i * 8)
+	if result.Value.Err == nil {
+		l.processLogs(signature, result.Context.Slot, result.Value.Logs)
 	}
-	offset += 8
+	l.markProcessed(signature, result.Context.Slot)
+}
+
+// parseTokenLockedEvent builds a message from a TokenLockedEvent
+func (l *Listener) parseTokenLockedEvent(event *TokenLockedEvent) (*types.CrossChainMessage, error) {
+	messageID := hex.EncodeToString(event.MessageID[:])
+	sender := event.Sender.String()
+	tokenMint := event.TokenMint.String()
 
-	// Destination chain (length-prefixed string)
-	destChainLen := int(data[offset])
-	offset++
-	if offset+destChainLen > len(data) {
-		return nil, fmt.Errorf("invalid destination chain length")
+	if event.Amount == 0 {
+		return nil, fmt.Errorf("invalid amount: 0")
+	}
+	if event.DestinationChain == "" {
+		return nil, fmt.Errorf("missing destination chain")
 	}
-	destChain := string(data[offset : offset+destChainLen])
 
 	// Build cross-chain message
 	tokenAddr, err := types.NewAddress(tokenMint, types.ChainTypeSolana)
@@ -310,7 +463,7 @@ func (l *Listener) parseTokenLockedEvent(data []byte, account solanago.PublicKey
 
 	payload := types.TokenTransferPayload{
 		TokenAddress:  tokenAddr,
-		Amount:        fmt.Sprintf("%d", amount),
+		Amount:        fmt.Sprintf("%d", event.Amount),
 		TokenStandard: "SPL",
 	}
 
@@ -325,7 +478,7 @@ func (l *Listener) parseTokenLockedEvent(data []byte, account solanago.PublicKey
 		return nil, fmt.Errorf("invalid sender address: %w", err)
 	}
 
-	recipientAddr, err := types.NewAddress(recipient, types.ChainTypeSolana)
+	recipientAddr, err := types.NewDestinationAddress(event.DestinationAddress, event.DestinationChain, l.chains)
 	if err != nil {
 		return nil, fmt.Errorf("invalid recipient address: %w", err)
 	}
@@ -339,12 +492,12 @@ func (l *Listener) parseTokenLockedEvent(data []byte, account solanago.PublicKey
 			ChainID: l.config.NetworkID,
 		},
 		DestinationChain: types.ChainInfo{
-			Name: destChain,
+			Name: event.DestinationChain,
 		},
 		Sender:    senderAddr,
 		Recipient: recipientAddr,
 		Payload:   payloadBytes,
-		Nonce:     0, // Would extract from account
+		Nonce:     event.Nonce,
 		Status:    types.MessageStatusPending,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -353,24 +506,11 @@ func (l *Listener) parseTokenLockedEvent(data []byte, account solanago.PublicKey
 	l.logger.Info().
 		Str("message_id", messageID).
 		Str("sender", sender).
-		Str("recipient", recipient).
+		Str("recipient", event.DestinationAddress).
 		Str("token", tokenMint).
-		Uint64("amount", amount).
-		Str("dest_chain", destChain).
+		Uint64("amount", event.Amount).
+		Str("dest_chain", event.DestinationChain).
 		Msg("Parsed token locked event")
 
 	return msg, nil
 }
-
-// parseNFTLockedEvent parses an NFT locked event from account data
-func (l *Listener) parseNFTLockedEvent(data []byte, account solanago.PublicKey) (*types.CrossChainMessage, error) {
-	// Similar structure to token locked but for NFTs
-	// Would parse Metaplex NFT metadata
-
-	l.logger.Debug().
-		Str("account", account.String()).
-		Msg("Parsing NFT locked event")
-
-	// Placeholder - full implementation would parse NFT-specific data
-	return nil, fmt.Errorf("NFT locked event parsing not fully implemented")
-}
@@ -227,6 +227,71 @@ var (
 		},
 		[]string{"chain"},
 	)
+
+	// RPC endpoint metrics. Endpoints are labelled by host so credentials
+	// and API keys in URLs are not exported.
+	RPCRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_rpc_requests_total",
+			Help: "Total number of RPC requests per endpoint",
+		},
+		[]string{"chain", "endpoint", "result"},
+	)
+
+	RPCRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "bridge_rpc_request_duration_seconds",
+			Help:    "RPC request latency per endpoint",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"chain", "endpoint"},
+	)
+
+	RPCEndpointHeadLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bridge_rpc_endpoint_head_lag",
+			Help: "Blocks an RPC endpoint's head trails the best endpoint's",
+		},
+		[]string{"chain", "endpoint"},
+	)
+
+	RPCEndpointScore = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bridge_rpc_endpoint_score",
+			Help: "Selection score of an RPC endpoint; lower is preferred",
+		},
+		[]string{"chain", "endpoint"},
+	)
+
+	RPCQuorumReads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_rpc_quorum_reads_total",
+			Help: "Total number of quorum reads by outcome",
+		},
+		[]string{"chain", "result"},
+	)
+
+	// Outbox metrics
+	OutboxPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bridge_outbox_published_total",
+		Help: "Total number of outbox entries published to the queue",
+	})
+
+	OutboxPublishFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bridge_outbox_publish_failures_total",
+		Help: "Total number of failed outbox publish attempts",
+	})
+
+	OutboxBacklog = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bridge_outbox_backlog",
+		Help: "Number of outbox entries waiting to be published",
+	})
+
+	OutboxPublishLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bridge_outbox_publish_latency_seconds",
+		Help:    "Time from outbox write to successful publish",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 30, 60, 300},
+	})
 )
 
 // RecordMessageProcessed records a processed message
@@ -0,0 +1,588 @@
+package nft
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
+	"github.com/rs/zerolog"
+)
+
+// Supported NFT standards
+const (
+	StandardERC721   = "ERC721"
+	StandardERC1155  = "ERC1155"
+	StandardMetaplex = "Metaplex"
+	StandardNEP171   = "NEP171"
+)
+
+const (
+	// DefaultIPFSGateway is used to resolve ipfs:// URIs
+	DefaultIPFSGateway = "https://ipfs.io/ipfs/"
+	// DefaultArweaveGateway is used to resolve ar:// URIs
+	DefaultArweaveGateway = "https://arweave.net/"
+
+	maxMetadataSize = 256 * 1024
+	fetchTimeout    = 10 * time.Second
+	maxRedirects    = 5
+)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net.IP.IsPrivate does not cover
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// nftABI contains the metadata view functions of ERC721 and ERC1155
+const nftABI = `[
+	{"name":"tokenURI","type":"function","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"name":"uri","type":"function","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]}
+]`
+
+// Metadata holds the resolved metadata of a single NFT
+type Metadata struct {
+	TokenURI string          `json:"token_uri"`
+	Name     string          `json:"name,omitempty"`
+	Symbol   string          `json:"symbol,omitempty"`
+	Raw      json.RawMessage `json:"raw,omitempty"`
+}
+
+// contractCaller is implemented by EVM clients that support eth_call
+type contractCaller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error)
+}
+
+// accountReader is implemented by Solana clients that can read account data
+type accountReader interface {
+	GetAccountData(ctx context.Context, account solana.PublicKey) ([]byte, error)
+}
+
+// viewCaller is implemented by NEAR clients that can call view functions
+type viewCaller interface {
+	ViewFunction(ctx context.Context, contractID string, methodName string, args interface{}) (json.RawMessage, error)
+}
+
+// Resolver resolves token URIs from source contracts and fetches the
+// off-chain metadata they point to
+type Resolver struct {
+	httpClient     *http.Client
+	ipfsGateway    string
+	arweaveGateway string
+	abi            abi.ABI
+	logger         zerolog.Logger
+}
+
+// NewResolver creates a new NFT metadata resolver
+func NewResolver(logger zerolog.Logger) *Resolver {
+	parsed, err := abi.JSON(strings.NewReader(nftABI))
+	if err != nil {
+		// The ABI is a constant, so this can only fail on a programming error
+		panic(fmt.Sprintf("invalid NFT ABI: %v", err))
+	}
+
+	return &Resolver{
+		httpClient:     newMetadataClient(),
+		ipfsGateway:    DefaultIPFSGateway,
+		arweaveGateway: DefaultArweaveGateway,
+		abi:            parsed,
+		logger:         logger.With().Str("component", "nft-resolver").Logger(),
+	}
+}
+
+// newMetadataClient returns an HTTP client for fetching token URIs. Token URIs
+// come from caller-chosen contracts, so every connection, including those made
+// while following redirects, is checked against the resolved IP address to
+// keep the resolver from reaching internal services.
+func newMetadataClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: fetchTimeout,
+		Control: rejectNonPublicAddress,
+	}
+
+	return &http.Client{
+		Timeout: fetchTimeout,
+		Transport: &http.Transport{
+			// No proxy: the dial check must see the real destination
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: fetchTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("unsupported redirect scheme: %s", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// rejectNonPublicAddress is a net.Dialer Control hook that refuses to connect
+// to loopback, private, link-local and other non-routable addresses
+func rejectNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid dial address %s: %w", address, err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid dial address %s", address)
+	}
+	if !isPublicIP(ip) {
+		return fmt.Errorf("refusing to fetch metadata from non-public address %s", ip)
+	}
+
+	return nil
+}
+
+// isPublicIP reports whether an address is globally routable
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		if ip4[0] == 0 || sharedAddressSpace.Contains(ip4) {
+			return false
+		}
+	}
+
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast())
+}
+
+// DefaultStandard returns the NFT standard used when a request does not specify one
+func DefaultStandard(chainType types.ChainType) (string, error) {
+	switch chainType {
+	case types.ChainTypeEVM:
+		return StandardERC721, nil
+	case types.ChainTypeSolana:
+		return StandardMetaplex, nil
+	case types.ChainTypeNEAR:
+		return StandardNEP171, nil
+	default:
+		return "", fmt.Errorf("NFTs are not supported on chain type %s", chainType)
+	}
+}
+
+// ValidateStandard checks that a standard exists on the given chain type
+func ValidateStandard(standard string, chainType types.ChainType) error {
+	var expected types.ChainType
+	switch standard {
+	case StandardERC721, StandardERC1155:
+		expected = types.ChainTypeEVM
+	case StandardMetaplex:
+		expected = types.ChainTypeSolana
+	case StandardNEP171:
+		expected = types.ChainTypeNEAR
+	default:
+		return fmt.Errorf("unsupported NFT standard: %s", standard)
+	}
+
+	if chainType != expected {
+		return fmt.Errorf("NFT standard %s is not available on %s chains", standard, chainType)
+	}
+
+	return nil
+}
+
+// Resolve looks up the token URI on the source chain and fetches its metadata.
+// Failing to fetch the off-chain metadata is not an error; the token URI is
+// still returned so the destination can resolve it later.
+func (r *Resolver) Resolve(ctx context.Context, client types.UniversalClient, standard, contract, tokenID string) (*Metadata, error) {
+	var (
+		meta *Metadata
+		err  error
+	)
+
+	switch standard {
+	case StandardERC721:
+		meta, err = r.resolveEVM(ctx, client, "tokenURI", contract, tokenID)
+	case StandardERC1155:
+		meta, err = r.resolveEVM(ctx, client, "uri", contract, tokenID)
+	case StandardMetaplex:
+		meta, err = r.resolveMetaplex(ctx, client, contract)
+	case StandardNEP171:
+		meta, err = r.resolveNEP171(ctx, client, contract, tokenID)
+	default:
+		return nil, fmt.Errorf("unsupported NFT standard: %s", standard)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(meta.Raw) == 0 && meta.TokenURI != "" {
+		raw, err := r.fetchMetadata(ctx, meta.TokenURI)
+		if err != nil {
+			r.logger.Warn().
+				Err(err).
+				Str("token_uri", meta.TokenURI).
+				Msg("Failed to fetch NFT metadata")
+		} else {
+			meta.Raw = raw
+		}
+	}
+
+	if meta.Name == "" && len(meta.Raw) > 0 {
+		var fields struct {
+			Name  string `json:"name"`
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(meta.Raw, &fields); err == nil {
+			meta.Name = fields.Name
+			if meta.Name == "" {
+				meta.Name = fields.Title
+			}
+		}
+	}
+
+	return meta, nil
+}
+
+// resolveEVM calls tokenURI (ERC721) or uri (ERC1155) on the contract
+func (r *Resolver) resolveEVM(ctx context.Context, client types.UniversalClient, method, contract, tokenID string) (*Metadata, error) {
+	caller, ok := client.(contractCaller)
+	if !ok {
+		return nil, fmt.Errorf("client does not support contract calls")
+	}
+
+	if !common.IsHexAddress(contract) {
+		return nil, fmt.Errorf("invalid contract address: %s", contract)
+	}
+
+	id, err := parseTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.abi.Pack(method, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+
+	to := common.HexToAddress(contract)
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	values, err := r.abi.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected %s result", method)
+	}
+
+	uri, ok := values[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s result type", method)
+	}
+
+	// ERC1155 clients must substitute {id} with the lowercase, zero-padded hex token ID
+	if method == "uri" {
+		uri = strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+	}
+
+	return &Metadata{TokenURI: uri}, nil
+}
+
+// resolveMetaplex reads the Metaplex metadata account for a mint
+func (r *Resolver) resolveMetaplex(ctx context.Context, client types.UniversalClient, mint string) (*Metadata, error) {
+	reader, ok := client.(accountReader)
+	if !ok {
+		return nil, fmt.Errorf("client does not support account reads")
+	}
+
+	mintKey, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mint address: %w", err)
+	}
+
+	metadataAccount, _, err := solana.FindTokenMetadataAddress(mintKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive metadata account: %w", err)
+	}
+
+	data, err := reader.GetAccountData(ctx, metadataAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata account: %w", err)
+	}
+
+	return decodeMetaplexMetadata(data)
+}
+
+// decodeMetaplexMetadata decodes the Borsh-encoded head of a Metaplex
+// metadata account: key, update authority, mint, name, symbol and uri
+func decodeMetaplexMetadata(data []byte) (*Metadata, error) {
+	offset := 1 + 32 + 32 // key + update_authority + mint
+	if len(data) < offset {
+		return nil, fmt.Errorf("metadata account too short")
+	}
+
+	readString := func() (string, error) {
+		if len(data) < offset+4 {
+			return "", fmt.Errorf("metadata account truncated")
+		}
+		length := int(binary.LittleEndian.Uint32(data[offset:]))
+		offset += 4
+		if length < 0 || len(data) < offset+length {
+			return "", fmt.Errorf("metadata account truncated")
+		}
+		value := string(data[offset : offset+length])
+		offset += length
+		// Metaplex pads fixed-size fields with null bytes
+		return strings.TrimRight(value, "\x00"), nil
+	}
+
+	name, err := readString()
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := readString()
+	if err != nil {
+		return nil, err
+	}
+	uri, err := readString()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		TokenURI: uri,
+		Name:     name,
+		Symbol:   symbol,
+	}, nil
+}
+
+// nep171Token is the JSON returned by nft_token
+type nep171Token struct {
+	TokenID  string          `json:"token_id"`
+	OwnerID  string          `json:"owner_id"`
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// nep177TokenMetadata is the subset of NEP-177 token metadata used to build a URI
+type nep177TokenMetadata struct {
+	Title     string `json:"title"`
+	Media     string `json:"media"`
+	Reference string `json:"reference"`
+}
+
+// resolveNEP171 calls nft_token and builds the token URI from its NEP-177 metadata
+func (r *Resolver) resolveNEP171(ctx context.Context, client types.UniversalClient, contract, tokenID string) (*Metadata, error) {
+	caller, ok := client.(viewCaller)
+	if !ok {
+		return nil, fmt.Errorf("client does not support view calls")
+	}
+
+	result, err := callNEARView(ctx, caller, contract, "nft_token", map[string]string{"token_id": tokenID})
+	if err != nil {
+		return nil, err
+	}
+
+	if string(result) == "null" {
+		return nil, fmt.Errorf("token %s not found on %s", tokenID, contract)
+	}
+
+	var token nep171Token
+	if err := json.Unmarshal(result, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode nft_token result: %w", err)
+	}
+
+	meta := &Metadata{}
+	if len(token.Metadata) == 0 || string(token.Metadata) == "null" {
+		return meta, nil
+	}
+
+	var tokenMeta nep177TokenMetadata
+	if err := json.Unmarshal(token.Metadata, &tokenMeta); err != nil {
+		return nil, fmt.Errorf("failed to decode token metadata: %w", err)
+	}
+	meta.Name = tokenMeta.Title
+
+	uri := tokenMeta.Reference
+	if uri == "" {
+		uri = tokenMeta.Media
+	}
+
+	// Relative references are resolved against the contract's base_uri
+	if uri != "" && !strings.Contains(uri, "://") && !strings.HasPrefix(uri, "data:") {
+		contractMeta, err := callNEARView(ctx, caller, contract, "nft_metadata", map[string]string{})
+		if err == nil {
+			var fields struct {
+				Symbol  string `json:"symbol"`
+				BaseURI string `json:"base_uri"`
+			}
+			if err := json.Unmarshal(contractMeta, &fields); err == nil {
+				meta.Symbol = fields.Symbol
+				if fields.BaseURI != "" {
+					uri = strings.TrimRight(fields.BaseURI, "/") + "/" + strings.TrimLeft(uri, "/")
+				}
+			}
+		}
+	}
+	meta.TokenURI = uri
+
+	// Without an off-chain reference the on-chain metadata is authoritative
+	if tokenMeta.Reference == "" {
+		meta.Raw = token.Metadata
+	}
+
+	return meta, nil
+}
+
+// callNEARView calls a view function and decodes the byte array result
+func callNEARView(ctx context.Context, caller viewCaller, contract, method string, args interface{}) (json.RawMessage, error) {
+	raw, err := caller.ViewFunction(ctx, contract, method, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	var response struct {
+		Bytes []int  `json:"result"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s failed: %s", method, response.Error)
+	}
+
+	result := make([]byte, len(response.Bytes))
+	for i, b := range response.Bytes {
+		result[i] = byte(b)
+	}
+
+	return result, nil
+}
+
+// fetchMetadata fetches and validates the JSON document behind a token URI
+func (r *Resolver) fetchMetadata(ctx context.Context, uri string) (json.RawMessage, error) {
+	if strings.HasPrefix(uri, "data:") {
+		return decodeDataURI(uri)
+	}
+
+	target, err := r.gatewayURL(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Articium-NFT-Resolver/1.0")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	if len(body) > maxMetadataSize {
+		return nil, fmt.Errorf("metadata exceeds %d bytes", maxMetadataSize)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("metadata is not valid JSON")
+	}
+
+	return body, nil
+}
+
+// gatewayURL rewrites ipfs:// and ar:// URIs to HTTP gateway URLs
+func (r *Resolver) gatewayURL(uri string) (string, error) {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return r.ipfsGateway + path, nil
+	case strings.HasPrefix(uri, "ar://"):
+		return r.arweaveGateway + strings.TrimPrefix(uri, "ar://"), nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid token URI: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("unsupported token URI scheme: %s", parsed.Scheme)
+	}
+
+	return uri, nil
+}
+
+// decodeDataURI decodes an inline data:application/json URI
+func decodeDataURI(uri string) (json.RawMessage, error) {
+	header, data, found := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !found {
+		return nil, fmt.Errorf("malformed data URI")
+	}
+
+	var body []byte
+	if strings.HasSuffix(header, ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode data URI: %w", err)
+		}
+		body = decoded
+	} else {
+		unescaped, err := url.PathUnescape(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode data URI: %w", err)
+		}
+		body = []byte(unescaped)
+	}
+
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("data URI is not valid JSON")
+	}
+
+	return body, nil
+}
+
+// parseTokenID parses a decimal or 0x-prefixed hex token ID
+func parseTokenID(tokenID string) (*big.Int, error) {
+	base := 10
+	if strings.HasPrefix(tokenID, "0x") || strings.HasPrefix(tokenID, "0X") {
+		tokenID, base = tokenID[2:], 16
+	}
+
+	id, ok := new(big.Int).SetString(tokenID, base)
+	if !ok || id.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token ID: %s", tokenID)
+	}
+	return id, nil
+}
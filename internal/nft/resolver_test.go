@@ -0,0 +1,210 @@
+package nft
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// borshString encodes a string the way Metaplex stores fixed-size fields
+func borshString(value string, size int) []byte {
+	padded := value + strings.Repeat("\x00", size-len(value))
+	out := make([]byte, 4, 4+len(padded))
+	binary.LittleEndian.PutUint32(out, uint32(len(padded)))
+	return append(out, padded...)
+}
+
+func TestDecodeMetaplexMetadata(t *testing.T) {
+	data := make([]byte, 1+32+32)
+	data = append(data, borshString("Articium Ape", 32)...)
+	data = append(data, borshString("APE", 10)...)
+	data = append(data, borshString("https://example.com/ape.json", 200)...)
+
+	meta, err := decodeMetaplexMetadata(data)
+	if err != nil {
+		t.Fatalf("decodeMetaplexMetadata failed: %v", err)
+	}
+	if meta.Name != "Articium Ape" || meta.Symbol != "APE" {
+		t.Errorf("Unexpected name/symbol: %q/%q", meta.Name, meta.Symbol)
+	}
+	if meta.TokenURI != "https://example.com/ape.json" {
+		t.Errorf("Unexpected token URI: %q", meta.TokenURI)
+	}
+
+	if _, err := decodeMetaplexMetadata(data[:40]); err == nil {
+		t.Error("Expected error for account shorter than the header")
+	}
+	if _, err := decodeMetaplexMetadata(data[:1+32+32+10]); err == nil {
+		t.Error("Expected error for truncated name")
+	}
+
+	// A length prefix pointing past the end of the account must not panic
+	corrupt := append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(corrupt[1+32+32:], 0xFFFFFFFF)
+	if _, err := decodeMetaplexMetadata(corrupt); err == nil {
+		t.Error("Expected error for oversized length prefix")
+	}
+}
+
+func TestParseTokenID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"42", "42", true},
+		{"0x2a", "42", true},
+		{"0X2A", "42", true},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", "115792089237316195423570985008687907853269984665640564039457584007913129639935", true},
+		{"-1", "", false},
+		{"0xzz", "", false},
+		{"abc", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		id, err := parseTokenID(tt.input)
+		if tt.ok {
+			if err != nil {
+				t.Errorf("parseTokenID(%q) failed: %v", tt.input, err)
+				continue
+			}
+			if id.String() != tt.want {
+				t.Errorf("parseTokenID(%q) = %s, want %s", tt.input, id, tt.want)
+			}
+		} else if err == nil {
+			t.Errorf("parseTokenID(%q) expected error", tt.input)
+		}
+	}
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	body := `{"name":"Inline"}`
+
+	raw, err := decodeDataURI("data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(body)))
+	if err != nil || string(raw) != body {
+		t.Errorf("Base64 data URI = %q, %v", raw, err)
+	}
+
+	raw, err = decodeDataURI(`data:application/json,%7B%22name%22%3A%22Inline%22%7D`)
+	if err != nil || string(raw) != body {
+		t.Errorf("Percent-encoded data URI = %q, %v", raw, err)
+	}
+
+	for _, uri := range []string{
+		"data:application/json",
+		"data:application/json;base64,!!!",
+		"data:application/json,not-json",
+	} {
+		if _, err := decodeDataURI(uri); err == nil {
+			t.Errorf("decodeDataURI(%q) expected error", uri)
+		}
+	}
+}
+
+func TestGatewayURL(t *testing.T) {
+	r := NewResolver(zerolog.Nop())
+
+	tests := map[string]string{
+		"ipfs://QmHash/1.json":      DefaultIPFSGateway + "QmHash/1.json",
+		"ipfs://ipfs/QmHash/1.json": DefaultIPFSGateway + "QmHash/1.json",
+		"ar://TxID":                 DefaultArweaveGateway + "TxID",
+		"https://example.com/1":     "https://example.com/1",
+		"http://example.com/1":      "http://example.com/1",
+	}
+	for uri, want := range tests {
+		got, err := r.gatewayURL(uri)
+		if err != nil || got != want {
+			t.Errorf("gatewayURL(%q) = %q, %v; want %q", uri, got, err, want)
+		}
+	}
+
+	for _, uri := range []string{"file:///etc/passwd", "gopher://example.com", "ftp://example.com/1"} {
+		if _, err := r.gatewayURL(uri); err == nil {
+			t.Errorf("gatewayURL(%q) expected error", uri)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"::ffff:127.0.0.1": false,
+		"224.0.0.1":        false,
+	}
+
+	for addr, want := range tests {
+		if got := isPublicIP(net.ParseIP(addr)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestFetchMetadata_RejectsInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"secret":"internal"}`))
+	}))
+	defer server.Close()
+
+	r := NewResolver(zerolog.Nop())
+	if _, err := r.fetchMetadata(context.Background(), server.URL); err == nil {
+		t.Fatal("Expected fetch from a loopback address to be rejected")
+	}
+
+	if err := rejectNonPublicAddress("tcp", "169.254.169.254:80", nil); err == nil {
+		t.Error("Expected cloud metadata address to be rejected")
+	}
+	if err := rejectNonPublicAddress("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("Expected public address to be allowed: %v", err)
+	}
+}
+
+func TestFetchMetadata_ValidatesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/ok.json":
+			_, _ = w.Write([]byte(`{"name":"Token"}`))
+		case "/invalid.json":
+			_, _ = w.Write([]byte(`<html></html>`))
+		case "/large.json":
+			_, _ = w.Write([]byte(`"` + strings.Repeat("a", maxMetadataSize) + `"`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	// Swap in an unrestricted client so the loopback test server is reachable
+	r := NewResolver(zerolog.Nop())
+	r.httpClient = server.Client()
+
+	raw, err := r.fetchMetadata(context.Background(), server.URL+"/ok.json")
+	if err != nil || string(raw) != `{"name":"Token"}` {
+		t.Errorf("fetchMetadata = %q, %v", raw, err)
+	}
+
+	for _, path := range []string{"/invalid.json", "/large.json", "/missing.json"} {
+		if _, err := r.fetchMetadata(context.Background(), server.URL+path); err == nil {
+			t.Errorf("fetchMetadata(%s) expected error", path)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
+	"github.com/EmekaIwuagwu/articium-hub/internal/queue"
+	"github.com/rs/zerolog"
+)
+
+// Config configures the outbox publisher
+type Config struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	Lease          time.Duration
+	MaxBackoff     time.Duration
+	Retention      time.Duration
+	PurgeInterval  time.Duration
+	PublishTimeout time.Duration
+}
+
+// DefaultConfig returns the default publisher configuration
+func DefaultConfig() *Config {
+	return &Config{
+		PollInterval:   time.Second,
+		BatchSize:      100,
+		Lease:          30 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Retention:      24 * time.Hour,
+		PurgeInterval:  time.Hour,
+		PublishTimeout: 10 * time.Second,
+	}
+}
+
+// Store is the outbox storage used by the publisher
+type Store interface {
+	ClaimOutboxEntries(ctx context.Context, limit int, lease time.Duration) ([]*database.OutboxEntry, error)
+	MarkOutboxPublished(ctx context.Context, id int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, errMsg string, retryAfter time.Duration) error
+	GetOutboxBacklog(ctx context.Context) (int64, error)
+	PurgePublishedOutbox(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// Publisher drains the message outbox to the queue with at-least-once
+// semantics. Any number of publishers may run against the same database;
+// entries are claimed with SKIP LOCKED and leased while being published.
+type Publisher struct {
+	config *Config
+	store  Store
+	queue  queue.Queue
+	logger zerolog.Logger
+	notify chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+// NewPublisher creates a new outbox publisher
+func NewPublisher(config *Config, store Store, q queue.Queue, logger zerolog.Logger) *Publisher {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Publisher{
+		config: config,
+		store:  store,
+		queue:  q,
+		logger: logger.With().Str("component", "outbox-publisher").Logger(),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Start starts the publisher loop
+func (p *Publisher) Start(ctx context.Context) error {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.logger.Info().
+		Dur("poll_interval", p.config.PollInterval).
+		Int("batch_size", p.config.BatchSize).
+		Msg("Starting outbox publisher")
+
+	p.wg.Add(1)
+	go p.run(ctx)
+
+	return nil
+}
+
+// Stop stops the publisher and waits for the current batch to finish
+func (p *Publisher) Stop() error {
+	p.logger.Info().Msg("Stopping outbox publisher")
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	return nil
+}
+
+// Notify wakes the publisher so a newly written entry is published without
+// waiting for the next poll. It never blocks.
+func (p *Publisher) Notify() {
+	if p == nil {
+		return
+	}
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run is the main publisher loop
+func (p *Publisher) run(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+
+	purgeTicker := time.NewTicker(p.config.PurgeInterval)
+	defer purgeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.drain(ctx)
+		case <-p.notify:
+			p.drain(ctx)
+		case <-purgeTicker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+// drain publishes batches until the outbox has no available entries
+func (p *Publisher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := p.PublishBatch(ctx)
+		if err != nil {
+			p.logger.Error().Err(err).Msg("Failed to publish outbox batch")
+			return
+		}
+		if published < p.config.BatchSize {
+			break
+		}
+	}
+
+	if backlog, err := p.store.GetOutboxBacklog(ctx); err == nil {
+		monitoring.OutboxBacklog.Set(float64(backlog))
+	}
+}
+
+// PublishBatch claims and publishes one batch of outbox entries. It returns
+// the number of entries claimed.
+func (p *Publisher) PublishBatch(ctx context.Context) (int, error) {
+	entries, err := p.store.ClaimOutboxEntries(ctx, p.config.BatchSize, p.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		publishCtx, cancel := context.WithTimeout(ctx, p.config.PublishTimeout)
+		err := p.queue.Publish(publishCtx, entry.Message)
+		cancel()
+
+		if err != nil {
+			backoff := p.backoff(entry.Attempts)
+			monitoring.OutboxPublishFailures.Inc()
+			p.logger.Warn().
+				Err(err).
+				Str("message_id", entry.MessageID).
+				Int("attempts", entry.Attempts).
+				Dur("retry_in", backoff).
+				Msg("Failed to publish outbox entry")
+
+			if markErr := p.store.MarkOutboxFailed(ctx, entry.ID, err.Error(), backoff); markErr != nil {
+				p.logger.Error().Err(markErr).Int64("outbox_id", entry.ID).Msg("Failed to record outbox failure")
+			}
+			continue
+		}
+
+		// If this fails the entry is re-published once its lease expires;
+		// consumers must tolerate duplicates
+		if err := p.store.MarkOutboxPublished(ctx, entry.ID); err != nil {
+			p.logger.Error().Err(err).Int64("outbox_id", entry.ID).Msg("Failed to mark outbox entry published")
+			continue
+		}
+
+		monitoring.OutboxPublished.Inc()
+		monitoring.OutboxPublishLatency.Observe(time.Since(entry.CreatedAt).Seconds())
+
+		p.logger.Debug().
+			Str("message_id", entry.MessageID).
+			Int("attempts", entry.Attempts).
+			Msg("Outbox entry published")
+	}
+
+	return len(entries), nil
+}
+
+// backoff returns an exponential retry delay capped at MaxBackoff
+func (p *Publisher) backoff(attempts int) time.Duration {
+	delay := p.config.PollInterval
+	for i := 1; i < attempts && delay < p.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.config.MaxBackoff {
+		delay = p.config.MaxBackoff
+	}
+	return delay
+}
+
+// purge removes published entries older than the retention period
+func (p *Publisher) purge(ctx context.Context) {
+	deleted, err := p.store.PurgePublishedOutbox(ctx, p.config.Retention)
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("Failed to purge published outbox entries")
+		return
+	}
+
+	if deleted > 0 {
+		p.logger.Info().Int64("deleted", deleted).Msg("Purged published outbox entries")
+	}
+}
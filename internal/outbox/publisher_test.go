@@ -0,0 +1,326 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/queue"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+// fakeEntry is an outbox row in fakeStore
+type fakeEntry struct {
+	entry       database.OutboxEntry
+	availableAt time.Time
+	publishedAt time.Time
+	lastError   string
+}
+
+// fakeStore mirrors the claim, lease and retry semantics of the Postgres
+// outbox against a manual clock
+type fakeStore struct {
+	mu      sync.Mutex
+	now     time.Time
+	nextID  int64
+	entries map[int64]*fakeEntry
+
+	failMarkPublished int
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		now:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		entries: make(map[int64]*fakeEntry),
+	}
+}
+
+func (s *fakeStore) add(messageID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	s.entries[s.nextID] = &fakeEntry{
+		entry: database.OutboxEntry{
+			ID:        s.nextID,
+			MessageID: messageID,
+			Message:   &types.CrossChainMessage{ID: messageID},
+			CreatedAt: s.now,
+		},
+		availableAt: s.now,
+	}
+	return s.nextID
+}
+
+func (s *fakeStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+func (s *fakeStore) get(id int64) fakeEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.entries[id]
+}
+
+func (s *fakeStore) ClaimOutboxEntries(ctx context.Context, limit int, lease time.Duration) ([]*database.OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(s.entries))
+	for id := range s.entries {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var claimed []*database.OutboxEntry
+	for _, id := range ids {
+		e := s.entries[id]
+		if !e.publishedAt.IsZero() || e.availableAt.After(s.now) {
+			continue
+		}
+		if len(claimed) == limit {
+			break
+		}
+		e.entry.Attempts++
+		e.availableAt = s.now.Add(lease)
+		entry := e.entry
+		claimed = append(claimed, &entry)
+	}
+	return claimed, nil
+}
+
+func (s *fakeStore) MarkOutboxPublished(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failMarkPublished > 0 {
+		s.failMarkPublished--
+		return errors.New("connection reset")
+	}
+	s.entries[id].publishedAt = s.now
+	s.entries[id].lastError = ""
+	return nil
+}
+
+func (s *fakeStore) MarkOutboxFailed(ctx context.Context, id int64, errMsg string, retryAfter time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[id].lastError = errMsg
+	s.entries[id].availableAt = s.now.Add(retryAfter)
+	return nil
+}
+
+func (s *fakeStore) GetOutboxBacklog(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, e := range s.entries {
+		if e.publishedAt.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *fakeStore) PurgePublishedOutbox(ctx context.Context, retention time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, e := range s.entries {
+		if !e.publishedAt.IsZero() && e.publishedAt.Before(s.now.Add(-retention)) {
+			delete(s.entries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// fakeQueue records published message IDs and fails the first failures publishes
+type fakeQueue struct {
+	mu        sync.Mutex
+	published []string
+	failures  int
+}
+
+func (q *fakeQueue) Publish(ctx context.Context, msg *types.CrossChainMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.failures > 0 {
+		q.failures--
+		return errors.New("queue unavailable")
+	}
+	q.published = append(q.published, msg.ID)
+	return nil
+}
+
+func (q *fakeQueue) Subscribe(ctx context.Context, handler queue.MessageHandler) error {
+	return nil
+}
+
+func (q *fakeQueue) Close() error {
+	return nil
+}
+
+func testConfig() *Config {
+	config := DefaultConfig()
+	config.BatchSize = 2
+	config.PollInterval = time.Second
+	config.MaxBackoff = 8 * time.Second
+	return config
+}
+
+func TestPublishBatch_ClaimsInOrder(t *testing.T) {
+	store := newFakeStore()
+	q := &fakeQueue{}
+	p := NewPublisher(testConfig(), store, q, zerolog.Nop())
+
+	for i := 1; i <= 3; i++ {
+		store.add(fmt.Sprintf("msg-%d", i))
+	}
+
+	for _, want := range []int{2, 1, 0} {
+		claimed, err := p.PublishBatch(context.Background())
+		if err != nil {
+			t.Fatalf("PublishBatch failed: %v", err)
+		}
+		if claimed != want {
+			t.Fatalf("Expected %d entries claimed, got %d", want, claimed)
+		}
+	}
+
+	if fmt.Sprint(q.published) != "[msg-1 msg-2 msg-3]" {
+		t.Errorf("Unexpected publish order: %v", q.published)
+	}
+	if backlog, _ := store.GetOutboxBacklog(context.Background()); backlog != 0 {
+		t.Errorf("Expected empty backlog, got %d", backlog)
+	}
+}
+
+func TestPublishBatch_LeaseExpiry(t *testing.T) {
+	store := newFakeStore()
+	q := &fakeQueue{}
+	config := testConfig()
+	p := NewPublisher(config, store, q, zerolog.Nop())
+
+	id := store.add("msg-1")
+
+	// The publish succeeds but recording it fails, so the entry stays leased
+	store.failMarkPublished = 1
+	if _, err := p.PublishBatch(context.Background()); err != nil {
+		t.Fatalf("PublishBatch failed: %v", err)
+	}
+
+	// Other publishers must not see the entry while the lease is held
+	store.advance(config.Lease - time.Millisecond)
+	if claimed, _ := p.PublishBatch(context.Background()); claimed != 0 {
+		t.Fatalf("Expected leased entry to be hidden, got %d claimed", claimed)
+	}
+
+	// Once the lease expires the entry is published again
+	store.advance(time.Millisecond)
+	if claimed, _ := p.PublishBatch(context.Background()); claimed != 1 {
+		t.Fatalf("Expected expired lease to be reclaimed, got %d claimed", claimed)
+	}
+
+	if len(q.published) != 2 {
+		t.Errorf("Expected at-least-once redelivery, got %v", q.published)
+	}
+	entry := store.get(id)
+	if entry.publishedAt.IsZero() || entry.entry.Attempts != 2 {
+		t.Errorf("Expected entry published on attempt 2, got attempts=%d published=%v", entry.entry.Attempts, entry.publishedAt)
+	}
+}
+
+func TestPublishBatch_RetryBackoff(t *testing.T) {
+	store := newFakeStore()
+	q := &fakeQueue{failures: 2}
+	config := testConfig()
+	p := NewPublisher(config, store, q, zerolog.Nop())
+
+	id := store.add("msg-1")
+
+	// First failure retries after one poll interval
+	_, _ = p.PublishBatch(context.Background())
+	entry := store.get(id)
+	if entry.lastError == "" {
+		t.Fatal("Expected publish failure to be recorded")
+	}
+	if got := entry.availableAt.Sub(store.now); got != config.PollInterval {
+		t.Fatalf("Expected first retry after %s, got %s", config.PollInterval, got)
+	}
+
+	store.advance(config.PollInterval - time.Millisecond)
+	if claimed, _ := p.PublishBatch(context.Background()); claimed != 0 {
+		t.Fatal("Expected entry to wait out its backoff")
+	}
+
+	// Second failure doubles the delay
+	store.advance(time.Millisecond)
+	_, _ = p.PublishBatch(context.Background())
+	entry = store.get(id)
+	if got := entry.availableAt.Sub(store.now); got != 2*config.PollInterval {
+		t.Fatalf("Expected second retry after %s, got %s", 2*config.PollInterval, got)
+	}
+
+	store.advance(2 * config.PollInterval)
+	_, _ = p.PublishBatch(context.Background())
+	entry = store.get(id)
+	if entry.publishedAt.IsZero() || entry.lastError != "" {
+		t.Errorf("Expected entry published after retries, got error %q", entry.lastError)
+	}
+	if entry.entry.Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", entry.entry.Attempts)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	p := NewPublisher(testConfig(), newFakeStore(), &fakeQueue{}, zerolog.Nop())
+
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second}
+	for attempts, expected := range want {
+		if got := p.backoff(attempts); got != expected {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, expected)
+		}
+	}
+}
+
+func TestPurgeRemovesOnlyExpiredPublishedEntries(t *testing.T) {
+	store := newFakeStore()
+	config := testConfig()
+	p := NewPublisher(config, store, &fakeQueue{}, zerolog.Nop())
+
+	old := store.add("msg-old")
+	_, _ = p.PublishBatch(context.Background())
+
+	store.advance(config.Retention)
+	recent := store.add("msg-recent")
+	_, _ = p.PublishBatch(context.Background())
+	pending := store.add("msg-pending")
+
+	store.advance(time.Second)
+	p.purge(context.Background())
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, ok := store.entries[old]; ok {
+		t.Error("Expected entry published before the retention period to be purged")
+	}
+	if _, ok := store.entries[recent]; !ok {
+		t.Error("Expected recently published entry to be kept")
+	}
+	if _, ok := store.entries[pending]; !ok {
+		t.Error("Expected unpublished entry to be kept")
+	}
+}
@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// primaryRetryInterval is how long the fallback is used after the primary
+// store fails before the primary is tried again
+const primaryRetryInterval = 30 * time.Second
+
+// FallbackStore uses a primary store, switching to a secondary store while
+// the primary is failing
+type FallbackStore struct {
+	primary    Store
+	secondary  Store
+	logger     zerolog.Logger
+	retryAfter atomic.Int64 // unix nanos until which the primary is skipped
+}
+
+// NewFallbackStore creates a store that falls back to secondary when
+// primary fails
+func NewFallbackStore(primary, secondary Store, logger zerolog.Logger) *FallbackStore {
+	return &FallbackStore{
+		primary:   primary,
+		secondary: secondary,
+		logger:    logger.With().Str("component", "ratelimit").Logger(),
+	}
+}
+
+// Allow implements Store
+func (s *FallbackStore) Allow(ctx context.Context, subject string, class Class, limit Limit) (*Result, error) {
+	if s.primaryAvailable() {
+		res, err := s.primary.Allow(ctx, subject, class, limit)
+		if err == nil {
+			return res, nil
+		}
+		s.primaryFailed(err)
+	}
+
+	RecordFallback()
+	return s.secondary.Allow(ctx, subject, class, limit)
+}
+
+// Usage implements Store. Counts from both stores are added together, since
+// requests may have been counted by either.
+func (s *FallbackStore) Usage(ctx context.Context, subject string, since time.Time) ([]Usage, error) {
+	primary, primaryErr := s.primary.Usage(ctx, subject, since)
+	secondary, secondaryErr := s.secondary.Usage(ctx, subject, since)
+	if primaryErr != nil && secondaryErr != nil {
+		return nil, primaryErr
+	}
+	if primaryErr != nil {
+		s.logger.Warn().Err(primaryErr).Msg("Failed to read usage from primary rate limit store")
+	}
+	if secondaryErr != nil {
+		s.logger.Warn().Err(secondaryErr).Msg("Failed to read usage from fallback rate limit store")
+	}
+
+	return mergeUsage(primary, secondary), nil
+}
+
+func (s *FallbackStore) primaryAvailable() bool {
+	return time.Now().UnixNano() >= s.retryAfter.Load()
+}
+
+func (s *FallbackStore) primaryFailed(err error) {
+	s.retryAfter.Store(time.Now().Add(primaryRetryInterval).UnixNano())
+	s.logger.Warn().
+		Err(err).
+		Dur("retry_in", primaryRetryInterval).
+		Msg("Primary rate limit store failed, using fallback")
+}
+
+// mergeUsage adds up usage windows from several stores
+func mergeUsage(sets ...[]Usage) []Usage {
+	merged := make(map[usageKey]*Usage)
+	for _, set := range sets {
+		for _, u := range set {
+			key := usageKey{class: u.Class, start: u.WindowStart.UTC()}
+			if m, ok := merged[key]; ok {
+				m.Allowed += u.Allowed
+				m.Rejected += u.Rejected
+				continue
+			}
+			copied := u
+			copied.WindowStart = key.start
+			merged[key] = &copied
+		}
+	}
+
+	usage := make([]Usage, 0, len(merged))
+	for _, u := range merged {
+		usage = append(usage, *u)
+	}
+	sortUsage(usage)
+	return usage
+}
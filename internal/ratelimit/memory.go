@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxMemoryBuckets bounds the memory store before idle buckets are pruned
+const maxMemoryBuckets = 100000
+
+// MemoryStore keeps rate limit state in process. Limits are not shared
+// between replicas, so it is only for development and tests.
+type MemoryStore struct {
+	buckets map[string]int64
+	usage   map[string]map[usageKey]*Usage
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+type usageKey struct {
+	class Class
+	start time.Time
+}
+
+// NewMemoryStore creates an in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]int64),
+		usage:   make(map[string]map[usageKey]*Usage),
+		now:     time.Now,
+	}
+}
+
+// Allow implements Store
+func (s *MemoryStore) Allow(ctx context.Context, subject string, class Class, limit Limit) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	nowMicros := now.UnixMicro()
+	if len(s.buckets) >= maxMemoryBuckets {
+		s.prune(nowMicros)
+	}
+
+	key := bucketKey(subject, class)
+	tat, res := gcra(nowMicros, s.buckets[key], limit)
+	s.buckets[key] = tat
+
+	s.record(subject, class, now, res.Allowed)
+	return res, nil
+}
+
+// Usage implements Store
+func (s *MemoryStore) Usage(ctx context.Context, subject string, since time.Time) ([]Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since = windowStart(since)
+	usage := []Usage{}
+	for key, u := range s.usage[subject] {
+		if !key.start.Before(since) {
+			usage = append(usage, *u)
+		}
+	}
+
+	sortUsage(usage)
+	return usage, nil
+}
+
+func (s *MemoryStore) record(subject string, class Class, now time.Time, allowed bool) {
+	windows, ok := s.usage[subject]
+	if !ok {
+		windows = make(map[usageKey]*Usage)
+		s.usage[subject] = windows
+	}
+
+	key := usageKey{class: class, start: windowStart(now)}
+	u, ok := windows[key]
+	if !ok {
+		u = &Usage{Class: class, WindowStart: key.start}
+		windows[key] = u
+	}
+
+	if allowed {
+		u.Allowed++
+	} else {
+		u.Rejected++
+	}
+}
+
+// prune drops buckets that have fully drained and expired usage windows
+func (s *MemoryStore) prune(nowMicros int64) {
+	for key, tat := range s.buckets {
+		if tat <= nowMicros {
+			delete(s.buckets, key)
+		}
+	}
+
+	cutoff := windowStart(time.UnixMicro(nowMicros).Add(-usageRetention))
+	for subject, windows := range s.usage {
+		for key := range windows {
+			if key.start.Before(cutoff) {
+				delete(windows, key)
+			}
+		}
+		if len(windows) == 0 {
+			delete(s.usage, subject)
+		}
+	}
+}
+
+// sortUsage orders usage by window, then class
+func sortUsage(usage []Usage) {
+	sort.Slice(usage, func(i, j int) bool {
+		if !usage[i].WindowStart.Equal(usage[j].WindowStart) {
+			return usage[i].WindowStart.Before(usage[j].WindowStart)
+		}
+		return usage[i].Class < usage[j].Class
+	})
+}
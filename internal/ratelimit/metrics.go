@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// RateLimitDecisions counts rate limit checks by class and outcome
+	RateLimitDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_api_rate_limit_decisions_total",
+			Help: "API rate limit checks by route class and outcome",
+		},
+		[]string{"class", "outcome"},
+	)
+
+	// RateLimitFallbacks counts checks served by the fallback store
+	RateLimitFallbacks = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bridge_api_rate_limit_fallbacks_total",
+		Help: "API rate limit checks served by the fallback store",
+	})
+
+	// RateLimitErrors counts checks that failed in every store and were let
+	// through
+	RateLimitErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bridge_api_rate_limit_errors_total",
+		Help: "API rate limit checks that failed and were allowed",
+	})
+)
+
+// RecordDecision records the outcome of a rate limit check
+func RecordDecision(class Class, allowed bool) {
+	outcome := "allowed"
+	if !allowed {
+		outcome = "rejected"
+	}
+	RateLimitDecisions.WithLabelValues(string(class), outcome).Inc()
+}
+
+// RecordFallback records a check served by the fallback store
+func RecordFallback() {
+	RateLimitFallbacks.Inc()
+}
+
+// RecordError records a failed check that was allowed
+func RecordError() {
+	RateLimitErrors.Inc()
+}
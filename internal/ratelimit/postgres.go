@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/rs/zerolog"
+)
+
+// pruneInterval is how often expired Postgres state is deleted
+const pruneInterval = time.Hour
+
+// PostgresStore keeps rate limit state in Postgres. It is slower than
+// Redis and meant as a fallback while Redis is unavailable.
+type PostgresStore struct {
+	db        *database.DB
+	logger    zerolog.Logger
+	lastPrune atomic.Int64
+}
+
+// NewPostgresStore creates a Postgres store
+func NewPostgresStore(db *database.DB, logger zerolog.Logger) *PostgresStore {
+	return &PostgresStore{
+		db:     db,
+		logger: logger.With().Str("component", "ratelimit-postgres").Logger(),
+	}
+}
+
+// Allow implements Store
+func (s *PostgresStore) Allow(ctx context.Context, subject string, class Class, limit Limit) (*Result, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	key := bucketKey(subject, class)
+
+	// Lock the bucket, creating it if needed, and read the database clock
+	// so replicas with skewed clocks agree
+	var tat, now int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO rate_limit_buckets (key, tat, expires_at)
+		VALUES ($1, 0, NOW())
+		ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+		RETURNING tat, (EXTRACT(EPOCH FROM clock_timestamp()) * 1000000)::BIGINT
+	`, key).Scan(&tat, &now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock rate limit bucket: %w", err)
+	}
+
+	newTAT, res := gcra(now, tat, limit)
+	if res.Allowed {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE rate_limit_buckets
+			SET tat = $2, expires_at = to_timestamp($2 / 1000000.0)
+			WHERE key = $1
+		`, key, newTAT)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update rate limit bucket: %w", err)
+		}
+	}
+
+	allowed, rejected := 0, 1
+	if res.Allowed {
+		allowed, rejected = 1, 0
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO rate_limit_usage (subject, class, window_start, allowed, rejected)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subject, class, window_start) DO UPDATE
+		SET allowed = rate_limit_usage.allowed + EXCLUDED.allowed,
+			rejected = rate_limit_usage.rejected + EXCLUDED.rejected
+	`, subject, class, windowStart(time.UnixMicro(now)), allowed, rejected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record rate limit usage: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rate limit check: %w", err)
+	}
+
+	s.maybePrune()
+	return res, nil
+}
+
+// Usage implements Store
+func (s *PostgresStore) Usage(ctx context.Context, subject string, since time.Time) ([]Usage, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT class, window_start, allowed, rejected
+		FROM rate_limit_usage
+		WHERE subject = $1 AND window_start >= $2
+		ORDER BY window_start ASC, class ASC
+	`, subject, windowStart(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rate limit usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []Usage{}
+	for rows.Next() {
+		var u Usage
+		if err := rows.Scan(&u.Class, &u.WindowStart, &u.Allowed, &u.Rejected); err != nil {
+			return nil, fmt.Errorf("failed to scan rate limit usage: %w", err)
+		}
+		u.WindowStart = u.WindowStart.UTC()
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rate limit usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// maybePrune prunes in the background if it has not run recently
+func (s *PostgresStore) maybePrune() {
+	last := s.lastPrune.Load()
+	now := time.Now().Unix()
+	if now-last < int64(pruneInterval.Seconds()) || !s.lastPrune.CompareAndSwap(last, now) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := s.prune(ctx); err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to prune rate limit state")
+		}
+	}()
+}
+
+// prune deletes drained buckets and usage older than the retention period
+func (s *PostgresStore) prune(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("failed to prune rate limit buckets: %w", err)
+	}
+
+	cutoff := windowStart(time.Now().Add(-usageRetention))
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_usage WHERE window_start < $1`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune rate limit usage: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package ratelimit implements GCRA rate limiting shared across API
+// replicas. State lives in Redis, with Postgres as a fallback, so a client
+// gets the same limit however many replicas serve it.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Class separates limits for read and write routes
+type Class string
+
+const (
+	ClassRead  Class = "read"
+	ClassWrite Class = "write"
+)
+
+// usageWindow is the granularity of usage counters
+const usageWindow = time.Hour
+
+// usageRetention is how long usage counters are kept
+const usageRetention = 7 * 24 * time.Hour
+
+// ClassForMethod returns the limit class of an HTTP method
+func ClassForMethod(method string) Class {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ClassRead
+	default:
+		return ClassWrite
+	}
+}
+
+// Limit allows PerMinute requests per minute on average, with up to Burst
+// requests at once. A Burst of 0 allows one minute's worth.
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+// interval is the time between requests at the sustained rate
+func (l Limit) interval() time.Duration {
+	return time.Minute / time.Duration(l.PerMinute)
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.PerMinute
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long to wait before a rejected request can succeed
+	RetryAfter time.Duration
+	// ResetAfter is how long until the full burst is available again
+	ResetAfter time.Duration
+}
+
+// Usage is the number of requests a subject made in one usage window
+type Usage struct {
+	Class       Class     `json:"class"`
+	WindowStart time.Time `json:"window_start"`
+	Allowed     int64     `json:"allowed"`
+	Rejected    int64     `json:"rejected"`
+}
+
+// Store checks and records requests against shared rate limit state
+type Store interface {
+	// Allow counts a request by subject against limit
+	Allow(ctx context.Context, subject string, class Class, limit Limit) (*Result, error)
+	// Usage returns the subject's request counts since a time
+	Usage(ctx context.Context, subject string, since time.Time) ([]Usage, error)
+}
+
+// gcra applies the generic cell rate algorithm. now and tat (the
+// theoretical arrival time) are in microseconds; the returned tat is the
+// value to store, unchanged if the request is rejected.
+func gcra(now, tat int64, limit Limit) (int64, *Result) {
+	interval := limit.interval().Microseconds()
+	burst := int64(limit.burst())
+
+	if tat < now {
+		tat = now
+	}
+	newTAT := tat + interval
+	allowAt := newTAT - interval*burst
+
+	res := &Result{Limit: int(burst)}
+	if now < allowAt {
+		res.RetryAfter = time.Duration(allowAt-now) * time.Microsecond
+		res.ResetAfter = time.Duration(tat-now) * time.Microsecond
+		return tat, res
+	}
+
+	res.Allowed = true
+	res.Remaining = int((now - allowAt) / interval)
+	res.ResetAfter = time.Duration(newTAT-now) * time.Microsecond
+	return newTAT, res
+}
+
+// bucketKey names the limiter state for a subject and class
+func bucketKey(subject string, class Class) string {
+	return subject + ":" + string(class)
+}
+
+// windowStart returns the start of the usage window containing t
+func windowStart(t time.Time) time.Time {
+	return t.UTC().Truncate(usageWindow)
+}
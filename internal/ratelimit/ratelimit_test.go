@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rs/zerolog"
+)
+
+func TestMemoryStoreBurstAndRefill(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	limit := Limit{PerMinute: 60, Burst: 3}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, err := store.Allow(ctx, "key:a", ClassRead, limit)
+		if err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+		if !res.Allowed {
+			t.Fatalf("request %d rejected within burst", i+1)
+		}
+		if res.Remaining != 2-i {
+			t.Errorf("request %d: expected %d remaining, got %d", i+1, 2-i, res.Remaining)
+		}
+	}
+
+	res, _ := store.Allow(ctx, "key:a", ClassRead, limit)
+	if res.Allowed {
+		t.Fatal("expected request beyond burst to be rejected")
+	}
+	if res.RetryAfter != time.Second {
+		t.Errorf("expected retry after 1s, got %v", res.RetryAfter)
+	}
+
+	// Writes are limited separately
+	if res, _ := store.Allow(ctx, "key:a", ClassWrite, limit); !res.Allowed {
+		t.Error("expected write to be allowed")
+	}
+
+	now = now.Add(time.Second)
+	if res, _ := store.Allow(ctx, "key:a", ClassRead, limit); !res.Allowed {
+		t.Error("expected request to be allowed after refill")
+	}
+
+	usage, err := store.Usage(ctx, "key:a", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("expected read and write usage, got %d entries", len(usage))
+	}
+	if usage[0].Class != ClassRead || usage[0].Allowed != 4 || usage[0].Rejected != 1 {
+		t.Errorf("unexpected read usage: %+v", usage[0])
+	}
+}
+
+type failingStore struct{ calls int }
+
+func (s *failingStore) Allow(ctx context.Context, subject string, class Class, limit Limit) (*Result, error) {
+	s.calls++
+	return nil, errors.New("connection refused")
+}
+
+func (s *failingStore) Usage(ctx context.Context, subject string, since time.Time) ([]Usage, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestFallbackStoreSkipsFailedPrimary(t *testing.T) {
+	primary := &failingStore{}
+	store := NewFallbackStore(primary, NewMemoryStore(), zerolog.Nop())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, err := store.Allow(ctx, "ip:10.0.0.1", ClassRead, Limit{PerMinute: 10})
+		if err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+		if !res.Allowed {
+			t.Fatal("expected request to be allowed by fallback")
+		}
+	}
+
+	if primary.calls != 1 {
+		t.Errorf("expected primary to be skipped after failing, got %d calls", primary.calls)
+	}
+}
+
+func TestRedisStoreBurstAndUsage(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := NewRedisStore(RedisConfig{Addr: server.Addr()})
+	defer store.Close()
+
+	limit := Limit{PerMinute: 60, Burst: 2}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		res, err := store.Allow(ctx, "key:a", ClassWrite, limit)
+		if err != nil {
+			t.Fatalf("Allow failed: %v", err)
+		}
+		if !res.Allowed || res.Remaining != 1-i {
+			t.Fatalf("request %d: expected allowed with %d remaining, got %+v", i+1, 1-i, res)
+		}
+	}
+
+	// The script is cached now, so this runs via EVALSHA
+	res, err := store.Allow(ctx, "key:a", ClassWrite, limit)
+	if err != nil {
+		t.Fatalf("Allow failed: %v", err)
+	}
+	if res.Allowed || res.RetryAfter <= 0 {
+		t.Errorf("expected request beyond burst to be rejected, got %+v", res)
+	}
+
+	usage, err := store.Usage(ctx, "key:a", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if len(usage) != 1 || usage[0].Class != ClassWrite || usage[0].Allowed != 2 || usage[0].Rejected != 1 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestRedisStoreUnavailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := NewRedisStore(RedisConfig{Addr: server.Addr(), Timeout: 100 * time.Millisecond})
+	defer store.Close()
+	server.Close()
+
+	if _, err := store.Allow(context.Background(), "key:a", ClassRead, Limit{PerMinute: 10}); err == nil {
+		t.Error("expected error when Redis is unreachable")
+	}
+}
@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig configures the Redis store
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// PoolSize is the maximum number of connections kept open
+	PoolSize int
+	// Timeout bounds dialing and each command
+	Timeout time.Duration
+}
+
+// gcraScript applies GCRA atomically in Redis and counts the request in
+// the subject's usage window. Redis's own clock is used so replicas with
+// skewed clocks agree. Keys share the subject as a hash tag so they live in
+// the same cluster slot.
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local usage_ttl = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then tat = now end
+local new_tat = tat + interval
+local allow_at = new_tat - interval * burst
+local result
+if now < allow_at then
+  redis.call('HINCRBY', KEYS[2], 'rejected', 1)
+  result = {0, 0, allow_at - now, tat - now}
+else
+  redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+  redis.call('HINCRBY', KEYS[2], 'allowed', 1)
+  result = {1, math.floor((now - allow_at) / interval), 0, new_tat - now}
+end
+redis.call('EXPIRE', KEYS[2], usage_ttl)
+return result
+`)
+
+// RedisStore keeps rate limit state in Redis
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis store. Connections are opened on demand.
+func NewRedisStore(config RedisConfig) *RedisStore {
+	if config.PoolSize <= 0 {
+		config.PoolSize = 10
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 500 * time.Millisecond
+	}
+
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:         config.Addr,
+			Password:     config.Password,
+			DB:           config.DB,
+			PoolSize:     config.PoolSize,
+			DialTimeout:  config.Timeout,
+			ReadTimeout:  config.Timeout,
+			WriteTimeout: config.Timeout,
+		}),
+	}
+}
+
+// Allow implements Store
+func (s *RedisStore) Allow(ctx context.Context, subject string, class Class, limit Limit) (*Result, error) {
+	keys := []string{
+		fmt.Sprintf("ratelimit:{%s}:%s", subject, class),
+		redisUsageKey(subject, class, windowStart(time.Now())),
+	}
+
+	// Run uses EVALSHA and loads the script if Redis does not have it cached
+	nums, err := gcraScript.Run(ctx, s.client, keys,
+		limit.interval().Microseconds(),
+		limit.burst(),
+		int(usageRetention.Seconds()),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("redis rate limit script failed: %w", err)
+	}
+	if len(nums) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script reply: %v", nums)
+	}
+
+	return &Result{
+		Allowed:    nums[0] == 1,
+		Limit:      limit.burst(),
+		Remaining:  int(nums[1]),
+		RetryAfter: time.Duration(nums[2]) * time.Microsecond,
+		ResetAfter: time.Duration(nums[3]) * time.Microsecond,
+	}, nil
+}
+
+// Usage implements Store
+func (s *RedisStore) Usage(ctx context.Context, subject string, since time.Time) ([]Usage, error) {
+	type window struct {
+		usage Usage
+		cmd   *redis.MapStringStringCmd
+	}
+
+	var windows []window
+	pipe := s.client.Pipeline()
+	now := windowStart(time.Now())
+	for start := windowStart(since); !start.After(now); start = start.Add(usageWindow) {
+		for _, class := range []Class{ClassRead, ClassWrite} {
+			windows = append(windows, window{
+				usage: Usage{Class: class, WindowStart: start},
+				cmd:   pipe.HGetAll(ctx, redisUsageKey(subject, class, start)),
+			})
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("redis usage query failed: %w", err)
+	}
+
+	usage := []Usage{}
+	for _, w := range windows {
+		fields := w.cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		w.usage.Allowed, _ = strconv.ParseInt(fields["allowed"], 10, 64)
+		w.usage.Rejected, _ = strconv.ParseInt(fields["rejected"], 10, 64)
+		usage = append(usage, w.usage)
+	}
+
+	return usage, nil
+}
+
+// Close closes the store's connections
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+func redisUsageKey(subject string, class Class, start time.Time) string {
+	return fmt.Sprintf("ratelimit:usage:{%s}:%s:%d", subject, class, start.Unix())
+}
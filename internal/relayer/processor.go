@@ -2,18 +2,29 @@ package relayer
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/alerting"
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/algorand"
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/aptos"
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/bitcoin"
+	"github.com/EmekaIwuagwu/articium-hub/internal/blockchain/cosmos"
 	"github.com/EmekaIwuagwu/articium-hub/internal/config"
 	"github.com/EmekaIwuagwu/articium-hub/internal/crypto"
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
 	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
 	"github.com/EmekaIwuagwu/articium-hub/internal/security"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
@@ -21,6 +32,12 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// ErrReleaseAwaitingSignatures is returned for a Bitcoin release that
+// needs more custody signatures. The message is left pending and the
+// release is retried from the database, see ResumeRelease, until the
+// relayers holding the custody keys reach the threshold.
+var ErrReleaseAwaitingSignatures = errors.New("release awaiting custody signatures")
+
 // Processor processes cross-chain messages and broadcasts them to destination chains
 type Processor struct {
 	clients   map[string]types.UniversalClient
@@ -28,6 +45,8 @@ type Processor struct {
 	db        *database.DB
 	config    *config.Config
 	validator *security.Validator
+	alerter   *alerting.Dispatcher
+	notifier  *webhooks.Notifier
 	logger    zerolog.Logger
 	chainCfg  map[string]*types.ChainConfig
 }
@@ -39,11 +58,13 @@ func NewProcessor(
 	db *database.DB,
 	cfg *config.Config,
 	validator *security.Validator,
+	alerter *alerting.Dispatcher,
+	notifier *webhooks.Notifier,
 	logger zerolog.Logger,
 ) *Processor {
 	chainCfg := make(map[string]*types.ChainConfig)
-	for _, chain := range cfg.Chains {
-		chainCfg[chain.Name] = &chain
+	for i := range cfg.Chains {
+		chainCfg[cfg.Chains[i].Name] = &cfg.Chains[i]
 	}
 
 	return &Processor{
@@ -52,6 +73,8 @@ func NewProcessor(
 		db:        db,
 		config:    cfg,
 		validator: validator,
+		alerter:   alerter,
+		notifier:  notifier,
 		logger:    logger.With().Str("component", "processor").Logger(),
 		chainCfg:  chainCfg,
 	}
@@ -103,6 +126,24 @@ func (p *Processor) ProcessMessage(ctx context.Context, msg *types.CrossChainMes
 		return fmt.Errorf("signature verification failed: %w", err)
 	}
 
+	// Notification failures are logged by the notifier and never fail
+	// processing
+	msg.Status = types.MessageStatusProcessing
+	p.notifier.NotifyMessagePending(ctx, msg)
+
+	return p.dispatch(ctx, msg, startTime)
+}
+
+// ResumeRelease continues a Bitcoin release that was still collecting
+// custody signatures. The message was validated and its signatures verified
+// before the release was created, so only the release is retried.
+func (p *Processor) ResumeRelease(ctx context.Context, msg *types.CrossChainMessage) error {
+	return p.dispatch(ctx, msg, time.Now())
+}
+
+// dispatch sends a validated message to its destination chain and records
+// the outcome
+func (p *Processor) dispatch(ctx context.Context, msg *types.CrossChainMessage, startTime time.Time) error {
 	// Process based on destination chain type
 	destClient, ok := p.clients[msg.DestinationChain.Name]
 	if !ok {
@@ -110,6 +151,7 @@ func (p *Processor) ProcessMessage(ctx context.Context, msg *types.CrossChainMes
 	}
 
 	var txHash string
+	var err error
 	switch destClient.GetChainType() {
 	case types.ChainTypeEVM:
 		txHash, err = p.processEVMMessage(ctx, msg, destClient)
@@ -117,10 +159,25 @@ func (p *Processor) ProcessMessage(ctx context.Context, msg *types.CrossChainMes
 		txHash, err = p.processSolanaMessage(ctx, msg, destClient)
 	case types.ChainTypeNEAR:
 		txHash, err = p.processNEARMessage(ctx, msg, destClient)
+	case types.ChainTypeAlgorand:
+		txHash, err = p.processAlgorandMessage(ctx, msg, destClient)
+	case types.ChainTypeAptos:
+		txHash, err = p.processAptosMessage(ctx, msg, destClient)
+	case types.ChainTypeCosmos:
+		txHash, err = p.processCosmosMessage(ctx, msg, destClient)
+	case types.ChainTypeBitcoin:
+		txHash, err = p.processBitcoinMessage(ctx, msg, destClient)
 	default:
 		return fmt.Errorf("unsupported chain type: %s", destClient.GetChainType())
 	}
 
+	if errors.Is(err, ErrReleaseAwaitingSignatures) {
+		p.logger.Info().
+			Err(err).
+			Str("message_id", msg.ID).
+			Msg("Release awaiting signatures")
+		return err
+	}
 	if err != nil {
 		p.logger.Error().
 			Err(err).
@@ -139,6 +196,9 @@ func (p *Processor) ProcessMessage(ctx context.Context, msg *types.CrossChainMes
 		// Don't return error - transaction was broadcast successfully
 	}
 
+	msg.Status = types.MessageStatusCompleted
+	p.notifier.NotifyMessageFinalized(ctx, msg)
+
 	p.logger.Info().
 		Str("message_id", msg.ID).
 		Str("tx_hash", txHash).
@@ -150,6 +210,20 @@ func (p *Processor) ProcessMessage(ctx context.Context, msg *types.CrossChainMes
 	return nil
 }
 
+// awaitsFinality reports whether the relayer waits for a destination
+// transaction to be final before reporting it confirmed. Clients wait under
+// the chain's finality policy.
+func awaitsFinality(chainCfg *types.ChainConfig) bool {
+	return chainCfg.ConfirmationBlocks > 0 || chainCfg.GetFinality() != types.FinalityDepth
+}
+
+// notifySubmitted records a message's destination transaction being
+// broadcast
+func (p *Processor) notifySubmitted(ctx context.Context, msg *types.CrossChainMessage, txHash string) {
+	msg.DestTxHash = txHash
+	p.notifier.NotifyMessageSubmitted(ctx, msg, txHash, 0)
+}
+
 // verifySignatures verifies validator signatures on the message
 func (p *Processor) verifySignatures(ctx context.Context, msg *types.CrossChainMessage) error {
 	// Get required signature threshold based on environment
@@ -219,9 +293,9 @@ func (p *Processor) verifyValidatorSignature(ctx context.Context, msg *types.Cro
 
 	// Verify signature based on chain type
 	switch chainType {
-	case types.ChainTypeEVM:
+	case types.ChainTypeEVM, types.ChainTypeCosmos, types.ChainTypeBitcoin:
 		return crypto.VerifyECDSASignature(msgHash, sigHex, sig.ValidatorAddress)
-	case types.ChainTypeSolana, types.ChainTypeNEAR:
+	case types.ChainTypeSolana, types.ChainTypeNEAR, types.ChainTypeAlgorand, types.ChainTypeAptos:
 		return crypto.VerifyEd25519Signature(msgHash, sigHex, sig.ValidatorAddress)
 	default:
 		return fmt.Errorf("unsupported chain type for signature verification")
@@ -285,9 +359,9 @@ func (p *Processor) processEVMMessage(ctx context.Context, msg *types.CrossChain
 
 	switch msg.Type {
 	case types.MessageTypeTokenTransfer:
-		tx, err = p.buildEVMTokenUnlockTx(msg, chainCfg)
+		tx, err = p.buildEVMTokenUnlockTx(ctx, msg, chainCfg)
 	case types.MessageTypeNFTTransfer:
-		tx, err = p.buildEVMNFTUnlockTx(msg, chainCfg)
+		tx, err = p.buildEVMNFTUnlockTx(ctx, msg, chainCfg)
 	default:
 		return "", fmt.Errorf("unsupported message type: %s", msg.Type)
 	}
@@ -307,23 +381,32 @@ func (p *Processor) processEVMMessage(ctx context.Context, msg *types.CrossChain
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
+	p.notifySubmitted(ctx, msg, txHash)
 
-	// Wait for confirmation if needed
-	if chainCfg.ConfirmationBlocks > 0 {
+	// Wait for finality if needed
+	if awaitsFinality(chainCfg) {
 		p.logger.Debug().
 			Str("tx_hash", txHash).
+			Str("finality", string(chainCfg.GetFinality())).
 			Uint64("confirmations", chainCfg.ConfirmationBlocks).
 			Msg("Waiting for transaction confirmation")
 
-		// In production, you would implement proper confirmation waiting
-		// For now, we'll just return the tx hash
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("tx_hash", txHash).
+				Msg("Confirmation wait failed, but transaction was sent")
+			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
+		}
 	}
 
 	return txHash, nil
 }
 
 // buildEVMTokenUnlockTx builds a token unlock transaction for EVM chains
-func (p *Processor) buildEVMTokenUnlockTx(msg *types.CrossChainMessage, chainCfg *types.ChainConfig) (*ethTypes.Transaction, error) {
+func (p *Processor) buildEVMTokenUnlockTx(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig) (*ethTypes.Transaction, error) {
 	// Parse payload
 	var payload types.TokenTransferPayload
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
@@ -407,7 +490,7 @@ func (p *Processor) buildEVMTokenUnlockTx(msg *types.CrossChainMessage, chainCfg
 }
 
 // buildEVMNFTUnlockTx builds an NFT unlock transaction for EVM chains
-func (p *Processor) buildEVMNFTUnlockTx(msg *types.CrossChainMessage, chainCfg *types.ChainConfig) (*ethTypes.Transaction, error) {
+func (p *Processor) buildEVMNFTUnlockTx(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig) (*ethTypes.Transaction, error) {
 	// Parse payload
 	var payload types.NFTTransferPayload
 	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
@@ -462,25 +545,29 @@ func (p *Processor) processSolanaMessage(ctx context.Context, msg *types.CrossCh
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
+	p.notifySubmitted(ctx, msg, txHash)
 
 	p.logger.Info().
 		Str("message_id", msg.ID).
 		Str("signature", txHash).
 		Msg("Solana transaction sent")
 
-	// Wait for confirmation if needed
-	if chainCfg.ConfirmationBlocks > 0 {
+	// Wait for finality if needed
+	if awaitsFinality(chainCfg) {
 		p.logger.Debug().
 			Str("signature", txHash).
+			Str("finality", string(chainCfg.GetFinality())).
 			Uint64("confirmations", chainCfg.ConfirmationBlocks).
 			Msg("Waiting for Solana transaction confirmation")
 
-		if err := client.WaitForConfirmation(ctx, txHash, 60*time.Second); err != nil {
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
 			p.logger.Warn().
 				Err(err).
 				Str("signature", txHash).
 				Msg("Confirmation wait failed, but transaction was sent")
 			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
 		}
 	}
 
@@ -527,31 +614,821 @@ func (p *Processor) processNEARMessage(ctx context.Context, msg *types.CrossChai
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
+	p.notifySubmitted(ctx, msg, txHash)
 
 	p.logger.Info().
 		Str("message_id", msg.ID).
 		Str("tx_hash", txHash).
 		Msg("NEAR transaction sent")
 
-	// Wait for confirmation if needed
-	if chainCfg.ConfirmationBlocks > 0 {
+	// Wait for finality if needed
+	if awaitsFinality(chainCfg) {
 		p.logger.Debug().
 			Str("tx_hash", txHash).
+			Str("finality", string(chainCfg.GetFinality())).
 			Uint64("confirmations", chainCfg.ConfirmationBlocks).
 			Msg("Waiting for NEAR transaction confirmation")
 
-		if err := client.WaitForConfirmation(ctx, txHash, 60*time.Second); err != nil {
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("tx_hash", txHash).
+				Msg("Confirmation wait failed, but transaction was sent")
+			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
+		}
+	}
+
+	return txHash, nil
+}
+
+// processAlgorandMessage processes a message for Algorand chains
+func (p *Processor) processAlgorandMessage(ctx context.Context, msg *types.CrossChainMessage, client types.UniversalClient) (string, error) {
+	p.logger.Debug().
+		Str("message_id", msg.ID).
+		Msg("Processing Algorand message")
+
+	// Get chain configuration
+	chainCfg, ok := p.chainCfg[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("chain config not found: %s", msg.DestinationChain.Name)
+	}
+
+	// Get signer for Algorand
+	signer, ok := p.signers[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("signer not found for Algorand")
+	}
+
+	// NFTs on Algorand are ASAs too, but the bridge application only
+	// unlocks fungible transfers
+	var tx []byte
+	var err error
+
+	switch msg.Type {
+	case types.MessageTypeTokenTransfer:
+		tx, err = p.buildAlgorandTokenUnlockTx(ctx, msg, chainCfg, signer)
+	default:
+		return "", fmt.Errorf("unsupported message type: %s", msg.Type)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	// Send transaction
+	txHash, err := client.SendTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	p.notifySubmitted(ctx, msg, txHash)
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_hash", txHash).
+		Msg("Algorand transaction sent")
+
+	// Wait for confirmation if needed
+	if awaitsFinality(chainCfg) {
+		p.logger.Debug().
+			Str("tx_hash", txHash).
+			Msg("Waiting for Algorand transaction confirmation")
+
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("tx_hash", txHash).
+				Msg("Confirmation wait failed, but transaction was sent")
+			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
+		}
+	}
+
+	return txHash, nil
+}
+
+// algorandUnlockSelector is the ARC-4 selector of the bridge application's
+// unlock method
+var algorandUnlockSelector = algorand.MethodSelector("unlock(byte[32],address,uint64,uint64,byte[])void")
+
+// algorandValidRounds is how many rounds an unlock transaction stays valid
+const algorandValidRounds = 1000
+
+// buildAlgorandTokenUnlockTx builds a signed Algorand application call that
+// unlocks tokens
+func (p *Processor) buildAlgorandTokenUnlockTx(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig, signer crypto.UniversalSigner) ([]byte, error) {
+	// Parse payload
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	appID, err := strconv.ParseUint(chainCfg.BridgeContract, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bridge application ID: %s", chainCfg.BridgeContract)
+	}
+
+	// Asset ID 0 is ALGO
+	assetID, err := strconv.ParseUint(payload.TokenAddress.Raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset ID: %s", payload.TokenAddress.Raw)
+	}
+
+	amount, err := strconv.ParseUint(payload.Amount, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %s", payload.Amount)
+	}
+
+	recipient, err := algorand.DecodeAddress(msg.Recipient.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	signerAddr, err := signer.GetAddress(types.ChainTypeAlgorand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer address: %w", err)
+	}
+	sender, err := algorand.DecodeAddress(signerAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer address: %w", err)
+	}
+
+	params, err := p.getAlgorandTransactionParams(ctx, msg.DestinationChain.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction params: %w", err)
+	}
+	if len(params.GenesisHash) != 32 {
+		return nil, fmt.Errorf("invalid genesis hash length: %d", len(params.GenesisHash))
+	}
+
+	// Collect validator signatures
+	var signatures []byte
+	for _, sig := range msg.ValidatorSignatures {
+		signatures = append(signatures, sig.Signature...)
+	}
+
+	messageID := algorandMessageID(msg.ID)
+
+	txn := &algorand.ApplicationCallTxn{
+		Sender: sender,
+		// The application pays for the inner transfer to the recipient
+		// out of this fee
+		Fee:           2 * params.MinFee,
+		FirstValid:    params.LastRound,
+		LastValid:     params.LastRound + algorandValidRounds,
+		GenesisID:     params.GenesisID,
+		ApplicationID: appID,
+		ApplicationArgs: [][]byte{
+			algorandUnlockSelector[:],
+			messageID[:],
+			recipient[:],
+			algorand.Uint64Arg(assetID),
+			algorand.Uint64Arg(amount),
+			algorand.BytesArg(signatures),
+		},
+		Accounts: [][32]byte{recipient},
+	}
+	copy(txn.GenesisHash[:], params.GenesisHash)
+	if assetID != 0 {
+		txn.ForeignAssets = []uint64{assetID}
+	}
+
+	signature, err := signer.Sign(ctx, txn.BytesToSign())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_id", txn.ID()).
+		Str("recipient", msg.Recipient.Raw).
+		Uint64("asset_id", assetID).
+		Str("amount", payload.Amount).
+		Msg("Built Algorand token unlock transaction")
+
+	return algorand.EncodeSignedTxn(txn, signature), nil
+}
+
+// algorandMessageID converts a message ID to the 32 bytes the bridge
+// application records. IDs that are not 32-byte hex strings are hashed.
+func algorandMessageID(id string) [32]byte {
+	var messageID [32]byte
+	if decoded, err := hex.DecodeString(strings.TrimPrefix(id, "0x")); err == nil && len(decoded) == 32 {
+		copy(messageID[:], decoded)
+		return messageID
+	}
+	return sha512.Sum512_256([]byte(id))
+}
+
+// processAptosMessage processes a message for Aptos chains
+func (p *Processor) processAptosMessage(ctx context.Context, msg *types.CrossChainMessage, client types.UniversalClient) (string, error) {
+	p.logger.Debug().
+		Str("message_id", msg.ID).
+		Msg("Processing Aptos message")
+
+	// Get chain configuration
+	chainCfg, ok := p.chainCfg[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("chain config not found: %s", msg.DestinationChain.Name)
+	}
+
+	// Get signer for Aptos
+	signer, ok := p.signers[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("signer not found for Aptos")
+	}
+
+	var tx []byte
+	var err error
+
+	switch msg.Type {
+	case types.MessageTypeTokenTransfer:
+		tx, err = p.buildAptosTokenUnlockTx(ctx, msg, chainCfg, signer)
+	default:
+		return "", fmt.Errorf("unsupported message type: %s", msg.Type)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	// Send transaction
+	txHash, err := client.SendTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	p.notifySubmitted(ctx, msg, txHash)
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_hash", txHash).
+		Msg("Aptos transaction sent")
+
+	// Wait for confirmation if needed
+	if awaitsFinality(chainCfg) {
+		p.logger.Debug().
+			Str("tx_hash", txHash).
+			Msg("Waiting for Aptos transaction confirmation")
+
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("tx_hash", txHash).
+				Msg("Confirmation wait failed, but transaction was sent")
+			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
+		}
+	}
+
+	return txHash, nil
+}
+
+// Gas settings of Aptos unlock transactions
+const (
+	aptosMaxGasAmount   = 100000
+	aptosExpirationSecs = 600
+)
+
+// buildAptosTokenUnlockTx builds a BCS-encoded signed call of the bridge
+// module's unlock_coin<CoinType> entry function, or unlock_fa for fungible
+// assets identified by their metadata address
+func (p *Processor) buildAptosTokenUnlockTx(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig, signer crypto.UniversalSigner) ([]byte, error) {
+	// Parse payload
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	module, err := aptos.ParseAddress(chainCfg.BridgeContract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bridge module address: %w", err)
+	}
+
+	recipient, err := aptos.ParseAddress(msg.Recipient.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	amount, err := strconv.ParseUint(payload.Amount, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %s", payload.Amount)
+	}
+
+	signerAddr, err := signer.GetAddress(types.ChainTypeAptos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer address: %w", err)
+	}
+	sender, err := aptos.ParseAddress(signerAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer address: %w", err)
+	}
+
+	publicKey, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer public key: %w", err)
+	}
+
+	params, err := p.getAptosTransactionParams(ctx, msg.DestinationChain.Name, signerAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction params: %w", err)
+	}
+
+	// Message IDs recorded on Aptos are raw bytes
+	messageID, err := hex.DecodeString(strings.TrimPrefix(msg.ID, "0x"))
+	if err != nil {
+		messageID = []byte(msg.ID)
+	}
+
+	signatures := make([][]byte, len(msg.ValidatorSignatures))
+	for i, sig := range msg.ValidatorSignatures {
+		signatures[i] = sig.Signature
+	}
+
+	call := aptos.EntryFunction{
+		Module: module,
+		Name:   "bridge",
+	}
+	token := payload.TokenAddress.Raw
+	if strings.Contains(token, "::") {
+		coinType, err := aptos.ParseTypeTag(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coin type: %w", err)
+		}
+		call.Function = "unlock_coin"
+		call.TypeArgs = []aptos.TypeTag{coinType}
+		call.Args = [][]byte{
+			aptos.BytesArg(messageID),
+			aptos.AddressArg(recipient),
+			aptos.U64Arg(amount),
+			aptos.BytesVectorArg(signatures),
+		}
+	} else {
+		metadata, err := aptos.ParseAddress(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fungible asset metadata address: %w", err)
+		}
+		call.Function = "unlock_fa"
+		call.Args = [][]byte{
+			aptos.BytesArg(messageID),
+			aptos.AddressArg(recipient),
+			aptos.AddressArg(metadata),
+			aptos.U64Arg(amount),
+			aptos.BytesVectorArg(signatures),
+		}
+	}
+
+	txn := &aptos.RawTransaction{
+		Sender:                  sender,
+		SequenceNumber:          params.SequenceNumber,
+		Payload:                 call,
+		MaxGasAmount:            aptosMaxGasAmount,
+		GasUnitPrice:            params.GasUnitPrice,
+		ExpirationTimestampSecs: uint64(time.Now().Unix()) + aptosExpirationSecs,
+		ChainID:                 params.ChainID,
+	}
+
+	signature, err := signer.Sign(ctx, txn.SigningMessage())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	signedTxn := aptos.EncodeSignedTransaction(txn, publicKey, signature)
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_hash", aptos.TransactionHash(signedTxn)).
+		Str("function", call.Function).
+		Str("recipient", msg.Recipient.Raw).
+		Str("amount", payload.Amount).
+		Msg("Built Aptos token unlock transaction")
+
+	return signedTxn, nil
+}
+
+// processCosmosMessage processes a message for Cosmos SDK chains
+func (p *Processor) processCosmosMessage(ctx context.Context, msg *types.CrossChainMessage, client types.UniversalClient) (string, error) {
+	p.logger.Debug().
+		Str("message_id", msg.ID).
+		Msg("Processing Cosmos message")
+
+	// Get chain configuration
+	chainCfg, ok := p.chainCfg[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("chain config not found: %s", msg.DestinationChain.Name)
+	}
+
+	// Get signer for Cosmos
+	signer, ok := p.signers[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("signer not found for Cosmos")
+	}
+
+	var tx []byte
+	var err error
+
+	switch msg.Type {
+	case types.MessageTypeTokenTransfer:
+		tx, err = p.buildCosmosTokenUnlockTx(ctx, msg, chainCfg, signer)
+	default:
+		return "", fmt.Errorf("unsupported message type: %s", msg.Type)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	// Send transaction
+	txHash, err := client.SendTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	p.notifySubmitted(ctx, msg, txHash)
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_hash", txHash).
+		Msg("Cosmos transaction sent")
+
+	// Wait for confirmation if needed
+	if awaitsFinality(chainCfg) {
+		p.logger.Debug().
+			Str("tx_hash", txHash).
+			Msg("Waiting for Cosmos transaction confirmation")
+
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
+			p.logger.Warn().
+				Err(err).
+				Str("tx_hash", txHash).
+				Msg("Confirmation wait failed, but transaction was sent")
+			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
+		}
+	}
+
+	return txHash, nil
+}
+
+// cosmosUnlockGasLimit is the gas limit of Cosmos unlock transactions
+// before the chain's multiplier is applied
+const cosmosUnlockGasLimit = 300000
+
+// cosmosUnlockMsg is the bridge contract's unlock execute message
+type cosmosUnlockMsg struct {
+	Unlock struct {
+		MessageID  string   `json:"message_id"`
+		Recipient  string   `json:"recipient"`
+		Token      string   `json:"token"`
+		Amount     string   `json:"amount"`
+		Signatures [][]byte `json:"signatures"`
+	} `json:"unlock"`
+}
+
+// buildCosmosTokenUnlockTx builds a signed TxRaw executing the bridge
+// contract's unlock message. Cosmos SDK chains sign the SHA-256 hash of
+// the sign doc with secp256k1, so the signer must expose SignHash.
+func (p *Processor) buildCosmosTokenUnlockTx(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig, signer crypto.UniversalSigner) ([]byte, error) {
+	// Parse payload
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	type HashSigner interface {
+		SignHash(hash []byte) ([]byte, error)
+	}
+	hashSigner, ok := signer.(HashSigner)
+	if !ok {
+		return nil, fmt.Errorf("signer does not support signing hashes")
+	}
+
+	publicKey, err := signer.GetPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer public key: %w", err)
+	}
+	publicKey, err = cosmos.CompressPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress signer public key: %w", err)
+	}
+
+	sender, err := cosmos.AccountAddress(publicKey, chainCfg.Bech32Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive signer address: %w", err)
+	}
+
+	account, err := p.getCosmosAccount(ctx, msg.DestinationChain.Name, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer account: %w", err)
+	}
+
+	var unlock cosmosUnlockMsg
+	unlock.Unlock.MessageID = strings.TrimPrefix(msg.ID, "0x")
+	unlock.Unlock.Recipient = msg.Recipient.Raw
+	unlock.Unlock.Token = payload.TokenAddress.Raw
+	unlock.Unlock.Amount = payload.Amount
+	unlock.Unlock.Signatures = make([][]byte, len(msg.ValidatorSignatures))
+	for i, sig := range msg.ValidatorSignatures {
+		unlock.Unlock.Signatures[i] = sig.Signature
+	}
+
+	executeMsg, err := json.Marshal(unlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal execute message: %w", err)
+	}
+
+	gasLimit := uint64(cosmosUnlockGasLimit)
+	if chainCfg.GasLimitMultiplier > 0 {
+		gasLimit = uint64(float64(gasLimit) * chainCfg.GasLimitMultiplier)
+	}
+	fee, err := cosmos.FeeForGas(chainCfg.GasPrice, gasLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute fee: %w", err)
+	}
+
+	tx := &cosmos.Tx{
+		Messages: []cosmos.MsgExecuteContract{{
+			Sender:   sender,
+			Contract: chainCfg.BridgeContract,
+			Msg:      executeMsg,
+		}},
+		Fee:      []cosmos.Coin{fee},
+		GasLimit: gasLimit,
+		PubKey:   publicKey,
+		Sequence: uint64(account.Sequence),
+	}
+
+	signBytes := tx.SignBytes(chainCfg.ChainID, uint64(account.AccountNumber))
+	hash := sha256.Sum256(signBytes)
+	signature, err := hashSigner.SignHash(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if len(signature) < 64 {
+		return nil, fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+
+	// Cosmos SDK expects R || S without the recovery ID
+	txRaw := tx.Encode(signature[:64])
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_hash", cosmos.TxHash(txRaw)).
+		Str("sender", sender).
+		Str("recipient", msg.Recipient.Raw).
+		Str("amount", payload.Amount).
+		Msg("Built Cosmos token unlock transaction")
+
+	return txRaw, nil
+}
+
+// processBitcoinMessage processes a message for Bitcoin chains
+func (p *Processor) processBitcoinMessage(ctx context.Context, msg *types.CrossChainMessage, client types.UniversalClient) (string, error) {
+	p.logger.Debug().
+		Str("message_id", msg.ID).
+		Msg("Processing Bitcoin message")
+
+	// Get chain configuration
+	chainCfg, ok := p.chainCfg[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("chain config not found: %s", msg.DestinationChain.Name)
+	}
+
+	// Get signer for Bitcoin
+	signer, ok := p.signers[msg.DestinationChain.Name]
+	if !ok {
+		return "", fmt.Errorf("signer not found for Bitcoin")
+	}
+
+	var txHash string
+	var err error
+
+	switch msg.Type {
+	case types.MessageTypeTokenTransfer:
+		txHash, err = p.releaseBitcoin(ctx, msg, chainCfg, signer, client)
+	default:
+		return "", fmt.Errorf("unsupported message type: %s", msg.Type)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to release: %w", err)
+	}
+	p.notifySubmitted(ctx, msg, txHash)
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("tx_hash", txHash).
+		Msg("Bitcoin transaction sent")
+
+	// Wait for confirmation if needed
+	if awaitsFinality(chainCfg) {
+		p.logger.Debug().
+			Str("tx_hash", txHash).
+			Msg("Waiting for Bitcoin transaction confirmation")
+
+		if err := client.WaitForConfirmation(ctx, txHash, chainCfg.GetConfirmationTimeout()); err != nil {
 			p.logger.Warn().
 				Err(err).
 				Str("tx_hash", txHash).
 				Msg("Confirmation wait failed, but transaction was sent")
 			// Don't fail - transaction was broadcast
+		} else {
+			p.notifier.NotifyMessageConfirmed(ctx, msg, chainCfg.ConfirmationBlocks)
 		}
 	}
 
 	return txHash, nil
 }
 
+// bitcoinReleaseAttempts bounds the retries of a release that other
+// relayers keep changing
+const bitcoinReleaseAttempts = 3
+
+// releaseBitcoin adds this relayer's signatures to the message's release
+// PSBT, creating it from the custody's UTXOs first if no relayer has. The
+// relayer whose signatures complete the threshold broadcasts the release.
+// Relayers coordinate through the database, retrying when another relayer
+// changed the release since it was read.
+func (p *Processor) releaseBitcoin(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig, signer crypto.UniversalSigner, client types.UniversalClient) (string, error) {
+	// Parse payload
+	var payload types.TokenTransferPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return "", fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	amount, err := strconv.ParseInt(payload.Amount, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid amount: %s", payload.Amount)
+	}
+
+	custody, err := bitcoin.NewCustody(chainCfg.BridgeContract, chainCfg.CustodyThreshold, chainCfg.CustodyPubKeys)
+	if err != nil {
+		return "", err
+	}
+
+	type HashSigner interface {
+		SignHash(hash []byte) ([]byte, error)
+	}
+	hashSigner, ok := signer.(HashSigner)
+	if !ok {
+		return "", fmt.Errorf("signer does not support signing hashes")
+	}
+
+	publicKey, err := signer.GetPublicKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get signer public key: %w", err)
+	}
+	publicKey, err = bitcoin.CompressPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to compress signer public key: %w", err)
+	}
+	if !custody.IsSigner(publicKey) {
+		p.logger.Warn().
+			Str("message_id", msg.ID).
+			Msg("Signer is not a custody key, release will not be signed")
+	}
+
+	for attempt := 0; attempt < bitcoinReleaseAttempts; attempt++ {
+		release, err := p.db.GetBitcoinRelease(ctx, msg.ID)
+		if err != nil {
+			return "", err
+		}
+		if release == nil {
+			release, err = p.createBitcoinRelease(ctx, msg, chainCfg, custody, amount)
+			if errors.Is(err, database.ErrBitcoinReleaseConflict) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		if release.Status == database.BitcoinReleaseBroadcast {
+			return release.TxID, nil
+		}
+
+		packet, err := bitcoin.DecodePSBTBase64(release.PSBT)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode release: %w", err)
+		}
+
+		signed := false
+		if custody.IsSigner(publicKey) && !packet.HasSignature(publicKey) {
+			for i := range packet.Inputs {
+				hash, err := packet.SigHash(i)
+				if err != nil {
+					return "", err
+				}
+				compact, err := hashSigner.SignHash(hash)
+				if err != nil {
+					return "", fmt.Errorf("failed to sign input %d: %w", i, err)
+				}
+				sig, err := bitcoin.EncodeSignature(compact)
+				if err != nil {
+					return "", err
+				}
+				if err := packet.AddPartialSig(i, publicKey, sig); err != nil {
+					return "", err
+				}
+			}
+			signed = true
+		}
+
+		if finalizeErr := packet.Finalize(); finalizeErr != nil {
+			if !signed {
+				return "", fmt.Errorf("%w: %v", ErrReleaseAwaitingSignatures, finalizeErr)
+			}
+			release.PSBT = packet.B64Encode()
+			err := p.db.UpdateBitcoinRelease(ctx, release)
+			if errors.Is(err, database.ErrBitcoinReleaseConflict) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			return "", fmt.Errorf("%w: %v", ErrReleaseAwaitingSignatures, finalizeErr)
+		}
+
+		tx, err := packet.Extract()
+		if err != nil {
+			return "", err
+		}
+		txHash, err := client.SendTransaction(ctx, tx.Serialize())
+		if err != nil {
+			return "", fmt.Errorf("failed to send transaction: %w", err)
+		}
+
+		release.PSBT = packet.B64Encode()
+		release.Status = database.BitcoinReleaseBroadcast
+		release.TxID = txHash
+		if err := p.db.UpdateBitcoinRelease(ctx, release); err != nil {
+			// Another relayer broadcasting the same release spends the
+			// same inputs, so the transactions share a txid
+			p.logger.Warn().
+				Err(err).
+				Str("message_id", msg.ID).
+				Str("tx_hash", txHash).
+				Msg("Failed to record release broadcast")
+		}
+
+		p.logger.Info().
+			Str("message_id", msg.ID).
+			Str("tx_hash", txHash).
+			Str("recipient", msg.Recipient.Raw).
+			Int64("amount_sats", amount).
+			Int("inputs", len(tx.TxIn)).
+			Msg("Broadcast Bitcoin release")
+
+		return txHash, nil
+	}
+
+	return "", fmt.Errorf("release %s changed concurrently %d times", msg.ID, bitcoinReleaseAttempts)
+}
+
+// createBitcoinRelease builds an unsigned release PSBT from the custody's
+// unspent outputs and reserves the outputs it spends
+func (p *Processor) createBitcoinRelease(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig, custody *bitcoin.Custody, amount int64) (*database.BitcoinRelease, error) {
+	unspent, err := p.db.ListUnspentBitcoinUTXOs(ctx, chainCfg.Name)
+	if err != nil {
+		return nil, err
+	}
+	utxos := make([]bitcoin.UTXO, len(unspent))
+	for i, utxo := range unspent {
+		utxos[i] = bitcoin.UTXO{TxID: utxo.TxID, Vout: utxo.Vout, Amount: utxo.AmountSats}
+	}
+
+	feeRate, err := p.estimateBitcoinFeeRate(ctx, chainCfg.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate fee rate: %w", err)
+	}
+
+	packet, selected, err := custody.BuildRelease(utxos, msg.Recipient.Raw, amount, feeRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build release: %w", err)
+	}
+	spends := make([]database.BitcoinUTXO, len(selected))
+	for i, utxo := range selected {
+		spends[i] = database.BitcoinUTXO{TxID: utxo.TxID, Vout: utxo.Vout, AmountSats: utxo.Amount}
+	}
+
+	release := &database.BitcoinRelease{
+		MessageID: msg.ID,
+		Chain:     chainCfg.Name,
+		PSBT:      packet.B64Encode(),
+	}
+	if err := p.db.CreateBitcoinRelease(ctx, release, spends); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info().
+		Str("message_id", msg.ID).
+		Str("txid", packet.Tx.TxID()).
+		Int64("fee_rate", feeRate).
+		Int("inputs", len(spends)).
+		Msg("Created Bitcoin release")
+
+	return release, nil
+}
+
 // buildSolanaTokenUnlockTx builds a Solana token unlock transaction
 func (p *Processor) buildSolanaTokenUnlockTx(ctx context.Context, msg *types.CrossChainMessage, chainCfg *types.ChainConfig, signer crypto.UniversalSigner) (*solana.Transaction, error) {
 	// Parse payload
@@ -881,6 +1758,82 @@ func (p *Processor) getRecentBlockhashFromClient(ctx context.Context, chainName
 	return solana.Hash{}, fmt.Errorf("client does not support GetRecentBlockhash")
 }
 
+// getAlgorandTransactionParams fetches transaction parameters from an
+// Algorand client
+func (p *Processor) getAlgorandTransactionParams(ctx context.Context, chainName string) (*algorand.TransactionParams, error) {
+	client, ok := p.clients[chainName]
+	if !ok {
+		return nil, fmt.Errorf("client not found for chain: %s", chainName)
+	}
+
+	type AlgorandParamsGetter interface {
+		GetTransactionParams(ctx context.Context) (*algorand.TransactionParams, error)
+	}
+
+	if algorandClient, ok := client.(AlgorandParamsGetter); ok {
+		return algorandClient.GetTransactionParams(ctx)
+	}
+
+	return nil, fmt.Errorf("client does not support GetTransactionParams")
+}
+
+// getAptosTransactionParams fetches the parameters of a sender's next
+// transaction from an Aptos client
+func (p *Processor) getAptosTransactionParams(ctx context.Context, chainName, sender string) (*aptos.TransactionParams, error) {
+	client, ok := p.clients[chainName]
+	if !ok {
+		return nil, fmt.Errorf("client not found for chain: %s", chainName)
+	}
+
+	type AptosParamsGetter interface {
+		GetTransactionParams(ctx context.Context, sender string) (*aptos.TransactionParams, error)
+	}
+
+	if aptosClient, ok := client.(AptosParamsGetter); ok {
+		return aptosClient.GetTransactionParams(ctx, sender)
+	}
+
+	return nil, fmt.Errorf("client does not support GetTransactionParams")
+}
+
+// getCosmosAccount fetches the account number and sequence of a signer
+// from a Cosmos client
+func (p *Processor) getCosmosAccount(ctx context.Context, chainName, address string) (*cosmos.Account, error) {
+	client, ok := p.clients[chainName]
+	if !ok {
+		return nil, fmt.Errorf("client not found for chain: %s", chainName)
+	}
+
+	type CosmosAccountGetter interface {
+		GetAccount(ctx context.Context, address string) (*cosmos.Account, error)
+	}
+
+	if cosmosClient, ok := client.(CosmosAccountGetter); ok {
+		return cosmosClient.GetAccount(ctx, address)
+	}
+
+	return nil, fmt.Errorf("client does not support GetAccount")
+}
+
+// estimateBitcoinFeeRate estimates a fee rate in sat/vB from a Bitcoin
+// client
+func (p *Processor) estimateBitcoinFeeRate(ctx context.Context, chainName string) (int64, error) {
+	client, ok := p.clients[chainName]
+	if !ok {
+		return 0, fmt.Errorf("client not found for chain: %s", chainName)
+	}
+
+	type BitcoinFeeEstimator interface {
+		EstimateFeeRate(ctx context.Context) (int64, error)
+	}
+
+	if bitcoinClient, ok := client.(BitcoinFeeEstimator); ok {
+		return bitcoinClient.EstimateFeeRate(ctx)
+	}
+
+	return 0, fmt.Errorf("client does not support EstimateFeeRate")
+}
+
 // getEVMSignerAddress gets the address of the EVM signer
 func (p *Processor) getEVMSignerAddress(chainName string) (common.Address, error) {
 	signer, ok := p.signers[chainName]
@@ -928,7 +1881,25 @@ func (p *Processor) getEVMGasPrice(ctx context.Context, chainName string) (*big.
 	}
 
 	if evmClient, ok := client.(EVMGasPriceGetter); ok {
-		return evmClient.SuggestGasPrice(ctx)
+		gasPrice, err := evmClient.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var maxGasPrice string
+		if chainCfg, ok := p.chainCfg[chainName]; ok {
+			maxGasPrice = chainCfg.MaxGasPrice
+		}
+		go func() {
+			if err := p.alerter.GasPrice(context.Background(), chainName, gasPrice, maxGasPrice); err != nil {
+				p.logger.Error().
+					Err(err).
+					Str("chain", chainName).
+					Msg("Failed to send gas price alert")
+			}
+		}()
+
+		return gasPrice, nil
 	}
 
 	return nil, fmt.Errorf("client does not support SuggestGasPrice")
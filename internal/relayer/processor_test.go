@@ -0,0 +1,34 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/config"
+	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/rs/zerolog"
+)
+
+func TestNewProcessor_KeepsPerChainConfig(t *testing.T) {
+	cfg := &config.Config{
+		Chains: []types.ChainConfig{
+			{Name: "ethereum", ChainType: types.ChainTypeEVM, Finality: types.FinalityFinalized},
+			{Name: "polygon", ChainType: types.ChainTypeEVM, Finality: types.FinalityDepth, ConfirmationBlocks: 64},
+		},
+	}
+
+	p := NewProcessor(nil, nil, nil, cfg, nil, nil, nil, zerolog.Nop())
+
+	ethereum, polygon := p.chainCfg["ethereum"], p.chainCfg["polygon"]
+	if ethereum == nil || polygon == nil {
+		t.Fatalf("Expected both chains to be configured, got %v", p.chainCfg)
+	}
+	if ethereum.Name != "ethereum" || ethereum.GetFinality() != types.FinalityFinalized {
+		t.Errorf("ethereum config = %s/%s, want ethereum/%s", ethereum.Name, ethereum.GetFinality(), types.FinalityFinalized)
+	}
+	if polygon.Name != "polygon" || polygon.GetFinality() != types.FinalityDepth {
+		t.Errorf("polygon config = %s/%s, want polygon/%s", polygon.Name, polygon.GetFinality(), types.FinalityDepth)
+	}
+	if ethereum.GetConfirmationTimeout() == polygon.GetConfirmationTimeout() {
+		t.Errorf("Expected confirmation timeouts to differ, both are %s", ethereum.GetConfirmationTimeout())
+	}
+}
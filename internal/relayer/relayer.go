@@ -2,10 +2,12 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/alerting"
 	"github.com/EmekaIwuagwu/articium-hub/internal/config"
 	"github.com/EmekaIwuagwu/articium-hub/internal/crypto"
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
@@ -13,15 +15,25 @@ import (
 	"github.com/EmekaIwuagwu/articium-hub/internal/queue"
 	"github.com/EmekaIwuagwu/articium-hub/internal/security"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
+	"github.com/EmekaIwuagwu/articium-hub/internal/webhooks"
 	"github.com/rs/zerolog"
 )
 
+// releaseRetryInterval is how often Bitcoin releases still collecting
+// custody signatures are retried
+const releaseRetryInterval = 30 * time.Second
+
+// releaseRetryBatch is the most releases per chain retried at once
+const releaseRetryBatch = 100
+
 // Relayer manages the message relay workers
 type Relayer struct {
 	config    *config.Config
 	db        *database.DB
 	queue     queue.Queue
 	processor *Processor
+	alerter   *alerting.Dispatcher
+	notifier  *webhooks.Notifier
 	logger    zerolog.Logger
 	workers   int
 	wg        sync.WaitGroup
@@ -38,17 +50,26 @@ func NewRelayer(
 	signers map[string]crypto.UniversalSigner,
 	logger zerolog.Logger,
 ) (*Relayer, error) {
+	// Create alert dispatcher
+	alerter := alerting.NewDispatcher(&cfg.Alerting, logger)
+
 	// Create security validator
-	validator := security.NewValidator(&cfg.Security, cfg.Environment, logger)
+	validator := security.NewValidator(&cfg.Security, cfg.Environment, alerter, logger)
+
+	// Create webhook notifier. Events are queued in the database and
+	// delivered by the API servers.
+	notifier := webhooks.NewQueueNotifier(db, logger)
 
 	// Create processor
-	processor := NewProcessor(clients, signers, db, cfg, validator, logger)
+	processor := NewProcessor(clients, signers, db, cfg, validator, alerter, notifier, logger)
 
 	return &Relayer{
 		config:    cfg,
 		db:        db,
 		queue:     q,
 		processor: processor,
+		alerter:   alerter,
+		notifier:  notifier,
 		logger:    logger.With().Str("component", "relayer").Logger(),
 		workers:   cfg.Relayer.Workers,
 		stopChan:  make(chan struct{}),
@@ -76,6 +97,10 @@ func (r *Relayer) Start(ctx context.Context) error {
 	r.wg.Add(1)
 	go r.collectMetrics(ctx)
 
+	// Retry Bitcoin releases still collecting custody signatures
+	r.wg.Add(1)
+	go r.retryReleases(ctx)
+
 	r.logger.Info().Msg("All relayer workers started")
 	return nil
 }
@@ -127,7 +152,22 @@ func (r *Relayer) handleMessage(ctx context.Context, msg *types.CrossChainMessag
 	// Process message
 	startTime := time.Now()
 	err := r.processor.ProcessMessage(ctx, msg)
-	duration := time.Since(startTime)
+
+	return r.recordOutcome(ctx, msg, err, time.Since(startTime), logger)
+}
+
+// recordOutcome records the result of processing a message
+func (r *Relayer) recordOutcome(ctx context.Context, msg *types.CrossChainMessage, err error, duration time.Duration, logger zerolog.Logger) error {
+	// The message stays pending and its release is retried from the
+	// database, possibly by a relayer holding another custody key. It is
+	// acknowledged, as queue redeliveries are capped.
+	if errors.Is(err, ErrReleaseAwaitingSignatures) {
+		logger.Info().
+			Str("message_id", msg.ID).
+			Dur("duration", duration).
+			Msg("Release awaiting signatures, release will be retried")
+		return nil
+	}
 
 	if err != nil {
 		logger.Error().
@@ -143,6 +183,10 @@ func (r *Relayer) handleMessage(ctx context.Context, msg *types.CrossChainMessag
 			"failed",
 		).Inc()
 
+		if alertErr := r.alerter.MessageOutcome(ctx, msg.DestinationChain.Name, false, err); alertErr != nil {
+			logger.Warn().Err(alertErr).Msg("Failed to send failure alert")
+		}
+
 		// Update message status to failed
 		if dbErr := r.db.UpdateMessageStatus(ctx, msg.ID, types.MessageStatusFailed, ""); dbErr != nil {
 			logger.Error().
@@ -151,6 +195,9 @@ func (r *Relayer) handleMessage(ctx context.Context, msg *types.CrossChainMessag
 				Msg("Failed to update message status")
 		}
 
+		msg.Status = types.MessageStatusFailed
+		r.notifier.NotifyMessageFailed(ctx, msg, err.Error())
+
 		return err
 	}
 
@@ -166,9 +213,64 @@ func (r *Relayer) handleMessage(ctx context.Context, msg *types.CrossChainMessag
 		"completed",
 	).Inc()
 
+	if alertErr := r.alerter.MessageOutcome(ctx, msg.DestinationChain.Name, true, nil); alertErr != nil {
+		logger.Warn().Err(alertErr).Msg("Failed to send resolve alert")
+	}
+
 	return nil
 }
 
+// retryReleases periodically retries Bitcoin releases still collecting
+// custody signatures, so each relayer adds its signature and the release is
+// broadcast once the threshold is met
+func (r *Relayer) retryReleases(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(releaseRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopChan:
+			return
+		case <-ticker.C:
+			r.retryPendingReleases(ctx)
+		}
+	}
+}
+
+// retryPendingReleases retries the pending releases of each Bitcoin chain
+func (r *Relayer) retryPendingReleases(ctx context.Context) {
+	for chainName, client := range r.clients {
+		if client.GetChainType() != types.ChainTypeBitcoin {
+			continue
+		}
+
+		messageIDs, err := r.db.ListSigningBitcoinReleases(ctx, chainName, releaseRetryBatch)
+		if err != nil {
+			r.logger.Warn().Err(err).Str("chain", chainName).Msg("Failed to list pending releases")
+			continue
+		}
+
+		for _, messageID := range messageIDs {
+			msg, err := r.db.GetMessage(ctx, messageID, "")
+			if err != nil {
+				r.logger.Warn().Err(err).Str("message_id", messageID).Msg("Failed to load message for release")
+				continue
+			}
+			if msg.Status == types.MessageStatusCompleted || msg.Status == types.MessageStatusFailed {
+				continue
+			}
+
+			startTime := time.Now()
+			err = r.processor.ResumeRelease(ctx, msg)
+			_ = r.recordOutcome(ctx, msg, err, time.Since(startTime), r.logger)
+		}
+	}
+}
+
 // healthCheck periodically checks the health of blockchain clients
 func (r *Relayer) healthCheck(ctx context.Context) {
 	defer r.wg.Done()
@@ -209,6 +311,9 @@ func (r *Relayer) performHealthCheck(ctx context.Context) {
 		// Update metrics
 		chainType := string(client.GetChainType())
 		monitoring.UpdateChainHealth(chainName, chainType, healthy)
+		if alertErr := r.alerter.ChainHealth(ctx, chainName, healthy); alertErr != nil {
+			r.logger.Warn().Err(alertErr).Str("chain", chainName).Msg("Failed to send chain health alert")
+		}
 		if healthy {
 			monitoring.UpdateChainBlockNumber(chainName, blockNumber)
 		}
@@ -306,6 +411,9 @@ func (r *Relayer) ProcessPendingMessages(ctx context.Context) error {
 					Str("message_id", msg.ID).
 					Msg("Failed to update message status")
 			}
+
+			msg.Status = types.MessageStatusFailed
+			r.notifier.NotifyMessageFailed(ctx, &msg, "timeout")
 			continue
 		}
 
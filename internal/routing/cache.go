@@ -201,6 +201,7 @@ func (rc *RouteCache) generateKey(query *RouteQuery) string {
 		"amount":       query.Amount.String(),
 		"max_hops":     query.MaxHops,
 		"optimize_for": query.OptimizeFor,
+		"org_id":       query.OrgID,
 	})
 
 	hash := sha256.Sum256(data)
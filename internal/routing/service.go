@@ -109,6 +109,9 @@ func (s *Service) FindRoutes(ctx context.Context, query *RouteQuery) (*RouteResu
 	if err != nil {
 		return nil, err
 	}
+	for _, route := range result.Routes {
+		route.OrgID = query.OrgID
+	}
 
 	// Store in cache
 	if s.config.CacheEnabled && len(result.Routes) > 0 {
@@ -309,8 +312,8 @@ func (s *Service) saveRoute(ctx context.Context, route *Route) error {
 		INSERT INTO routes (
 			id, source_chain, dest_chain, total_hops,
 			total_cost, total_time_seconds, total_fee,
-			score, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			score, status, created_at, updated_at, org_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
 		ON CONFLICT (id) DO NOTHING
 	`
 
@@ -326,6 +329,7 @@ func (s *Service) saveRoute(ctx context.Context, route *Route) error {
 		route.Status,
 		route.CreatedAt,
 		route.UpdatedAt,
+		route.OrgID,
 	)
 
 	return err
@@ -336,7 +340,7 @@ func (s *Service) getRoute(ctx context.Context, routeID string) (*Route, error)
 		SELECT
 			id, source_chain, dest_chain, total_hops,
 			total_cost, total_time_seconds, total_fee,
-			score, status, created_at, updated_at
+			score, status, created_at, updated_at, COALESCE(org_id, '')
 		FROM routes
 		WHERE id = $1
 	`
@@ -362,6 +366,7 @@ func (s *Service) getRoute(ctx context.Context, routeID string) (*Route, error)
 		&route.Status,
 		&route.CreatedAt,
 		&route.UpdatedAt,
+		&route.OrgID,
 	)
 
 	if err != nil {
@@ -19,6 +19,7 @@ const (
 // Route represents a multi-hop path between chains
 type Route struct {
 	ID          string        `json:"id"`
+	OrgID       string        `json:"org_id,omitempty"`
 	SourceChain string        `json:"source_chain"`
 	DestChain   string        `json:"dest_chain"`
 	Hops        []Hop         `json:"hops"`
@@ -73,6 +74,7 @@ type RouteQuery struct {
 	MaxCost      *big.Int `json:"max_cost,omitempty"`
 	MaxTime      int64    `json:"max_time_seconds,omitempty"`
 	MinLiquidity *big.Int `json:"min_liquidity,omitempty"`
+	OrgID        string   `json:"-"` // Organization that owns the discovered routes
 }
 
 // RouteResult represents the result of a route search
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/alerting"
 	"github.com/EmekaIwuagwu/articium-hub/internal/config"
 	"github.com/EmekaIwuagwu/articium-hub/internal/monitoring"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
@@ -23,6 +24,9 @@ type Validator struct {
 	// Fraud detection
 	fraudDetector *FraudDetector
 
+	// Alert dispatcher (may be nil)
+	alerter *alerting.Dispatcher
+
 	// Emergency pause state
 	isPaused bool
 }
@@ -31,6 +35,7 @@ type Validator struct {
 func NewValidator(
 	securityConfig *config.SecurityConfig,
 	env types.Environment,
+	alerter *alerting.Dispatcher,
 	logger zerolog.Logger,
 ) *Validator {
 	return &Validator{
@@ -39,6 +44,7 @@ func NewValidator(
 		logger:        logger.With().Str("component", "security").Logger(),
 		rateLimiter:   NewRateLimiter(securityConfig, logger),
 		fraudDetector: NewFraudDetector(securityConfig, logger),
+		alerter:       alerter,
 		isPaused:      false,
 	}
 }
@@ -92,6 +98,14 @@ func (v *Validator) ValidateMessage(ctx context.Context, msg *types.CrossChainMe
 				Str("reason", reason).
 				Msg("Suspicious transaction detected")
 			monitoring.RecordSuspiciousTransaction(reason, msg.SourceChain.Name)
+			go func() {
+				if err := v.alerter.FraudFlag(context.Background(), msg, reason); err != nil {
+					v.logger.Error().
+						Err(err).
+						Str("message_id", msg.ID).
+						Msg("Failed to send fraud alert")
+				}
+			}()
 			return fmt.Errorf("transaction flagged as suspicious: %s", reason)
 		}
 	}
@@ -163,10 +177,15 @@ func (v *Validator) alertLargeTransaction(msg *types.CrossChainMessage, amount *
 		Str("dest", msg.DestinationChain.Name).
 		Msg("ALERT: Large transaction detected")
 
-	// TODO: Send webhook notification if configured
-	if v.config.AlertingWebhook != "" {
-		// Send alert to webhook
-	}
+	// Delivered asynchronously so slow sinks never hold up relaying
+	go func() {
+		if err := v.alerter.LargeTransaction(context.Background(), msg, amount); err != nil {
+			v.logger.Error().
+				Err(err).
+				Str("message_id", msg.ID).
+				Msg("Failed to send large transaction alert")
+		}
+	}()
 }
 
 // extractAmount extracts the amount from message payload
@@ -1,8 +1,13 @@
 package types
 
 import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base32"
 	"fmt"
 	"strings"
+
+	"github.com/EmekaIwuagwu/articium-hub/internal/crypto/bech32"
 )
 
 // AddressFormat represents the format of a blockchain address
@@ -12,8 +17,24 @@ const (
 	AddressFormatEVM    AddressFormat = "EVM"    // 0x... (40 hex chars)
 	AddressFormatBase58 AddressFormat = "BASE58" // Solana format
 	AddressFormatNamed  AddressFormat = "NAMED"  // NEAR account.testnet
+	AddressFormatBase32 AddressFormat = "BASE32" // Algorand format
+	AddressFormatHex32  AddressFormat = "HEX32"  // Aptos 0x... (up to 64 hex chars)
+	AddressFormatBech32 AddressFormat = "BECH32" // Cosmos cosmos1...
+	AddressFormatSegWit AddressFormat = "SEGWIT" // Bitcoin bc1...
 )
 
+// NewDestinationAddress parses an address on a message's destination chain.
+// Lock events record only the destination chain's name, so its address type
+// is taken from that chain's configuration.
+func NewDestinationAddress(raw, destChain string, chains []ChainConfig) (Address, error) {
+	for i := range chains {
+		if chains[i].Name == destChain {
+			return NewAddress(raw, chains[i].ChainType)
+		}
+	}
+	return Address{}, fmt.Errorf("unknown destination chain: %s", destChain)
+}
+
 // Address represents a cross-chain address
 type Address struct {
 	Raw       string        `json:"raw" db:"raw"`
@@ -48,6 +69,30 @@ func NewAddress(raw string, chainType ChainType) (Address, error) {
 		}
 		addr.Format = AddressFormatNamed
 
+	case ChainTypeAlgorand:
+		if err := validateAlgorandAddress(raw); err != nil {
+			return Address{}, err
+		}
+		addr.Format = AddressFormatBase32
+
+	case ChainTypeAptos:
+		if err := validateAptosAddress(raw); err != nil {
+			return Address{}, err
+		}
+		addr.Format = AddressFormatHex32
+
+	case ChainTypeCosmos:
+		if err := validateCosmosAddress(raw); err != nil {
+			return Address{}, err
+		}
+		addr.Format = AddressFormatBech32
+
+	case ChainTypeBitcoin:
+		if err := validateBitcoinAddress(raw); err != nil {
+			return Address{}, err
+		}
+		addr.Format = AddressFormatSegWit
+
 	default:
 		return Address{}, fmt.Errorf("unsupported chain type: %s", chainType)
 	}
@@ -133,6 +178,77 @@ func validateNEARAddress(addr string) error {
 	return nil
 }
 
+// validateAlgorandAddress validates an Algorand address: the base32
+// encoding of a public key followed by the last 4 bytes of its SHA-512/256
+// hash
+func validateAlgorandAddress(addr string) error {
+	if len(addr) != 58 {
+		return fmt.Errorf("Algorand address must be 58 characters in base32")
+	}
+
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(addr)
+	if err != nil {
+		return fmt.Errorf("Algorand address is not valid base32: %w", err)
+	}
+	if len(decoded) != 36 {
+		return fmt.Errorf("Algorand address must decode to 36 bytes")
+	}
+
+	checksum := sha512.Sum512_256(decoded[:32])
+	if !bytes.Equal(checksum[28:], decoded[32:]) {
+		return fmt.Errorf("Algorand address has an invalid checksum")
+	}
+	return nil
+}
+
+// validateAptosAddress validates an Aptos account address. Leading zeros
+// may be omitted, as in the short form of special addresses like 0x1.
+func validateAptosAddress(addr string) error {
+	if !strings.HasPrefix(addr, "0x") {
+		return fmt.Errorf("Aptos address must start with 0x")
+	}
+	if len(addr) < 3 || len(addr) > 66 {
+		return fmt.Errorf("Aptos address must be 0x followed by 1-64 hex characters")
+	}
+	for _, c := range addr[2:] {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return fmt.Errorf("Aptos address contains invalid hex character: %c", c)
+		}
+	}
+	return nil
+}
+
+// validateCosmosAddress validates a bech32 Cosmos SDK address: 20 bytes
+// for accounts, 32 for module and contract accounts
+func validateCosmosAddress(addr string) error {
+	if strings.ToLower(addr) != addr {
+		return fmt.Errorf("Cosmos address must be lowercase")
+	}
+	_, data, err := bech32.Decode(addr)
+	if err != nil {
+		return fmt.Errorf("Cosmos address is not valid bech32: %w", err)
+	}
+	if len(data) != 20 && len(data) != 32 {
+		return fmt.Errorf("Cosmos address must decode to 20 or 32 bytes, got %d", len(data))
+	}
+	return nil
+}
+
+// validateBitcoinAddress validates a native SegWit address on mainnet,
+// testnet/signet or regtest
+func validateBitcoinAddress(addr string) error {
+	hrp, _, _, err := bech32.DecodeSegWit(addr)
+	if err != nil {
+		return fmt.Errorf("Bitcoin address is not a valid SegWit address: %w", err)
+	}
+	switch hrp {
+	case "bc", "tb", "bcrt":
+		return nil
+	default:
+		return fmt.Errorf("unknown Bitcoin network prefix: %s", hrp)
+	}
+}
+
 // isBase58Char checks if a character is valid in base58 encoding
 func isBase58Char(c rune) bool {
 	// Base58 alphabet: 123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz
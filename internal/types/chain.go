@@ -2,10 +2,15 @@ package types
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"time"
 )
 
+// ErrSubscriptionNotSupported is returned by SubscribeToEvents when a chain
+// client cannot push events. Callers poll instead.
+var ErrSubscriptionNotSupported = errors.New("event subscriptions not supported")
+
 // ChainType represents the blockchain architecture
 type ChainType string
 
@@ -15,6 +20,8 @@ const (
 	ChainTypeNEAR     ChainType = "NEAR"
 	ChainTypeAlgorand ChainType = "ALGORAND"
 	ChainTypeAptos    ChainType = "APTOS"
+	ChainTypeCosmos   ChainType = "COSMOS"
+	ChainTypeBitcoin  ChainType = "BITCOIN"
 )
 
 // Environment represents the deployment environment
@@ -26,6 +33,28 @@ const (
 	EnvironmentMainnet     Environment = "mainnet"
 )
 
+// FinalityPolicy selects when listeners and the relayer treat a block as
+// final
+type FinalityPolicy string
+
+const (
+	// FinalityDepth treats blocks ConfirmationBlocks below the head as final
+	FinalityDepth FinalityPolicy = "depth"
+	// FinalitySafe follows the chain's safe head: the EVM "safe" tag, Solana
+	// "confirmed" commitment or NEAR "near-final" finality
+	FinalitySafe FinalityPolicy = "safe"
+	// FinalityFinalized follows protocol finality: the EVM "finalized" tag,
+	// Solana "finalized" commitment or NEAR "final" finality
+	FinalityFinalized FinalityPolicy = "finalized"
+)
+
+// FinalityClient is implemented by clients of chains with protocol finality
+type FinalityClient interface {
+	// GetFinalizedBlockNumber returns the highest block that is final under
+	// the chain's finality policy
+	GetFinalizedBlockNumber(ctx context.Context) (uint64, error)
+}
+
 // ChainInfo contains blockchain-specific information
 type ChainInfo struct {
 	Name        string      `json:"name"`
@@ -79,34 +108,47 @@ type UniversalClient interface {
 	GetNativeBalance(ctx context.Context, address string) (*big.Int, error)
 	GetTokenBalance(ctx context.Context, address string, tokenAddress string) (*big.Int, error)
 
-	// Event subscription
+	// Event subscription. The channel receives the chain's native event
+	// values until ctx is done or the subscription drops, then is closed.
+	// Events emitted while no subscription was open are not replayed; the
+	// caller fills the gap by polling. Clients without push support return
+	// ErrSubscriptionNotSupported.
 	SubscribeToEvents(ctx context.Context, contractAddress string, eventSignature string) (chan interface{}, error)
 }
 
 // ChainConfig represents the configuration for a blockchain
 type ChainConfig struct {
-	Name               string      `mapstructure:"name"`
-	ChainType          ChainType   `mapstructure:"chain_type"`
-	Environment        Environment `mapstructure:"environment"`
-	ChainID            string      `mapstructure:"chain_id"`
-	NetworkID          string      `mapstructure:"network_id"`
-	RPCEndpoints       []string    `mapstructure:"rpc_endpoints"`
-	WSEndpoint         string      `mapstructure:"ws_endpoint"`
-	BridgeContract     string      `mapstructure:"bridge_contract"`
-	BridgeProgram      string      `mapstructure:"bridge_program"`
-	StartBlock         uint64      `mapstructure:"start_block"`
-	StartSlot          uint64      `mapstructure:"start_slot"`
-	ConfirmationBlocks uint64      `mapstructure:"confirmation_blocks"`
-	ConfirmationSlots  uint64      `mapstructure:"confirmation_slots"`
-	BlockTime          string      `mapstructure:"block_time"`
-	MaxGasPrice        string      `mapstructure:"max_gas_price"`
-	GasLimitMultiplier float64     `mapstructure:"gas_limit_multiplier"`
-	MaxReorgDepth      uint64      `mapstructure:"max_reorg_depth"`
-	PollInterval       string      `mapstructure:"poll_interval"`
-	Commitment         string      `mapstructure:"commitment"`
-	ComputeUnitPrice   string      `mapstructure:"compute_unit_price"`
-	MaxRetries         int         `mapstructure:"max_retries"`
-	Enabled            bool        `mapstructure:"enabled"`
+	Name               string         `mapstructure:"name"`
+	ChainType          ChainType      `mapstructure:"chain_type"`
+	Environment        Environment    `mapstructure:"environment"`
+	ChainID            string         `mapstructure:"chain_id"`
+	NetworkID          string         `mapstructure:"network_id"`
+	RPCEndpoints       []string       `mapstructure:"rpc_endpoints"`
+	RPCQuorum          int            `mapstructure:"rpc_quorum"`
+	RPCMaxHeadLag      uint64         `mapstructure:"rpc_max_head_lag"`
+	WSEndpoint         string         `mapstructure:"ws_endpoint"`
+	IndexerEndpoint    string         `mapstructure:"indexer_endpoint"`
+	RESTEndpoint       string         `mapstructure:"rest_endpoint"`
+	Bech32Prefix       string         `mapstructure:"bech32_prefix"`
+	GasPrice           string         `mapstructure:"gas_price"`
+	CustodyPubKeys     []string       `mapstructure:"custody_pubkeys"`
+	CustodyThreshold   int            `mapstructure:"custody_threshold"`
+	BridgeContract     string         `mapstructure:"bridge_contract"`
+	BridgeProgram      string         `mapstructure:"bridge_program"`
+	StartBlock         uint64         `mapstructure:"start_block"`
+	StartSlot          uint64         `mapstructure:"start_slot"`
+	ConfirmationBlocks uint64         `mapstructure:"confirmation_blocks"`
+	ConfirmationSlots  uint64         `mapstructure:"confirmation_slots"`
+	Finality           FinalityPolicy `mapstructure:"finality"`
+	BlockTime          string         `mapstructure:"block_time"`
+	MaxGasPrice        string         `mapstructure:"max_gas_price"`
+	GasLimitMultiplier float64        `mapstructure:"gas_limit_multiplier"`
+	MaxReorgDepth      uint64         `mapstructure:"max_reorg_depth"`
+	PollInterval       string         `mapstructure:"poll_interval"`
+	Commitment         string         `mapstructure:"commitment"`
+	ComputeUnitPrice   string         `mapstructure:"compute_unit_price"`
+	MaxRetries         int            `mapstructure:"max_retries"`
+	Enabled            bool           `mapstructure:"enabled"`
 }
 
 // GetBlockTimeDuration returns block time as duration
@@ -121,6 +163,39 @@ func (c *ChainConfig) GetBlockTimeDuration() time.Duration {
 	return duration
 }
 
+// GetFinality returns the chain's finality policy, FinalityDepth by default
+func (c *ChainConfig) GetFinality() FinalityPolicy {
+	if c.Finality == "" {
+		return FinalityDepth
+	}
+	return c.Finality
+}
+
+// finalityDelays are typical times for a transaction to reach the safe or
+// finalized head. EVM delays cover Ethereum's one and two epoch checkpoints.
+var finalityDelays = map[ChainType]map[FinalityPolicy]time.Duration{
+	ChainTypeEVM:    {FinalitySafe: 7 * time.Minute, FinalityFinalized: 13 * time.Minute},
+	ChainTypeSolana: {FinalitySafe: 2 * time.Second, FinalityFinalized: 13 * time.Second},
+	ChainTypeNEAR:   {FinalitySafe: 2 * time.Second, FinalityFinalized: 3 * time.Second},
+}
+
+// GetConfirmationTimeout returns how long to wait for a transaction to be
+// final under the chain's finality policy: twice the policy's typical delay,
+// and at least a minute
+func (c *ChainConfig) GetConfirmationTimeout() time.Duration {
+	delay := time.Duration(c.ConfirmationBlocks+1) * c.GetBlockTimeDuration()
+	if policy := c.GetFinality(); policy != FinalityDepth {
+		if d, ok := finalityDelays[c.ChainType][policy]; ok {
+			delay = d
+		}
+	}
+
+	if timeout := 2 * delay; timeout > time.Minute {
+		return timeout
+	}
+	return time.Minute
+}
+
 // GetPollIntervalDuration returns poll interval as duration
 func (c *ChainConfig) GetPollIntervalDuration() time.Duration {
 	if c.PollInterval == "" {
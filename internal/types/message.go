@@ -35,10 +35,14 @@ type CrossChainMessage struct {
 	Type  MessageType `json:"type" db:"message_type"`
 	Nonce uint64      `json:"nonce" db:"nonce"`
 
+	// Owning organization, empty for messages observed on-chain
+	OrgID string `json:"org_id,omitempty" db:"org_id"`
+
 	// Source chain info
-	SourceChain  ChainInfo `json:"source_chain" db:"-"`
-	SourceTxHash string    `json:"source_tx_hash" db:"source_tx_hash"`
-	SourceBlock  uint64    `json:"source_block" db:"source_block"`
+	SourceChain    ChainInfo `json:"source_chain" db:"-"`
+	SourceTxHash   string    `json:"source_tx_hash" db:"source_tx_hash"`
+	SourceBlock    uint64    `json:"source_block" db:"source_block"`
+	SourceLogIndex uint64    `json:"source_log_index" db:"source_log_index"`
 
 	// Destination chain info
 	DestinationChain ChainInfo `json:"destination_chain" db:"-"`
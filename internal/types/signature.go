@@ -63,9 +63,9 @@ func (s *Signature) Validate() error {
 // GetSchemeForChain returns the appropriate signature scheme for a chain type
 func GetSchemeForChain(chainType ChainType) (SignatureScheme, error) {
 	switch chainType {
-	case ChainTypeEVM:
+	case ChainTypeEVM, ChainTypeCosmos, ChainTypeBitcoin:
 		return SignatureSchemeECDSA, nil
-	case ChainTypeSolana, ChainTypeNEAR:
+	case ChainTypeSolana, ChainTypeNEAR, ChainTypeAlgorand, ChainTypeAptos:
 		return SignatureSchemeEd25519, nil
 	default:
 		return "", fmt.Errorf("unknown chain type: %s", chainType)
@@ -10,25 +10,50 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/auth"
 	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/pkg/webhooksig"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
-// DeliveryService handles webhook delivery with retry logic
+// probeCheckInterval is how often suspended webhooks are checked for a due
+// probe
+const probeCheckInterval = 30 * time.Second
+
+// eventTypeProbe is the event type of recovery probes
+const eventTypeProbe EventType = "webhook.probe"
+
+// Audit log entries for webhook health changes
+const (
+	auditWebhookSuspended   = "webhook_suspended"
+	auditWebhookReactivated = "webhook_reactivated"
+	auditTargetWebhook      = "webhook"
+	auditActorSystem        = "system"
+)
+
+// DeliveryService handles webhook delivery with retry logic. Events are
+// queued in Postgres and claimed with SKIP LOCKED, so any number of
+// replicas can deliver from the same queue, each attempt is made by one
+// replica, and undelivered events survive restarts.
 type DeliveryService struct {
-	config    *WebhookDeliveryConfig
-	registry  *Registry
-	db        *database.DB
-	logger    zerolog.Logger
-	client    *http.Client
-	eventChan chan *WebhookEvent
-	wg        sync.WaitGroup
-	ctx       context.Context
-	cancel    context.CancelFunc
+	config   *WebhookDeliveryConfig
+	registry *Registry
+	db       *database.DB
+	logger   zerolog.Logger
+	client   *http.Client
+	audit    *auth.AuditLogger
+	notify   chan struct{}
+	inFlight atomic.Int32
+	probing  atomic.Bool
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 // NewDeliveryService creates a new webhook delivery service
@@ -41,6 +66,27 @@ func NewDeliveryService(
 	if config == nil {
 		config = DefaultDeliveryConfig()
 	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MaxPerEndpoint <= 0 {
+		config.MaxPerEndpoint = config.MaxConcurrent
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultDeliveryConfig().FailureThreshold
+	}
+	if config.ProbeInterval <= 0 {
+		config.ProbeInterval = DefaultDeliveryConfig().ProbeInterval
+	}
+	if config.ReplayRate <= 0 {
+		config.ReplayRate = DefaultDeliveryConfig().ReplayRate
+	}
+	if config.MaxReplayEvents <= 0 {
+		config.MaxReplayEvents = DefaultDeliveryConfig().MaxReplayEvents
+	}
+	if config.Lease <= config.TimeoutDuration {
+		config.Lease = 2 * config.TimeoutDuration
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -52,56 +98,55 @@ func NewDeliveryService(
 		client: &http.Client{
 			Timeout: config.TimeoutDuration,
 		},
-		eventChan: make(chan *WebhookEvent, 1000),
-		ctx:       ctx,
-		cancel:    cancel,
+		audit:  auth.NewAuditLogger(db, logger),
+		notify: make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
 	}
 }
 
-// Start starts the webhook delivery service
+// Start starts the webhook delivery service. Events left pending by a
+// previous run, including deliveries interrupted by a crash once their
+// lease expires, are picked up by the first poll.
 func (s *DeliveryService) Start(ctx context.Context) error {
 	s.logger.Info().
 		Int("max_concurrent", s.config.MaxConcurrent).
 		Int("max_retries", s.config.MaxRetries).
 		Msg("Starting webhook delivery service")
 
-	// Start worker goroutines
-	for i := 0; i < s.config.MaxConcurrent; i++ {
-		s.wg.Add(1)
-		go s.worker(i)
-	}
+	SetWebhookQueueCapacity(s.config.MaxConcurrent)
 
-	// Start retry processor
 	s.wg.Add(1)
-	go s.retryProcessor()
+	go s.run()
 
 	return nil
 }
 
-// Stop stops the webhook delivery service
+// Stop stops claiming events and waits for in-flight deliveries to finish.
+// Events not yet claimed stay queued for the next run.
 func (s *DeliveryService) Stop() error {
 	s.logger.Info().Msg("Stopping webhook delivery service")
 	s.cancel()
-	close(s.eventChan)
 	s.wg.Wait()
 	return nil
 }
 
 // Dispatch queues a webhook event for delivery
-func (s *DeliveryService) Dispatch(event *WebhookEvent) error {
-	select {
-	case s.eventChan <- event:
-		RecordWebhookDispatched()
-		return nil
-	case <-time.After(5 * time.Second):
-		return fmt.Errorf("timeout queuing webhook event")
+func (s *DeliveryService) Dispatch(ctx context.Context, event *WebhookEvent) error {
+	if err := s.enqueueEvent(ctx, event); err != nil {
+		return err
 	}
+
+	RecordWebhookDispatched()
+	s.wake()
+	return nil
 }
 
-// DispatchToWebhooks dispatches an event to all registered webhooks
-func (s *DeliveryService) DispatchToWebhooks(ctx context.Context, eventType EventType, payload map[string]interface{}) error {
+// DispatchToWebhooks dispatches an event to all registered webhooks. If
+// orgID is set, only that organization's webhooks receive it.
+func (s *DeliveryService) DispatchToWebhooks(ctx context.Context, eventType EventType, orgID string, payload map[string]interface{}) error {
 	// Get all active webhooks for this event type
-	webhooks, err := s.registry.GetActiveWebhooksForEvent(ctx, eventType)
+	webhooks, err := s.registry.GetActiveWebhooksForEvent(ctx, eventType, orgID)
 	if err != nil {
 		return fmt.Errorf("failed to get webhooks: %w", err)
 	}
@@ -116,6 +161,13 @@ func (s *DeliveryService) DispatchToWebhooks(ctx context.Context, eventType Even
 	// Filter webhooks based on payload criteria
 	filteredWebhooks := s.filterWebhooks(webhooks, payload)
 
+	// Every webhook is sent the same body, which is fixed now so retries
+	// send the same bytes
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
 	// Create and dispatch events
 	for _, webhook := range filteredWebhooks {
 		event := &WebhookEvent{
@@ -125,22 +177,10 @@ func (s *DeliveryService) DispatchToWebhooks(ctx context.Context, eventType Even
 			Payload:     payload,
 			Timestamp:   time.Now().UTC(),
 			DeliveryURL: webhook.URL,
+			Body:        body,
 		}
 
-		// Generate HMAC signature
-		event.Signature = s.generateSignature(webhook.Secret, payload)
-
-		// Save event to database
-		if err := s.saveEvent(ctx, event); err != nil {
-			s.logger.Error().
-				Err(err).
-				Str("event_id", event.ID).
-				Msg("Failed to save webhook event")
-			continue
-		}
-
-		// Dispatch for delivery
-		if err := s.Dispatch(event); err != nil {
+		if err := s.Dispatch(ctx, event); err != nil {
 			s.logger.Error().
 				Err(err).
 				Str("event_id", event.ID).
@@ -156,28 +196,89 @@ func (s *DeliveryService) DispatchToWebhooks(ctx context.Context, eventType Even
 	return nil
 }
 
-// worker processes webhook events
-func (s *DeliveryService) worker(id int) {
+// wake makes the delivery loop check the queue without waiting for the
+// next poll. It never blocks.
+func (s *DeliveryService) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run claims due events until the service is stopped
+func (s *DeliveryService) run() {
 	defer s.wg.Done()
 
-	s.logger.Debug().Int("worker_id", id).Msg("Webhook worker started")
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	probeTicker := time.NewTicker(probeCheckInterval)
+	defer probeTicker.Stop()
 
 	for {
 		select {
 		case <-s.ctx.Done():
-			s.logger.Debug().Int("worker_id", id).Msg("Webhook worker stopped")
 			return
-		case event, ok := <-s.eventChan:
-			if !ok {
-				return
+		case <-probeTicker.C:
+			if s.probing.CompareAndSwap(false, true) {
+				s.wg.Add(1)
+				go func() {
+					defer s.wg.Done()
+					defer s.probing.Store(false)
+					s.probeSuspended()
+				}()
 			}
-			s.deliverEvent(event, 1)
+		case <-ticker.C:
+			s.drain()
+			if pending, err := s.pendingEvents(s.ctx); err == nil {
+				SetWebhookQueueSize(pending)
+			}
+		case <-s.notify:
+			s.drain()
+		}
+	}
+}
+
+// drain claims as many due events as there are free delivery slots and
+// delivers them concurrently
+func (s *DeliveryService) drain() {
+	for s.ctx.Err() == nil {
+		free := s.config.MaxConcurrent - int(s.inFlight.Load())
+		if free <= 0 {
+			return
+		}
+
+		events, err := s.claimEvents(s.ctx, free)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to claim webhook events")
+			return
+		}
+
+		for _, event := range events {
+			s.inFlight.Add(1)
+			s.wg.Add(1)
+			go func(event *WebhookEvent) {
+				defer s.wg.Done()
+				s.deliverEvent(event)
+				s.inFlight.Add(-1)
+				s.wake()
+			}(event)
+		}
+
+		if len(events) < free {
+			return
 		}
 	}
 }
 
-// deliverEvent attempts to deliver a webhook event
-func (s *DeliveryService) deliverEvent(event *WebhookEvent, attemptNumber int) {
+// deliverEvent makes one delivery attempt and records its outcome. It is
+// not cancelled by Stop, so a shutdown lets the attempt finish rather than
+// leaving it to be retried after its lease.
+func (s *DeliveryService) deliverEvent(event *WebhookEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.TimeoutDuration+10*time.Second)
+	defer cancel()
+
+	attemptNumber := event.Attempts
 	start := time.Now()
 
 	s.logger.Debug().
@@ -187,38 +288,38 @@ func (s *DeliveryService) deliverEvent(event *WebhookEvent, attemptNumber int) {
 		Int("attempt", attemptNumber).
 		Msg("Delivering webhook")
 
+	if attemptNumber > 1 {
+		RecordWebhookRetry()
+	}
+
 	// Prepare request
-	payload, err := json.Marshal(event.Payload)
-	if err != nil {
-		s.logger.Error().
-			Err(err).
-			Str("event_id", event.ID).
-			Msg("Failed to marshal webhook payload")
-		s.recordAttempt(event, attemptNumber, 0, "", err.Error(), false, time.Since(start), nil)
-		return
+	body := event.Body
+	if body == nil {
+		var err error
+		if body, err = json.Marshal(event.Payload); err != nil {
+			s.logger.Error().
+				Err(err).
+				Str("event_id", event.ID).
+				Msg("Failed to marshal webhook payload")
+			s.finishAttempt(ctx, event, 0, "", err.Error(), false, time.Since(start), nil)
+			return
+		}
 	}
 
-	req, err := http.NewRequestWithContext(s.ctx, "POST", event.DeliveryURL, bytes.NewReader(payload))
+	// Secrets are looked up at send time so a rotation applies to retries
+	webhook, err := s.registry.Get(ctx, event.WebhookID)
 	if err != nil {
 		s.logger.Error().
 			Err(err).
 			Str("event_id", event.ID).
-			Msg("Failed to create webhook request")
-		s.recordAttempt(event, attemptNumber, 0, "", err.Error(), false, time.Since(start), nil)
+			Str("webhook_id", event.WebhookID).
+			Msg("Failed to load webhook for delivery")
+		s.finishAttempt(ctx, event, 0, "", err.Error(), false, time.Since(start), s.calculateNextRetry(attemptNumber))
 		return
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "Articium-Webhook/1.0")
-	req.Header.Set("X-Webhook-ID", event.WebhookID)
-	req.Header.Set("X-Event-ID", event.ID)
-	req.Header.Set("X-Event-Type", string(event.EventType))
-	req.Header.Set("X-Webhook-Signature", event.Signature)
-	req.Header.Set("X-Webhook-Timestamp", event.Timestamp.Format(time.RFC3339))
-
 	// Send request
-	resp, err := s.client.Do(req)
+	statusCode, responseBody, err := s.send(ctx, webhook, event.DeliveryURL, eventHeaders(event), body)
 	if err != nil {
 		s.logger.Warn().
 			Err(err).
@@ -226,156 +327,233 @@ func (s *DeliveryService) deliverEvent(event *WebhookEvent, attemptNumber int) {
 			Int("attempt", attemptNumber).
 			Msg("Webhook delivery failed")
 
-		s.recordAttempt(event, attemptNumber, 0, "", err.Error(), false, time.Since(start), s.calculateNextRetry(attemptNumber))
-		s.registry.IncrementFailCount(s.ctx, event.WebhookID)
+		s.finishAttempt(ctx, event, 0, "", err.Error(), false, time.Since(start), s.calculateNextRetry(attemptNumber))
+		s.recordFailure(ctx, webhook)
 		RecordWebhookFailed()
 		return
 	}
-	defer resp.Body.Close()
-
-	// Read response
-	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 10*1024)) // Limit to 10KB
 
 	// Check status code
-	success := resp.StatusCode >= 200 && resp.StatusCode < 300
-
-	s.recordAttempt(event, attemptNumber, resp.StatusCode, string(responseBody), "", success, time.Since(start), s.calculateNextRetry(attemptNumber))
+	success := statusCode >= 200 && statusCode < 300
+	RecordWebhookResponse(statusCode)
 
 	if success {
+		s.finishAttempt(ctx, event, statusCode, responseBody, "", true, time.Since(start), nil)
+
 		s.logger.Info().
 			Str("event_id", event.ID).
-			Int("status_code", resp.StatusCode).
+			Int("status_code", statusCode).
 			Int("attempt", attemptNumber).
 			Msg("Webhook delivered successfully")
 
-		s.registry.IncrementSuccessCount(s.ctx, event.WebhookID)
+		s.registry.IncrementSuccessCount(ctx, event.WebhookID)
 		RecordWebhookDelivered()
 		RecordWebhookLatency(time.Since(start).Seconds())
 	} else {
+		errMsg := fmt.Sprintf("unexpected status code %d", statusCode)
+		s.finishAttempt(ctx, event, statusCode, responseBody, errMsg, false, time.Since(start), s.calculateNextRetry(attemptNumber))
+
 		s.logger.Warn().
 			Str("event_id", event.ID).
-			Int("status_code", resp.StatusCode).
+			Int("status_code", statusCode).
 			Int("attempt", attemptNumber).
 			Msg("Webhook delivery failed with non-2xx status")
 
-		s.registry.IncrementFailCount(s.ctx, event.WebhookID)
+		s.recordFailure(ctx, webhook)
 		RecordWebhookFailed()
 	}
 }
 
-// retryProcessor periodically checks for failed deliveries and retries them
-func (s *DeliveryService) retryProcessor() {
-	defer s.wg.Done()
+// send posts a signed body to a webhook and returns the response status
+// and up to 10KB of the response body
+func (s *DeliveryService) send(ctx context.Context, webhook *Webhook, url string, headers map[string]string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create webhook request: %w", err)
+	}
 
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+	// Set headers. The signature binds the send time so captured deliveries
+	// cannot be replayed once the receiver's tolerance has passed.
+	sentAt := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Articium-Webhook/1.0")
+	req.Header.Set("X-Webhook-ID", webhook.ID)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(webhooksig.SignatureHeader, webhooksig.Header(sentAt, body, webhook.SigningSecrets(sentAt)...))
+	if webhook.SendsLegacySignature(sentAt) {
+		req.Header.Set(LegacySignatureHeader, legacySignature(webhook.Secret, body))
+	}
 
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			s.processRetries()
-		}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
 	}
-}
+	defer resp.Body.Close()
 
-// processRetries finds and retries failed webhook deliveries
-func (s *DeliveryService) processRetries() {
-	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
-	defer cancel()
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 10*1024)) // Limit to 10KB
+	return resp.StatusCode, string(responseBody), nil
+}
 
-	query := `
-		SELECT DISTINCT ON (event_id)
-			wa.event_id,
-			we.webhook_id,
-			we.event_type,
-			we.payload,
-			we.timestamp,
-			we.signature,
-			we.delivery_url,
-			wa.attempt_number
-		FROM webhook_attempts wa
-		JOIN webhook_events we ON wa.event_id = we.id
-		WHERE wa.success = false
-		AND wa.next_retry_at IS NOT NULL
-		AND wa.next_retry_at <= NOW()
-		AND wa.attempt_number < $1
-		ORDER BY event_id, wa.attempted_at DESC
-		LIMIT 100
-	`
+// eventHeaders returns the headers describing an event. A replayed event
+// keeps its original event ID, so receivers that deduplicate by event ID
+// skip events they already processed, and is marked as a replay.
+func eventHeaders(event *WebhookEvent) map[string]string {
+	headers := map[string]string{
+		"X-Event-ID":          event.ID,
+		"X-Event-Type":        string(event.EventType),
+		"X-Webhook-Timestamp": event.Timestamp.Format(time.RFC3339),
+	}
+	if event.ReplayOf != "" {
+		headers["X-Event-ID"] = event.ReplayOf
+		headers["X-Webhook-Replay"] = "true"
+		headers["X-Webhook-Replay-ID"] = event.ReplayID
+	}
+	return headers
+}
 
-	rows, err := s.db.QueryContext(ctx, query, s.config.MaxRetries)
+// recordFailure counts a failed attempt against a webhook's health and, if
+// that suspends the webhook, records the suspension in the audit log. The
+// suspended webhook itself no longer receives events, so the
+// webhook.disabled event only reaches the organization's other webhooks.
+func (s *DeliveryService) recordFailure(ctx context.Context, webhook *Webhook) {
+	suspended, err := s.registry.IncrementFailCount(ctx, webhook.ID, s.config.FailureThreshold)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to query webhook retries")
+		s.logger.Error().Err(err).Str("webhook_id", webhook.ID).Msg("Failed to record webhook failure")
+		return
+	}
+	if !suspended {
 		return
 	}
-	defer rows.Close()
-
-	retryCount := 0
-	for rows.Next() {
-		var event WebhookEvent
-		var attemptNumber int
-		var payloadJSON []byte
-
-		err := rows.Scan(
-			&event.ID,
-			&event.WebhookID,
-			&event.EventType,
-			&payloadJSON,
-			&event.Timestamp,
-			&event.Signature,
-			&event.DeliveryURL,
-			&attemptNumber,
-		)
-		if err != nil {
-			s.logger.Error().Err(err).Msg("Failed to scan webhook retry")
-			continue
-		}
 
-		// Unmarshal payload
-		if err := json.Unmarshal(payloadJSON, &event.Payload); err != nil {
-			s.logger.Error().Err(err).Msg("Failed to unmarshal webhook payload")
-			continue
-		}
+	RecordWebhookSuspended()
+	RecordWebhookStatusChange(WebhookStatusActive, WebhookStatusFailed)
+
+	s.recordAudit(ctx, auditWebhookSuspended, webhook, map[string]string{
+		"url":                  webhook.URL,
+		"reason":               "consecutive_failures",
+		"consecutive_failures": strconv.Itoa(s.config.FailureThreshold),
+	})
+
+	payload := map[string]interface{}{
+		"webhook_id":           webhook.ID,
+		"url":                  webhook.URL,
+		"reason":               "consecutive_failures",
+		"consecutive_failures": s.config.FailureThreshold,
+		"suspended_at":         time.Now().UTC(),
+	}
+	if err := s.DispatchToWebhooks(ctx, EventWebhookDisabled, webhook.OrgID, payload); err != nil {
+		s.logger.Error().Err(err).Str("webhook_id", webhook.ID).Msg("Failed to dispatch webhook.disabled event")
+	}
+}
 
-		// Retry delivery
-		go s.deliverEvent(&event, attemptNumber+1)
-		retryCount++
+// probeSuspended sends a probe to each suspended webhook that is due one,
+// and reactivates those that respond with success. Their held events are
+// then delivered.
+func (s *DeliveryService) probeSuspended() {
+	webhooks, err := s.registry.ClaimProbes(s.ctx, s.config.MaxConcurrent, s.config.ProbeInterval)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to claim webhook probes")
+		return
 	}
 
-	if retryCount > 0 {
-		s.logger.Info().
-			Int("count", retryCount).
-			Msg("Queued webhook retries")
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		wg.Add(1)
+		go func(webhook *Webhook) {
+			defer wg.Done()
+			s.probe(webhook)
+		}(webhook)
 	}
+	wg.Wait()
 }
 
-// saveEvent saves a webhook event to the database
-func (s *DeliveryService) saveEvent(ctx context.Context, event *WebhookEvent) error {
-	query := `
-		INSERT INTO webhook_events (
-			id, webhook_id, event_type, payload,
-			timestamp, signature, delivery_url
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
+// probe sends a webhook.probe request to a suspended webhook
+func (s *DeliveryService) probe(webhook *Webhook) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.config.TimeoutDuration)
+	defer cancel()
+
+	now := time.Now().UTC()
+	eventID := uuid.New().String()
+	body, _ := json.Marshal(map[string]interface{}{
+		"probe":      true,
+		"webhook_id": webhook.ID,
+		"timestamp":  now,
+	})
+
+	headers := map[string]string{
+		"X-Event-ID":          eventID,
+		"X-Event-Type":        string(eventTypeProbe),
+		"X-Webhook-Timestamp": now.Format(time.RFC3339),
+	}
+	statusCode, _, err := s.send(ctx, webhook, webhook.URL, headers, body)
+	recovered := err == nil && statusCode >= 200 && statusCode < 300
+	RecordWebhookProbe(recovered)
+
+	if !recovered {
+		s.logger.Debug().
+			Err(err).
+			Str("webhook_id", webhook.ID).
+			Int("status_code", statusCode).
+			Msg("Suspended webhook probe failed")
+		return
+	}
 
-	payloadJSON, err := json.Marshal(event.Payload)
+	reactivated, err := s.registry.Reactivate(ctx, webhook.ID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		s.logger.Error().Err(err).Str("webhook_id", webhook.ID).Msg("Failed to reactivate webhook")
+		return
+	}
+	if !reactivated {
+		return
 	}
 
-	_, err = s.db.ExecContext(ctx, query,
-		event.ID,
-		event.WebhookID,
-		event.EventType,
-		payloadJSON,
-		event.Timestamp,
-		event.Signature,
-		event.DeliveryURL,
-	)
+	RecordWebhookStatusChange(WebhookStatusFailed, WebhookStatusActive)
+	s.recordAudit(ctx, auditWebhookReactivated, webhook, map[string]string{
+		"url":         webhook.URL,
+		"status_code": strconv.Itoa(statusCode),
+	})
+	s.logger.Info().
+		Str("webhook_id", webhook.ID).
+		Msg("Suspended webhook recovered and was reactivated")
+	s.wake()
+}
 
-	return err
+// recordAudit records a change to a webhook's health in the audit log of
+// its organization
+func (s *DeliveryService) recordAudit(ctx context.Context, eventType string, webhook *Webhook, details map[string]string) {
+	s.audit.RecordEntry(ctx, &database.AuditEntry{
+		EventType:  eventType,
+		Success:    true,
+		ActorID:    auditActorSystem,
+		OrgID:      webhook.OrgID,
+		TargetType: auditTargetWebhook,
+		TargetID:   webhook.ID,
+		Details:    details,
+	})
+}
+
+// finishAttempt records a delivery attempt and updates the event's queue
+// state: delivered, failed for good, or rescheduled for nextRetry
+func (s *DeliveryService) finishAttempt(
+	ctx context.Context,
+	event *WebhookEvent,
+	statusCode int,
+	responseBody string,
+	errorMessage string,
+	success bool,
+	duration time.Duration,
+	nextRetry *time.Time,
+) {
+	s.recordAttempt(event, event.Attempts, statusCode, responseBody, errorMessage, success, duration, nextRetry)
+
+	if err := s.completeEvent(ctx, event, success, errorMessage, nextRetry); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("event_id", event.ID).
+			Msg("Failed to update webhook event")
+	}
 }
 
 // recordAttempt records a delivery attempt
@@ -440,15 +618,11 @@ func (s *DeliveryService) calculateNextRetry(attemptNumber int) *time.Time {
 	return &nextRetry
 }
 
-// generateSignature generates an HMAC signature for the payload
-func (s *DeliveryService) generateSignature(secret string, payload map[string]interface{}) string {
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		return ""
-	}
-
+// legacySignature generates the unbound HMAC sent as LegacySignatureHeader
+// to webhooks that opted in
+func legacySignature(secret string, body []byte) string {
 	h := hmac.New(sha256.New, []byte(secret))
-	h.Write(payloadJSON)
+	h.Write(body)
 	return hex.EncodeToString(h.Sum(nil))
 }
 
@@ -0,0 +1,163 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/pkg/webhooksig"
+	"github.com/rs/zerolog"
+)
+
+// newMockDeliveryService returns a delivery service whose database is
+// backed by sqlmock
+func newMockDeliveryService(t *testing.T) (*DeliveryService, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+	})
+
+	db := &database.DB{DB: conn}
+	s := NewDeliveryService(nil, NewRegistry(db, zerolog.Nop()), db, zerolog.Nop())
+	t.Cleanup(s.cancel)
+	return s, mock
+}
+
+// expectAuditEntry expects an audit log entry about a webhook
+func expectAuditEntry(mock sqlmock.Sqlmock, eventType, orgID, webhookID string) {
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT hash FROM auth_audit_log").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO auth_audit_log").
+		WithArgs(eventType, true, auditActorSystem, "", "", orgID, auditTargetWebhook, webhookID,
+			"", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+}
+
+func TestSend_LegacySignatureIsOptIn(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	s := NewDeliveryService(nil, nil, nil, zerolog.Nop())
+	defer s.cancel()
+	body := []byte(`{"event":"message.created"}`)
+
+	webhook := &Webhook{ID: "hook", Secret: "secret"}
+	if _, _, err := s.send(context.Background(), webhook, server.URL, nil, body); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if headers.Get(webhooksig.SignatureHeader) == "" {
+		t.Error("Expected every delivery to be signed")
+	}
+	if got := headers.Get(LegacySignatureHeader); got != "" {
+		t.Errorf("Expected no legacy signature without opt-in, got %q", got)
+	}
+
+	webhook.LegacySignature = true
+	if _, _, err := s.send(context.Background(), webhook, server.URL, nil, body); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if got := headers.Get(LegacySignatureHeader); got != legacySignature("secret", body) {
+		t.Errorf("Expected legacy signature for opted-in webhook, got %q", got)
+	}
+}
+
+func TestSendsLegacySignature_EndsAtSunset(t *testing.T) {
+	webhook := &Webhook{LegacySignature: true}
+
+	if !webhook.SendsLegacySignature(LegacySignatureSunset.Add(-time.Second)) {
+		t.Error("Expected legacy signature before the sunset")
+	}
+	if webhook.SendsLegacySignature(LegacySignatureSunset) {
+		t.Error("Expected no legacy signature from the sunset on")
+	}
+}
+
+func TestRecordFailure_BelowThresholdNotifiesNobody(t *testing.T) {
+	s, mock := newMockDeliveryService(t)
+	webhook := &Webhook{ID: "hook", OrgID: "org-a", URL: "https://example.com/hook"}
+
+	mock.ExpectQuery("UPDATE webhooks").
+		WithArgs("hook", s.config.FailureThreshold, healthWeight).
+		WillReturnRows(sqlmock.NewRows([]string{"suspended"}).AddRow(false))
+
+	s.recordFailure(context.Background(), webhook)
+}
+
+func TestRecordFailure_SuspensionIsAudited(t *testing.T) {
+	s, mock := newMockDeliveryService(t)
+	webhook := &Webhook{ID: "hook", OrgID: "org-a", URL: "https://example.com/hook"}
+
+	mock.ExpectQuery("UPDATE webhooks").
+		WithArgs("hook", s.config.FailureThreshold, healthWeight).
+		WillReturnRows(sqlmock.NewRows([]string{"suspended"}).AddRow(true))
+	// The suspended webhook cannot be told about its own suspension, so it
+	// is recorded in the organization's audit log
+	expectAuditEntry(mock, auditWebhookSuspended, "org-a", "hook")
+	mock.ExpectQuery("FROM webhooks").
+		WithArgs(string(EventWebhookDisabled), "org-a").
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	s.recordFailure(context.Background(), webhook)
+}
+
+func TestProbe_RecoveryReactivatesWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Event-Type") != string(eventTypeProbe) {
+			t.Errorf("Expected a probe request, got %q", r.Header.Get("X-Event-Type"))
+		}
+	}))
+	defer server.Close()
+
+	s, mock := newMockDeliveryService(t)
+	webhook := &Webhook{ID: "hook", OrgID: "org-a", URL: server.URL, Secret: "secret"}
+
+	mock.ExpectExec("UPDATE webhooks .* WHERE id = \\$1 AND status = 'FAILED'").
+		WithArgs("hook", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAuditEntry(mock, auditWebhookReactivated, "org-a", "hook")
+
+	s.probe(webhook)
+}
+
+func TestProbe_FailureKeepsWebhookSuspended(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// No queries are expected: the webhook stays suspended
+	s, _ := newMockDeliveryService(t)
+	s.probe(&Webhook{ID: "hook", OrgID: "org-a", URL: server.URL, Secret: "secret"})
+}
+
+func TestProbe_PausedWebhookNotReactivated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	s, mock := newMockDeliveryService(t)
+
+	// The owner paused the webhook while it was suspended
+	mock.ExpectExec("UPDATE webhooks").
+		WithArgs("hook", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.probe(&Webhook{ID: "hook", OrgID: "org-a", URL: server.URL, Secret: "secret"})
+}
@@ -3,7 +3,9 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
 	"github.com/EmekaIwuagwu/articium-hub/internal/types"
 	"github.com/rs/zerolog"
 )
@@ -28,6 +30,18 @@ func NewNotifier(
 	}
 }
 
+// NewQueueNotifier creates a notifier for services that only queue webhook
+// events. The events are delivered by the delivery service of whichever API
+// server claims them, so the notifying service needs no delivery loop.
+func NewQueueNotifier(db *database.DB, logger zerolog.Logger) *Notifier {
+	registry := NewRegistry(db, logger)
+	return NewNotifier(
+		NewDeliveryService(nil, registry, db, logger),
+		NewTrackingService(db, logger),
+		logger,
+	)
+}
+
 // NotifyMessageCreated sends webhook notifications when a message is created
 func (n *Notifier) NotifyMessageCreated(ctx context.Context, message *types.CrossChainMessage) error {
 	payload := map[string]interface{}{
@@ -45,7 +59,10 @@ func (n *Notifier) NotifyMessageCreated(ctx context.Context, message *types.Cros
 		Timestamp: message.CreatedAt,
 	}
 
-	return n.dispatchEvent(ctx, EventMessageCreated, payload, event)
+	// Publish to tracking streams
+	n.track(ctx, EventMessageCreated, message, nil)
+
+	return n.dispatchEvent(ctx, EventMessageCreated, messageOrgID(message), payload, event)
 }
 
 // NotifyMessagePending sends webhook notifications when a message is pending
@@ -63,7 +80,10 @@ func (n *Notifier) NotifyMessagePending(ctx context.Context, message *types.Cros
 		Timestamp: message.UpdatedAt,
 	}
 
-	return n.dispatchEvent(ctx, EventMessagePending, payload, event)
+	// Publish to tracking streams
+	n.track(ctx, EventMessagePending, message, nil)
+
+	return n.dispatchEvent(ctx, EventMessagePending, messageOrgID(message), payload, event)
 }
 
 // NotifyMessageSubmitted sends webhook notifications when a message is submitted
@@ -86,11 +106,10 @@ func (n *Notifier) NotifyMessageSubmitted(ctx context.Context, message *types.Cr
 	}
 
 	// Record timeline event
-	if err := n.recordTimelineEvent(ctx, message.ID, "message_submitted", "Message submitted to destination chain", txHash, blockNumber, message.DestinationChain.ChainID); err != nil {
-		n.logger.Warn().Err(err).Msg("Failed to record timeline event")
-	}
+	timeline := timelineEvent("message_submitted", "Message submitted to destination chain", txHash, blockNumber, message.DestinationChain.ChainID)
+	n.track(ctx, EventMessageSubmitted, message, timeline)
 
-	return n.dispatchEvent(ctx, EventMessageSubmitted, payload, event)
+	return n.dispatchEvent(ctx, EventMessageSubmitted, messageOrgID(message), payload, event)
 }
 
 // NotifyMessageConfirmed sends webhook notifications when a message is confirmed
@@ -113,11 +132,10 @@ func (n *Notifier) NotifyMessageConfirmed(ctx context.Context, message *types.Cr
 	}
 
 	// Record timeline event
-	if err := n.recordTimelineEvent(ctx, message.ID, "message_confirmed", fmt.Sprintf("Message confirmed with %d confirmations", confirmations), message.DestTxHash, 0, message.DestinationChain.ChainID); err != nil {
-		n.logger.Warn().Err(err).Msg("Failed to record timeline event")
-	}
+	timeline := timelineEvent("message_confirmed", fmt.Sprintf("Message confirmed with %d confirmations", confirmations), message.DestTxHash, 0, message.DestinationChain.ChainID)
+	n.track(ctx, EventMessageConfirmed, message, timeline)
 
-	return n.dispatchEvent(ctx, EventMessageConfirmed, payload, event)
+	return n.dispatchEvent(ctx, EventMessageConfirmed, messageOrgID(message), payload, event)
 }
 
 // NotifyMessageFinalized sends webhook notifications when a message is finalized
@@ -138,11 +156,10 @@ func (n *Notifier) NotifyMessageFinalized(ctx context.Context, message *types.Cr
 	}
 
 	// Record timeline event
-	if err := n.recordTimelineEvent(ctx, message.ID, "message_finalized", "Message finalized on destination chain", message.DestTxHash, 0, message.DestinationChain.ChainID); err != nil {
-		n.logger.Warn().Err(err).Msg("Failed to record timeline event")
-	}
+	timeline := timelineEvent("message_finalized", "Message finalized on destination chain", message.DestTxHash, 0, message.DestinationChain.ChainID)
+	n.track(ctx, EventMessageFinalized, message, timeline)
 
-	return n.dispatchEvent(ctx, EventMessageFinalized, payload, event)
+	return n.dispatchEvent(ctx, EventMessageFinalized, messageOrgID(message), payload, event)
 }
 
 // NotifyMessageFailed sends webhook notifications when a message fails
@@ -163,11 +180,19 @@ func (n *Notifier) NotifyMessageFailed(ctx context.Context, message *types.Cross
 	}
 
 	// Record timeline event
-	if err := n.recordTimelineEvent(ctx, message.ID, "message_failed", fmt.Sprintf("Message failed: %s", errorMsg), "", 0, ""); err != nil {
-		n.logger.Warn().Err(err).Msg("Failed to record timeline event")
-	}
+	timeline := timelineEvent("message_failed", fmt.Sprintf("Message failed: %s", errorMsg), "", 0, "")
+	n.track(ctx, EventMessageFailed, message, timeline)
 
-	return n.dispatchEvent(ctx, EventMessageFailed, payload, event)
+	return n.dispatchEvent(ctx, EventMessageFailed, messageOrgID(message), payload, event)
+}
+
+// RecordMessageBatched records a message being added to a batch in its
+// timeline and tracking streams. Webhooks are notified once per batch by
+// NotifyBatchCreated.
+func (n *Notifier) RecordMessageBatched(ctx context.Context, message *types.CrossChainMessage, batchID string) {
+	timeline := timelineEvent("message_batched", fmt.Sprintf("Message added to batch %s", batchID), "", 0, "")
+	timeline.Metadata["batch_id"] = batchID
+	n.track(ctx, EventBatchCreated, message, timeline)
 }
 
 // NotifyBatchCreated sends webhook notifications when a batch is created
@@ -184,7 +209,7 @@ func (n *Notifier) NotifyBatchCreated(ctx context.Context, batchID string, messa
 		Event:        EventBatchCreated,
 	}
 
-	return n.dispatchEvent(ctx, EventBatchCreated, payload, event)
+	return n.dispatchEvent(ctx, EventBatchCreated, "", payload, event)
 }
 
 // NotifyBatchSubmitted sends webhook notifications when a batch is submitted
@@ -203,7 +228,7 @@ func (n *Notifier) NotifyBatchSubmitted(ctx context.Context, batchID string, mes
 		TxHash:       txHash,
 	}
 
-	return n.dispatchEvent(ctx, EventBatchSubmitted, payload, event)
+	return n.dispatchEvent(ctx, EventBatchSubmitted, "", payload, event)
 }
 
 // NotifyBatchConfirmed sends webhook notifications when a batch is confirmed
@@ -226,7 +251,7 @@ func (n *Notifier) NotifyBatchConfirmed(ctx context.Context, batchID string, mes
 		SavingsPercent: savingsPercent,
 	}
 
-	return n.dispatchEvent(ctx, EventBatchConfirmed, payload, event)
+	return n.dispatchEvent(ctx, EventBatchConfirmed, "", payload, event)
 }
 
 // NotifyBatchFailed sends webhook notifications when a batch fails
@@ -245,17 +270,19 @@ func (n *Notifier) NotifyBatchFailed(ctx context.Context, batchID string, messag
 		ErrorMessage: errorMsg,
 	}
 
-	return n.dispatchEvent(ctx, EventBatchFailed, payload, event)
+	return n.dispatchEvent(ctx, EventBatchFailed, "", payload, event)
 }
 
 // Helper methods
 
-func (n *Notifier) dispatchEvent(ctx context.Context, eventType EventType, payload map[string]interface{}, event interface{}) error {
+// dispatchEvent delivers an event to subscribed webhooks. Message events only
+// reach the owning organization's webhooks; batch events reach all of them.
+func (n *Notifier) dispatchEvent(ctx context.Context, eventType EventType, orgID string, payload map[string]interface{}, event interface{}) error {
 	// Record the event type in metrics
 	RecordWebhookEvent(eventType)
 
 	// Dispatch to all registered webhooks
-	if err := n.delivery.DispatchToWebhooks(ctx, eventType, payload); err != nil {
+	if err := n.delivery.DispatchToWebhooks(ctx, eventType, orgID, payload); err != nil {
 		n.logger.Error().
 			Err(err).
 			Str("event_type", string(eventType)).
@@ -270,17 +297,51 @@ func (n *Notifier) dispatchEvent(ctx context.Context, eventType EventType, paylo
 	return nil
 }
 
-func (n *Notifier) recordTimelineEvent(ctx context.Context, messageID, eventType, description, txHash string, blockNumber uint64, chainID string) error {
-	event := &TimelineEvent{
+// messageOrgID returns the organization whose webhooks receive a message's
+// events. Messages observed on-chain have no owner and are only reported to
+// the operator's default organization.
+func messageOrgID(message *types.CrossChainMessage) string {
+	if message.OrgID == "" {
+		return database.DefaultOrganizationID
+	}
+	return message.OrgID
+}
+
+// track records a message event in the message's timeline, if it has a
+// timeline entry, and publishes it to live tracking streams. Failures are
+// logged rather than returned so they never hold up webhook delivery.
+func (n *Notifier) track(ctx context.Context, eventType EventType, message *types.CrossChainMessage, timeline *TimelineEvent) {
+	if timeline != nil {
+		if err := n.trackingService.RecordEvent(ctx, message.ID, timeline); err != nil {
+			n.logger.Warn().Err(err).Msg("Failed to record timeline event")
+		}
+	}
+
+	event := &StreamEvent{
+		MessageID:   message.ID,
+		OrgID:       messageOrgID(message),
+		EventType:   eventType,
+		Status:      string(message.Status),
+		Sender:      message.Sender.Raw,
+		SourceChain: message.SourceChain.ChainID,
+		DestChain:   message.DestinationChain.ChainID,
+		Timeline:    timeline,
+	}
+	if err := n.trackingService.PublishStreamEvent(ctx, event); err != nil {
+		n.logger.Warn().Err(err).Msg("Failed to publish stream event")
+	}
+}
+
+func timelineEvent(eventType, description, txHash string, blockNumber uint64, chainID string) *TimelineEvent {
+	return &TimelineEvent{
 		EventType:   eventType,
+		Timestamp:   time.Now().UTC(),
 		Description: description,
 		TxHash:      txHash,
 		BlockNumber: blockNumber,
 		ChainID:     chainID,
 		Metadata:    make(map[string]interface{}),
 	}
-
-	return n.trackingService.RecordEvent(ctx, messageID, event)
 }
 
 // Example usage in message processor:
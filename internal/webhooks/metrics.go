@@ -68,6 +68,20 @@ var (
 		Help: "Maximum capacity of webhook delivery queue",
 	})
 
+	// Endpoint health metrics
+	WebhooksSuspended = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bridge_webhooks_suspended_total",
+		Help: "Total number of webhooks suspended after consecutive delivery failures",
+	})
+
+	WebhookProbes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bridge_webhook_probes_total",
+			Help: "Total number of health probes sent to suspended webhooks",
+		},
+		[]string{"result"},
+	)
+
 	// Event type metrics
 	WebhookEventsByType = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -102,6 +116,20 @@ var (
 		Help:    "Tracking query latency in seconds",
 		Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5},
 	})
+
+	// Live tracking stream metrics
+	TrackingStreamsOpen = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bridge_tracking_streams_open",
+			Help: "Number of open live tracking streams",
+		},
+		[]string{"transport"},
+	)
+
+	TrackingStreamsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bridge_tracking_streams_dropped_total",
+		Help: "Total number of tracking streams closed for falling behind",
+	})
 )
 
 // Record functions for easier metric recording
@@ -164,6 +192,20 @@ func RecordWebhookLatency(seconds float64) {
 	WebhookDeliveryLatency.Observe(seconds)
 }
 
+// RecordWebhookSuspended records a webhook being suspended
+func RecordWebhookSuspended() {
+	WebhooksSuspended.Inc()
+}
+
+// RecordWebhookProbe records a health probe result
+func RecordWebhookProbe(recovered bool) {
+	result := "failed"
+	if recovered {
+		result = "recovered"
+	}
+	WebhookProbes.WithLabelValues(result).Inc()
+}
+
 // RecordWebhookEvent records a webhook event by type
 func RecordWebhookEvent(eventType EventType) {
 	WebhookEventsByType.WithLabelValues(string(eventType)).Inc()
@@ -189,6 +231,22 @@ func RecordTrackingQueryLatency(seconds float64) {
 	TrackingQueryLatency.Observe(seconds)
 }
 
+// RecordTrackingStreamOpened records a live tracking stream being opened
+func RecordTrackingStreamOpened(transport string) {
+	TrackingStreamsOpen.WithLabelValues(transport).Inc()
+}
+
+// RecordTrackingStreamClosed records a live tracking stream being closed
+func RecordTrackingStreamClosed(transport string) {
+	TrackingStreamsOpen.WithLabelValues(transport).Dec()
+}
+
+// RecordTrackingStreamDropped records a tracking stream closed for falling
+// behind
+func RecordTrackingStreamDropped() {
+	TrackingStreamsDropped.Inc()
+}
+
 // SetWebhookQueueSize updates the webhook queue size gauge
 func SetWebhookQueueSize(size int) {
 	WebhookQueueSize.Set(float64(size))
@@ -0,0 +1,190 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Delivery queue statuses of webhook events
+const (
+	eventStatusPending   = "PENDING"
+	eventStatusDelivered = "DELIVERED"
+	eventStatusFailed    = "FAILED"
+)
+
+// enqueueEvent saves an event as pending delivery
+func (s *DeliveryService) enqueueEvent(ctx context.Context, event *WebhookEvent) error {
+	payloadJSON, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if event.Body == nil {
+		event.Body = payloadJSON
+	}
+
+	query := `
+		INSERT INTO webhook_events (
+			id, webhook_id, event_type, payload,
+			timestamp, body, delivery_url, status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = s.db.ExecContext(ctx, query,
+		event.ID,
+		event.WebhookID,
+		event.EventType,
+		payloadJSON,
+		event.Timestamp,
+		string(event.Body),
+		event.DeliveryURL,
+		eventStatusPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook event: %w", err)
+	}
+
+	return nil
+}
+
+// claimEvents claims up to limit due events for delivery. Claimed events are
+// hidden from other replicas for the lease duration. Events of webhooks
+// that are not active stay queued until the webhook is resumed or recovers.
+// No more than MaxPerEndpoint events per webhook are in flight across
+// replicas, except briefly when replicas claim at the same moment. Events
+// are ranked per webhook before the limit is applied, so a backlogged
+// endpoint cannot crowd out the others.
+func (s *DeliveryService) claimEvents(ctx context.Context, limit int) ([]*WebhookEvent, error) {
+	query := `
+		WITH in_flight AS (
+			SELECT webhook_id, COUNT(*) AS deliveries
+			FROM webhook_events
+			WHERE status = 'PENDING' AND claimed_at IS NOT NULL AND available_at > NOW()
+			GROUP BY webhook_id
+		),
+		ranked AS (
+			SELECT e.id,
+				ROW_NUMBER() OVER (PARTITION BY e.webhook_id ORDER BY e.available_at)
+					+ COALESCE(f.deliveries, 0) AS slot
+			FROM webhook_events e
+			JOIN webhooks w ON w.id = e.webhook_id
+			LEFT JOIN in_flight f ON f.webhook_id = e.webhook_id
+			WHERE e.status = 'PENDING' AND e.available_at <= NOW()
+			AND w.status = 'ACTIVE'
+			AND COALESCE(f.deliveries, 0) < $3
+		),
+		candidates AS (
+			SELECT e.id
+			FROM webhook_events e
+			WHERE e.id IN (SELECT id FROM ranked WHERE slot <= $3)
+			AND e.status = 'PENDING' AND e.available_at <= NOW()
+			ORDER BY e.available_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_events
+		SET attempts = attempts + 1,
+			claimed_at = NOW(),
+			available_at = NOW() + ($2 * INTERVAL '1 millisecond')
+		WHERE id IN (SELECT id FROM candidates)
+		RETURNING id, webhook_id, event_type, payload, timestamp,
+			COALESCE(body, ''), delivery_url, attempts,
+			COALESCE(replay_of, ''), COALESCE(replay_id, '')
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, limit, s.config.Lease.Milliseconds(), s.config.MaxPerEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*WebhookEvent
+	for rows.Next() {
+		var event WebhookEvent
+		var payloadJSON []byte
+		var body string
+
+		err := rows.Scan(
+			&event.ID,
+			&event.WebhookID,
+			&event.EventType,
+			&payloadJSON,
+			&event.Timestamp,
+			&body,
+			&event.DeliveryURL,
+			&event.Attempts,
+			&event.ReplayOf,
+			&event.ReplayID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook event: %w", err)
+		}
+
+		if err := json.Unmarshal(payloadJSON, &event.Payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook payload %s: %w", event.ID, err)
+		}
+		if body != "" {
+			event.Body = []byte(body)
+		}
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate webhook events: %w", err)
+	}
+
+	return events, nil
+}
+
+// completeEvent records the outcome of a delivery attempt. A nil nextRetry
+// ends delivery: the event is marked delivered or, if the attempt failed,
+// failed for good. The update only applies if the event has not been
+// claimed again since, so a delivery that outlived its lease cannot
+// overwrite a newer attempt.
+func (s *DeliveryService) completeEvent(ctx context.Context, event *WebhookEvent, delivered bool, errMsg string, nextRetry *time.Time) error {
+	status := eventStatusPending
+	switch {
+	case delivered:
+		status = eventStatusDelivered
+	case nextRetry == nil:
+		status = eventStatusFailed
+	}
+
+	query := `
+		UPDATE webhook_events
+		SET status = $3,
+			last_error = NULLIF($4, ''),
+			available_at = COALESCE($5, NOW()),
+			claimed_at = NULL,
+			completed_at = CASE WHEN $3 = 'PENDING' THEN NULL ELSE NOW() END
+		WHERE id = $1 AND attempts = $2
+	`
+
+	result, err := s.db.ExecContext(ctx, query, event.ID, event.Attempts, status, errMsg, nextRetry)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook event: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		s.logger.Warn().
+			Str("event_id", event.ID).
+			Int("attempt", event.Attempts).
+			Msg("Webhook event was claimed again before its delivery finished")
+	}
+
+	return nil
+}
+
+// pendingEvents returns the number of events awaiting delivery
+func (s *DeliveryService) pendingEvents(ctx context.Context) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM webhook_events WHERE status = 'PENDING'`
+
+	if err := s.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pending webhook events: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,230 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database"
+	"github.com/EmekaIwuagwu/articium-hub/internal/database/dbtest"
+	"github.com/rs/zerolog"
+)
+
+// newTestDeliveryService returns a delivery service on a fresh schema in
+// the test database, with at most maxPerEndpoint deliveries per webhook
+func newTestDeliveryService(t *testing.T, maxPerEndpoint int) *DeliveryService {
+	t.Helper()
+
+	db := &database.DB{DB: dbtest.Open(t, "../database", database.SchemaFiles)}
+	config := DefaultDeliveryConfig()
+	config.TimeoutDuration = 100 * time.Millisecond
+	config.Lease = 300 * time.Millisecond
+	config.MaxPerEndpoint = maxPerEndpoint
+
+	s := NewDeliveryService(config, NewRegistry(db, zerolog.Nop()), db, zerolog.Nop())
+	t.Cleanup(s.cancel)
+	return s
+}
+
+// insertWebhook writes a webhook with the given status directly
+func insertWebhook(t *testing.T, s *DeliveryService, id, status string) {
+	t.Helper()
+
+	_, err := s.db.Exec(`
+		INSERT INTO webhooks (id, url, secret, events, status, created_by)
+		VALUES ($1, 'https://example.com/hook', 'secret', '{message.created}', $2, 'test')
+	`, id, status)
+	if err != nil {
+		t.Fatalf("Failed to insert webhook: %v", err)
+	}
+}
+
+// enqueueEvents queues n events for a webhook, oldest first
+func enqueueEvents(t *testing.T, s *DeliveryService, webhookID string, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		event := &WebhookEvent{
+			ID:          fmt.Sprintf("%s-event-%d", webhookID, i),
+			WebhookID:   webhookID,
+			EventType:   EventMessageCreated,
+			Payload:     map[string]interface{}{"n": i},
+			Timestamp:   time.Now().UTC(),
+			DeliveryURL: "https://example.com/hook",
+		}
+		if err := s.enqueueEvent(context.Background(), event); err != nil {
+			t.Fatalf("enqueueEvent failed: %v", err)
+		}
+	}
+}
+
+// countByWebhook counts claimed events per webhook
+func countByWebhook(events []*WebhookEvent) map[string]int {
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.WebhookID]++
+	}
+	return counts
+}
+
+func TestClaimEvents_PerEndpointInFlightCap(t *testing.T) {
+	s := newTestDeliveryService(t, 2)
+	ctx := context.Background()
+
+	insertWebhook(t, s, "busy", "ACTIVE")
+	insertWebhook(t, s, "quiet", "ACTIVE")
+	insertWebhook(t, s, "suspended", "FAILED")
+	enqueueEvents(t, s, "busy", 5)
+	enqueueEvents(t, s, "quiet", 1)
+	enqueueEvents(t, s, "suspended", 1)
+
+	// One busy endpoint must not take every delivery slot
+	events, err := s.claimEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("claimEvents failed: %v", err)
+	}
+	counts := countByWebhook(events)
+	if counts["busy"] != 2 || counts["quiet"] != 1 || counts["suspended"] != 0 {
+		t.Fatalf("Expected 2 busy and 1 quiet event claimed, got %v", counts)
+	}
+
+	// The busy endpoint's slots are taken until a delivery completes
+	if again, _ := s.claimEvents(ctx, 10); len(again) != 0 {
+		t.Fatalf("Expected no events while the endpoint is at its cap, got %v", countByWebhook(again))
+	}
+
+	var busy *WebhookEvent
+	for _, event := range events {
+		if event.WebhookID == "busy" {
+			busy = event
+			break
+		}
+	}
+	if err := s.completeEvent(ctx, busy, true, "", nil); err != nil {
+		t.Fatalf("completeEvent failed: %v", err)
+	}
+
+	next, err := s.claimEvents(ctx, 10)
+	if err != nil {
+		t.Fatalf("claimEvents failed: %v", err)
+	}
+	if counts := countByWebhook(next); len(next) != 1 || counts["busy"] != 1 {
+		t.Errorf("Expected the freed slot to be refilled by one busy event, got %v", counts)
+	}
+}
+
+func TestClaimEvents_SaturatedEndpointDoesNotStarveOthers(t *testing.T) {
+	s := newTestDeliveryService(t, 2)
+	ctx := context.Background()
+
+	// The saturated endpoint's backlog is older than any other event
+	insertWebhook(t, s, "saturated", "ACTIVE")
+	insertWebhook(t, s, "idle", "ACTIVE")
+	enqueueEvents(t, s, "saturated", 40)
+	enqueueEvents(t, s, "idle", 1)
+
+	first, err := s.claimEvents(ctx, 2)
+	if err != nil {
+		t.Fatalf("claimEvents failed: %v", err)
+	}
+	if counts := countByWebhook(first); counts["saturated"] != 2 {
+		t.Fatalf("Expected the oldest events to be claimed first, got %v", counts)
+	}
+
+	// The saturated endpoint's backlog fills far more than limit * 4 rows,
+	// but the idle endpoint must still be served
+	next, err := s.claimEvents(ctx, 2)
+	if err != nil {
+		t.Fatalf("claimEvents failed: %v", err)
+	}
+	if counts := countByWebhook(next); len(next) != 1 || counts["idle"] != 1 {
+		t.Errorf("Expected the idle endpoint's event to be claimed, got %v", counts)
+	}
+}
+
+func TestCompleteEvent_StaleAttemptIgnored(t *testing.T) {
+	s := newTestDeliveryService(t, 2)
+	ctx := context.Background()
+
+	insertWebhook(t, s, "hook", "ACTIVE")
+	enqueueEvents(t, s, "hook", 1)
+
+	first, err := s.claimEvents(ctx, 1)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("Expected one claimed event, got %d: %v", len(first), err)
+	}
+
+	// The first delivery outlives its lease and the event is claimed again
+	time.Sleep(s.config.Lease + 100*time.Millisecond)
+	second, err := s.claimEvents(ctx, 1)
+	if err != nil || len(second) != 1 || second[0].Attempts != 2 {
+		t.Fatalf("Expected the event to be reclaimed on attempt 2, got %+v: %v", second, err)
+	}
+
+	// The stale attempt's failure must not overwrite the newer attempt
+	if err := s.completeEvent(ctx, first[0], false, "timeout", nil); err != nil {
+		t.Fatalf("completeEvent failed: %v", err)
+	}
+	if status := eventStatus(t, s, first[0].ID); status != eventStatusPending {
+		t.Fatalf("Expected stale completion to be ignored, got status %s", status)
+	}
+
+	if err := s.completeEvent(ctx, second[0], true, "", nil); err != nil {
+		t.Fatalf("completeEvent failed: %v", err)
+	}
+	if status := eventStatus(t, s, first[0].ID); status != eventStatusDelivered {
+		t.Errorf("Expected current attempt to complete the event, got status %s", status)
+	}
+}
+
+func eventStatus(t *testing.T, s *DeliveryService, eventID string) string {
+	t.Helper()
+
+	var status string
+	if err := s.db.QueryRow(`SELECT status FROM webhook_events WHERE id = $1`, eventID).Scan(&status); err != nil {
+		t.Fatalf("Failed to read event status: %v", err)
+	}
+	return status
+}
+
+func TestCompleteEvent_GuardsOnAttempt(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer conn.Close()
+
+	s := NewDeliveryService(nil, nil, &database.DB{DB: conn}, zerolog.Nop())
+	defer s.cancel()
+
+	retry := time.Now().Add(time.Minute)
+	tests := []struct {
+		name      string
+		delivered bool
+		nextRetry *time.Time
+		status    string
+	}{
+		{"delivered", true, nil, eventStatusDelivered},
+		{"retry", false, &retry, eventStatusPending},
+		{"exhausted", false, nil, eventStatusFailed},
+	}
+
+	for _, tt := range tests {
+		event := &WebhookEvent{ID: "event-1", Attempts: 3}
+
+		// A newer claim has bumped attempts, so no row matches
+		mock.ExpectExec("UPDATE webhook_events .* WHERE id = \\$1 AND attempts = \\$2").
+			WithArgs("event-1", 3, tt.status, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := s.completeEvent(context.Background(), event, tt.delivered, "", tt.nextRetry); err != nil {
+			t.Errorf("%s: completeEvent failed: %v", tt.name, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet expectations: %v", err)
+	}
+}
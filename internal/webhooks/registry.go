@@ -14,6 +14,15 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// webhookColumns are the columns scanned by scanWebhook
+const webhookColumns = `
+			id, url, secret, events, status, description,
+			created_by, org_id, created_at, updated_at, last_used_at,
+			fail_count, success_count,
+			source_chains, dest_chains, min_amount, max_amount,
+			COALESCE(previous_secret, ''), previous_secret_expires_at,
+			health_score, suspended_at, last_probe_at, legacy_signature`
+
 // Registry manages webhook registrations
 type Registry struct {
 	db     *database.DB
@@ -38,6 +47,9 @@ func (r *Registry) Register(ctx context.Context, webhook *Webhook) error {
 	webhook.UpdatedAt = webhook.CreatedAt
 	webhook.FailCount = 0
 	webhook.SuccessCount = 0
+	if webhook.OrgID == "" {
+		webhook.OrgID = database.DefaultOrganizationID
+	}
 
 	// Validate webhook
 	if err := r.validateWebhook(webhook); err != nil {
@@ -49,8 +61,9 @@ func (r *Registry) Register(ctx context.Context, webhook *Webhook) error {
 		INSERT INTO webhooks (
 			id, url, secret, events, status, description,
 			created_by, created_at, updated_at, fail_count, success_count,
-			source_chains, dest_chains, min_amount, max_amount
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			source_chains, dest_chains, min_amount, max_amount, org_id,
+			legacy_signature
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 
 	events := make([]string, len(webhook.Events))
@@ -74,6 +87,8 @@ func (r *Registry) Register(ctx context.Context, webhook *Webhook) error {
 		pq.Array(webhook.DestChains),
 		webhook.MinAmount,
 		webhook.MaxAmount,
+		webhook.OrgID,
+		webhook.LegacySignature,
 	)
 
 	if err != nil {
@@ -92,38 +107,12 @@ func (r *Registry) Register(ctx context.Context, webhook *Webhook) error {
 // Get retrieves a webhook by ID
 func (r *Registry) Get(ctx context.Context, webhookID string) (*Webhook, error) {
 	query := `
-		SELECT
-			id, url, secret, events, status, description,
-			created_by, created_at, updated_at, last_used_at,
-			fail_count, success_count,
-			source_chains, dest_chains, min_amount, max_amount
+		SELECT ` + webhookColumns + `
 		FROM webhooks
 		WHERE id = $1
 	`
 
-	webhook := &Webhook{}
-	var events pq.StringArray
-	var sourceChains, destChains pq.StringArray
-
-	err := r.db.QueryRowContext(ctx, query, webhookID).Scan(
-		&webhook.ID,
-		&webhook.URL,
-		&webhook.Secret,
-		&events,
-		&webhook.Status,
-		&webhook.Description,
-		&webhook.CreatedBy,
-		&webhook.CreatedAt,
-		&webhook.UpdatedAt,
-		&webhook.LastUsedAt,
-		&webhook.FailCount,
-		&webhook.SuccessCount,
-		&sourceChains,
-		&destChains,
-		&webhook.MinAmount,
-		&webhook.MaxAmount,
-	)
-
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, webhookID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("webhook not found")
 	}
@@ -131,31 +120,20 @@ func (r *Registry) Get(ctx context.Context, webhookID string) (*Webhook, error)
 		return nil, fmt.Errorf("failed to get webhook: %w", err)
 	}
 
-	// Convert string arrays to typed arrays
-	webhook.Events = make([]EventType, len(events))
-	for i, e := range events {
-		webhook.Events[i] = EventType(e)
-	}
-	webhook.SourceChains = sourceChains
-	webhook.DestChains = destChains
-
 	return webhook, nil
 }
 
-// List retrieves all webhooks for a user
-func (r *Registry) List(ctx context.Context, createdBy string) ([]*Webhook, error) {
+// List retrieves all webhooks owned by an organization, or every webhook if
+// orgID is empty
+func (r *Registry) List(ctx context.Context, orgID string) ([]*Webhook, error) {
 	query := `
-		SELECT
-			id, url, secret, events, status, description,
-			created_by, created_at, updated_at, last_used_at,
-			fail_count, success_count,
-			source_chains, dest_chains, min_amount, max_amount
+		SELECT ` + webhookColumns + `
 		FROM webhooks
-		WHERE created_by = $1
+		WHERE ($1 = '' OR org_id = $1)
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, createdBy)
+	rows, err := r.db.QueryContext(ctx, query, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list webhooks: %w", err)
 	}
@@ -163,39 +141,10 @@ func (r *Registry) List(ctx context.Context, createdBy string) ([]*Webhook, erro
 
 	webhooks := []*Webhook{}
 	for rows.Next() {
-		webhook := &Webhook{}
-		var events pq.StringArray
-		var sourceChains, destChains pq.StringArray
-
-		err := rows.Scan(
-			&webhook.ID,
-			&webhook.URL,
-			&webhook.Secret,
-			&events,
-			&webhook.Status,
-			&webhook.Description,
-			&webhook.CreatedBy,
-			&webhook.CreatedAt,
-			&webhook.UpdatedAt,
-			&webhook.LastUsedAt,
-			&webhook.FailCount,
-			&webhook.SuccessCount,
-			&sourceChains,
-			&destChains,
-			&webhook.MinAmount,
-			&webhook.MaxAmount,
-		)
+		webhook, err := scanWebhook(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan webhook: %w", err)
 		}
-
-		webhook.Events = make([]EventType, len(events))
-		for i, e := range events {
-			webhook.Events[i] = EventType(e)
-		}
-		webhook.SourceChains = sourceChains
-		webhook.DestChains = destChains
-
 		webhooks = append(webhooks, webhook)
 	}
 
@@ -210,7 +159,7 @@ func (r *Registry) Update(ctx context.Context, webhook *Webhook) error {
 		UPDATE webhooks
 		SET url = $2, events = $3, status = $4, description = $5,
 		    updated_at = $6, source_chains = $7, dest_chains = $8,
-		    min_amount = $9, max_amount = $10
+		    min_amount = $9, max_amount = $10, legacy_signature = $11
 		WHERE id = $1
 	`
 
@@ -230,6 +179,7 @@ func (r *Registry) Update(ctx context.Context, webhook *Webhook) error {
 		pq.Array(webhook.DestChains),
 		webhook.MinAmount,
 		webhook.MaxAmount,
+		webhook.LegacySignature,
 	)
 
 	if err != nil {
@@ -277,11 +227,14 @@ func (r *Registry) Delete(ctx context.Context, webhookID string) error {
 	return nil
 }
 
-// UpdateStatus updates the status of a webhook
+// UpdateStatus updates the status of a webhook. Setting a webhook active
+// clears its failure streak and suspension.
 func (r *Registry) UpdateStatus(ctx context.Context, webhookID string, status WebhookStatus) error {
 	query := `
 		UPDATE webhooks
-		SET status = $2, updated_at = $3
+		SET status = $2, updated_at = $3,
+		    fail_count = CASE WHEN $2 = 'ACTIVE' THEN 0 ELSE fail_count END,
+		    suspended_at = CASE WHEN $2 = 'ACTIVE' THEN NULL ELSE suspended_at END
 		WHERE id = $1
 	`
 
@@ -302,18 +255,22 @@ func (r *Registry) UpdateStatus(ctx context.Context, webhookID string, status We
 	return nil
 }
 
-// IncrementSuccessCount increments the success count and updates last used time
+// healthWeight is the weight of the latest attempt in the health score
+const healthWeight = 0.1
+
+// IncrementSuccessCount records a successful delivery: it increments the
+// success count, ends the failure streak and raises the health score
 func (r *Registry) IncrementSuccessCount(ctx context.Context, webhookID string) error {
 	query := `
 		UPDATE webhooks
 		SET success_count = success_count + 1,
 		    last_used_at = $2,
 		    fail_count = 0,
-		    status = CASE WHEN status = 'FAILED' THEN 'ACTIVE' ELSE status END
+		    health_score = health_score * (1 - $3) + $3
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, webhookID, time.Now().UTC())
+	_, err := r.db.ExecContext(ctx, query, webhookID, time.Now().UTC(), healthWeight)
 	if err != nil {
 		return fmt.Errorf("failed to increment success count: %w", err)
 	}
@@ -321,41 +278,159 @@ func (r *Registry) IncrementSuccessCount(ctx context.Context, webhookID string)
 	return nil
 }
 
-// IncrementFailCount increments the fail count
-func (r *Registry) IncrementFailCount(ctx context.Context, webhookID string) error {
+// IncrementFailCount records a failed delivery: it extends the failure
+// streak and lowers the health score. An active webhook whose streak
+// reaches threshold is suspended; suspended reports whether this call
+// suspended it.
+func (r *Registry) IncrementFailCount(ctx context.Context, webhookID string, threshold int) (suspended bool, err error) {
+	// The SET expressions see the row before the update, so suspended_at is
+	// only set to this statement's NOW() on the transition
 	query := `
 		UPDATE webhooks
 		SET fail_count = fail_count + 1,
+		    health_score = health_score * (1 - $3),
 		    status = CASE
-		        WHEN fail_count >= 10 THEN 'FAILED'
+		        WHEN status = 'ACTIVE' AND fail_count + 1 >= $2 THEN 'FAILED'
 		        ELSE status
+		    END,
+		    suspended_at = CASE
+		        WHEN status = 'ACTIVE' AND fail_count + 1 >= $2 THEN NOW()
+		        ELSE suspended_at
 		    END
 		WHERE id = $1
+		RETURNING COALESCE(suspended_at = NOW(), false)
 	`
 
-	_, err := r.db.ExecContext(ctx, query, webhookID)
+	err = r.db.QueryRowContext(ctx, query, webhookID, threshold, healthWeight).Scan(&suspended)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to increment fail count: %w", err)
+		return false, fmt.Errorf("failed to increment fail count: %w", err)
 	}
 
-	return nil
+	if suspended {
+		r.logger.Warn().
+			Str("webhook_id", webhookID).
+			Int("consecutive_failures", threshold).
+			Msg("Webhook suspended after consecutive delivery failures")
+	}
+
+	return suspended, nil
 }
 
-// GetActiveWebhooksForEvent retrieves all active webhooks subscribed to an event
-func (r *Registry) GetActiveWebhooksForEvent(ctx context.Context, eventType EventType) ([]*Webhook, error) {
+// ClaimProbes claims up to limit suspended webhooks that are due a health
+// probe. A claimed webhook is not probed again by any replica for interval.
+func (r *Registry) ClaimProbes(ctx context.Context, limit int, interval time.Duration) ([]*Webhook, error) {
 	query := `
-		SELECT
-			id, url, secret, events, status, description,
-			created_by, created_at, updated_at, last_used_at,
-			fail_count, success_count,
-			source_chains, dest_chains, min_amount, max_amount
+		UPDATE webhooks
+		SET last_probe_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhooks
+			WHERE status = 'FAILED'
+			AND (last_probe_at IS NULL OR last_probe_at <= NOW() - ($2 * INTERVAL '1 millisecond'))
+			ORDER BY last_probe_at NULLS FIRST
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookColumns
+
+	rows, err := r.db.QueryContext(ctx, query, limit, interval.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook probes: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// Reactivate re-enables a suspended webhook. It does nothing if the webhook
+// is no longer suspended, for example because its owner paused it.
+func (r *Registry) Reactivate(ctx context.Context, webhookID string) (bool, error) {
+	query := `
+		UPDATE webhooks
+		SET status = 'ACTIVE', fail_count = 0, suspended_at = NULL, updated_at = $2
+		WHERE id = $1 AND status = 'FAILED'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, webhookID, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to reactivate webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RotateSecret replaces a webhook's signing secret. Deliveries are signed
+// with both the new and previous secret until the grace period ends, so
+// receivers can switch secrets without rejecting deliveries. A zero grace
+// period invalidates the previous secret immediately.
+func (r *Registry) RotateSecret(ctx context.Context, webhookID string, grace time.Duration) (*Webhook, error) {
+	now := time.Now().UTC()
+	var previousExpiresAt *time.Time
+	if grace > 0 {
+		expires := now.Add(grace)
+		previousExpiresAt = &expires
+	}
+
+	query := `
+		UPDATE webhooks
+		SET previous_secret = CASE WHEN $3::TIMESTAMP IS NULL THEN NULL ELSE secret END,
+		    previous_secret_expires_at = $3,
+		    secret = $2,
+		    updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, webhookID, generateSecret(), previousExpiresAt, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("webhook not found")
+	}
+
+	r.logger.Info().
+		Str("webhook_id", webhookID).
+		Dur("grace_period", grace).
+		Msg("Webhook secret rotated")
+
+	return r.Get(ctx, webhookID)
+}
+
+// GetActiveWebhooksForEvent retrieves all active webhooks subscribed to an
+// event. If orgID is set, only that organization's webhooks are returned.
+func (r *Registry) GetActiveWebhooksForEvent(ctx context.Context, eventType EventType, orgID string) ([]*Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
 		FROM webhooks
 		WHERE status = 'ACTIVE'
 		AND $1 = ANY(events)
+		AND ($2 = '' OR org_id = $2)
 		ORDER BY created_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, string(eventType))
+	rows, err := r.db.QueryContext(ctx, query, string(eventType), orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active webhooks: %w", err)
 	}
@@ -363,39 +438,10 @@ func (r *Registry) GetActiveWebhooksForEvent(ctx context.Context, eventType Even
 
 	webhooks := []*Webhook{}
 	for rows.Next() {
-		webhook := &Webhook{}
-		var events pq.StringArray
-		var sourceChains, destChains pq.StringArray
-
-		err := rows.Scan(
-			&webhook.ID,
-			&webhook.URL,
-			&webhook.Secret,
-			&events,
-			&webhook.Status,
-			&webhook.Description,
-			&webhook.CreatedBy,
-			&webhook.CreatedAt,
-			&webhook.UpdatedAt,
-			&webhook.LastUsedAt,
-			&webhook.FailCount,
-			&webhook.SuccessCount,
-			&sourceChains,
-			&destChains,
-			&webhook.MinAmount,
-			&webhook.MaxAmount,
-		)
+		webhook, err := scanWebhook(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan webhook: %w", err)
 		}
-
-		webhook.Events = make([]EventType, len(events))
-		for i, e := range events {
-			webhook.Events[i] = EventType(e)
-		}
-		webhook.SourceChains = sourceChains
-		webhook.DestChains = destChains
-
 		webhooks = append(webhooks, webhook)
 	}
 
@@ -416,7 +462,71 @@ func (r *Registry) validateWebhook(webhook *Webhook) error {
 		return fmt.Errorf("created_by is required")
 	}
 
-	// Validate event types
+	return ValidateEventTypes(webhook.Events)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner) (*Webhook, error) {
+	webhook := &Webhook{}
+	var events pq.StringArray
+	var sourceChains, destChains pq.StringArray
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		&events,
+		&webhook.Status,
+		&webhook.Description,
+		&webhook.CreatedBy,
+		&webhook.OrgID,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+		&webhook.LastUsedAt,
+		&webhook.FailCount,
+		&webhook.SuccessCount,
+		&sourceChains,
+		&destChains,
+		&webhook.MinAmount,
+		&webhook.MaxAmount,
+		&webhook.PreviousSecret,
+		&webhook.PreviousSecretExpiresAt,
+		&webhook.HealthScore,
+		&webhook.SuspendedAt,
+		&webhook.LastProbeAt,
+		&webhook.LegacySignature,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert string arrays to typed arrays
+	webhook.Events = make([]EventType, len(events))
+	for i, e := range events {
+		webhook.Events[i] = EventType(e)
+	}
+	webhook.SourceChains = sourceChains
+	webhook.DestChains = destChains
+
+	return webhook, nil
+}
+
+// generateSecret generates a random secret for webhook signing
+func generateSecret() string {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		// Fallback to UUID if random fails
+		return uuid.New().String()
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// ValidateEventTypes checks that every event type is one webhooks can
+// subscribe to
+func ValidateEventTypes(events []EventType) error {
 	validEvents := map[EventType]bool{
 		EventMessageCreated:   true,
 		EventMessagePending:   true,
@@ -428,9 +538,10 @@ func (r *Registry) validateWebhook(webhook *Webhook) error {
 		EventBatchSubmitted:   true,
 		EventBatchConfirmed:   true,
 		EventBatchFailed:      true,
+		EventWebhookDisabled:  true,
 	}
 
-	for _, event := range webhook.Events {
+	for _, event := range events {
 		if !validEvents[event] {
 			return fmt.Errorf("invalid event type: %s", event)
 		}
@@ -438,13 +549,3 @@ func (r *Registry) validateWebhook(webhook *Webhook) error {
 
 	return nil
 }
-
-// generateSecret generates a random secret for webhook signing
-func generateSecret() string {
-	bytes := make([]byte, 32)
-	if _, err := rand.Read(bytes); err != nil {
-		// Fallback to UUID if random fails
-		return uuid.New().String()
-	}
-	return hex.EncodeToString(bytes)
-}
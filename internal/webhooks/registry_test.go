@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIncrementFailCount_SuspendsAtThreshold(t *testing.T) {
+	s := newTestDeliveryService(t, 2)
+	ctx := context.Background()
+	insertWebhook(t, s, "hook", "ACTIVE")
+
+	const threshold = 3
+	for i := 1; i < threshold; i++ {
+		suspended, err := s.registry.IncrementFailCount(ctx, "hook", threshold)
+		if err != nil || suspended {
+			t.Fatalf("Failure %d: expected no suspension, got %v, %v", i, suspended, err)
+		}
+	}
+
+	suspended, err := s.registry.IncrementFailCount(ctx, "hook", threshold)
+	if err != nil || !suspended {
+		t.Fatalf("Expected failure %d to suspend the webhook, got %v, %v", threshold, suspended, err)
+	}
+	webhook, err := s.registry.Get(ctx, "hook")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if webhook.Status != WebhookStatusFailed || webhook.SuspendedAt == nil {
+		t.Errorf("Expected webhook suspended, got status %s", webhook.Status)
+	}
+
+	// Only the transition reports a suspension
+	if suspended, _ := s.registry.IncrementFailCount(ctx, "hook", threshold); suspended {
+		t.Error("Expected further failures not to suspend the webhook again")
+	}
+}
+
+func TestClaimProbes_ReactivatesRecoveredWebhook(t *testing.T) {
+	s := newTestDeliveryService(t, 2)
+	ctx := context.Background()
+	insertWebhook(t, s, "suspended", "FAILED")
+	insertWebhook(t, s, "active", "ACTIVE")
+
+	probes, err := s.registry.ClaimProbes(ctx, 10, time.Hour)
+	if err != nil || len(probes) != 1 || probes[0].ID != "suspended" {
+		t.Fatalf("Expected the suspended webhook to be claimed for a probe, got %v: %v", probes, err)
+	}
+	if again, _ := s.registry.ClaimProbes(ctx, 10, time.Hour); len(again) != 0 {
+		t.Fatalf("Expected no probe before the interval passes, got %d", len(again))
+	}
+
+	reactivated, err := s.registry.Reactivate(ctx, "suspended")
+	if err != nil || !reactivated {
+		t.Fatalf("Expected webhook to be reactivated, got %v, %v", reactivated, err)
+	}
+	webhook, _ := s.registry.Get(ctx, "suspended")
+	if webhook.Status != WebhookStatusActive || webhook.FailCount != 0 || webhook.SuspendedAt != nil {
+		t.Errorf("Expected a clean active webhook, got %+v", webhook)
+	}
+
+	// A webhook its owner paused is left alone
+	if err := s.registry.UpdateStatus(ctx, "active", WebhookStatusPaused); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	if reactivated, _ := s.registry.Reactivate(ctx, "active"); reactivated {
+		t.Error("Expected a paused webhook not to be reactivated")
+	}
+}
@@ -0,0 +1,188 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrReplayInProgress is returned when a webhook still has replayed
+	// events waiting to be delivered
+	ErrReplayInProgress = errors.New("a replay is already in progress for this webhook")
+	// ErrReplayTooLarge is returned when a replay matches more events than
+	// one replay may queue
+	ErrReplayTooLarge = errors.New("replay matches too many events")
+	// ErrEventNotFound is returned when an event does not exist or belongs
+	// to another webhook
+	ErrEventNotFound = errors.New("webhook event not found")
+	// ErrEventPending is returned when redelivering an event whose delivery
+	// has not finished
+	ErrEventPending = errors.New("webhook event is still being delivered")
+)
+
+// replayWhere selects original events of a webhook matching a replay
+// filter. Events still being delivered, and replays themselves, are never
+// replayed.
+const replayWhere = `
+	WHERE webhook_id = $1
+	AND timestamp >= $2 AND timestamp < $3
+	AND (cardinality($4::TEXT[]) = 0 OR event_type = ANY($4))
+	AND status IN ('DELIVERED', 'FAILED')
+	AND ($5 = false OR status = 'FAILED')
+	AND replay_of IS NULL
+`
+
+// Replay queues the webhook's events matching filter for delivery again.
+// Replayed events are spread out at ReplayRate per second. With dryRun, the
+// matching events are only counted.
+func (s *DeliveryService) Replay(ctx context.Context, webhook *Webhook, filter *ReplayFilter, dryRun bool) (*ReplayResult, error) {
+	args := []interface{}{
+		webhook.ID,
+		filter.From.UTC(),
+		filter.To.UTC(),
+		pq.Array(eventTypeStrings(filter.EventTypes)),
+		filter.FailedOnly,
+	}
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_events`+replayWhere, args...).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count replay events: %w", err)
+	}
+
+	result := &ReplayResult{
+		Count:    count,
+		DryRun:   dryRun,
+		Duration: s.replayDuration(count).String(),
+	}
+	if count > s.config.MaxReplayEvents {
+		return result, fmt.Errorf("%w: %d events, at most %d allowed", ErrReplayTooLarge, count, s.config.MaxReplayEvents)
+	}
+	if dryRun || count == 0 {
+		return result, nil
+	}
+
+	result.ReplayID = uuid.New().String()
+	queued, err := s.queueReplay(ctx, webhook, result.ReplayID, `SELECT id FROM webhook_events`+replayWhere, args...)
+	if err != nil {
+		return nil, err
+	}
+	result.Count = queued
+
+	s.logger.Info().
+		Str("webhook_id", webhook.ID).
+		Str("replay_id", result.ReplayID).
+		Int("events", queued).
+		Msg("Webhook events queued for replay")
+
+	return result, nil
+}
+
+// Redeliver queues a single event of the webhook for delivery again
+func (s *DeliveryService) Redeliver(ctx context.Context, webhook *Webhook, eventID string) (*ReplayResult, error) {
+	var status string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT status FROM webhook_events WHERE id = $1 AND webhook_id = $2`,
+		eventID, webhook.ID,
+	).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	if status == eventStatusPending {
+		return nil, ErrEventPending
+	}
+
+	replayID := uuid.New().String()
+	if _, err := s.queueReplay(ctx, webhook, replayID, `SELECT id FROM webhook_events WHERE webhook_id = $1 AND id = $2`, webhook.ID, eventID); err != nil {
+		return nil, err
+	}
+
+	return &ReplayResult{ReplayID: replayID, Count: 1}, nil
+}
+
+// queueReplay copies the events selected by selectIDs as new pending events
+// of the replay. The replay is refused while an earlier one is still being
+// delivered. selectIDs must take the webhook ID as $1.
+func (s *DeliveryService) queueReplay(ctx context.Context, webhook *Webhook, replayID, selectIDs string, args ...interface{}) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize replays of the same webhook
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM webhooks WHERE id = $1 FOR UPDATE`, webhook.ID); err != nil {
+		return 0, fmt.Errorf("failed to lock webhook: %w", err)
+	}
+
+	var inProgress bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM webhook_events
+			WHERE webhook_id = $1 AND replay_id IS NOT NULL AND status = 'PENDING'
+		)
+	`, webhook.ID).Scan(&inProgress)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check for replays in progress: %w", err)
+	}
+	if inProgress {
+		return 0, ErrReplayInProgress
+	}
+
+	// Events are queued to the webhook's current URL, spaced 1/rate apart
+	n := len(args)
+	query := fmt.Sprintf(`
+		INSERT INTO webhook_events (
+			id, webhook_id, event_type, payload, timestamp, body,
+			delivery_url, status, available_at, replay_of, replay_id
+		)
+		SELECT
+			gen_random_uuid()::TEXT, e.webhook_id, e.event_type, e.payload, e.timestamp,
+			COALESCE(e.body, e.payload::TEXT), $%d, 'PENDING',
+			NOW() + (ROW_NUMBER() OVER (ORDER BY e.timestamp) - 1) * ($%d * INTERVAL '1 millisecond'),
+			e.id, $%d
+		FROM webhook_events e
+		WHERE e.id IN (%s)
+	`, n+1, n+2, n+3, selectIDs)
+
+	interval := time.Second / time.Duration(s.config.ReplayRate)
+	args = append(args, webhook.URL, interval.Milliseconds(), replayID)
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue replay: %w", err)
+	}
+
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit replay: %w", err)
+	}
+
+	s.wake()
+	return int(queued), nil
+}
+
+// replayDuration estimates how long count replayed events take to be sent
+func (s *DeliveryService) replayDuration(count int) time.Duration {
+	return time.Duration(count) * time.Second / time.Duration(s.config.ReplayRate)
+}
+
+func eventTypeStrings(eventTypes []EventType) []string {
+	strs := make([]string, len(eventTypes))
+	for i, e := range eventTypes {
+		strs[i] = string(e)
+	}
+	return strs
+}
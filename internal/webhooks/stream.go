@@ -0,0 +1,342 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// streamPageSize bounds the events read from the stream table at once
+const streamPageSize = 500
+
+// streamPruneInterval is how often events older than the retention are
+// deleted
+const streamPruneInterval = time.Hour
+
+// ErrTooManyStreams is returned when a server has no room for another
+// tracking stream
+var ErrTooManyStreams = errors.New("too many open tracking streams")
+
+// PublishStreamEvent saves an event for live tracking streams. Every API
+// server tails the saved events, so a stream receives events published by
+// any service.
+func (t *TrackingService) PublishStreamEvent(ctx context.Context, event *StreamEvent) error {
+	var timelineJSON []byte
+	if event.Timeline != nil {
+		data, err := json.Marshal(event.Timeline)
+		if err != nil {
+			return fmt.Errorf("failed to marshal timeline event: %w", err)
+		}
+		timelineJSON = data
+	}
+
+	query := `
+		INSERT INTO message_stream_events (
+			message_id, org_id, event_type, status,
+			sender, source_chain, dest_chain, timeline
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	err := t.db.QueryRowContext(ctx, query,
+		event.MessageID,
+		event.OrgID,
+		event.EventType,
+		event.Status,
+		event.Sender,
+		event.SourceChain,
+		event.DestChain,
+		timelineJSON,
+	).Scan(&event.ID, &event.Timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to publish stream event: %w", err)
+	}
+
+	return nil
+}
+
+// StreamEvents returns up to limit events after afterID that match filter,
+// oldest first
+func (t *TrackingService) StreamEvents(ctx context.Context, afterID int64, filter *StreamFilter, limit int) ([]*StreamEvent, error) {
+	query := `
+		SELECT id, message_id, org_id, event_type, status,
+			sender, source_chain, dest_chain, timeline, created_at
+		FROM message_stream_events
+		WHERE id > $1
+		AND ($2 = '' OR org_id = $2)
+		AND ($3 = '' OR message_id = $3)
+		AND ($4 = '' OR sender = $4)
+		AND ($5 = '' OR source_chain = $5 OR dest_chain = $5)
+		ORDER BY id
+		LIMIT $6
+	`
+
+	rows, err := t.db.QueryContext(ctx, query,
+		afterID, filter.OrgID, filter.MessageID, filter.Sender, filter.Chain, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stream events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*StreamEvent
+	for rows.Next() {
+		var event StreamEvent
+		var timelineJSON []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.MessageID,
+			&event.OrgID,
+			&event.EventType,
+			&event.Status,
+			&event.Sender,
+			&event.SourceChain,
+			&event.DestChain,
+			&timelineJSON,
+			&event.Timestamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stream event: %w", err)
+		}
+
+		if timelineJSON != nil {
+			event.Timeline = &TimelineEvent{}
+			if err := json.Unmarshal(timelineJSON, event.Timeline); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal timeline of stream event %d: %w", event.ID, err)
+			}
+		}
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate stream events: %w", err)
+	}
+
+	return events, nil
+}
+
+// StreamSubscription receives the events of a tracking stream
+type StreamSubscription struct {
+	filter StreamFilter
+	events chan *StreamEvent
+	closed bool
+}
+
+// Events returns the subscription's events. The channel is closed when the
+// subscription falls too far behind or the hub stops; the client should
+// reconnect and resume from the last event it received.
+func (s *StreamSubscription) Events() <-chan *StreamEvent {
+	return s.events
+}
+
+// StreamHub fans out published stream events to the tracking streams open
+// on this server. It tails the stream table instead of relying on a push
+// from the publisher, so events reach every server whichever service
+// published them.
+type StreamHub struct {
+	tracking    *TrackingService
+	config      *StreamConfig
+	logger      zerolog.Logger
+	mu          sync.Mutex
+	subscribers map[*StreamSubscription]struct{}
+	cursor      int64
+	wg          sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// NewStreamHub creates a new tracking stream hub
+func NewStreamHub(config *StreamConfig, tracking *TrackingService, logger zerolog.Logger) *StreamHub {
+	if config == nil {
+		config = DefaultStreamConfig()
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultStreamConfig().PollInterval
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultStreamConfig().Retention
+	}
+	if config.MaxSubscribers <= 0 {
+		config.MaxSubscribers = DefaultStreamConfig().MaxSubscribers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &StreamHub{
+		tracking:    tracking,
+		config:      config,
+		logger:      logger.With().Str("component", "tracking-stream").Logger(),
+		subscribers: make(map[*StreamSubscription]struct{}),
+		cursor:      -1,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start starts tailing published stream events
+func (h *StreamHub) Start(ctx context.Context) error {
+	h.logger.Info().
+		Dur("poll_interval", h.config.PollInterval).
+		Dur("retention", h.config.Retention).
+		Msg("Starting tracking stream hub")
+
+	h.wg.Add(1)
+	go h.run()
+
+	return nil
+}
+
+// Stop stops the hub and ends every open stream
+func (h *StreamHub) Stop() error {
+	h.logger.Info().Msg("Stopping tracking stream hub")
+	h.cancel()
+	h.wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		h.closeLocked(sub)
+	}
+	return nil
+}
+
+// Subscribe opens a stream of the events matching filter published from now
+// on. Events published earlier are read with TrackingService.StreamEvents.
+func (h *StreamHub) Subscribe(filter StreamFilter) (*StreamSubscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.ctx.Err() != nil {
+		return nil, fmt.Errorf("tracking stream hub is stopped")
+	}
+	if len(h.subscribers) >= h.config.MaxSubscribers {
+		return nil, ErrTooManyStreams
+	}
+
+	sub := &StreamSubscription{
+		filter: filter,
+		events: make(chan *StreamEvent, h.config.BufferSize),
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe closes a stream
+func (h *StreamHub) Unsubscribe(sub *StreamSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeLocked(sub)
+}
+
+func (h *StreamHub) closeLocked(sub *StreamSubscription) {
+	delete(h.subscribers, sub)
+	if !sub.closed {
+		sub.closed = true
+		close(sub.events)
+	}
+}
+
+// run polls for new events and prunes expired ones until the hub stops
+func (h *StreamHub) run() {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.config.PollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Now()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.poll(h.ctx); err != nil && h.ctx.Err() == nil {
+				h.logger.Error().Err(err).Msg("Failed to poll stream events")
+			}
+
+			if time.Since(lastPrune) >= streamPruneInterval {
+				lastPrune = time.Now()
+				h.prune(h.ctx)
+			}
+		}
+	}
+}
+
+// poll broadcasts the events published since the last poll. The first
+// poll only records the latest event, so a new server does not replay the
+// whole retention window to its streams.
+func (h *StreamHub) poll(ctx context.Context) error {
+	if h.cursor < 0 {
+		var cursor int64
+		err := h.tracking.db.QueryRowContext(ctx,
+			`SELECT COALESCE(MAX(id), 0) FROM message_stream_events`,
+		).Scan(&cursor)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get latest stream event: %w", err)
+		}
+		h.cursor = cursor
+		return nil
+	}
+
+	for {
+		events, err := h.tracking.StreamEvents(ctx, h.cursor, &StreamFilter{}, streamPageSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		h.broadcast(events)
+		h.cursor = events[len(events)-1].ID
+
+		if len(events) < streamPageSize {
+			return nil
+		}
+	}
+}
+
+// broadcast sends events to the streams they match. A stream whose buffer
+// is full is closed rather than blocking every other stream.
+func (h *StreamHub) broadcast(events []*StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, event := range events {
+		for sub := range h.subscribers {
+			if !sub.filter.Matches(event) {
+				continue
+			}
+
+			select {
+			case sub.events <- event:
+			default:
+				h.logger.Warn().Msg("Closing tracking stream that fell behind")
+				RecordTrackingStreamDropped()
+				h.closeLocked(sub)
+			}
+		}
+	}
+}
+
+// prune deletes events older than the retention. Clients cannot resume
+// from a pruned event.
+func (h *StreamHub) prune(ctx context.Context) {
+	result, err := h.tracking.db.ExecContext(ctx,
+		`DELETE FROM message_stream_events WHERE created_at < NOW() - ($1 * INTERVAL '1 millisecond')`,
+		h.config.Retention.Milliseconds())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to prune stream events")
+		return
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+		h.logger.Debug().Int64("events", rows).Msg("Pruned stream events")
+	}
+}
@@ -0,0 +1,60 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestStreamHubBroadcast(t *testing.T) {
+	hub := NewStreamHub(&StreamConfig{BufferSize: 2}, nil, zerolog.Nop())
+
+	byMessage, err := hub.Subscribe(StreamFilter{OrgID: "org-1", MessageID: "msg-1"})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	byChain, _ := hub.Subscribe(StreamFilter{Chain: "solana-devnet"})
+
+	hub.broadcast([]*StreamEvent{
+		{ID: 1, MessageID: "msg-1", OrgID: "org-1", SourceChain: "ethereum-sepolia", DestChain: "solana-devnet"},
+		{ID: 2, MessageID: "msg-1", OrgID: "org-2", SourceChain: "ethereum-sepolia", DestChain: "polygon-amoy"},
+		{ID: 3, MessageID: "msg-2", OrgID: "org-1", SourceChain: "solana-devnet", DestChain: "polygon-amoy"},
+	})
+
+	if got := receivedIDs(byMessage); len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected message stream to receive event 1, got %v", got)
+	}
+	if got := receivedIDs(byChain); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("expected chain stream to receive events 1 and 3, got %v", got)
+	}
+
+	// A stream that falls behind is closed so the client resumes
+	hub.broadcast([]*StreamEvent{
+		{ID: 4, DestChain: "solana-devnet"},
+		{ID: 5, DestChain: "solana-devnet"},
+		{ID: 6, DestChain: "solana-devnet"},
+	})
+
+	var count int
+	for range byChain.Events() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 buffered events before the stream closed, got %d", count)
+	}
+	if _, ok := hub.subscribers[byChain]; ok {
+		t.Error("expected stream that fell behind to be unsubscribed")
+	}
+}
+
+func receivedIDs(sub *StreamSubscription) []int64 {
+	var ids []int64
+	for {
+		select {
+		case event := <-sub.Events():
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
@@ -3,6 +3,7 @@ package webhooks
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,10 +26,11 @@ func NewTrackingService(db *database.DB, logger zerolog.Logger) *TrackingService
 	}
 }
 
-// TrackMessage retrieves detailed tracking information for a message
-func (t *TrackingService) TrackMessage(ctx context.Context, messageID string) (*MessageTimeline, error) {
+// TrackMessage retrieves detailed tracking information for a message. If
+// orgID is set, messages owned by other organizations are not found.
+func (t *TrackingService) TrackMessage(ctx context.Context, messageID, orgID string) (*MessageTimeline, error) {
 	// Get message
-	message, err := t.getMessage(ctx, messageID)
+	message, err := t.getMessage(ctx, messageID, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -60,9 +62,9 @@ func (t *TrackingService) QueryMessages(ctx context.Context, query *TrackingQuer
 	sqlQuery, args := t.buildQuery(query)
 
 	// Get total count
-	countQuery := t.buildCountQuery(query)
+	countQuery, countArgs := t.buildCountQuery(query)
 	var totalCount int
-	err := t.db.QueryRowContext(ctx, countQuery, args[:len(args)-2]...).Scan(&totalCount)
+	err := t.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get count: %w", err)
 	}
@@ -95,8 +97,9 @@ func (t *TrackingService) QueryMessages(ctx context.Context, query *TrackingQuer
 	return result, nil
 }
 
-// GetMessageByTxHash retrieves a message by transaction hash
-func (t *TrackingService) GetMessageByTxHash(ctx context.Context, txHash string) (*types.CrossChainMessage, error) {
+// GetMessageByTxHash retrieves a message by transaction hash. If orgID is
+// set, only that organization's messages are searched.
+func (t *TrackingService) GetMessageByTxHash(ctx context.Context, txHash, orgID string) (*types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, source_chain, dest_chain, sender, recipient,
@@ -104,11 +107,12 @@ func (t *TrackingService) GetMessageByTxHash(ctx context.Context, txHash string)
 			source_tx_hash, dest_tx_hash, validator_signatures,
 			created_at, updated_at, submitted_at, confirmed_at
 		FROM messages
-		WHERE source_tx_hash = $1 OR dest_tx_hash = $1
+		WHERE (source_tx_hash = $1 OR dest_tx_hash = $1)
+		AND ($2 = '' OR org_id = $2)
 		LIMIT 1
 	`
 
-	row := t.db.QueryRowContext(ctx, query, txHash)
+	row := t.db.QueryRowContext(ctx, query, txHash, orgID)
 	return t.scanMessage(row)
 }
 
@@ -121,11 +125,13 @@ func (t *TrackingService) RecordEvent(ctx context.Context, messageID string, eve
 		) VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	metadataJSON := "{}"
+	metadataJSON := []byte("{}")
 	if len(event.Metadata) > 0 {
-		// Convert metadata to JSON
-		// For simplicity, we'll store as string
-		metadataJSON = fmt.Sprintf("%v", event.Metadata)
+		data, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event metadata: %w", err)
+		}
+		metadataJSON = data
 	}
 
 	_, err := t.db.ExecContext(ctx, query,
@@ -151,8 +157,9 @@ func (t *TrackingService) RecordEvent(ctx context.Context, messageID string, eve
 	return nil
 }
 
-// GetRecentMessages retrieves recently created messages
-func (t *TrackingService) GetRecentMessages(ctx context.Context, limit int) ([]*types.CrossChainMessage, error) {
+// GetRecentMessages retrieves recently created messages. If orgID is set,
+// only that organization's messages are returned.
+func (t *TrackingService) GetRecentMessages(ctx context.Context, orgID string, limit int) ([]*types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, source_chain, dest_chain, sender, recipient,
@@ -160,11 +167,12 @@ func (t *TrackingService) GetRecentMessages(ctx context.Context, limit int) ([]*
 			source_tx_hash, dest_tx_hash, validator_signatures,
 			created_at, updated_at, submitted_at, confirmed_at
 		FROM messages
+		WHERE ($2 = '' OR org_id = $2)
 		ORDER BY created_at DESC
 		LIMIT $1
 	`
 
-	rows, err := t.db.QueryContext(ctx, query, limit)
+	rows, err := t.db.QueryContext(ctx, query, limit, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query recent messages: %w", err)
 	}
@@ -182,8 +190,9 @@ func (t *TrackingService) GetRecentMessages(ctx context.Context, limit int) ([]*
 	return messages, nil
 }
 
-// GetMessagesByStatus retrieves messages by status
-func (t *TrackingService) GetMessagesByStatus(ctx context.Context, status string, limit int) ([]*types.CrossChainMessage, error) {
+// GetMessagesByStatus retrieves messages by status. If orgID is set, only
+// that organization's messages are returned.
+func (t *TrackingService) GetMessagesByStatus(ctx context.Context, orgID, status string, limit int) ([]*types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, source_chain, dest_chain, sender, recipient,
@@ -191,12 +200,12 @@ func (t *TrackingService) GetMessagesByStatus(ctx context.Context, status string
 			source_tx_hash, dest_tx_hash, validator_signatures,
 			created_at, updated_at, submitted_at, confirmed_at
 		FROM messages
-		WHERE status = $1
+		WHERE status = $1 AND ($3 = '' OR org_id = $3)
 		ORDER BY created_at DESC
 		LIMIT $2
 	`
 
-	rows, err := t.db.QueryContext(ctx, query, status, limit)
+	rows, err := t.db.QueryContext(ctx, query, status, limit, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query messages by status: %w", err)
 	}
@@ -216,7 +225,7 @@ func (t *TrackingService) GetMessagesByStatus(ctx context.Context, status string
 
 // Helper functions
 
-func (t *TrackingService) getMessage(ctx context.Context, messageID string) (*types.CrossChainMessage, error) {
+func (t *TrackingService) getMessage(ctx context.Context, messageID, orgID string) (*types.CrossChainMessage, error) {
 	query := `
 		SELECT
 			id, source_chain, dest_chain, sender, recipient,
@@ -224,10 +233,10 @@ func (t *TrackingService) getMessage(ctx context.Context, messageID string) (*ty
 			source_tx_hash, dest_tx_hash, validator_signatures,
 			created_at, updated_at, submitted_at, confirmed_at
 		FROM messages
-		WHERE id = $1
+		WHERE id = $1 AND ($2 = '' OR org_id = $2)
 	`
 
-	row := t.db.QueryRowContext(ctx, query, messageID)
+	row := t.db.QueryRowContext(ctx, query, messageID, orgID)
 	return t.scanMessage(row)
 }
 
@@ -252,7 +261,7 @@ func (t *TrackingService) getTimelineEvents(ctx context.Context, messageID strin
 		var event TimelineEvent
 		var txHash, chainID sql.NullString
 		var blockNumber sql.NullInt64
-		var metadata string
+		var metadata []byte
 
 		err := rows.Scan(
 			&event.EventType,
@@ -277,8 +286,12 @@ func (t *TrackingService) getTimelineEvents(ctx context.Context, messageID strin
 			event.ChainID = chainID.String
 		}
 
-		// Parse metadata if needed
 		event.Metadata = make(map[string]interface{})
+		if len(metadata) > 0 {
+			if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal timeline event metadata: %w", err)
+			}
+		}
 
 		events = append(events, event)
 	}
@@ -314,6 +327,8 @@ func (t *TrackingService) estimateCompletion(message *types.CrossChainMessage, e
 }
 
 func (t *TrackingService) buildQuery(query *TrackingQuery) (string, []interface{}) {
+	where, args := t.buildWhere(query)
+
 	sql := `
 		SELECT
 			id, source_chain, dest_chain, sender, recipient,
@@ -321,12 +336,44 @@ func (t *TrackingService) buildQuery(query *TrackingQuery) (string, []interface{
 			source_tx_hash, dest_tx_hash, validator_signatures,
 			created_at, updated_at, submitted_at, confirmed_at
 		FROM messages
-		WHERE 1=1
-	`
+	` + where
+
+	sql += " ORDER BY created_at DESC"
+
+	// Add limit and offset
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	sql += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, query.Offset)
+
+	return sql, args
+}
+
+func (t *TrackingService) buildCountQuery(query *TrackingQuery) (string, []interface{}) {
+	where, args := t.buildWhere(query)
+	return "SELECT COUNT(*) FROM messages " + where, args
+}
+
+// buildWhere builds the WHERE clause and arguments shared by the query and
+// count for a tracking query
+func (t *TrackingService) buildWhere(query *TrackingQuery) (string, []interface{}) {
+	sql := "WHERE 1=1"
 
 	args := []interface{}{}
 	argIndex := 1
 
+	if query.OrgID != "" {
+		sql += fmt.Sprintf(" AND org_id = $%d", argIndex)
+		args = append(args, query.OrgID)
+		argIndex++
+	}
+
 	if query.MessageID != "" {
 		sql += fmt.Sprintf(" AND id = $%d", argIndex)
 		args = append(args, query.MessageID)
@@ -378,54 +425,11 @@ func (t *TrackingService) buildQuery(query *TrackingQuery) (string, []interface{
 	if query.ToDate != nil {
 		sql += fmt.Sprintf(" AND created_at <= $%d", argIndex)
 		args = append(args, query.ToDate)
-		argIndex++
 	}
 
-	sql += " ORDER BY created_at DESC"
-
-	// Add limit and offset
-	limit := query.Limit
-	if limit <= 0 {
-		limit = 50
-	}
-	if limit > 1000 {
-		limit = 1000
-	}
-
-	sql += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, limit, query.Offset)
-
 	return sql, args
 }
 
-func (t *TrackingService) buildCountQuery(query *TrackingQuery) string {
-	sql := "SELECT COUNT(*) FROM messages WHERE 1=1"
-
-	if query.MessageID != "" {
-		sql += " AND id = $1"
-	}
-	if query.TxHash != "" {
-		sql += " AND (source_tx_hash = $1 OR dest_tx_hash = $1)"
-	}
-	if query.Sender != "" {
-		sql += " AND sender = $1"
-	}
-	if query.Recipient != "" {
-		sql += " AND recipient = $1"
-	}
-	if query.SourceChain != "" {
-		sql += " AND source_chain = $1"
-	}
-	if query.DestChain != "" {
-		sql += " AND dest_chain = $1"
-	}
-	if query.Status != "" {
-		sql += " AND status = $1"
-	}
-
-	return sql
-}
-
 type scanner interface {
 	Scan(dest ...interface{}) error
 }
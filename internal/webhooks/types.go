@@ -20,8 +20,18 @@ const (
 	EventBatchSubmitted   EventType = "batch.submitted"
 	EventBatchConfirmed   EventType = "batch.confirmed"
 	EventBatchFailed      EventType = "batch.failed"
+	EventWebhookDisabled  EventType = "webhook.disabled"
 )
 
+// LegacySignatureHeader carries the deprecated unbound HMAC-SHA256 of the
+// request body. It is only sent to webhooks that opt in, and to none after
+// LegacySignatureSunset; receivers should verify the Articium-Signature
+// header instead.
+const LegacySignatureHeader = "X-Webhook-Signature"
+
+// LegacySignatureSunset is the date LegacySignatureHeader stops being sent
+var LegacySignatureSunset = time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+
 // WebhookStatus represents the status of a webhook registration
 type WebhookStatus string
 
@@ -41,12 +51,28 @@ type Webhook struct {
 	Status       WebhookStatus `json:"status"`
 	Description  string        `json:"description,omitempty"`
 	CreatedBy    string        `json:"created_by"`
+	OrgID        string        `json:"org_id"`
 	CreatedAt    time.Time     `json:"created_at"`
 	UpdatedAt    time.Time     `json:"updated_at"`
 	LastUsedAt   *time.Time    `json:"last_used_at,omitempty"`
-	FailCount    int           `json:"fail_count"`
+	FailCount    int           `json:"fail_count"` // consecutive failures
 	SuccessCount int           `json:"success_count"`
 
+	// HealthScore is a weighted success rate of recent deliveries, from 0
+	// to 1. SuspendedAt is set while the webhook is suspended for failing.
+	HealthScore float64    `json:"health_score"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	LastProbeAt *time.Time `json:"last_probe_at,omitempty"`
+
+	// PreviousSecret is still accepted by receivers until
+	// PreviousSecretExpiresAt, after the secret is rotated
+	PreviousSecret          string     `json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+
+	// LegacySignature also sends the deprecated LegacySignatureHeader,
+	// until LegacySignatureSunset
+	LegacySignature bool `json:"legacy_signature"`
+
 	// Filtering options
 	SourceChains []string `json:"source_chains,omitempty"`
 	DestChains   []string `json:"dest_chains,omitempty"`
@@ -61,8 +87,34 @@ type WebhookEvent struct {
 	EventType   EventType              `json:"event_type"`
 	Payload     map[string]interface{} `json:"payload"`
 	Timestamp   time.Time              `json:"timestamp"`
-	Signature   string                 `json:"signature,omitempty"`
 	DeliveryURL string                 `json:"delivery_url"`
+
+	// Body is the exact request body sent, fixed when the event is first
+	// delivered so that retries send and sign the same bytes
+	Body []byte `json:"-"`
+	// Attempts is the number of times the event has been claimed for
+	// delivery, including the current attempt
+	Attempts int `json:"-"`
+	// ReplayOf is the original event of a replayed event, and ReplayID
+	// the replay request that queued it
+	ReplayOf string `json:"replay_of,omitempty"`
+	ReplayID string `json:"replay_id,omitempty"`
+}
+
+// SigningSecrets returns the secrets deliveries are signed with: the
+// current secret, and the previous one during a rotation's grace period
+func (w *Webhook) SigningSecrets(now time.Time) []string {
+	secrets := []string{w.Secret}
+	if w.PreviousSecret != "" && w.PreviousSecretExpiresAt != nil && now.Before(*w.PreviousSecretExpiresAt) {
+		secrets = append(secrets, w.PreviousSecret)
+	}
+	return secrets
+}
+
+// SendsLegacySignature reports whether deliveries sent at now carry the
+// deprecated LegacySignatureHeader
+func (w *Webhook) SendsLegacySignature(now time.Time) bool {
+	return w.LegacySignature && now.Before(LegacySignatureSunset)
 }
 
 // WebhookDeliveryAttempt represents an attempt to deliver a webhook
@@ -86,6 +138,44 @@ type WebhookDeliveryConfig struct {
 	RetryDelays     []time.Duration `json:"retry_delays"`
 	TimeoutDuration time.Duration   `json:"timeout_duration"`
 	MaxConcurrent   int             `json:"max_concurrent"`
+	// PollInterval is how often the delivery queue is checked for due
+	// events when no new event has been dispatched
+	PollInterval time.Duration `json:"poll_interval"`
+	// Lease is how long a claimed event is hidden from other replicas. If
+	// the replica crashes mid-delivery, the event is delivered again once
+	// the lease expires. It must exceed TimeoutDuration.
+	Lease time.Duration `json:"lease"`
+	// MaxPerEndpoint caps concurrent deliveries to one webhook so a slow
+	// receiver cannot take every delivery slot
+	MaxPerEndpoint int `json:"max_per_endpoint"`
+	// FailureThreshold is the number of consecutive failed attempts after
+	// which a webhook is suspended
+	FailureThreshold int `json:"failure_threshold"`
+	// ProbeInterval is how often a suspended webhook is probed for recovery
+	ProbeInterval time.Duration `json:"probe_interval"`
+	// ReplayRate is the number of replayed events per second queued for a
+	// webhook, so a large replay does not flood the receiver
+	ReplayRate int `json:"replay_rate"`
+	// MaxReplayEvents is the most events one replay may queue
+	MaxReplayEvents int `json:"max_replay_events"`
+}
+
+// ReplayFilter selects the events of a webhook to replay
+type ReplayFilter struct {
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	EventTypes []EventType `json:"event_types,omitempty"`
+	// FailedOnly limits the replay to events whose delivery failed
+	FailedOnly bool `json:"failed_only,omitempty"`
+}
+
+// ReplayResult describes the events queued by a replay
+type ReplayResult struct {
+	ReplayID string `json:"replay_id,omitempty"`
+	Count    int    `json:"count"`
+	DryRun   bool   `json:"dry_run"`
+	// Duration is roughly how long the replayed events take to be sent
+	Duration string `json:"estimated_duration"`
 }
 
 // DefaultDeliveryConfig returns default webhook delivery configuration
@@ -99,8 +189,15 @@ func DefaultDeliveryConfig() *WebhookDeliveryConfig {
 			1 * time.Hour,
 			6 * time.Hour,
 		},
-		TimeoutDuration: 30 * time.Second,
-		MaxConcurrent:   10,
+		TimeoutDuration:  30 * time.Second,
+		MaxConcurrent:    10,
+		PollInterval:     time.Second,
+		Lease:            2 * time.Minute,
+		MaxPerEndpoint:   2,
+		FailureThreshold: 10,
+		ProbeInterval:    5 * time.Minute,
+		ReplayRate:       10,
+		MaxReplayEvents:  10000,
 	}
 }
 
@@ -129,6 +226,7 @@ type BatchEvent struct {
 
 // TrackingQuery represents a query for tracking messages
 type TrackingQuery struct {
+	OrgID       string     `json:"org_id,omitempty"` // empty for all organizations
 	MessageID   string     `json:"message_id,omitempty"`
 	TxHash      string     `json:"tx_hash,omitempty"`
 	Sender      string     `json:"sender,omitempty"`
@@ -142,6 +240,66 @@ type TrackingQuery struct {
 	Offset      int        `json:"offset,omitempty"`
 }
 
+// StreamEvent is a message status transition or timeline event pushed to
+// live tracking streams
+type StreamEvent struct {
+	ID          int64          `json:"id"`
+	MessageID   string         `json:"message_id"`
+	OrgID       string         `json:"-"`
+	EventType   EventType      `json:"event_type"`
+	Status      string         `json:"status"`
+	Sender      string         `json:"sender,omitempty"`
+	SourceChain string         `json:"source_chain,omitempty"`
+	DestChain   string         `json:"dest_chain,omitempty"`
+	Timeline    *TimelineEvent `json:"timeline,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+// StreamFilter selects the events a tracking stream receives. Empty fields
+// match every event.
+type StreamFilter struct {
+	OrgID     string
+	MessageID string
+	Sender    string
+	// Chain matches events of messages from or to the chain ID
+	Chain string
+}
+
+// Matches reports whether the filter selects an event
+func (f *StreamFilter) Matches(event *StreamEvent) bool {
+	if f.OrgID != "" && event.OrgID != f.OrgID {
+		return false
+	}
+	if f.MessageID != "" && event.MessageID != f.MessageID {
+		return false
+	}
+	if f.Sender != "" && event.Sender != f.Sender {
+		return false
+	}
+	if f.Chain != "" && event.SourceChain != f.Chain && event.DestChain != f.Chain {
+		return false
+	}
+	return true
+}
+
+// StreamConfig holds configuration for live tracking streams
+type StreamConfig struct {
+	PollInterval   time.Duration `json:"poll_interval"`
+	Retention      time.Duration `json:"retention"`
+	BufferSize     int           `json:"buffer_size"`
+	MaxSubscribers int           `json:"max_subscribers"`
+}
+
+// DefaultStreamConfig returns default tracking stream configuration
+func DefaultStreamConfig() *StreamConfig {
+	return &StreamConfig{
+		PollInterval:   500 * time.Millisecond,
+		Retention:      24 * time.Hour,
+		BufferSize:     256,
+		MaxSubscribers: 5000,
+	}
+}
+
 // TrackingResult represents the result of a tracking query
 type TrackingResult struct {
 	Messages   []*types.CrossChainMessage `json:"messages"`
@@ -0,0 +1,153 @@
+// Package webhooksig signs and verifies Articium webhook deliveries.
+//
+// Every delivery carries an Articium-Signature header of the form
+//
+//	Articium-Signature: t=1700000000,v1=5257a869...,v1=9f1c2e04...
+//
+// where t is the Unix time the delivery was sent and each v1 is a hex
+// HMAC-SHA256 of "<t>.<raw request body>" under one of the webhook's
+// secrets. While a rotated secret is in its grace period, the header carries
+// a signature for both the new and previous secret, so receivers can switch
+// secrets at any point during the grace period.
+//
+// Receivers should verify the raw body before parsing it:
+//
+//	body, err := webhooksig.VerifyRequest(r, secret, webhooksig.DefaultTolerance)
+//	if err != nil {
+//		http.Error(w, "invalid signature", http.StatusBadRequest)
+//		return
+//	}
+//
+// The timestamp check rejects captured deliveries replayed after the
+// tolerance. Receivers that must also reject replays within the tolerance
+// should remember the X-Event-ID of deliveries they have processed.
+//
+// The X-Webhook-Signature header, an HMAC of the body alone, is deprecated.
+// It is only sent to webhooks registered with legacy_signature set, and is
+// no longer sent to any webhook from 2027-04-01.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header carrying a delivery's signatures
+const SignatureHeader = "Articium-Signature"
+
+// DefaultTolerance is how old a delivery's timestamp may be before it is
+// rejected as a replay
+const DefaultTolerance = 5 * time.Minute
+
+// maxBodySize bounds the body read by VerifyRequest
+const maxBodySize = 1 << 20
+
+var (
+	// ErrInvalidHeader is returned when the signature header is missing or
+	// malformed
+	ErrInvalidHeader = errors.New("webhooksig: invalid signature header")
+	// ErrNoValidSignature is returned when no signature matches the secret
+	ErrNoValidSignature = errors.New("webhooksig: no valid signature found")
+	// ErrTimestampExpired is returned when the timestamp is outside the
+	// tolerance
+	ErrTimestampExpired = errors.New("webhooksig: timestamp outside tolerance")
+)
+
+// Sign returns the hex v1 signature of body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(t.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Header returns the signature header value for body sent at t, with one
+// signature per secret
+func Header(t time.Time, body []byte, secrets ...string) string {
+	parts := []string{"t=" + strconv.FormatInt(t.Unix(), 10)}
+	for _, secret := range secrets {
+		parts = append(parts, "v1="+Sign(secret, t, body))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Verify checks that header carries a valid signature of body under secret
+// and that its timestamp is within tolerance of now. A zero tolerance
+// disables the timestamp check.
+func Verify(body []byte, header, secret string, tolerance time.Duration) error {
+	return verifyAt(body, header, secret, tolerance, time.Now())
+}
+
+// VerifyRequest reads the request body and verifies its signature header.
+// The body is returned so it can be parsed after verification.
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("webhooksig: failed to read body: %w", err)
+	}
+
+	if err := Verify(body, r.Header.Get(SignatureHeader), secret, tolerance); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func verifyAt(body []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	t, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if tolerance > 0 {
+		if age := now.Sub(t); age > tolerance || age < -tolerance {
+			return ErrTimestampExpired
+		}
+	}
+
+	expected := []byte(Sign(secret, t, body))
+	for _, sig := range signatures {
+		if hmac.Equal(expected, []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrNoValidSignature
+}
+
+// parseHeader splits a signature header into its timestamp and v1
+// signatures. Unknown schemes are ignored so new ones can be added.
+func parseHeader(header string) (time.Time, []string, error) {
+	var t time.Time
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return time.Time{}, nil, ErrInvalidHeader
+		}
+
+		switch key {
+		case "t":
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return time.Time{}, nil, ErrInvalidHeader
+			}
+			t = time.Unix(unix, 0)
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if t.IsZero() || len(signatures) == 0 {
+		return time.Time{}, nil, ErrInvalidHeader
+	}
+	return t, signatures, nil
+}
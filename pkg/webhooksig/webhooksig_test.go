@@ -0,0 +1,56 @@
+package webhooksig
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"message.confirmed","id":"msg-1"}`)
+	sent := time.Unix(1700000000, 0)
+	header := Header(sent, body, "new-secret", "old-secret")
+
+	tests := []struct {
+		name    string
+		body    []byte
+		header  string
+		secret  string
+		now     time.Time
+		wantErr error
+	}{
+		{"current secret", body, header, "new-secret", sent.Add(time.Minute), nil},
+		{"previous secret during grace period", body, header, "old-secret", sent, nil},
+		{"wrong secret", body, header, "other-secret", sent, ErrNoValidSignature},
+		{"tampered body", []byte(`{"event":"message.failed","id":"msg-1"}`), header, "new-secret", sent, ErrNoValidSignature},
+		{"replayed after tolerance", body, header, "new-secret", sent.Add(DefaultTolerance + time.Second), ErrTimestampExpired},
+		{"timestamp in the future", body, header, "new-secret", sent.Add(-DefaultTolerance - time.Second), ErrTimestampExpired},
+		{"missing header", body, "", "new-secret", sent, ErrInvalidHeader},
+		{"no signatures", body, "t=1700000000", "new-secret", sent, ErrInvalidHeader},
+		{"unknown scheme ignored", body, header + ",v0=abc", "new-secret", sent, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyAt(tt.body, tt.header, tt.secret, DefaultTolerance, tt.now)
+			if err != tt.wantErr {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"test":true}`)
+	req := httptest.NewRequest("POST", "/hook", bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, Header(time.Now(), body, "secret"))
+
+	got, err := VerifyRequest(req, "secret", DefaultTolerance)
+	if err != nil {
+		t.Fatalf("VerifyRequest failed: %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("expected body %s, got %s", body, got)
+	}
+}